@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditFrom   string
+	auditTo     string
+	auditMethod string
+	auditSerial string
+	auditFormat string
+	auditOutput string
+)
+
+func init() {
+	auditExportCmd.Flags().StringVar(&auditFrom, "from", "", "export checks at or after this date (YYYY-MM-DD) or this long ago, e.g. 30d")
+	auditExportCmd.Flags().StringVar(&auditTo, "to", "", "export checks before this date (YYYY-MM-DD) or this long ago, e.g. 7d")
+	auditExportCmd.Flags().StringVar(&auditMethod, "method", "", "export checks of a method: 'crl', 'ocsp'")
+	auditExportCmd.Flags().StringVar(&auditSerial, "serial", "", "export checks of a serial number in decimal, hex or colon separated hex notation")
+	auditExportCmd.Flags().StringVarP(&auditFormat, "format", "f", "csv", "output format: 'csv', 'json', 'jsonl'")
+	auditExportCmd.Flags().StringVarP(&auditOutput, "output", "o", "", "write the audit log to a file instead of stdout")
+
+	auditCmd.AddCommand(auditExportCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Work with the audit log of revocation checks",
+}
+
+var auditExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the audit log of revocation checks",
+	Long: "Export the audit log of revocation checks, the most recent first. Every check of a certificate against the stored CRLs or an OCSP responder " +
+		"is recorded with the CRL version or responder it was checked against and the result",
+	Example: "certguard audit export --from 2026-01-01 --to 2026-04-01 -o audit.csv\ncertguard audit export --from 30d --method ocsp --format jsonl",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+		if auditFormat != "csv" && auditFormat != "json" && auditFormat != "jsonl" {
+			return fmt.Errorf("unsupported format: %s, allowed values: csv, json, jsonl", auditFormat)
+		}
+
+		filter, err := auditFilter(time.Now())
+		if err != nil {
+			return err
+		}
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		commands, err := newCommands(storage)
+		if err != nil {
+			return err
+		}
+
+		var entries []*crl.AuditEntry
+		switch msg := commands.AuditLog(filter)().(type) {
+		case messages.ErrorMsg:
+			return msg.Err
+		case messages.AuditLogMsg:
+			entries = msg.Entries
+		default:
+			return errors.New("unexpected result of reading the audit log")
+		}
+
+		var out io.Writer = os.Stdout
+		if auditOutput != "" {
+			f, err := os.Create(auditOutput)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		switch auditFormat {
+		case "json":
+			return crl.WriteAuditJSON(out, entries)
+		case "jsonl":
+			return crl.WriteAuditJSONL(out, entries)
+		default:
+			return crl.WriteAuditCSV(out, entries)
+		}
+	},
+}
+
+// auditFilter builds the filter of the audit export flags
+func auditFilter(now time.Time) (crl.AuditFilter, error) {
+	var filter crl.AuditFilter
+	var err error
+	if filter.From, err = parseAuditTime(auditFrom, now); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseAuditTime(auditTo, now); err != nil {
+		return filter, err
+	}
+
+	if auditMethod != "" {
+		if filter.Method, err = crl.ParseAuditMethod(auditMethod); err != nil {
+			return filter, err
+		}
+	}
+
+	if auditSerial != "" {
+		serialNumbers, err := crl.ParseSerialNumber(auditSerial)
+		if err != nil {
+			return filter, err
+		}
+		filter.SerialNumber = serialNumbers[0]
+	}
+
+	return filter, nil
+}
+
+// parseAuditTime parses a date formatted as YYYY-MM-DD or a duration before now, an empty value is the zero time
+func parseAuditTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return date, nil
+	}
+
+	ago, err := crl.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s, use a date formatted as YYYY-MM-DD or a duration like 30d", value)
+	}
+	return now.Add(-ago), nil
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/spf13/cobra"
+)
+
+// exportToStdout is the output of an export that is written to stdout
+const exportToStdout = commands.ExportToStdout
+
+var (
+	crlExportFormat string
+	crlExportOutput string
+	crlImportName   string
+	crlImportValid  string
+)
+
+func init() {
+	crlExportCmd.Flags().StringVarP(&crlExportFormat, "format", "f", "csv", "output format: 'csv', 'json', 'jsonl', 'index', 'der', 'pem'")
+	crlExportCmd.Flags().StringVarP(&crlExportOutput, "output", "o", exportToStdout, "write the export to a new file instead of stdout")
+
+	crlImportCACmd.Flags().StringVarP(&crlImportName, "name", "n", "", "name of the unsigned CRL, the common name of the CA so the certificates it issued are searched on it")
+	crlImportCACmd.Flags().StringVar(&crlImportValid, "valid-for", "7d", "time the imported revocations are current, e.g. 1d, 2w or 12h")
+	_ = crlImportCACmd.MarkFlagRequired("name")
+
+	crlCmd.AddCommand(crlExportCmd)
+	crlCmd.AddCommand(crlImportCACmd)
+	rootCmd.AddCommand(crlCmd)
+}
+
+var crlCmd = &cobra.Command{
+	Use:   "crl",
+	Short: "Work with the stored CRLs",
+}
+
+var crlExportCmd = &cobra.Command{
+	Use:   "export <name>",
+	Short: "Export a stored CRL and its revoked certificates",
+	Long: "Export a stored CRL, named after the common name of its issuer, and its revoked certificates. " +
+		"csv, json and jsonl contain the serial number, revocation date, reason and entry extensions of every revoked certificate, " +
+		"index writes the index.txt database of the OpenSSL ca command and der and pem re-export the original CRL",
+	Example: "certguard crl export \"Example CA\" --format json -o example-ca.json\ncertguard crl export \"Example CA\" --format pem",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+		format, err := crl.ParseExportFormat(crlExportFormat)
+		if err != nil {
+			return err
+		}
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		commands, err := newCommands(storage)
+		if err != nil {
+			return err
+		}
+
+		switch msg := commands.ExportRevocationList(args[0], crlExportOutput, format)().(type) {
+		case messages.ErrorMsg:
+			return msg.Err
+		case messages.RevocationListExportedMsg:
+			if msg.Path == exportToStdout {
+				return nil
+			}
+			if msg.Format.Raw() {
+				fmt.Printf("exported CRL %s to %s\n", msg.Name, msg.Path)
+			} else {
+				fmt.Printf("exported CRL %s with %d revoked certificates to %s\n", msg.Name, msg.RevokedCertificates, msg.Path)
+			}
+			return nil
+		default:
+			return errors.New("unexpected result of exporting the CRL")
+		}
+	},
+}
+
+var crlImportCACmd = &cobra.Command{
+	Use:   "import-ca <file>",
+	Short: "Import the revoked certificates of a CA database as an unsigned CRL",
+	Long: "Import the revoked certificates of a CA that does not publish a CRL, from the index.txt of the OpenSSL ca command " +
+		"or from a JSON export of step-ca revoked certificate records or EJBCA revocation statuses. " +
+		"The revocations are stored as a CRL that is marked as unsigned, they are not signed by the CA. " +
+		"Importing the CA database again adds new revocations and renews the CRL",
+	Example: "certguard crl import-ca /etc/ssl/internal-ca/index.txt --name \"Internal CA\"\ncertguard crl import-ca revoked.json --name \"Step CA\" --valid-for 1d",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+		validity, err := crl.ParseDuration(crlImportValid)
+		if err != nil {
+			return err
+		}
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		commands, err := newCommands(storage)
+		if err != nil {
+			return err
+		}
+
+		switch msg := commands.ImportCADatabase(args[0], crlImportName, validity)().(type) {
+		case messages.ErrorMsg:
+			return msg.Err
+		case messages.CADatabaseImportedMsg:
+			fmt.Printf("imported %d revoked certificates from the %s database %s as unsigned CRL %s, %d certificates are not revoked\n", msg.RevokedCertificates, msg.Format, msg.Path, msg.Name, msg.Skipped)
+			return nil
+		default:
+			return errors.New("unexpected result of importing the CA database")
+		}
+	},
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/pimg/certguard/pkg/ct"
+	domain_ct "github.com/pimg/certguard/pkg/domain/ct"
+	"github.com/spf13/cobra"
+)
+
+const ctLogListFileName = "ct_log_list.json"
+
+func init() {
+	ctLogsCmd.AddCommand(ctLogsUpdateCmd)
+	rootCmd.AddCommand(ctLogsCmd)
+}
+
+var ctLogsCmd = &cobra.Command{
+	Use:   "ctlogs",
+	Short: "Manage the Certificate Transparency log list",
+}
+
+var ctLogsUpdateCmd = &cobra.Command{
+	Use:     "update [url]",
+	Short:   "Download the latest Certificate Transparency log list to the cache directory",
+	Example: "certguard ctlogs update",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logListURL := ct.LogListURL
+		if len(args) == 1 {
+			logListURL = args[0]
+		}
+
+		rawLogList, err := ct.FetchLogList(logListURL)
+		if err != nil {
+			return err
+		}
+
+		path, err := ctLogListPath()
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(filepath.Dir(path), 0o775)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(path, rawLogList, 0o644)
+		if err != nil {
+			return errors.Join(errors.New("could not store CT log list"), err)
+		}
+
+		fmt.Printf("CT log list stored at: %s\n", path)
+		return nil
+	},
+}
+
+// loadCTLogList loads the CT log list from the configured location or the cache directory, falling back to the bundled log list
+func loadCTLogList() *domain_ct.LogList {
+	path, err := ctLogListPath()
+	if err != nil {
+		return domain_ct.BundledLogList()
+	}
+
+	rawLogList, err := os.ReadFile(path)
+	if err != nil {
+		return domain_ct.BundledLogList()
+	}
+
+	logList, err := domain_ct.ParseLogList(rawLogList)
+	if err != nil {
+		log.Printf("could not parse CT log list at: %s, using bundled log list: %v", path, err)
+		return domain_ct.BundledLogList()
+	}
+
+	log.Printf("loaded CT log list from: %s", path)
+	return logList
+}
+
+func ctPolicy() domain_ct.Policy {
+	return domain_ct.Policy{
+		MinSCTs:      v.Config().CT.MinSCTs,
+		MinOperators: v.Config().CT.MinOperators,
+	}
+}
+
+func ctLogListPath() (string, error) {
+	if v.Config().CT.LogList != "" {
+		return v.Config().CT.LogList, nil
+	}
+
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, ctLogListFileName), nil
+}
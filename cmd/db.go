@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pimg/certguard/config"
+	"github.com/pimg/certguard/internal/adapter/db"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/spf13/cobra"
+)
+
+var pruneDryRun bool
+
+func init() {
+	dbPruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "report the CRLs that would be removed without removing them")
+	dbPruneCmd.Flags().Int("keep-versions", 0, "number of CRLs kept per issuer, overrides config.retention.keep_versions")
+	dbPruneCmd.Flags().String("expired-for", "", "remove CRLs that are past their next update for longer than this duration, e.g. 30d, overrides config.retention.expired_for")
+	dbPruneCmd.Flags().String("not-refreshed-for", "", "remove CRLs that have not been downloaded or imported for this duration, e.g. 90d, overrides config.retention.not_refreshed_for")
+	_ = v.BindPFlag("config.retention.keep_versions", dbPruneCmd.Flags().Lookup("keep-versions"))
+	_ = v.BindPFlag("config.retention.expired_for", dbPruneCmd.Flags().Lookup("expired-for"))
+	_ = v.BindPFlag("config.retention.not_refreshed_for", dbPruneCmd.Flags().Lookup("not-refreshed-for"))
+
+	dbCmd.AddCommand(dbBackupCmd, dbRestoreCmd, dbExportCmd, dbImportCmd, dbPruneCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Back up, restore, export and import the stored CRLs and certificates",
+}
+
+var dbBackupCmd = &cobra.Command{
+	Use:     "backup <file>",
+	Short:   "Write a consistent snapshot of the local database, including its migrations, to a file",
+	Example: "certguard db backup certguard-backup.db",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+		if err := requireLocalStorage("backup"); err != nil {
+			return err
+		}
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		libSqlStorage, ok := storage.Repository.(*db.LibSqlStorage)
+		if !ok {
+			return errors.New("the storage does not support backups")
+		}
+
+		if err := libSqlStorage.Backup(context.Background(), args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("database backed up to %s\n", args[0])
+		return nil
+	},
+}
+
+var dbRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Replace the local database with a backup",
+	Long: "Replace the local database with a backup made with certguard db backup. The backup is verified before the database is replaced, " +
+		"migrations of a newer version of certguard are applied after the restore",
+	Example: "certguard db restore certguard-backup.db",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+		if err := requireLocalStorage("restore"); err != nil {
+			return err
+		}
+
+		cacheDir, err := cacheDir()
+		if err != nil {
+			return err
+		}
+
+		if err := db.Restore(context.Background(), args[0], cacheDir); err != nil {
+			return err
+		}
+
+		// opening the storage applies the migrations the backup is missing
+		_, closeStorage, err := openStorage()
+		if err != nil {
+			return errors.Join(errors.New("database restored, but the migrations could not be applied"), err)
+		}
+		closeStorage()
+
+		fmt.Printf("database restored from %s\n", args[0])
+		return nil
+	},
+}
+
+var dbExportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Export the stored CRLs and certificates to a portable archive",
+	Long: "Export the stored CRLs and the certificate inventory to a gzip compressed tarball with the DER encoded CRLs, " +
+		"a manifest.json with their metadata and the certificates in certificates.jsonl. The archive can be imported in every storage backend",
+	Example: "certguard db export certguard.tar.gz",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		commands, err := newCommands(storage)
+		if err != nil {
+			return err
+		}
+
+		switch msg := commands.ExportArchive(args[0])().(type) {
+		case messages.ErrorMsg:
+			return msg.Err
+		case messages.ArchiveExportedMsg:
+			fmt.Printf("exported %d CRLs and %d certificates to %s\n", msg.RevocationLists, msg.Certificates, msg.Path)
+			return nil
+		default:
+			return errors.New("unexpected result of exporting the archive")
+		}
+	},
+}
+
+var dbImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import the CRLs and certificates of an archive made with certguard db export",
+	Long: "Import the CRLs and certificates of an archive made with certguard db export. " +
+		"CRLs are skipped when the stored CRL with the same name is as recent as the archived CRL",
+	Example: "certguard db import certguard.tar.gz",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		commands, err := newCommands(storage)
+		if err != nil {
+			return err
+		}
+
+		switch msg := commands.ImportArchive(args[0])().(type) {
+		case messages.ErrorMsg:
+			return msg.Err
+		case messages.ArchiveImportedMsg:
+			for _, skipped := range msg.Skipped {
+				fmt.Printf("skipped %s\n", skipped)
+			}
+			fmt.Printf("imported %d CRLs with %d revoked certificates and %d certificates from %s\n", msg.RevocationLists, msg.RevokedCertificates, msg.Certificates, msg.Path)
+			return nil
+		default:
+			return errors.New("unexpected result of importing the archive")
+		}
+	},
+}
+
+var dbPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the stored CRLs selected by the retention policy",
+	Long: "Remove the stored CRLs selected by the retention policy in config.retention: the versions beyond keep_versions per issuer, " +
+		"CRLs that are past their next update for longer than expired_for and CRLs that have not been refreshed for not_refreshed_for. " +
+		"The retention policy is applied when CertGuard starts as well",
+	Example: "certguard db prune --dry-run\ncertguard db prune --expired-for 30d --not-refreshed-for 90d",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+		policy, err := retentionPolicy()
+		if err != nil {
+			return err
+		}
+		if !policy.Enabled() {
+			fmt.Println("no retention policy configured, set config.retention or use the --keep-versions, --expired-for and --not-refreshed-for flags")
+			return nil
+		}
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		commands, err := newCommands(storage)
+		if err != nil {
+			return err
+		}
+
+		var pruned messages.PrunedRevocationListsMsg
+		switch msg := commands.PruneRevocationLists(policy, pruneDryRun)().(type) {
+		case messages.ErrorMsg:
+			return msg.Err
+		case messages.PrunedRevocationListsMsg:
+			pruned = msg
+		default:
+			return errors.New("unexpected result of pruning the CRLs")
+		}
+
+		action := "removed"
+		if pruned.DryRun {
+			action = "would remove"
+		}
+		for _, revocationList := range pruned.Pruned {
+			fmt.Printf("%s CRL %s: %s\n", action, revocationList.RevocationList.Name, revocationList.Reason)
+		}
+		fmt.Printf("%s %d CRLs\n", action, len(pruned.Pruned))
+		return nil
+	},
+}
+
+// requireLocalStorage returns an error when the configured storage is not the local libsql database, which is the only storage that is backed up as a file
+func requireLocalStorage(action string) error {
+	storageType := v.Config().Storage.Type
+	if storageType != config.StorageLibSQL && storageType != "" {
+		return fmt.Errorf("%s is only supported for the %s storage, use certguard db export and import for the %s storage", action, config.StorageLibSQL, storageType)
+	}
+	return nil
+}
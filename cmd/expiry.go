@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/spf13/cobra"
+)
+
+// exit codes of certguard expiry, errors exit with 1
+const (
+	exitExpiring = 2
+	exitExpired  = 3
+)
+
+var (
+	expiryWithin string
+	expiryJSON   bool
+)
+
+func init() {
+	expiryCmd.Flags().StringVarP(&expiryWithin, "within", "w", "30d", "report certificates that expire within this duration, e.g. 7d, 2w or 12h")
+	expiryCmd.Flags().BoolVar(&expiryJSON, "json", false, "print the certificates as JSON")
+	rootCmd.AddCommand(expiryCmd)
+}
+
+type expiryResult struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial_number"`
+	Fingerprint  string    `json:"fingerprint"`
+	NotAfter     time.Time `json:"not_after"`
+	DaysLeft     int       `json:"days_left"`
+	Expired      bool      `json:"expired"`
+	Source       string    `json:"source"`
+}
+
+var expiryCmd = &cobra.Command{
+	Use:   "expiry",
+	Short: "List the certificates in the inventory that are expired or expire soon",
+	Long: "List the certificates in the inventory that are expired or expire within the given duration. " +
+		"The command exits with status 2 when certificates expire within the duration and with status 3 when expired certificates are found",
+	Example: "certguard expiry --within 30d\ncertguard expiry --json -w 2w",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+		within, err := crl.ParseDuration(expiryWithin)
+		if err != nil {
+			return err
+		}
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		commands, err := newCommands(storage)
+		if err != nil {
+			return err
+		}
+
+		var certificates []*crl.StoredCertificate
+		switch msg := commands.ExpiringCertificates(within)().(type) {
+		case messages.ErrorMsg:
+			return msg.Err
+		case messages.ListCertificatesResponseMsg:
+			certificates = msg.Certificates
+		default:
+			return errors.New("unexpected result of listing the certificates")
+		}
+
+		now := time.Now()
+		results := make([]expiryResult, 0, len(certificates))
+		expired := 0
+		for _, certificate := range certificates {
+			result := expiryResult{
+				Subject:      certificate.Subject,
+				Issuer:       certificate.Issuer,
+				SerialNumber: certificate.SerialNumber,
+				Fingerprint:  certificate.Fingerprint,
+				NotAfter:     certificate.NotAfter,
+				DaysLeft:     int(certificate.NotAfter.Sub(now).Hours() / 24),
+				Expired:      crl.BucketFor(certificate.NotAfter, now) == crl.Expired,
+				Source:       certificate.Source,
+			}
+			if result.Expired {
+				expired++
+			}
+			results = append(results, result)
+		}
+
+		if expiryJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(results); err != nil {
+				return err
+			}
+		} else if err := printExpiryResults(results, expiryWithin); err != nil {
+			return err
+		}
+
+		cmd.SilenceErrors = true
+		switch {
+		case expired > 0:
+			return &exitError{code: exitExpired, err: fmt.Errorf("%d certificate(s) expired", expired)}
+		case len(results) > 0:
+			return &exitError{code: exitExpiring, err: fmt.Errorf("%d certificate(s) expire within %s", len(results), expiryWithin)}
+		}
+		return nil
+	},
+}
+
+func printExpiryResults(results []expiryResult, within string) error {
+	if len(results) == 0 {
+		fmt.Printf("no certificates expire within %s\n", within)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SUBJECT\tISSUER\tNOT AFTER\tDAYS LEFT\tSOURCE")
+	for _, result := range results {
+		daysLeft := fmt.Sprint(result.DaysLeft)
+		if result.Expired {
+			daysLeft = "expired"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.Subject, result.Issuer, result.NotAfter.Format(time.DateOnly), daysLeft, result.Source)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pimg/certguard/pkg/domain/certificate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintJSON        bool
+	lintPassword    string
+	lintProfilePath string
+)
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintJSON, "json", false, "print the findings as JSON")
+	lintCmd.Flags().StringVarP(&lintProfilePath, "profile", "P", "", "path to a YAML lint profile, overrides config.lint.profile")
+	lintCmd.Flags().StringVar(&lintPassword, "password", "", "password of PKCS#12 bundles")
+	rootCmd.AddCommand(lintCmd)
+}
+
+type lintFileResult struct {
+	File         string                  `json:"file"`
+	Error        string                  `json:"error,omitempty"`
+	Certificates []lintCertificateResult `json:"certificates"`
+}
+
+type lintCertificateResult struct {
+	Subject      string                `json:"subject"`
+	SerialNumber string                `json:"serial_number"`
+	Leaf         bool                  `json:"leaf"`
+	Findings     []certificate.Finding `json:"findings"`
+}
+
+var lintCmd = &cobra.Command{
+	Use:     "lint <file>...",
+	Short:   "Lint certificates against the CA/Browser Forum Baseline Requirements or a custom profile",
+	Long:    "Lint certificates against the CA/Browser Forum Baseline Requirements or a custom profile, the command exits with a non-zero status when findings with severity error are found",
+	Example: "certguard lint --json github.com-chain.pem",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		if lintProfilePath != "" {
+			v.Config().Lint.Profile = lintProfilePath
+		}
+
+		profile, err := lintProfile()
+		if err != nil {
+			return err
+		}
+
+		results := make([]lintFileResult, 0, len(args))
+		errorCount := 0
+		for _, path := range args {
+			result := lintFile(profile, path)
+			for _, cert := range result.Certificates {
+				errorCount += certificate.CountSeverity(cert.Findings, certificate.SeverityError)
+			}
+			if result.Error != "" {
+				errorCount++
+			}
+			results = append(results, result)
+		}
+
+		if lintJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(results); err != nil {
+				return err
+			}
+		} else {
+			printLintResults(profile, results)
+		}
+
+		if errorCount > 0 {
+			return fmt.Errorf("lint found %d error(s)", errorCount)
+		}
+		return nil
+	},
+}
+
+func lintFile(profile *certificate.Profile, path string) lintFileResult {
+	result := lintFileResult{File: path, Certificates: make([]lintCertificateResult, 0)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	certificates, err := certificate.ParseCertificates(data, lintPassword)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	chain, err := certificate.OrderChain(certificates)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	leaf := chain.Leaf()
+	for _, cert := range chain.Certificates {
+		result.Certificates = append(result.Certificates, lintCertificateResult{
+			Subject:      cert.Subject.String(),
+			SerialNumber: cert.SerialNumber.String(),
+			Leaf:         cert == leaf,
+			Findings:     profile.Lint(cert, cert == leaf),
+		})
+	}
+
+	return result
+}
+
+func printLintResults(profile *certificate.Profile, results []lintFileResult) {
+	fmt.Printf("profile: %s\n", profile.Name)
+	for _, result := range results {
+		fmt.Printf("\n%s\n", result.File)
+		if result.Error != "" {
+			fmt.Printf("  [error] %s\n", result.Error)
+		}
+
+		for _, cert := range result.Certificates {
+			fmt.Printf("  %s\n", cert.Subject)
+			if len(cert.Findings) == 0 {
+				fmt.Println("    no findings")
+			}
+			for _, finding := range cert.Findings {
+				fmt.Printf("    [%s] %s: %s\n", finding.Severity, finding.Rule, finding.Message)
+			}
+		}
+	}
+}
+
+// lintProfile loads the configured lint profile, falling back to the bundled CA/Browser Forum profile
+func lintProfile() (*certificate.Profile, error) {
+	if v.Config().Lint.Profile == "" {
+		return certificate.DefaultProfile(), nil
+	}
+
+	profile, err := certificate.LoadProfile(v.Config().Lint.Profile)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not load lint profile"), err)
+	}
+	return profile, nil
+}
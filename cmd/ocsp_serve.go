@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pimg/certguard/config"
+	"github.com/pimg/certguard/pkg/domain/certificate"
+	domain_ocsp "github.com/pimg/certguard/pkg/domain/ocsp"
+	"github.com/spf13/cobra"
+)
+
+var ocspServeIssuer config.OCSPIssuer
+
+func init() {
+	ocspServeCmd.Flags().String("listen", "", "address the responder listens on, overrides config.ocsp.listen (default 127.0.0.1:8890)")
+	ocspServeCmd.Flags().StringVar(&ocspServeIssuer.Certificate, "issuer", "", "certificate of a CA to answer for, in addition to config.ocsp.issuers")
+	ocspServeCmd.Flags().StringVar(&ocspServeIssuer.ResponderCertificate, "responder-cert", "", "delegated OCSP responder certificate issued by the --issuer CA")
+	ocspServeCmd.Flags().StringVar(&ocspServeIssuer.ResponderKey, "responder-key", "", "private key of the responder certificate")
+	ocspServeCmd.Flags().StringVar(&ocspServeIssuer.CRL, "crl", "", "name of the stored CRL of the --issuer CA, the common name of the CA when it is not set")
+	_ = v.BindPFlag("config.ocsp.listen", ocspServeCmd.Flags().Lookup("listen"))
+	rootCmd.AddCommand(ocspServeCmd)
+}
+
+var ocspServeCmd = &cobra.Command{
+	Use:   "ocsp-serve",
+	Short: "Answer OCSP requests from the stored CRLs",
+	Long: "Run an RFC 6960 OCSP responder for CAs that only publish CRLs. Requests are answered from the revoked certificates of the stored CRL of the CA, " +
+		"a serial number that is not on the CRL is good. Responses are signed with a delegated responder certificate issued by the CA and echo the nonce of a request. " +
+		"Requests are accepted with POST and GET on any path, a CRL that is downloaded or imported while the responder runs is used for the next request. " +
+		"The responder answers tryLater while the CRL of a CA is not stored or is past its nextUpdate",
+	Example: "certguard ocsp-serve --issuer ca.pem --responder-cert ocsp.pem --responder-key ocsp-key.pem\n" +
+		"certguard ocsp-serve --listen 0.0.0.0:8890",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+
+		configured := v.Config().OCSP.Issuers
+		if ocspServeIssuer.Certificate != "" || ocspServeIssuer.ResponderCertificate != "" || ocspServeIssuer.ResponderKey != "" {
+			configured = append(configured, ocspServeIssuer)
+		}
+		if len(configured) == 0 {
+			return errors.New("no issuers to answer for, set --issuer, --responder-cert and --responder-key or configure config.ocsp.issuers")
+		}
+
+		issuers := make([]domain_ocsp.ResponderIssuer, 0, len(configured))
+		for _, issuer := range configured {
+			loaded, err := loadResponderIssuer(issuer)
+			if err != nil {
+				return err
+			}
+			issuers = append(issuers, loaded)
+		}
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		responder, err := domain_ocsp.NewResponder(storage.Repository, issuers)
+		if err != nil {
+			return err
+		}
+
+		listen := v.Config().OCSP.Listen
+		for _, issuer := range issuers {
+			fmt.Printf("answering OCSP requests for %s signed by %s\n", issuer.Certificate.Subject.CommonName, issuer.ResponderCertificate.Subject.CommonName)
+		}
+		fmt.Printf("listening on http://%s, press ctrl+c to stop\n", listen)
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           responder,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+// loadResponderIssuer reads the CA certificate, the responder certificate and the responder key of an issuer
+func loadResponderIssuer(issuer config.OCSPIssuer) (domain_ocsp.ResponderIssuer, error) {
+	if issuer.Certificate == "" || issuer.ResponderCertificate == "" || issuer.ResponderKey == "" {
+		return domain_ocsp.ResponderIssuer{}, errors.New("an issuer needs a certificate, a responder certificate and a responder key")
+	}
+
+	caCertificate, err := readCertificate(issuer.Certificate)
+	if err != nil {
+		return domain_ocsp.ResponderIssuer{}, err
+	}
+	responderCertificate, err := readCertificate(issuer.ResponderCertificate)
+	if err != nil {
+		return domain_ocsp.ResponderIssuer{}, err
+	}
+
+	data, err := os.ReadFile(issuer.ResponderKey)
+	if err != nil {
+		return domain_ocsp.ResponderIssuer{}, errors.Join(fmt.Errorf("could not read the responder key %s", issuer.ResponderKey), err)
+	}
+	key, err := domain_ocsp.ParsePrivateKey(data)
+	if err != nil {
+		return domain_ocsp.ResponderIssuer{}, errors.Join(fmt.Errorf("could not parse the responder key %s", issuer.ResponderKey), err)
+	}
+
+	return domain_ocsp.ResponderIssuer{
+		Certificate:          caCertificate,
+		ResponderCertificate: responderCertificate,
+		ResponderKey:         key,
+		CRL:                  issuer.CRL,
+	}, nil
+}
+
+// readCertificate reads the first certificate of a file
+func readCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("could not read the certificate %s", path), err)
+	}
+	certificates, err := certificate.ParseCertificates(data, "")
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("could not parse the certificate %s", path), err)
+	}
+	return certificates[0], nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -11,17 +12,21 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pimg/certguard/config"
 	"github.com/pimg/certguard/internal/adapter/db"
+	"github.com/pimg/certguard/internal/adapter/memory"
+	"github.com/pimg/certguard/internal/adapter/postgres"
 	"github.com/pimg/certguard/internal/ports/models"
 	cmds "github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
 	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/certificate"
 	"github.com/pimg/certguard/pkg/domain/crl"
 	"github.com/spf13/cobra"
 )
 
-var v *config.ViperConfig
+// v is initialized before the init functions, subcommands bind their flags to it
+var v = config.NewViperConfig()
 
 func init() {
-	v = config.NewViperConfig()
 	rootCmd.PersistentFlags().BoolVarP(&v.Config().Log.Debug, "debug", "d", false, "enables debug logging to a file located in ~/.local/certguard/debug.log")
 	rootCmd.PersistentFlags().StringVarP(&v.Config().Theme.Name, "theme", "t", "dracula", "set the theme of the application. Allowed values: 'dracula', 'gruvbox'")
 
@@ -42,6 +47,11 @@ var rootCmd = &cobra.Command{
 }
 
 func runInteractiveCertGuard(cmd *cobra.Command, args []string) error {
+	return runTUI(nil)
+}
+
+// runTUI starts the interactive application, the startup function can provide a command that is run when the application starts
+func runTUI(startup func(commands *cmds.Commands) tea.Cmd) error {
 	debug := v.Config().Log.Debug
 	theme := v.Config().Theme.Name
 
@@ -64,16 +74,55 @@ func runInteractiveCertGuard(cmd *cobra.Command, args []string) error {
 		defer f.Close()
 	}
 
-	cacheDir, err := cacheDir()
+	storage, closeStorage, err := openStorage()
 	if err != nil {
 		return err
 	}
+	defer closeStorage()
 
-	importDir, err := importDir()
+	styles.NewStyles(theme)
+
+	commands, err := newCommands(storage)
+	if err != nil {
+		return err
+	}
+
+	err = pruneOnStartup(commands)
 	if err != nil {
 		return err
 	}
 
+	baseModel := models.NewBaseModel(commands)
+	if startup != nil {
+		baseModel = baseModel.WithStartupCommand(startup(commands))
+	}
+
+	if _, err := tea.NewProgram(baseModel).Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// repository is a storage backend that has to be initialized before use and closed on exit
+type repository interface {
+	crl.Repository
+	InitDB(ctx context.Context) error
+	CloseDB() error
+}
+
+// openStorage opens the configured storage backend, the returned function closes the storage
+func openStorage() (*crl.Storage, func(), error) {
+	cacheDir, err := cacheDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	importDir, err := importDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the cache directory holds downloaded CRLs and exports for every storage backend
 	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
 		err := os.MkdirAll(cacheDir, 0o775)
 		if err != nil {
@@ -82,43 +131,162 @@ func runInteractiveCertGuard(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	dbConnection, err := db.NewDBConnection(cacheDir)
+	repository, err := openRepository(cacheDir)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	libsqlStorage := db.NewLibSqlStorage(dbConnection)
-	defer func() {
-		err := libsqlStorage.CloseDB()
+	closeStorage := func() {
+		err := repository.CloseDB()
 		if err != nil {
 			log.Printf("could not close database: %v", err)
 		}
-	}()
+	}
 
-	err = libsqlStorage.InitDB(context.Background())
+	err = repository.InitDB(context.Background())
 	if err != nil {
-		return err
+		closeStorage()
+		return nil, nil, err
 	}
 
-	storage, err := crl.NewStorage(libsqlStorage, cacheDir, importDir)
+	storage, err := crl.NewStorage(repository, cacheDir, importDir)
 	if err != nil {
-		return err
+		closeStorage()
+		return nil, nil, err
 	}
 
 	log.Printf("cache initialized at: %s", cacheDir)
+	return storage, closeStorage, nil
+}
 
-	styles.NewStyles(theme)
+// openRepository connects to the storage backend configured with config.storage.type
+func openRepository(cacheDir string) (repository, error) {
+	storageConfig := v.Config().Storage
+	switch storageConfig.Type {
+	case config.StorageLibSQL, "":
+		dbConnection, err := db.NewDBConnection(cacheDir)
+		if err != nil {
+			return nil, err
+		}
+		return db.NewLibSqlStorage(dbConnection), nil
+	case config.StorageLibSQLRemote:
+		if storageConfig.URL == "" {
+			return nil, errors.New("config.storage.url is required for the libsql-remote storage")
+		}
+		dbConnection, err := db.NewRemoteDBConnection(storageConfig.URL, storageConfig.AuthToken)
+		if err != nil {
+			return nil, err
+		}
+		return db.NewLibSqlStorage(dbConnection), nil
+	case config.StoragePostgres:
+		if storageConfig.URL == "" {
+			return nil, errors.New("config.storage.url is required for the postgres storage")
+		}
+		dbConnection, err := postgres.NewDBConnection(storageConfig.URL)
+		if err != nil {
+			return nil, err
+		}
+		return postgres.NewPostgresStorage(dbConnection), nil
+	case config.StorageMemory:
+		return memory.NewMemoryStorage(), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s, supported types are: %s, %s, %s and %s",
+			storageConfig.Type, config.StorageLibSQL, config.StorageLibSQLRemote, config.StoragePostgres, config.StorageMemory)
+	}
+}
+
+// disableLogging discards the debug logging of the commands for non interactive subcommands, unless debug logging is enabled
+func disableLogging() {
+	if !v.Config().Log.Debug {
+		log.SetOutput(io.Discard)
+	}
+}
 
+// newCommands creates the commands with the configured CT, CSR and lint settings
+func newCommands(storage *crl.Storage) (*cmds.Commands, error) {
 	commands := cmds.NewCommands(storage)
+	commands.SetCertificateTransparency(loadCTLogList(), ctPolicy())
+	commands.SetCSRPolicy(csrPolicy())
 
-	if _, err := tea.NewProgram(models.NewBaseModel(commands)).Run(); err != nil {
-		return err
+	profile, err := lintProfile()
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	commands.SetLintProfile(profile)
+
+	return commands, nil
+}
+
+// exitError is returned by subcommands that report their result with a specific exit status
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+
+	var exit *exitError
+	if errors.As(err, &exit) {
+		fmt.Fprintln(os.Stderr, exit.err)
+		os.Exit(exit.code)
+	}
+	return err
+}
+
+func csrPolicy() certificate.CSRPolicy {
+	return certificate.CSRPolicy{
+		MinRSAKeySize:              v.Config().CSR.MinRSAKeySize,
+		MinECKeySize:               v.Config().CSR.MinECKeySize,
+		AllowedKeyAlgorithms:       v.Config().CSR.AllowedKeyAlgorithms,
+		AllowedSignatureAlgorithms: v.Config().CSR.AllowedSignatureAlgorithms,
+		RequireSANs:                v.Config().CSR.RequireSANs,
+	}
+}
+
+// retentionPolicy parses the configured retention of stored CRLs, rules that are not set are disabled
+func retentionPolicy() (crl.RetentionPolicy, error) {
+	retention := v.Config().Retention
+	policy := crl.RetentionPolicy{KeepVersions: retention.KeepVersions}
+	if policy.KeepVersions < 0 {
+		return policy, fmt.Errorf("invalid config.retention.keep_versions: %d", policy.KeepVersions)
+	}
+
+	var err error
+	if retention.ExpiredFor != "" {
+		policy.ExpiredFor, err = crl.ParseDuration(retention.ExpiredFor)
+		if err != nil {
+			return policy, errors.Join(errors.New("invalid config.retention.expired_for"), err)
+		}
+	}
+	if retention.NotRefreshedFor != "" {
+		policy.NotRefreshedFor, err = crl.ParseDuration(retention.NotRefreshedFor)
+		if err != nil {
+			return policy, errors.Join(errors.New("invalid config.retention.not_refreshed_for"), err)
+		}
+	}
+
+	return policy, nil
+}
+
+// pruneOnStartup applies the retention policy before the TUI starts, the pruned CRLs are reported in the debug log
+func pruneOnStartup(commands *cmds.Commands) error {
+	policy, err := retentionPolicy()
+	if err != nil {
+		return err
+	}
+	if !policy.Enabled() {
+		return nil
+	}
+
+	if msg, ok := commands.PruneRevocationLists(policy, false)().(messages.ErrorMsg); ok {
+		log.Printf("could not prune the stored CRLs: %v", msg.Err)
+	}
+	return nil
 }
 
 func cacheDir() (string, error) {
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	cmds "github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/pkg/scan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scanServerName string
+	scanStartTLS   string
+	scanCAFile     string
+	scanPEM        bool
+)
+
+func init() {
+	scanCmd.Flags().StringVar(&scanServerName, "sni", "", "server name sent as SNI and used for hostname verification, defaults to the host")
+	scanCmd.Flags().StringVar(&scanStartTLS, "starttls", "", "negotiate TLS with STARTTLS, allowed values: 'smtp', 'imap', 'ldap'")
+	scanCmd.Flags().StringVar(&scanCAFile, "ca", "", "PEM file with CA certificates to verify the chain, defaults to the system roots")
+	scanCmd.Flags().BoolVar(&scanPEM, "pem", false, "print the presented chain as PEM instead of opening it in the TUI")
+	rootCmd.AddCommand(scanCmd)
+}
+
+var scanCmd = &cobra.Command{
+	Use:     "scan <host:port>",
+	Short:   "Fetch and inspect the certificate chain presented by a TLS endpoint",
+	Example: "certguard scan github.com:443\ncertguard scan --starttls smtp smtp.gmail.com:587\ncertguard scan --pem --sni internal.example.com 10.0.0.1:8443",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		opts, err := cmds.ScanOptions(args[0], scanServerName, scanStartTLS, scanCAFile)
+		if err != nil {
+			return err
+		}
+
+		if !scanPEM {
+			return runTUI(func(commands *cmds.Commands) tea.Cmd {
+				return commands.ScanEndpoint(opts)
+			})
+		}
+
+		result, err := scan.Scan(context.Background(), opts)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "connected to %s using %s, %s\n", result.Address, result.TLSVersion, result.CipherSuite)
+		if result.VerifyError != nil {
+			fmt.Fprintf(os.Stderr, "presented chain is not trusted: %v\n", result.VerifyError)
+		}
+		if len(result.StapledOCSP) > 0 {
+			fmt.Fprintf(os.Stderr, "stapled OCSP response: %d bytes\n", len(result.StapledOCSP))
+		}
+
+		for _, cert := range result.Certificates {
+			if err := pem.Encode(os.Stdout, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
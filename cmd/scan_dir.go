@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/scan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scanDirOCSP     bool
+	scanDirPassword string
+	scanDirSort     string
+	scanDirFormat   string
+	scanDirOutput   string
+)
+
+func init() {
+	scanDirCmd.Flags().BoolVar(&scanDirOCSP, "ocsp", false, "query the OCSP responder of every certificate in addition to the stored CRLs")
+	scanDirCmd.Flags().StringVar(&scanDirPassword, "password", "", "password of PKCS#12 bundles")
+	scanDirCmd.Flags().StringVar(&scanDirSort, "sort", "path", "sort the report on: 'path', 'expiry', 'revocation', 'issuer', 'subject'")
+	scanDirCmd.Flags().StringVarP(&scanDirFormat, "format", "f", "table", "output format: 'table', 'csv', 'json'")
+	scanDirCmd.Flags().StringVarP(&scanDirOutput, "output", "o", "", "write the report to a file instead of stdout")
+	rootCmd.AddCommand(scanDirCmd)
+}
+
+var scanDirCmd = &cobra.Command{
+	Use:     "scan-dir <path>",
+	Short:   "Discover certificates in a directory and check them against the stored CRLs",
+	Long:    "Recursively discover certificates in PEM, DER, PKCS#7 and PKCS#12 files, each certificate is checked against the stored CRLs and optionally its OCSP responder",
+	Example: "certguard scan-dir /etc/ssl/private\ncertguard scan-dir --ocsp --sort expiry --format csv -o report.csv ./secrets",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+		sortField, err := scan.ParseSortField(scanDirSort)
+		if err != nil {
+			return err
+		}
+
+		if scanDirFormat != "table" && scanDirFormat != "csv" && scanDirFormat != "json" {
+			return fmt.Errorf("unsupported format: %s, allowed values: table, csv, json", scanDirFormat)
+		}
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		commands, err := newCommands(storage)
+		if err != nil {
+			return err
+		}
+
+		var report *scan.Report
+		switch msg := commands.ScanDirectory(args[0], scanDirPassword, scanDirOCSP)().(type) {
+		case messages.ErrorMsg:
+			return msg.Err
+		case messages.DirectoryReportMsg:
+			report = msg.Report
+		default:
+			return errors.New("unexpected result of the directory scan")
+		}
+		report.Sort(sortField)
+
+		var out io.Writer = os.Stdout
+		if scanDirOutput != "" {
+			f, err := os.Create(scanDirOutput)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		switch scanDirFormat {
+		case "csv":
+			return report.WriteCSV(out)
+		case "json":
+			return report.WriteJSON(out)
+		default:
+			return printScanDirReport(out, report)
+		}
+	},
+}
+
+func printScanDirReport(out io.Writer, report *scan.Report) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tSUBJECT\tISSUER\tEXPIRES\tDAYS\tREVOCATION\tERROR")
+	for _, entry := range report.Entries {
+		if entry.NotAfter.IsZero() {
+			fmt.Fprintf(w, "%s\t\t\t\t\t\t%s\n", entry.Path, entry.Error)
+			continue
+		}
+
+		revocation := string(entry.Revocation)
+		if entry.RevocationSource != "" {
+			revocation += " (" + entry.RevocationSource + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", entry.Path, entry.Subject, entry.Issuer, entry.NotAfter.Format(time.DateOnly), entry.DaysLeft(report.Created), revocation, entry.Error)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/spf13/cobra"
+)
+
+var searchJSON bool
+
+func init() {
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "print the revoked certificates as JSON")
+	rootCmd.AddCommand(searchCmd)
+}
+
+type searchResult struct {
+	SerialNumber     string    `json:"serial_number"`
+	SerialNumberHex  string    `json:"serial_number_hex"`
+	RevokedBy        string    `json:"revoked_by"`
+	RevocationListID int64     `json:"revocation_list_id"`
+	RevocationReason string    `json:"revocation_reason"`
+	RevocationDate   time.Time `json:"revocation_date"`
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <serial number>",
+	Short: "Search all stored CRLs for a serial number",
+	Long: "Search all stored CRLs for a serial number in decimal, hex (0x1000) or colon separated hex (10:00) notation. " +
+		"Serial numbers of only digits are searched in decimal and in hex notation",
+	Example: "certguard search 4096\ncertguard search 0x1000\ncertguard search --json 7d:6b:65:3f:71:52:b6:4a",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+
+		storage, closeStorage, err := openStorage()
+		if err != nil {
+			return err
+		}
+		defer closeStorage()
+
+		commands, err := newCommands(storage)
+		if err != nil {
+			return err
+		}
+
+		// serial numbers copied from a browser are often separated by spaces
+		query := strings.Join(args, " ")
+		var search messages.SerialNumberSearchMsg
+		switch msg := commands.SearchSerialNumber(query)().(type) {
+		case messages.ErrorMsg:
+			return msg.Err
+		case messages.SerialNumberSearchMsg:
+			search = msg
+		default:
+			return errors.New("unexpected result of searching the serial number")
+		}
+
+		results := make([]searchResult, len(search.RevokedCertificates))
+		for i, revokedCertificate := range search.RevokedCertificates {
+			results[i] = searchResult{
+				SerialNumber:     revokedCertificate.SerialNumber,
+				SerialNumberHex:  crl.FormatSerialNumberHex(revokedCertificate.SerialNumber),
+				RevokedBy:        revokedCertificate.RevokedBy,
+				RevocationListID: revokedCertificate.RevocationListID,
+				RevocationReason: revokedCertificate.RevocationReason.String(),
+				RevocationDate:   revokedCertificate.RevocationDate,
+			}
+		}
+
+		if searchJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(results)
+		}
+		return printSearchResults(query, results)
+	},
+}
+
+func printSearchResults(query string, results []searchResult) error {
+	if len(results) == 0 {
+		fmt.Printf("serial number %s is not revoked on any stored CRL\n", query)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERIAL NUMBER\tSERIAL NUMBER (HEX)\tREVOKED BY\tREASON\tREVOKED")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.SerialNumber, result.SerialNumberHex, result.RevokedBy, result.RevocationReason, result.RevocationDate.Format(time.DateOnly))
+	}
+	return w.Flush()
+}
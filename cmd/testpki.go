@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pimg/certguard/pkg/testpki"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testPKIName        string
+	testPKILeaves      []string
+	testPKIRevoke      []string
+	testPKIDeltaRevoke []string
+	testPKIURL         string
+	testPKIServe       string
+)
+
+func init() {
+	testPKICmd.Flags().StringVar(&testPKIName, "name", testpki.DefaultName, "prefix of the common names of the root and intermediate CA")
+	testPKICmd.Flags().StringArrayVar(&testPKILeaves, "leaf", []string{"leaf.certguard.test"}, "common name of a leaf certificate, repeat for more leaf certificates")
+	testPKICmd.Flags().StringArrayVar(&testPKIRevoke, "revoke", nil, "revoke serial[:reason] on the base CRL, e.g. 4096:keyCompromise or 0x1001")
+	testPKICmd.Flags().StringArrayVar(&testPKIDeltaRevoke, "delta-revoke", nil, "revoke serial[:reason] on the delta CRL")
+	testPKICmd.Flags().StringVar(&testPKIURL, "url", "", "base URL of the CRLs and the OCSP responder in the certificates, http://<serve address> when --serve is set")
+	testPKICmd.Flags().StringVar(&testPKIServe, "serve", "", "serve the CRLs and a local OCSP responder on the address, e.g. 127.0.0.1:8889")
+	rootCmd.AddCommand(testPKICmd)
+}
+
+var testPKICmd = &cobra.Command{
+	Use:   "testpki <dir>",
+	Short: "Generate a test PKI with CRLs and an optional local OCSP responder",
+	Long: "Generate a root and intermediate CA, leaf certificates and a base and delta CRL of the intermediate CA with chosen revoked serial numbers and reasons. " +
+		"The leaf certificates get the serial numbers 4096, 4097 and up in the order of --leaf. " +
+		"The certificates, private keys and CRLs are written to a new directory that CertGuard imports, the delta CRL is not imported because CertGuard only stores base CRLs. " +
+		"--serve serves the CRLs and answers OCSP requests until certguard is stopped",
+	Example: "certguard testpki ./pki --leaf www.example.test --leaf mail.example.test --revoke 4096:keyCompromise --delta-revoke 4097:superseded\n" +
+		"certguard testpki ./pki --revoke 4096 --serve 127.0.0.1:8889",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		disableLogging()
+		opts := testpki.Options{
+			Name:   testPKIName,
+			Leaves: testPKILeaves,
+			URL:    testPKIURL,
+		}
+		if opts.URL == "" && testPKIServe != "" {
+			opts.URL = "http://" + testPKIServe
+		}
+
+		for _, revocations := range []struct {
+			values []string
+			delta  bool
+		}{{testPKIRevoke, false}, {testPKIDeltaRevoke, true}} {
+			for _, value := range revocations.values {
+				revocation, err := testpki.ParseRevocation(value, revocations.delta)
+				if err != nil {
+					return err
+				}
+				opts.Revocations = append(opts.Revocations, revocation)
+			}
+		}
+
+		pki, err := testpki.Generate(opts)
+		if err != nil {
+			return err
+		}
+
+		written, err := pki.Write(args[0])
+		if err != nil {
+			return err
+		}
+		for _, path := range written {
+			fmt.Println(path)
+		}
+
+		if testPKIServe == "" {
+			return nil
+		}
+
+		fmt.Printf("serving the CRLs and OCSP responder of %s on http://%s, press ctrl+c to stop\n", pki.Intermediate.Certificate.Subject.CommonName, testPKIServe)
+		server := &http.Server{
+			Addr:              testPKIServe,
+			Handler:           pki.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
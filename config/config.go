@@ -5,6 +5,12 @@ type Config struct {
 	ImportDirectory string
 	Log             Log
 	Theme           Theme
+	CT              CT
+	CSR             CSR
+	Lint            Lint
+	Storage         Storage
+	Retention       Retention
+	OCSP            OCSP
 }
 
 type Log struct {
@@ -16,6 +22,61 @@ type Theme struct {
 	Name string
 }
 
+type CT struct {
+	LogList      string
+	MinSCTs      int
+	MinOperators int
+}
+
+type CSR struct {
+	MinRSAKeySize              int
+	MinECKeySize               int
+	AllowedKeyAlgorithms       []string
+	AllowedSignatureAlgorithms []string
+	RequireSANs                bool
+}
+
+type Lint struct {
+	Profile string
+}
+
+// storage backends of the certificate revocation lists and the certificate inventory
+const (
+	StorageLibSQL       = "libsql"
+	StorageLibSQLRemote = "libsql-remote"
+	StoragePostgres     = "postgres"
+	StorageMemory       = "memory"
+)
+
+type Storage struct {
+	Type string
+	// URL is the address of the libsql-remote and postgres backends
+	URL       string
+	AuthToken string
+}
+
+// Retention configures when stored CRLs are pruned, durations are given in days (30d), weeks (2w) or as Go durations
+type Retention struct {
+	KeepVersions    int
+	ExpiredFor      string
+	NotRefreshedFor string
+}
+
+// OCSP configures the OCSP responder of certguard ocsp-serve
+type OCSP struct {
+	Listen  string
+	Issuers []OCSPIssuer
+}
+
+// OCSPIssuer is a CA the OCSP responder answers for, the certificates and the key are PEM or DER files
+type OCSPIssuer struct {
+	Certificate          string `mapstructure:"certificate"`
+	ResponderCertificate string `mapstructure:"responder_certificate"`
+	ResponderKey         string `mapstructure:"responder_key"`
+	// CRL is the name of the stored CRL of the CA, the common name of the CA when it is empty
+	CRL string `mapstructure:"crl"`
+}
+
 func New() *Config {
 	return &Config{}
 }
@@ -59,9 +59,38 @@ func (v *ViperConfig) InitializeConfig() error {
 	// like --favorite-color which we fix in the setFlags function
 	v.AutomaticEnv()
 
+	v.SetDefault("config.ct.policy.min_operators", 2)
+	v.SetDefault("config.csr.policy.min_rsa_key_size", 2048)
+	v.SetDefault("config.csr.policy.min_ec_key_size", 256)
+	v.SetDefault("config.csr.policy.allowed_key_algorithms", []string{"RSA", "ECDSA", "Ed25519"})
+	v.SetDefault("config.csr.policy.require_sans", true)
+	v.SetDefault("config.storage.type", StorageLibSQL)
+	v.SetDefault("config.ocsp.listen", "127.0.0.1:8890")
+
+	// nested keys are not bound by AutomaticEnv, credentials should not have to be stored in the config file
+	_ = v.BindEnv("config.storage.url", envPrefix+"_STORAGE_URL")
+	_ = v.BindEnv("config.storage.auth_token", envPrefix+"_STORAGE_AUTH_TOKEN")
+
 	v.cfg.Theme.Name = v.GetString("config.theme.name")
 	v.cfg.Log.Debug = v.GetBool("config.log.debug")
 	v.cfg.Log.Directory = v.GetString("config.log.file")
+	v.cfg.CT.LogList = v.GetString("config.ct.log_list")
+	v.cfg.CT.MinSCTs = v.GetInt("config.ct.policy.min_scts")
+	v.cfg.CT.MinOperators = v.GetInt("config.ct.policy.min_operators")
+	v.cfg.CSR.MinRSAKeySize = v.GetInt("config.csr.policy.min_rsa_key_size")
+	v.cfg.CSR.MinECKeySize = v.GetInt("config.csr.policy.min_ec_key_size")
+	v.cfg.CSR.AllowedKeyAlgorithms = v.GetStringSlice("config.csr.policy.allowed_key_algorithms")
+	v.cfg.CSR.AllowedSignatureAlgorithms = v.GetStringSlice("config.csr.policy.allowed_signature_algorithms")
+	v.cfg.CSR.RequireSANs = v.GetBool("config.csr.policy.require_sans")
+	v.cfg.Lint.Profile = v.GetString("config.lint.profile")
+	v.cfg.Storage.Type = v.GetString("config.storage.type")
+	v.cfg.Storage.URL = v.GetString("config.storage.url")
+	v.cfg.Storage.AuthToken = v.GetString("config.storage.auth_token")
+	v.cfg.Retention.KeepVersions = v.GetInt("config.retention.keep_versions")
+	v.cfg.Retention.ExpiredFor = v.GetString("config.retention.expired_for")
+	v.cfg.Retention.NotRefreshedFor = v.GetString("config.retention.not_refreshed_for")
+	v.cfg.OCSP.Listen = v.GetString("config.ocsp.listen")
 
-	return nil
+	// the issuers are a list of maps, GetString cannot read them
+	return v.UnmarshalKey("config.ocsp.issuers", &v.cfg.OCSP.Issuers)
 }
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pimg/certguard/internal/adapter/db/queries"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// SaveAuditEntry appends a revocation check to the audit log
+func (s *LibSqlStorage) SaveAuditEntry(ctx context.Context, entry *crl.AuditEntry) (int64, error) {
+	params := queries.CreateAuditEntryParams{
+		CheckedAt:    entry.CheckedAt.UTC(),
+		Method:       string(entry.Method),
+		Serialnumber: entry.SerialNumber,
+		Subject:      entry.Subject,
+		Issuer:       entry.Issuer,
+		Source:       entry.Source,
+		Result:       string(entry.Result),
+		Detail:       entry.Detail,
+		Origin:       entry.Origin,
+	}
+	if !entry.SourceThisUpdate.IsZero() {
+		params.SourceThisUpdate = sql.NullTime{Time: entry.SourceThisUpdate.UTC(), Valid: true}
+	}
+
+	id, err := s.Queries.CreateAuditEntry(ctx, params)
+	if err != nil {
+		return 0, errors.Join(errors.New("could not save audit entry"), err)
+	}
+
+	return id, nil
+}
+
+// ListAuditEntries lists the audit entries that match the filter, the most recent first
+func (s *LibSqlStorage) ListAuditEntries(ctx context.Context, filter crl.AuditFilter) ([]*crl.AuditEntry, error) {
+	// a negative limit returns all rows in SQLite
+	params := queries.ListAuditEntriesParams{Limit: -1}
+	if filter.Limit > 0 {
+		params.Limit = int64(filter.Limit)
+	}
+	if !filter.From.IsZero() {
+		params.CheckedFrom = filter.From.UTC()
+	}
+	if !filter.To.IsZero() {
+		params.CheckedTo = filter.To.UTC()
+	}
+	if filter.Method != "" {
+		params.Method = string(filter.Method)
+	}
+	if filter.SerialNumber != "" {
+		params.Serialnumber = filter.SerialNumber
+	}
+
+	dbEntries, err := s.Queries.ListAuditEntries(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*crl.AuditEntry, len(dbEntries))
+	for i, dbEntry := range dbEntries {
+		checkedAt, ok := dbEntry.CheckedAt.(time.Time)
+		if !ok {
+			return nil, errors.New("invalid checked_at")
+		}
+
+		entries[i] = &crl.AuditEntry{
+			ID:           dbEntry.ID,
+			CheckedAt:    checkedAt,
+			Method:       crl.AuditMethod(dbEntry.Method),
+			SerialNumber: dbEntry.Serialnumber,
+			Subject:      dbEntry.Subject,
+			Issuer:       dbEntry.Issuer,
+			Source:       dbEntry.Source,
+			Result:       crl.AuditResult(dbEntry.Result),
+			Detail:       dbEntry.Detail,
+			Origin:       dbEntry.Origin,
+		}
+		// source_this_update is NULL when no CRL of the issuer is stored
+		if sourceThisUpdate, ok := dbEntry.SourceThisUpdate.(time.Time); ok {
+			entries[i].SourceThisUpdate = sourceThisUpdate
+		}
+	}
+
+	return entries, nil
+}
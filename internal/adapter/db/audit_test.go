@@ -0,0 +1,22 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogIsAppendOnly(t *testing.T) {
+	storage := newTestStorage(t)
+	id, err := storage.SaveAuditEntry(context.Background(), &crl.AuditEntry{CheckedAt: time.Now(), Method: crl.AuditMethodCRL, SerialNumber: "10", Result: crl.AuditResultNotRevoked})
+	assert.NoError(t, err)
+
+	_, err = storage.DB.Exec("UPDATE audit_log SET result = 'revoked' WHERE id = ?", id)
+	assert.ErrorContains(t, err, "append-only")
+
+	_, err = storage.DB.Exec("DELETE FROM audit_log WHERE id = ?", id)
+	assert.ErrorContains(t, err, "append-only")
+}
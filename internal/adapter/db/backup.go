@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/rubenv/sql-migrate"
+)
+
+// dbFile is the name of the database file in the cache directory
+const dbFile = "certguard.db"
+
+// Backup writes a consistent snapshot of the database, including the applied migrations, to a new SQLite file at path
+func (s *LibSqlStorage) Backup(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("backup file %s already exists", path)
+	}
+
+	// VACUUM INTO reads the database in a single transaction, writes that are in progress are not included
+	_, err := s.DB.ExecContext(ctx, "VACUUM INTO ?", path)
+	if err != nil {
+		return errors.Join(errors.New("could not back up the database"), err)
+	}
+	log.Printf("database backed up to %s", path)
+
+	return nil
+}
+
+// Restore replaces the database in dbLocation with the backup at backupPath, the database must not be opened while it is restored.
+// The backup is verified before the database is replaced, migrations that are newer than the backup are applied when the database is opened.
+func Restore(ctx context.Context, backupPath, dbLocation string) error {
+	if _, err := os.Stat(backupPath); err != nil {
+		return errors.Join(errors.New("could not read the backup"), err)
+	}
+
+	err := verifyBackup(ctx, backupPath)
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(dbLocation, dbFile)
+	tmp := target + ".tmp"
+	err = copyFile(backupPath, tmp)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return errors.Join(errors.New("could not copy the backup"), err)
+	}
+
+	// the write ahead log belongs to the database that is replaced and would corrupt the restored database
+	for _, suffix := range []string{"-wal", "-shm"} {
+		err := os.Remove(target + suffix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			_ = os.Remove(tmp)
+			return errors.Join(errors.New("could not remove the write ahead log of the database"), err)
+		}
+	}
+
+	err = os.Rename(tmp, target)
+	if err != nil {
+		return errors.Join(errors.New("could not replace the database"), err)
+	}
+	log.Printf("database restored from %s", backupPath)
+
+	return nil
+}
+
+// verifyBackup checks the integrity of the backup and that its migrations are known to this version of certguard
+// nolint: errcheck // checking err in defer results in panic
+func verifyBackup(ctx context.Context, backupPath string) error {
+	backup, err := sql.Open("libsql", "file:"+backupPath+"?mode=ro")
+	if err != nil {
+		return errors.Join(errors.New("could not open the backup"), err)
+	}
+	defer backup.Close()
+
+	var integrity string
+	err = backup.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&integrity)
+	if err != nil {
+		return errors.Join(errors.New("backup is not a certguard database"), err)
+	}
+	if integrity != "ok" {
+		return fmt.Errorf("backup is corrupt: %s", integrity)
+	}
+
+	migrations, err := (&migrate.EmbedFileSystemMigrationSource{FileSystem: dbMigrations, Root: "schema"}).FindMigrations()
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Id] = true
+	}
+
+	rows, err := backup.QueryContext(ctx, "SELECT id FROM gorp_migrations")
+	if err != nil {
+		return errors.Join(errors.New("backup is not a certguard database"), err)
+	}
+	defer rows.Close()
+
+	applied := 0
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		if !known[id] {
+			return fmt.Errorf("backup contains migration %s that is unknown to this version of certguard", id)
+		}
+		applied++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if applied == 0 {
+		return errors.New("backup is not a certguard database: no migrations are applied")
+	}
+
+	return nil
+}
+
+// nolint: errcheck // checking err in defer results in panic
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	storage := newTestStorage(t)
+	id, err := storage.Save(ctx, &crl.CertificateRevocationList{Name: "Backup CA", Signature: []byte{1}, ThisUpdate: time.Now(), NextUpdate: time.Now()})
+	assert.NoError(t, err)
+	_, err = storage.SaveRevokedCertificates(ctx, id, generateRevocationListEntries(10))
+	assert.NoError(t, err)
+
+	backup := filepath.Join(t.TempDir(), "backup.db")
+	assert.NoError(t, storage.Backup(ctx, backup))
+	assert.ErrorContains(t, storage.Backup(ctx, backup), "already exists")
+
+	dbLocation := t.TempDir()
+	assert.NoError(t, Restore(ctx, backup, dbLocation))
+
+	connection, err := NewDBConnection(dbLocation)
+	assert.NoError(t, err)
+	restored := NewLibSqlStorage(connection)
+	t.Cleanup(func() { _ = restored.CloseDB() })
+	assert.NoError(t, restored.InitDB(ctx))
+
+	revocationList, err := restored.Find(ctx, "Backup CA")
+	assert.NoError(t, err)
+	count, err := restored.CountRevokedCertificates(ctx, revocationList.ID, crl.RevokedCertificateFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, count)
+}
+
+func TestRestoreInvalidBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	notADatabase := filepath.Join(dir, "not-a-database.db")
+	assert.NoError(t, os.WriteFile(notADatabase, []byte("not a database"), 0o644))
+	assert.Error(t, Restore(ctx, notADatabase, t.TempDir()))
+
+	assert.Error(t, Restore(ctx, filepath.Join(dir, "missing.db"), t.TempDir()))
+
+	storage := newTestStorage(t)
+	_, err := storage.DB.Exec("INSERT INTO gorp_migrations (id, applied_at) VALUES ('999_future.sql', CURRENT_TIMESTAMP)")
+	assert.NoError(t, err)
+	future := filepath.Join(dir, "future.db")
+	assert.NoError(t, storage.Backup(ctx, future))
+
+	dbLocation := t.TempDir()
+	assert.ErrorContains(t, Restore(ctx, future, dbLocation), "unknown to this version")
+	assert.NoFileExists(t, filepath.Join(dbLocation, dbFile))
+}
@@ -5,15 +5,19 @@ import (
 	"crypto/x509"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pimg/certguard/internal/adapter/db/queries"
 	"github.com/pimg/certguard/pkg/domain/crl"
 )
 
-// insert record
+// Save inserts a version of a certificate revocation list, a known version with the same name and this update is replaced except for its ID and URL.
+// The revoked certificates of a replaced version are removed, they are saved again with SaveRevokedCertificates.
+// nolint: errcheck // checking err in defer results in panic
 func (s *LibSqlStorage) Save(ctx context.Context, crl *crl.CertificateRevocationList) (int64, error) {
 	params := queries.CreateCertificateRevocationListParams{
 		Name:       crl.Name,
@@ -23,7 +27,15 @@ func (s *LibSqlStorage) Save(ctx context.Context, crl *crl.CertificateRevocation
 			Time:  crl.NextUpdate,
 			Valid: true,
 		},
-		Raw: crl.Raw,
+		Raw:      crl.Raw,
+		Unsigned: crl.Unsigned,
+	}
+
+	if !crl.LastRefreshed.IsZero() {
+		params.LastRefreshed = sql.NullTime{
+			Time:  crl.LastRefreshed,
+			Valid: true,
+		}
 	}
 
 	if crl.URL != nil {
@@ -33,19 +45,38 @@ func (s *LibSqlStorage) Save(ctx context.Context, crl *crl.CertificateRevocation
 		}
 	}
 
-	id, err := s.Queries.CreateCertificateRevocationList(ctx, params)
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := s.Queries.WithTx(tx)
+	id, err := qtx.CreateCertificateRevocationList(ctx, params)
 	if err != nil {
 		log.Println("could not create certificate revocation list")
 		return 0, err
 	}
 
+	err = qtx.DeleteRevokedCertificatesByRevocationList(ctx, id)
+	if err != nil {
+		return 0, errors.Join(errors.New("could not remove the revoked certificates of the replaced certificate revocation list"), err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
 	log.Printf("crl with id: %d stored\n", id)
 	return id, nil
 }
 
-// Find a Certificate Revocation List
+// Find the latest version of a Certificate Revocation List
 func (s *LibSqlStorage) Find(ctx context.Context, name string) (*crl.CertificateRevocationList, error) {
 	dbCrl, err := s.Queries.GetCertificateRevocationList(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", crl.ErrNotFound, name)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -55,6 +86,7 @@ func (s *LibSqlStorage) Find(ctx context.Context, name string) (*crl.Certificate
 		Name:      dbCrl.Name,
 		Signature: dbCrl.Signature,
 		Raw:       dbCrl.Raw,
+		Unsigned:  dbCrl.Unsigned,
 	}
 
 	nextUpdate, ok := dbCrl.NextUpdate.(time.Time)
@@ -69,6 +101,11 @@ func (s *LibSqlStorage) Find(ctx context.Context, name string) (*crl.Certificate
 	}
 	revocationList.ThisUpdate = thisUpdate
 
+	// last_refreshed is NULL for CRLs saved without a refresh time
+	if lastRefreshed, ok := dbCrl.LastRefreshed.(time.Time); ok {
+		revocationList.LastRefreshed = lastRefreshed
+	}
+
 	return revocationList, nil
 }
 
@@ -105,47 +142,83 @@ func (s *LibSqlStorage) List(ctx context.Context) ([]*crl.CertificateRevocationL
 			NextUpdate: nextUpdate,
 			Raw:        dbCrl.Raw,
 			URL:        url,
+			Unsigned:   dbCrl.Unsigned,
+		}
+
+		if lastRefreshed, ok := dbCrl.LastRefreshed.(time.Time); ok {
+			cRLs[i].LastRefreshed = lastRefreshed
 		}
 	}
 
 	return cRLs, nil
 }
 
-// save revoked certificates
+// revokedCertificatesBatchSize is the number of rows in a multi-row insert, 5 parameters per row stay well below the SQLite limit of 32766 parameters
+const revokedCertificatesBatchSize = 500
+
+// save revoked certificates with multi-row inserts in a single transaction, the statement for a full batch is prepared once
 // nolint: errcheck // checking err in defer results in panic
 func (s *LibSqlStorage) SaveRevokedCertificates(ctx context.Context, revocationListId int64, revokedCertificates []x509.RevocationListEntry) (int, error) {
-	tx, err := s.DB.Begin()
+	if len(revokedCertificates) == 0 {
+		return 0, nil
+	}
+
+	reasons := make([]string, len(revokedCertificates))
+	for i, revokedCertificateEntry := range revokedCertificates {
+		reason, ok := crl.RevocationReasons[revokedCertificateEntry.ReasonCode]
+		if !ok {
+			return 0, errors.New("invalid ReasonCode on revoked certificate")
+		}
+		reasons[i] = reason.String()
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 	defer tx.Rollback()
 
-	qtx := s.Queries.WithTx(tx)
+	preparedRows := min(len(revokedCertificates), revokedCertificatesBatchSize)
+	batchStatement, err := tx.PrepareContext(ctx, insertRevokedCertificatesQuery(preparedRows))
+	if err != nil {
+		return 0, errors.Join(errors.New("could not prepare insert of revoked certificates"), err)
+	}
+	defer batchStatement.Close()
 
-	rowsAffected := 0
-	for _, revokedCertificateEntry := range revokedCertificates {
-		reason, ok := crl.RevocationReasons[revokedCertificateEntry.ReasonCode]
-		if !ok {
-			return 0, errors.New("invalid ReasonCode on revoked certificate")
+	args := make([]any, 0, 5*revokedCertificatesBatchSize)
+	for start := 0; start < len(revokedCertificates); start += revokedCertificatesBatchSize {
+		end := min(start+revokedCertificatesBatchSize, len(revokedCertificates))
+
+		args = args[:0]
+		for i, revokedCertificateEntry := range revokedCertificates[start:end] {
+			args = append(args, revokedCertificateEntry.SerialNumber.String(), revokedCertificateEntry.SerialNumber.Text(16), revokedCertificateEntry.RevocationTime, reasons[start+i], revocationListId)
 		}
 
-		err := qtx.CreateRevokedCertificates(ctx, queries.CreateRevokedCertificatesParams{
-			Serialnumber:   revokedCertificateEntry.SerialNumber.String(),
-			RevocationDate: revokedCertificateEntry.RevocationTime,
-			Reason:         reason.String(),
-			RevocationList: revocationListId,
-		})
+		if end-start == preparedRows {
+			_, err = batchStatement.ExecContext(ctx, args...)
+		} else {
+			_, err = tx.ExecContext(ctx, insertRevokedCertificatesQuery(end-start), args...)
+		}
 		if err != nil {
-			return 0, errors.Join(errors.New("could not save certificate revocation list entry"), err)
+			return 0, errors.Join(errors.New("could not save certificate revocation list entries"), err)
 		}
-		rowsAffected++
 	}
 
-	if rowsAffected != len(revokedCertificates) {
-		return 0, errors.New("not all revoked certificates could be saved")
-	}
+	return len(revokedCertificates), tx.Commit()
+}
 
-	return rowsAffected, tx.Commit()
+// insertRevokedCertificatesQuery creates a multi-row insert, sqlc cannot generate queries with a variable number of rows
+func insertRevokedCertificatesQuery(rows int) string {
+	var query strings.Builder
+	query.WriteString("INSERT INTO revoked_certificate(serialnumber, serialnumber_hex, revocation_date, reason, revocation_list) VALUES ")
+	for i := range rows {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString("(?,?,?,?,?)")
+	}
+	query.WriteString(" ON CONFLICT DO NOTHING")
+	return query.String()
 }
 
 // delete certificate revocation list
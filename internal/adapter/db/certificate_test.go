@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStorage(t *testing.T) *LibSqlStorage {
+	t.Helper()
+	connection, err := NewDBConnection(t.TempDir())
+	assert.NoError(t, err)
+
+	storage := NewLibSqlStorage(connection)
+	t.Cleanup(func() { _ = storage.CloseDB() })
+	assert.NoError(t, storage.InitDB(context.Background()))
+	return storage
+}
+
+func readTestChain(t *testing.T) []*x509.Certificate {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "testing", "pki", "github.com-chain.pem"))
+	assert.NoError(t, err)
+
+	var certificates []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		assert.NoError(t, err)
+		certificates = append(certificates, cert)
+	}
+	return certificates
+}
+
+func TestCertificateInventory(t *testing.T) {
+	ctx := context.Background()
+	storage := newTestStorage(t)
+	chain := readTestChain(t)
+
+	firstSeen := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, cert := range chain {
+		_, err := storage.SaveCertificate(ctx, crl.NewStoredCertificate(cert, "input", firstSeen))
+		assert.NoError(t, err)
+	}
+
+	lastSeen := firstSeen.Add(24 * time.Hour)
+	id, err := storage.SaveCertificate(ctx, crl.NewStoredCertificate(chain[0], "import github.com.pem", lastSeen))
+	assert.NoError(t, err)
+
+	stored, err := storage.FindCertificate(ctx, crl.Fingerprint(chain[0]))
+	assert.NoError(t, err)
+	assert.Equal(t, id, stored.ID)
+	assert.Equal(t, chain[0].Raw, stored.Raw)
+	assert.Equal(t, chain[0].SerialNumber.String(), stored.SerialNumber)
+	assert.Equal(t, "import github.com.pem", stored.Source)
+	assert.Equal(t, firstSeen, stored.FirstSeen)
+	assert.Equal(t, lastSeen, stored.LastSeen)
+	assert.Equal(t, chain[0].NotAfter.UTC(), stored.NotAfter)
+	assert.False(t, stored.Revoked())
+
+	certificates, err := storage.ListCertificates(ctx, crl.CertificateFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 3)
+	assert.Equal(t, "CN=github.com", certificates[0].Subject)
+
+	certificates, err = storage.ListCertificates(ctx, crl.CertificateFilter{Issuer: "usertrust"})
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 2)
+
+	certificates, err = storage.ListCertificates(ctx, crl.CertificateFilter{ExpiresBefore: time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)})
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 2)
+
+	certificates, err = storage.ListCertificates(ctx, crl.CertificateFilter{Revocation: crl.RevocationFilterRevoked})
+	assert.NoError(t, err)
+	assert.Empty(t, certificates)
+
+	certificates, err = storage.ListCertificates(ctx, crl.CertificateFilter{Revocation: crl.RevocationFilterNotRevoked})
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 3)
+
+	assert.NoError(t, storage.DeleteCertificate(ctx, id))
+	stored, err = storage.FindCertificate(ctx, crl.Fingerprint(chain[0]))
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestCertificateInventoryRevoked(t *testing.T) {
+	ctx := context.Background()
+	storage := newTestStorage(t)
+	chain := readTestChain(t)
+
+	_, err := storage.SaveCertificate(ctx, crl.NewStoredCertificate(chain[0], "input", time.Now()))
+	assert.NoError(t, err)
+
+	crlID, err := storage.Save(ctx, &crl.CertificateRevocationList{Name: "Sectigo", Signature: []byte{1}, ThisUpdate: time.Now(), NextUpdate: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+	_, err = storage.SaveRevokedCertificates(ctx, crlID, []x509.RevocationListEntry{{SerialNumber: chain[0].SerialNumber, RevocationTime: time.Now()}})
+	assert.NoError(t, err)
+
+	certificates, err := storage.ListCertificates(ctx, crl.CertificateFilter{Revocation: crl.RevocationFilterRevoked})
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 1)
+	assert.Equal(t, "Sectigo", certificates[0].RevokedBy)
+	assert.True(t, certificates[0].Revoked())
+}
@@ -6,8 +6,10 @@ import (
 	"embed"
 	"errors"
 	"log"
+	"net/url"
 
 	"github.com/pimg/certguard/internal/adapter/db/queries"
+	"github.com/pimg/certguard/pkg/domain/crl"
 	"github.com/rubenv/sql-migrate"
 	_ "github.com/tursodatabase/go-libsql"
 )
@@ -29,7 +31,30 @@ func NewLibSqlStorage(db *sql.DB) *LibSqlStorage {
 
 func NewDBConnection(dbLocation string) (*sql.DB, error) {
 	log.Println("Connecting to DB...")
-	db, err := sql.Open("libsql", "file:"+dbLocation+"/certguard.db?_journal_mode=WAL&busy_timeout=5000_foreign_keys=on")
+	db, err := sql.Open("libsql", "file:"+dbLocation+"/"+dbFile+"?_journal_mode=WAL&busy_timeout=5000_foreign_keys=on")
+	if err != nil {
+		log.Printf("Error connecting to DB: %v", err)
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// NewRemoteDBConnection connects to a remote libsql server, such as sqld or Turso, the URL uses the libsql, http or https scheme
+func NewRemoteDBConnection(dbURL, authToken string) (*sql.DB, error) {
+	log.Println("Connecting to remote DB...")
+	dsn, err := url.Parse(dbURL)
+	if err != nil {
+		return nil, errors.Join(errors.New("invalid libsql URL"), err)
+	}
+
+	if authToken != "" {
+		query := dsn.Query()
+		query.Set("authToken", authToken)
+		dsn.RawQuery = query.Encode()
+	}
+
+	db, err := sql.Open("libsql", dsn.String())
 	if err != nil {
 		log.Printf("Error connecting to DB: %v", err)
 		return nil, err
@@ -50,7 +75,43 @@ func (s *LibSqlStorage) InitDB(ctx context.Context) error {
 	}
 	log.Printf("database initialized, applied %d migrations!", n)
 
-	return nil
+	return s.backfillSerialNumberHex(ctx)
+}
+
+// backfillSerialNumberHex sets the hex serial number of revoked certificates that were stored before the column existed
+// nolint: errcheck // checking err in defer results in panic
+func (s *LibSqlStorage) backfillSerialNumberHex(ctx context.Context) error {
+	for {
+		dbRevCerts, err := s.Queries.ListRevokedCertificatesWithoutHex(ctx, 10_000)
+		if err != nil {
+			return errors.Join(errors.New("could not read revoked certificates without hex serial number"), err)
+		}
+		if len(dbRevCerts) == 0 {
+			return nil
+		}
+
+		tx, err := s.DB.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		qtx := s.Queries.WithTx(tx)
+		for _, revokedCertificate := range dbRevCerts {
+			err := qtx.UpdateRevokedCertificateHex(ctx, queries.UpdateRevokedCertificateHexParams{
+				SerialnumberHex: sql.NullString{String: crl.SerialNumberHex(revokedCertificate.Serialnumber), Valid: true},
+				ID:              revokedCertificate.ID,
+			})
+			if err != nil {
+				return errors.Join(errors.New("could not set hex serial number"), err)
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		log.Printf("set hex serial number of %d revoked certificates", len(dbRevCerts))
+	}
 }
 
 func (s *LibSqlStorage) CloseDB() error {
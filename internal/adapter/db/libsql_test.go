@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/pimg/certguard/internal/adapter/storagetest"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLibSqlStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) crl.Repository {
+		return newTestStorage(t)
+	})
+}
+
+// TestRemoteLibSqlStorage runs against a sqld server, e.g. CERTGUARD_TEST_LIBSQL_URL=http://127.0.0.1:8080
+func TestRemoteLibSqlStorage(t *testing.T) {
+	dbURL := os.Getenv("CERTGUARD_TEST_LIBSQL_URL")
+	if dbURL == "" {
+		t.Skip("CERTGUARD_TEST_LIBSQL_URL not set")
+	}
+
+	storagetest.Run(t, func(t *testing.T) crl.Repository {
+		connection, err := NewRemoteDBConnection(dbURL, os.Getenv("CERTGUARD_TEST_LIBSQL_AUTH_TOKEN"))
+		assert.NoError(t, err)
+
+		storage := NewLibSqlStorage(connection)
+		t.Cleanup(func() { _ = storage.CloseDB() })
+		// the audit log is append-only, it is recreated by migrating it again
+		_, _ = connection.Exec("DROP TABLE IF EXISTS audit_log")
+		_, _ = connection.Exec("DELETE FROM gorp_migrations WHERE id = '005_audit_log.sql'")
+		assert.NoError(t, storage.InitDB(context.Background()))
+		// the remote database is shared between tests
+		for _, table := range []string{"revoked_certificate", "certificate_revocation_list", "certificate"} {
+			_, err := connection.Exec("DELETE FROM " + table)
+			assert.NoError(t, err)
+		}
+		return storage
+	})
+}
@@ -0,0 +1,24 @@
+-- name: CreateAuditEntry :one
+INSERT INTO audit_log(
+    checked_at,
+    method,
+    serialnumber,
+    subject,
+    issuer,
+    source,
+    source_this_update,
+    result,
+    detail,
+    origin
+) VALUES (?,?,?,?,?,?,?,?,?,?)
+RETURNING id;
+
+-- name: ListAuditEntries :many
+SELECT id, DATETIME(checked_at) as checked_at, method, serialnumber, subject, issuer, source, DATETIME(source_this_update) as source_this_update, result, detail, origin
+FROM audit_log
+WHERE (sqlc.narg(checked_from) IS NULL OR DATETIME(checked_at) >= DATETIME(sqlc.narg(checked_from)))
+  AND (sqlc.narg(checked_to) IS NULL OR DATETIME(checked_at) < DATETIME(sqlc.narg(checked_to)))
+  AND (sqlc.narg(method) IS NULL OR method = sqlc.narg(method))
+  AND (sqlc.narg(serialnumber) IS NULL OR serialnumber = sqlc.narg(serialnumber))
+ORDER BY checked_at DESC, id DESC
+LIMIT sqlc.arg(limit);
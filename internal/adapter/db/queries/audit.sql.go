@@ -0,0 +1,133 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: audit.sql
+
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createAuditEntry = `-- name: CreateAuditEntry :one
+INSERT INTO audit_log(
+    checked_at,
+    method,
+    serialnumber,
+    subject,
+    issuer,
+    source,
+    source_this_update,
+    result,
+    detail,
+    origin
+) VALUES (?,?,?,?,?,?,?,?,?,?)
+RETURNING id
+`
+
+type CreateAuditEntryParams struct {
+	CheckedAt        time.Time
+	Method           string
+	Serialnumber     string
+	Subject          string
+	Issuer           string
+	Source           string
+	SourceThisUpdate sql.NullTime
+	Result           string
+	Detail           string
+	Origin           string
+}
+
+func (q *Queries) CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createAuditEntry,
+		arg.CheckedAt,
+		arg.Method,
+		arg.Serialnumber,
+		arg.Subject,
+		arg.Issuer,
+		arg.Source,
+		arg.SourceThisUpdate,
+		arg.Result,
+		arg.Detail,
+		arg.Origin,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listAuditEntries = `-- name: ListAuditEntries :many
+SELECT id, DATETIME(checked_at) as checked_at, method, serialnumber, subject, issuer, source, DATETIME(source_this_update) as source_this_update, result, detail, origin
+FROM audit_log
+WHERE (?1 IS NULL OR DATETIME(checked_at) >= DATETIME(?1))
+  AND (?2 IS NULL OR DATETIME(checked_at) < DATETIME(?2))
+  AND (?3 IS NULL OR method = ?3)
+  AND (?4 IS NULL OR serialnumber = ?4)
+ORDER BY checked_at DESC, id DESC
+LIMIT ?5
+`
+
+type ListAuditEntriesParams struct {
+	CheckedFrom  interface{}
+	CheckedTo    interface{}
+	Method       interface{}
+	Serialnumber interface{}
+	Limit        int64
+}
+
+type ListAuditEntriesRow struct {
+	ID               int64
+	CheckedAt        interface{}
+	Method           string
+	Serialnumber     string
+	Subject          string
+	Issuer           string
+	Source           string
+	SourceThisUpdate interface{}
+	Result           string
+	Detail           string
+	Origin           string
+}
+
+func (q *Queries) ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]ListAuditEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditEntries,
+		arg.CheckedFrom,
+		arg.CheckedTo,
+		arg.Method,
+		arg.Serialnumber,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAuditEntriesRow
+	for rows.Next() {
+		var i ListAuditEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CheckedAt,
+			&i.Method,
+			&i.Serialnumber,
+			&i.Subject,
+			&i.Issuer,
+			&i.Source,
+			&i.SourceThisUpdate,
+			&i.Result,
+			&i.Detail,
+			&i.Origin,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
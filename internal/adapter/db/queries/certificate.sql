@@ -0,0 +1,46 @@
+-- name: CreateCertificate :one
+INSERT INTO certificate(
+    fingerprint,
+    subject,
+    issuer,
+    serialnumber,
+    not_before,
+    not_after,
+    raw,
+    source,
+    first_seen,
+    last_seen
+) VALUES (?,?,?,?,?,?,?,?,?,?)
+  ON CONFLICT DO UPDATE SET
+    source = excluded.source,
+    last_seen = excluded.last_seen
+RETURNING id;
+
+-- name: GetCertificate :one
+SELECT cert.id, cert.fingerprint, cert.subject, cert.issuer, cert.serialnumber, DATETIME(cert.not_before) as not_before, DATETIME(cert.not_after) as not_after,
+       cert.raw, cert.source, DATETIME(cert.first_seen) as first_seen, DATETIME(cert.last_seen) as last_seen, crl.name AS revoked_by
+FROM certificate AS cert
+-- a serial number on more than one CRL is shown with the first CRL that revoked it
+LEFT JOIN revoked_certificate AS revoked ON revoked.id = (
+    SELECT earliest.id FROM revoked_certificate AS earliest WHERE earliest.serialnumber = cert.serialnumber ORDER BY earliest.revocation_list LIMIT 1
+)
+LEFT JOIN certificate_revocation_list AS crl ON crl.id = revoked.revocation_list
+WHERE cert.fingerprint = ?;
+
+-- name: ListCertificates :many
+SELECT cert.id, cert.fingerprint, cert.subject, cert.issuer, cert.serialnumber, DATETIME(cert.not_before) as not_before, DATETIME(cert.not_after) as not_after,
+       cert.raw, cert.source, DATETIME(cert.first_seen) as first_seen, DATETIME(cert.last_seen) as last_seen, crl.name AS revoked_by
+FROM certificate AS cert
+-- a serial number on more than one CRL is shown with the first CRL that revoked it
+LEFT JOIN revoked_certificate AS revoked ON revoked.id = (
+    SELECT earliest.id FROM revoked_certificate AS earliest WHERE earliest.serialnumber = cert.serialnumber ORDER BY earliest.revocation_list LIMIT 1
+)
+LEFT JOIN certificate_revocation_list AS crl ON crl.id = revoked.revocation_list
+WHERE (sqlc.narg(issuer) IS NULL OR cert.issuer LIKE '%' || sqlc.narg(issuer) || '%')
+  AND (sqlc.narg(expires_before) IS NULL OR DATETIME(cert.not_after) <= DATETIME(sqlc.narg(expires_before)))
+  AND (sqlc.narg(revoked) IS NULL OR (crl.name IS NOT NULL) = sqlc.narg(revoked))
+ORDER BY cert.not_after, cert.id;
+
+-- name: DeleteCertificate :exec
+DELETE FROM certificate
+WHERE id = ?;
@@ -5,27 +5,27 @@ INSERT INTO certificate_revocation_list(
     this_update,
     next_update,
     url,
-    raw
-) VALUES (?,?,?,?,?,?)
+    raw,
+    last_refreshed,
+    unsigned
+) VALUES (?,?,?,?,?,?,?,?)
   ON CONFLICT DO UPDATE SET
+    signature = excluded.signature,
     this_update = excluded.this_update,
-    next_update = excluded.next_update
+    next_update = excluded.next_update,
+    raw = excluded.raw,
+    last_refreshed = excluded.last_refreshed,
+    unsigned = excluded.unsigned
 RETURNING id;
 
--- name: UpdateCertificateRevocationList :one
-UPDATE certificate_revocation_list
-set this_update = ?,
-    next_update = ?,
-    raw = ?
-WHERE name = ?
-RETURNING *;
-
 -- name: GetCertificateRevocationList :one
-SELECT id, name, signature, DATETIME(this_update) as this_update, DATETIME(next_update) as next_update, url, raw FROM certificate_revocation_list
-WHERE name = ?;
+SELECT id, name, signature, DATETIME(this_update) as this_update, DATETIME(next_update) as next_update, url, raw, DATETIME(last_refreshed) as last_refreshed, unsigned FROM certificate_revocation_list
+WHERE name = ?
+ORDER BY DATETIME(this_update) DESC, id DESC
+LIMIT 1;
 
 -- name: ListCertificateRevocationLists :many
-SELECT id, name, signature, DATETIME(this_update) as this_update, DATETIME(next_update) as next_update, url, raw  FROM certificate_revocation_list
+SELECT id, name, signature, DATETIME(this_update) as this_update, DATETIME(next_update) as next_update, url, raw, DATETIME(last_refreshed) as last_refreshed, unsigned FROM certificate_revocation_list
 ORDER BY id;
 
 -- name: DeleteCertificateRevocationList :exec
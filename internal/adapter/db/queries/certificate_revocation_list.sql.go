@@ -18,21 +18,29 @@ INSERT INTO certificate_revocation_list(
     this_update,
     next_update,
     url,
-    raw
-) VALUES (?,?,?,?,?,?)
+    raw,
+    last_refreshed,
+    unsigned
+) VALUES (?,?,?,?,?,?,?,?)
   ON CONFLICT DO UPDATE SET
+    signature = excluded.signature,
     this_update = excluded.this_update,
-    next_update = excluded.next_update
+    next_update = excluded.next_update,
+    raw = excluded.raw,
+    last_refreshed = excluded.last_refreshed,
+    unsigned = excluded.unsigned
 RETURNING id
 `
 
 type CreateCertificateRevocationListParams struct {
-	Name       string
-	Signature  []byte
-	ThisUpdate time.Time
-	NextUpdate sql.NullTime
-	Url        sql.NullString
-	Raw        []byte
+	Name          string
+	Signature     []byte
+	ThisUpdate    time.Time
+	NextUpdate    sql.NullTime
+	Url           sql.NullString
+	Raw           []byte
+	LastRefreshed sql.NullTime
+	Unsigned      bool
 }
 
 func (q *Queries) CreateCertificateRevocationList(ctx context.Context, arg CreateCertificateRevocationListParams) (int64, error) {
@@ -43,6 +51,8 @@ func (q *Queries) CreateCertificateRevocationList(ctx context.Context, arg Creat
 		arg.NextUpdate,
 		arg.Url,
 		arg.Raw,
+		arg.LastRefreshed,
+		arg.Unsigned,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -60,18 +70,22 @@ func (q *Queries) DeleteCertificateRevocationList(ctx context.Context, id int64)
 }
 
 const getCertificateRevocationList = `-- name: GetCertificateRevocationList :one
-SELECT id, name, signature, DATETIME(this_update) as this_update, DATETIME(next_update) as next_update, url, raw FROM certificate_revocation_list
+SELECT id, name, signature, DATETIME(this_update) as this_update, DATETIME(next_update) as next_update, url, raw, DATETIME(last_refreshed) as last_refreshed, unsigned FROM certificate_revocation_list
 WHERE name = ?
+ORDER BY DATETIME(this_update) DESC, id DESC
+LIMIT 1
 `
 
 type GetCertificateRevocationListRow struct {
-	ID         int64
-	Name       string
-	Signature  []byte
-	ThisUpdate interface{}
-	NextUpdate interface{}
-	Url        sql.NullString
-	Raw        []byte
+	ID            int64
+	Name          string
+	Signature     []byte
+	ThisUpdate    interface{}
+	NextUpdate    interface{}
+	Url           sql.NullString
+	Raw           []byte
+	LastRefreshed interface{}
+	Unsigned      bool
 }
 
 func (q *Queries) GetCertificateRevocationList(ctx context.Context, name string) (GetCertificateRevocationListRow, error) {
@@ -85,23 +99,27 @@ func (q *Queries) GetCertificateRevocationList(ctx context.Context, name string)
 		&i.NextUpdate,
 		&i.Url,
 		&i.Raw,
+		&i.LastRefreshed,
+		&i.Unsigned,
 	)
 	return i, err
 }
 
 const listCertificateRevocationLists = `-- name: ListCertificateRevocationLists :many
-SELECT id, name, signature, DATETIME(this_update) as this_update, DATETIME(next_update) as next_update, url, raw  FROM certificate_revocation_list
+SELECT id, name, signature, DATETIME(this_update) as this_update, DATETIME(next_update) as next_update, url, raw, DATETIME(last_refreshed) as last_refreshed, unsigned FROM certificate_revocation_list
 ORDER BY id
 `
 
 type ListCertificateRevocationListsRow struct {
-	ID         int64
-	Name       string
-	Signature  []byte
-	ThisUpdate interface{}
-	NextUpdate interface{}
-	Url        sql.NullString
-	Raw        []byte
+	ID            int64
+	Name          string
+	Signature     []byte
+	ThisUpdate    interface{}
+	NextUpdate    interface{}
+	Url           sql.NullString
+	Raw           []byte
+	LastRefreshed interface{}
+	Unsigned      bool
 }
 
 func (q *Queries) ListCertificateRevocationLists(ctx context.Context) ([]ListCertificateRevocationListsRow, error) {
@@ -121,6 +139,8 @@ func (q *Queries) ListCertificateRevocationLists(ctx context.Context) ([]ListCer
 			&i.NextUpdate,
 			&i.Url,
 			&i.Raw,
+			&i.LastRefreshed,
+			&i.Unsigned,
 		); err != nil {
 			return nil, err
 		}
@@ -134,39 +154,3 @@ func (q *Queries) ListCertificateRevocationLists(ctx context.Context) ([]ListCer
 	}
 	return items, nil
 }
-
-const updateCertificateRevocationList = `-- name: UpdateCertificateRevocationList :one
-UPDATE certificate_revocation_list
-set this_update = ?,
-    next_update = ?,
-    raw = ?
-WHERE name = ?
-RETURNING id, name, signature, this_update, next_update, url, raw
-`
-
-type UpdateCertificateRevocationListParams struct {
-	ThisUpdate time.Time
-	NextUpdate sql.NullTime
-	Raw        []byte
-	Name       string
-}
-
-func (q *Queries) UpdateCertificateRevocationList(ctx context.Context, arg UpdateCertificateRevocationListParams) (CertificateRevocationList, error) {
-	row := q.db.QueryRowContext(ctx, updateCertificateRevocationList,
-		arg.ThisUpdate,
-		arg.NextUpdate,
-		arg.Raw,
-		arg.Name,
-	)
-	var i CertificateRevocationList
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Signature,
-		&i.ThisUpdate,
-		&i.NextUpdate,
-		&i.Url,
-		&i.Raw,
-	)
-	return i, err
-}
@@ -1,14 +1,19 @@
 -- name: CreateRevokedCertificates :exec
 INSERT INTO revoked_certificate(
     serialnumber,
+    serialnumber_hex,
     revocation_date,
     reason,
     revocation_list
 ) VALUES (
-          ?,?,?,?
+          ?,?,?,?,?
 )
 ON CONFLICT DO NOTHING;
 
+-- name: DeleteRevokedCertificatesByRevocationList :exec
+DELETE FROM revoked_certificate
+WHERE revocation_list = ?;
+
 -- name: GetRevokedCertificatesByRevocationList :many
 SELECT id, serialnumber, DATETIME(revocation_date) as revocation_date, reason, revocation_list
 FROM revoked_certificate
@@ -16,7 +21,50 @@ WHERE revocation_list = ?
 ORDER BY revocation_date;
 
 -- name: GetRevokedCertificate :one
-SELECT cert.id, cert.serialnumber, cert.reason, DATETIME(cert.revocation_date) as revocation_date, crl.name AS revoked_by
+SELECT cert.id, cert.serialnumber, cert.reason, DATETIME(cert.revocation_date) as revocation_date, cert.revocation_list, crl.name AS revoked_by
+FROM revoked_certificate as cert
+JOIN certificate_revocation_list AS crl ON crl.id = cert.revocation_list
+WHERE serialnumber = ?
+ORDER BY cert.revocation_list
+LIMIT 1;
+
+
+-- name: ListRevokedCertificates :many
+SELECT id, serialnumber, DATETIME(revocation_date) as revocation_date, reason, revocation_list
+FROM revoked_certificate
+WHERE revocation_list = sqlc.arg(revocation_list)
+  AND (sqlc.narg(serialnumber_prefix) IS NULL OR serialnumber LIKE sqlc.narg(serialnumber_prefix) || '%')
+  AND (sqlc.narg(serialnumber_hex_prefix) IS NULL OR serialnumber_hex LIKE sqlc.narg(serialnumber_hex_prefix) || '%')
+  AND (sqlc.narg(reason) IS NULL OR reason = sqlc.narg(reason))
+  AND (sqlc.narg(revoked_after) IS NULL OR DATETIME(revocation_date) >= DATETIME(sqlc.narg(revoked_after)))
+  AND (sqlc.narg(revoked_before) IS NULL OR DATETIME(revocation_date) < DATETIME(sqlc.narg(revoked_before)))
+ORDER BY revocation_date, id
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountRevokedCertificates :one
+SELECT COUNT(*)
+FROM revoked_certificate
+WHERE revocation_list = sqlc.arg(revocation_list)
+  AND (sqlc.narg(serialnumber_prefix) IS NULL OR serialnumber LIKE sqlc.narg(serialnumber_prefix) || '%')
+  AND (sqlc.narg(serialnumber_hex_prefix) IS NULL OR serialnumber_hex LIKE sqlc.narg(serialnumber_hex_prefix) || '%')
+  AND (sqlc.narg(reason) IS NULL OR reason = sqlc.narg(reason))
+  AND (sqlc.narg(revoked_after) IS NULL OR DATETIME(revocation_date) >= DATETIME(sqlc.narg(revoked_after)))
+  AND (sqlc.narg(revoked_before) IS NULL OR DATETIME(revocation_date) < DATETIME(sqlc.narg(revoked_before)));
+
+-- name: ListRevokedCertificatesWithoutHex :many
+SELECT id, serialnumber
+FROM revoked_certificate
+WHERE serialnumber_hex IS NULL
+LIMIT ?;
+
+-- name: UpdateRevokedCertificateHex :exec
+UPDATE revoked_certificate
+SET serialnumber_hex = ?
+WHERE id = ?;
+
+-- name: GetRevokedCertificatesBySerialNumber :many
+SELECT cert.id, cert.serialnumber, cert.reason, DATETIME(cert.revocation_date) as revocation_date, cert.revocation_list, crl.name AS revoked_by
 FROM revoked_certificate as cert
 JOIN certificate_revocation_list AS crl ON crl.id = cert.revocation_list
-WHERE serialnumber = ?;
+WHERE serialnumber = ?
+ORDER BY cert.revocation_list;
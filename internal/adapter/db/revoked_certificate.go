@@ -7,6 +7,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/pimg/certguard/internal/adapter/db/queries"
 	"github.com/pimg/certguard/pkg/domain/crl"
 )
 
@@ -38,6 +39,72 @@ func (s *LibSqlStorage) FindRevokedCertificates(ctx context.Context, revocationL
 	return revokedCertificates, nil
 }
 
+// ListRevokedCertificates lists a page of the revoked certificates in a CRL that match the filter, ordered by revocation date
+func (s *LibSqlStorage) ListRevokedCertificates(ctx context.Context, revocationListID int64, filter crl.RevokedCertificateFilter, offset, limit int) ([]*crl.RevokedCertificate, error) {
+	params := queries.ListRevokedCertificatesParams{
+		RevocationList: revocationListID,
+		Offset:         int64(offset),
+		Limit:          int64(limit),
+	}
+	params.SerialnumberPrefix, params.SerialnumberHexPrefix, params.Reason, params.RevokedAfter, params.RevokedBefore = revokedCertificateFilterParams(filter)
+
+	dbRevCerts, err := s.Queries.ListRevokedCertificates(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	revokedCertificates := make([]*crl.RevokedCertificate, len(dbRevCerts))
+	for i, revokedCertificate := range dbRevCerts {
+		revocationDate, ok := revokedCertificate.RevocationDate.(time.Time)
+		if !ok {
+			return nil, errors.New("invalid revocation date")
+		}
+
+		revokedCertificates[i] = &crl.RevokedCertificate{
+			SerialNumber:     revokedCertificate.Serialnumber,
+			RevocationReason: crl.RevocationReason(revokedCertificate.Reason),
+			RevocationDate:   revocationDate,
+			RevocationListID: revokedCertificate.RevocationList,
+		}
+	}
+
+	return revokedCertificates, nil
+}
+
+// CountRevokedCertificates counts the revoked certificates in a CRL that match the filter
+func (s *LibSqlStorage) CountRevokedCertificates(ctx context.Context, revocationListID int64, filter crl.RevokedCertificateFilter) (int, error) {
+	params := queries.CountRevokedCertificatesParams{RevocationList: revocationListID}
+	params.SerialnumberPrefix, params.SerialnumberHexPrefix, params.Reason, params.RevokedAfter, params.RevokedBefore = revokedCertificateFilterParams(filter)
+
+	count, err := s.Queries.CountRevokedCertificates(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// revokedCertificateFilterParams converts the filter to query parameters, nil parameters are not filtered on
+func revokedCertificateFilterParams(filter crl.RevokedCertificateFilter) (serialNumberPrefix, serialNumberHexPrefix, reason, revokedAfter, revokedBefore any) {
+	if filter.SerialNumberPrefix != "" {
+		if filter.SerialNumberHex {
+			serialNumberHexPrefix = filter.SerialNumberPrefix
+		} else {
+			serialNumberPrefix = filter.SerialNumberPrefix
+		}
+	}
+	if filter.Reason != "" {
+		reason = filter.Reason.String()
+	}
+	if !filter.RevokedAfter.IsZero() {
+		revokedAfter = filter.RevokedAfter.UTC()
+	}
+	if !filter.RevokedBefore.IsZero() {
+		revokedBefore = filter.RevokedBefore.UTC()
+	}
+	return serialNumberPrefix, serialNumberHexPrefix, reason, revokedAfter, revokedBefore
+}
+
 func (s *LibSqlStorage) FindRevokedCertificate(ctx context.Context, serialnumber string) (*crl.RevokedCertificate, error) {
 	log.Printf("find revoked certificate by serial number: %s", serialnumber)
 	dbRevokedCertificate, err := s.Queries.GetRevokedCertificate(ctx, serialnumber)
@@ -63,7 +130,34 @@ func (s *LibSqlStorage) FindRevokedCertificate(ctx context.Context, serialnumber
 		SerialNumber:     dbRevokedCertificate.Serialnumber,
 		RevocationReason: crl.RevocationReason(dbRevokedCertificate.Reason),
 		RevocationDate:   revocationDate,
-		RevocationListID: dbRevokedCertificate.ID,
+		RevocationListID: dbRevokedCertificate.RevocationList,
 		RevokedBy:        dbRevokedCertificate.RevokedBy,
 	}, nil
 }
+
+// FindRevokedCertificatesBySerialNumber finds the revoked certificates with the serial number on all stored CRLs
+func (s *LibSqlStorage) FindRevokedCertificatesBySerialNumber(ctx context.Context, serialnumber string) ([]*crl.RevokedCertificate, error) {
+	log.Printf("find revoked certificates by serial number: %s", serialnumber)
+	dbRevCerts, err := s.Queries.GetRevokedCertificatesBySerialNumber(ctx, serialnumber)
+	if err != nil {
+		return nil, err
+	}
+
+	revokedCertificates := make([]*crl.RevokedCertificate, len(dbRevCerts))
+	for i, revokedCertificate := range dbRevCerts {
+		revocationDate, ok := revokedCertificate.RevocationDate.(time.Time)
+		if !ok {
+			return nil, errors.New("invalid revocation date")
+		}
+
+		revokedCertificates[i] = &crl.RevokedCertificate{
+			SerialNumber:     revokedCertificate.Serialnumber,
+			RevocationReason: crl.RevocationReason(revokedCertificate.Reason),
+			RevocationDate:   revocationDate,
+			RevocationListID: revokedCertificate.RevocationList,
+			RevokedBy:        revokedCertificate.RevokedBy,
+		}
+	}
+
+	return revokedCertificates, nil
+}
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"crypto/x509"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/adapter/db/queries"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateRevocationListEntries(n int) []x509.RevocationListEntry {
+	entries := make([]x509.RevocationListEntry, n)
+	revocationTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range entries {
+		serialNumber := new(big.Int).Lsh(big.NewInt(int64(i+1)), 96)
+		entries[i] = x509.RevocationListEntry{
+			SerialNumber:   serialNumber.Add(serialNumber, big.NewInt(int64(i))),
+			RevocationTime: revocationTime.Add(time.Duration(i) * time.Second),
+			ReasonCode:     i % 2,
+		}
+	}
+	return entries
+}
+
+func newBenchmarkStorage(b *testing.B) (*LibSqlStorage, int64) {
+	b.Helper()
+	connection, err := NewDBConnection(b.TempDir())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	storage := NewLibSqlStorage(connection)
+	b.Cleanup(func() { _ = storage.CloseDB() })
+	if err := storage.InitDB(context.Background()); err != nil {
+		b.Fatal(err)
+	}
+
+	id, err := storage.Save(context.Background(), &crl.CertificateRevocationList{Name: "Generated CA", Signature: []byte{1}, ThisUpdate: time.Now(), NextUpdate: time.Now()})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return storage, id
+}
+
+// BenchmarkSaveRevokedCertificates compares the multi-row inserts with one insert per revoked certificate
+func BenchmarkSaveRevokedCertificates(b *testing.B) {
+	ctx := context.Background()
+	for _, n := range []int{10_000, 100_000} {
+		entries := generateRevocationListEntries(n)
+
+		b.Run(fmt.Sprintf("single-row/%d", n), func(b *testing.B) {
+			storage, id := newBenchmarkStorage(b)
+			for b.Loop() {
+				b.StopTimer()
+				_, _ = storage.DB.Exec("DELETE FROM revoked_certificate")
+				b.StartTimer()
+
+				tx, err := storage.DB.BeginTx(ctx, nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				qtx := storage.Queries.WithTx(tx)
+				for _, entry := range entries {
+					err := qtx.CreateRevokedCertificates(ctx, queries.CreateRevokedCertificatesParams{
+						Serialnumber:    entry.SerialNumber.String(),
+						SerialnumberHex: sql.NullString{String: entry.SerialNumber.Text(16), Valid: true},
+						RevocationDate:  entry.RevocationTime,
+						Reason:          crl.RevocationReasons[entry.ReasonCode].String(),
+						RevocationList:  id,
+					})
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+				if err := tx.Commit(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("batched/%d", n), func(b *testing.B) {
+			storage, id := newBenchmarkStorage(b)
+			for b.Loop() {
+				b.StopTimer()
+				_, _ = storage.DB.Exec("DELETE FROM revoked_certificate")
+				b.StartTimer()
+
+				if _, err := storage.SaveRevokedCertificates(ctx, id, entries); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveRevokedCertificatesBatches(t *testing.T) {
+	ctx := context.Background()
+	storage := newTestStorage(t)
+	id, err := storage.Save(ctx, &crl.CertificateRevocationList{Name: "Generated CA", Signature: []byte{1}, ThisUpdate: time.Now(), NextUpdate: time.Now()})
+	assert.NoError(t, err)
+
+	// a full batch followed by a partial batch
+	entries := generateRevocationListEntries(revokedCertificatesBatchSize + 7)
+	saved, err := storage.SaveRevokedCertificates(ctx, id, entries)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entries), saved)
+
+	stored, err := storage.FindRevokedCertificates(ctx, id)
+	assert.NoError(t, err)
+	assert.Len(t, stored, len(entries))
+	assert.Equal(t, entries[len(entries)-1].SerialNumber.String(), stored[len(stored)-1].SerialNumber)
+}
+
+func TestBackfillSerialNumberHex(t *testing.T) {
+	ctx := context.Background()
+	storage := newTestStorage(t)
+	id, err := storage.Save(ctx, &crl.CertificateRevocationList{Name: "Generated CA", Signature: []byte{1}, ThisUpdate: time.Now(), NextUpdate: time.Now()})
+	assert.NoError(t, err)
+
+	// revoked certificates stored before the hex serial number column existed
+	_, err = storage.DB.ExecContext(ctx, "INSERT INTO revoked_certificate(serialnumber, revocation_date, reason, revocation_list) VALUES ('255', ?, 'unspecified', ?)", time.Now(), id)
+	assert.NoError(t, err)
+
+	assert.NoError(t, storage.InitDB(ctx))
+
+	revokedCertificates, err := storage.ListRevokedCertificates(ctx, id, crl.RevokedCertificateFilter{SerialNumberPrefix: "ff", SerialNumberHex: true}, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, revokedCertificates, 1)
+	assert.Equal(t, "255", revokedCertificates[0].SerialNumber)
+}
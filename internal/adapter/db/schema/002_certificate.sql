@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS certificate (
+    id integer primary key,
+    fingerprint text unique not null,
+    subject text not null,
+    issuer text not null,
+    serialnumber text not null,
+    not_before DATE not null,
+    not_after DATE not null,
+    raw BLOB not null,
+    source text not null,
+    first_seen DATE not null,
+    last_seen DATE not null
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificate_fingerprint
+    ON certificate(fingerprint);
+
+CREATE INDEX IF NOT EXISTS idx_certificate_serialnumber
+    ON certificate(serialnumber);
+
+CREATE INDEX IF NOT EXISTS idx_certificate_not_after
+    ON certificate(not_after);
+
+-- +migrate Down
+DROP TABLE certificate;
@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE revoked_certificate ADD COLUMN serialnumber_hex text;
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber_hex
+    ON revoked_certificate(serialnumber_hex);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_revocation_list_date
+    ON revoked_certificate(revocation_list, revocation_date);
+
+-- +migrate Down
+DROP INDEX idx_revoked_certificate_revocation_list_date;
+
+DROP INDEX idx_revoked_certificate_serialnumber_hex;
+
+ALTER TABLE revoked_certificate DROP COLUMN serialnumber_hex;
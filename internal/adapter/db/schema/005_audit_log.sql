@@ -0,0 +1,42 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS audit_log (
+    id integer primary key,
+    checked_at DATE not null,
+    method text not null,
+    serialnumber text not null,
+    subject text not null,
+    issuer text not null,
+    source text not null,
+    source_this_update DATE,
+    result text not null,
+    detail text not null,
+    origin text not null
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_checked_at
+    ON audit_log(checked_at);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_serialnumber
+    ON audit_log(serialnumber);
+
+-- the audit log is append-only
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS audit_log_no_delete;
+
+DROP TRIGGER IF EXISTS audit_log_no_update;
+
+DROP TABLE audit_log;
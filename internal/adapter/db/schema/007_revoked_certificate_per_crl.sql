@@ -0,0 +1,56 @@
+-- +migrate Up
+-- a serial number is unique per CRL instead of across all CRLs, CAs can revoke certificates with the same serial number.
+-- SQLite cannot drop a column constraint, the table is recreated without it
+CREATE TABLE revoked_certificate_per_crl (
+    id integer primary key,
+    serialnumber text not null,
+    revocation_date DATE not null,
+    reason text not null,
+    revocation_list integer not null,
+    serialnumber_hex text,
+    foreign key (revocation_list) references certificate_revocation_list(id)
+       ON DELETE CASCADE,
+    unique (revocation_list, serialnumber)
+);
+
+INSERT INTO revoked_certificate_per_crl (id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex)
+SELECT id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex FROM revoked_certificate;
+
+DROP TABLE revoked_certificate;
+
+ALTER TABLE revoked_certificate_per_crl RENAME TO revoked_certificate;
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber
+    ON revoked_certificate(serialnumber);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber_hex
+    ON revoked_certificate(serialnumber_hex);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_revocation_list_date
+    ON revoked_certificate(revocation_list, revocation_date);
+
+-- +migrate Down
+-- serial numbers that are on more than one CRL keep the revocation of the first CRL
+CREATE TABLE revoked_certificate_unique_serial (
+    id integer primary key,
+    serialnumber text unique not null,
+    revocation_date DATE not null,
+    reason text not null,
+    revocation_list integer not null,
+    serialnumber_hex text,
+    foreign key (revocation_list) references certificate_revocation_list(id)
+       ON DELETE CASCADE
+);
+
+INSERT OR IGNORE INTO revoked_certificate_unique_serial (id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex)
+SELECT id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex FROM revoked_certificate ORDER BY revocation_list;
+
+DROP TABLE revoked_certificate;
+
+ALTER TABLE revoked_certificate_unique_serial RENAME TO revoked_certificate;
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber_hex
+    ON revoked_certificate(serialnumber_hex);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_revocation_list_date
+    ON revoked_certificate(revocation_list, revocation_date);
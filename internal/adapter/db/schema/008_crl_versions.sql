@@ -0,0 +1,138 @@
+-- +migrate Up
+-- a CRL is stored once per version, the name and this update of a CRL are unique instead of the name.
+-- SQLite cannot drop a column constraint, both tables are recreated. The revoked certificates are moved to a table without
+-- a foreign key first, so recreating the CRL table cannot cascade into them.
+CREATE TABLE certificate_revocation_list_versions (
+    id integer primary key,
+    name text not null,
+    signature BLOB unique not null,
+    this_update DATE not null,
+    next_update DATE,
+    url text,
+    raw BLOB,
+    last_refreshed DATE,
+    unsigned BOOLEAN NOT NULL DEFAULT false,
+    unique (name, this_update)
+);
+
+INSERT INTO certificate_revocation_list_versions (id, name, signature, this_update, next_update, url, raw, last_refreshed, unsigned)
+SELECT id, name, signature, this_update, next_update, url, raw, last_refreshed, unsigned FROM certificate_revocation_list;
+
+CREATE TABLE revoked_certificate_moved (
+    id integer primary key,
+    serialnumber text not null,
+    revocation_date DATE not null,
+    reason text not null,
+    revocation_list integer not null,
+    serialnumber_hex text
+);
+
+INSERT INTO revoked_certificate_moved (id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex)
+SELECT id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex FROM revoked_certificate;
+
+DROP TABLE revoked_certificate;
+
+DROP TABLE certificate_revocation_list;
+
+ALTER TABLE certificate_revocation_list_versions RENAME TO certificate_revocation_list;
+
+CREATE TABLE revoked_certificate (
+    id integer primary key,
+    serialnumber text not null,
+    revocation_date DATE not null,
+    reason text not null,
+    revocation_list integer not null,
+    serialnumber_hex text,
+    foreign key (revocation_list) references certificate_revocation_list(id)
+       ON DELETE CASCADE,
+    unique (revocation_list, serialnumber)
+);
+
+INSERT INTO revoked_certificate (id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex)
+SELECT id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex FROM revoked_certificate_moved;
+
+DROP TABLE revoked_certificate_moved;
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber
+    ON revoked_certificate(serialnumber);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber_hex
+    ON revoked_certificate(serialnumber_hex);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_revocation_list_date
+    ON revoked_certificate(revocation_list, revocation_date);
+
+-- +migrate Down
+-- only the latest version of every CRL is kept, the revoked certificates of the older versions are removed with them
+DELETE FROM revoked_certificate WHERE revocation_list IN (
+    SELECT older.id FROM certificate_revocation_list AS older
+    JOIN certificate_revocation_list AS newer ON newer.name = older.name
+        AND (DATETIME(newer.this_update) > DATETIME(older.this_update) OR (DATETIME(newer.this_update) = DATETIME(older.this_update) AND newer.id > older.id))
+);
+
+CREATE TABLE certificate_revocation_list_unique_name (
+    id integer primary key,
+    name text unique not null,
+    signature BLOB unique not null,
+    this_update DATE not null,
+    next_update DATE,
+    url text,
+    raw BLOB,
+    last_refreshed DATE,
+    unsigned BOOLEAN NOT NULL DEFAULT false
+);
+
+INSERT INTO certificate_revocation_list_unique_name (id, name, signature, this_update, next_update, url, raw, last_refreshed, unsigned)
+SELECT id, name, signature, this_update, next_update, url, raw, last_refreshed, unsigned FROM certificate_revocation_list
+WHERE id NOT IN (
+    SELECT older.id FROM certificate_revocation_list AS older
+    JOIN certificate_revocation_list AS newer ON newer.name = older.name
+        AND (DATETIME(newer.this_update) > DATETIME(older.this_update) OR (DATETIME(newer.this_update) = DATETIME(older.this_update) AND newer.id > older.id))
+);
+
+CREATE TABLE revoked_certificate_moved (
+    id integer primary key,
+    serialnumber text not null,
+    revocation_date DATE not null,
+    reason text not null,
+    revocation_list integer not null,
+    serialnumber_hex text
+);
+
+INSERT INTO revoked_certificate_moved (id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex)
+SELECT id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex FROM revoked_certificate;
+
+DROP TABLE revoked_certificate;
+
+DROP TABLE certificate_revocation_list;
+
+ALTER TABLE certificate_revocation_list_unique_name RENAME TO certificate_revocation_list;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificate_revocation_list_name
+    ON certificate_revocation_list(name);
+
+CREATE TABLE revoked_certificate (
+    id integer primary key,
+    serialnumber text not null,
+    revocation_date DATE not null,
+    reason text not null,
+    revocation_list integer not null,
+    serialnumber_hex text,
+    foreign key (revocation_list) references certificate_revocation_list(id)
+       ON DELETE CASCADE,
+    unique (revocation_list, serialnumber)
+);
+
+INSERT INTO revoked_certificate (id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex)
+SELECT id, serialnumber, revocation_date, reason, revocation_list, serialnumber_hex FROM revoked_certificate_moved;
+
+DROP TABLE revoked_certificate_moved;
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber
+    ON revoked_certificate(serialnumber);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber_hex
+    ON revoked_certificate(serialnumber_hex);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_revocation_list_date
+    ON revoked_certificate(revocation_list, revocation_date);
@@ -0,0 +1,381 @@
+// Package memory stores certificate revocation lists and the certificate inventory in memory, nothing is persisted when certguard exits
+package memory
+
+import (
+	"cmp"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// revokedCertificateKey makes a serial number unique per CRL, CAs can revoke certificates with the same serial number
+type revokedCertificateKey struct {
+	revocationListID int64
+	serialNumber     string
+}
+
+type MemoryStorage struct {
+	mu                  sync.RWMutex
+	nextID              int64
+	revocationLists     map[int64]*crl.CertificateRevocationList
+	revokedCertificates map[revokedCertificateKey]*crl.RevokedCertificate
+	certificates        map[int64]*crl.StoredCertificate
+	auditEntries        []*crl.AuditEntry
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		revocationLists:     make(map[int64]*crl.CertificateRevocationList),
+		revokedCertificates: make(map[revokedCertificateKey]*crl.RevokedCertificate),
+		certificates:        make(map[int64]*crl.StoredCertificate),
+	}
+}
+
+// InitDB is a no-op, the in-memory storage does not need migrations
+func (s *MemoryStorage) InitDB(_ context.Context) error {
+	log.Println("in-memory storage initialized")
+	return nil
+}
+
+// CloseDB is a no-op, the stored data is discarded with the storage
+func (s *MemoryStorage) CloseDB() error {
+	return nil
+}
+
+func (s *MemoryStorage) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+// Save inserts a version of a certificate revocation list, a known version with the same name and this update is replaced except for its ID and URL.
+// The revoked certificates of a replaced version are removed, they are saved again with SaveRevokedCertificates.
+func (s *MemoryStorage) Save(_ context.Context, revocationList *crl.CertificateRevocationList) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored := s.findVersion(revocationList.Name, revocationList.ThisUpdate); stored != nil {
+		stored.Signature = revocationList.Signature
+		stored.NextUpdate = revocationList.NextUpdate
+		stored.Raw = revocationList.Raw
+		stored.LastRefreshed = revocationList.LastRefreshed
+		stored.Unsigned = revocationList.Unsigned
+		s.deleteRevokedCertificates(stored.ID)
+		return stored.ID, nil
+	}
+
+	stored := *revocationList
+	stored.ID = s.newID()
+	s.revocationLists[stored.ID] = &stored
+
+	log.Printf("crl with id: %d stored\n", stored.ID)
+	return stored.ID, nil
+}
+
+// Find the latest version of a Certificate Revocation List
+func (s *MemoryStorage) Find(_ context.Context, name string) (*crl.CertificateRevocationList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored := s.findByName(name)
+	if stored == nil {
+		return nil, fmt.Errorf("%w: %s", crl.ErrNotFound, name)
+	}
+
+	revocationList := *stored
+	return &revocationList, nil
+}
+
+// findByName returns the version of a CRL with the latest this update, the latest saved version for equal updates
+func (s *MemoryStorage) findByName(name string) *crl.CertificateRevocationList {
+	var latest *crl.CertificateRevocationList
+	for _, revocationList := range s.revocationLists {
+		if revocationList.Name != name {
+			continue
+		}
+		if latest == nil || revocationList.ThisUpdate.After(latest.ThisUpdate) || (revocationList.ThisUpdate.Equal(latest.ThisUpdate) && revocationList.ID > latest.ID) {
+			latest = revocationList
+		}
+	}
+	return latest
+}
+
+func (s *MemoryStorage) findVersion(name string, thisUpdate time.Time) *crl.CertificateRevocationList {
+	for _, revocationList := range s.revocationLists {
+		if revocationList.Name == name && revocationList.ThisUpdate.Equal(thisUpdate) {
+			return revocationList
+		}
+	}
+	return nil
+}
+
+// List all Certificate Revocation Lists
+func (s *MemoryStorage) List(_ context.Context) ([]*crl.CertificateRevocationList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revocationLists := make([]*crl.CertificateRevocationList, 0, len(s.revocationLists))
+	for _, stored := range s.revocationLists {
+		revocationList := *stored
+		revocationLists = append(revocationLists, &revocationList)
+	}
+	slices.SortFunc(revocationLists, func(a, b *crl.CertificateRevocationList) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return revocationLists, nil
+}
+
+// Delete removes a certificate revocation list together with its revoked certificates
+func (s *MemoryStorage) Delete(_ context.Context, revocationListId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.revocationLists, revocationListId)
+	s.deleteRevokedCertificates(revocationListId)
+
+	return nil
+}
+
+func (s *MemoryStorage) deleteRevokedCertificates(revocationListID int64) {
+	for key := range s.revokedCertificates {
+		if key.revocationListID == revocationListID {
+			delete(s.revokedCertificates, key)
+		}
+	}
+}
+
+// SaveRevokedCertificates saves all entries or none, serial numbers that are already stored for the CRL are skipped
+func (s *MemoryStorage) SaveRevokedCertificates(_ context.Context, revocationListId int64, revokedCertificates []x509.RevocationListEntry) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.revocationLists[revocationListId]; !ok {
+		return 0, fmt.Errorf("certificate revocation list %d not found", revocationListId)
+	}
+
+	entries := make([]*crl.RevokedCertificate, len(revokedCertificates))
+	for i, revokedCertificateEntry := range revokedCertificates {
+		reason, ok := crl.RevocationReasons[revokedCertificateEntry.ReasonCode]
+		if !ok {
+			return 0, errors.New("invalid ReasonCode on revoked certificate")
+		}
+
+		entries[i] = &crl.RevokedCertificate{
+			SerialNumber:     revokedCertificateEntry.SerialNumber.String(),
+			RevocationReason: reason,
+			RevocationDate:   revokedCertificateEntry.RevocationTime,
+			RevocationListID: revocationListId,
+		}
+	}
+
+	for _, entry := range entries {
+		key := revokedCertificateKey{revocationListID: revocationListId, serialNumber: entry.SerialNumber}
+		if _, ok := s.revokedCertificates[key]; !ok {
+			s.revokedCertificates[key] = entry
+		}
+	}
+
+	return len(entries), nil
+}
+
+// FindRevokedCertificates finds all revoked certificates in a CRL ordered by revocation date
+func (s *MemoryStorage) FindRevokedCertificates(_ context.Context, revocationListID int64) ([]*crl.RevokedCertificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revokedCertificates := make([]*crl.RevokedCertificate, 0)
+	for _, stored := range s.revokedCertificates {
+		if stored.RevocationListID == revocationListID {
+			revokedCertificate := *stored
+			revokedCertificates = append(revokedCertificates, &revokedCertificate)
+		}
+	}
+	slices.SortFunc(revokedCertificates, func(a, b *crl.RevokedCertificate) int {
+		return cmp.Or(a.RevocationDate.Compare(b.RevocationDate), cmp.Compare(a.SerialNumber, b.SerialNumber))
+	})
+
+	return revokedCertificates, nil
+}
+
+// ListRevokedCertificates lists a page of the revoked certificates in a CRL that match the filter, ordered by revocation date
+func (s *MemoryStorage) ListRevokedCertificates(ctx context.Context, revocationListID int64, filter crl.RevokedCertificateFilter, offset, limit int) ([]*crl.RevokedCertificate, error) {
+	revokedCertificates, err := s.filterRevokedCertificates(ctx, revocationListID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return revokedCertificates[min(offset, len(revokedCertificates)):min(offset+limit, len(revokedCertificates))], nil
+}
+
+// CountRevokedCertificates counts the revoked certificates in a CRL that match the filter
+func (s *MemoryStorage) CountRevokedCertificates(ctx context.Context, revocationListID int64, filter crl.RevokedCertificateFilter) (int, error) {
+	revokedCertificates, err := s.filterRevokedCertificates(ctx, revocationListID, filter)
+	return len(revokedCertificates), err
+}
+
+func (s *MemoryStorage) filterRevokedCertificates(ctx context.Context, revocationListID int64, filter crl.RevokedCertificateFilter) ([]*crl.RevokedCertificate, error) {
+	revokedCertificates, err := s.FindRevokedCertificates(ctx, revocationListID)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(revokedCertificates, func(revokedCertificate *crl.RevokedCertificate) bool {
+		return !filter.Matches(revokedCertificate)
+	}), nil
+}
+
+// FindRevokedCertificate finds a revoked certificate by serial number on the first CRL that revoked it, nil is returned when the certificate is not revoked
+func (s *MemoryStorage) FindRevokedCertificate(_ context.Context, serialnumber string) (*crl.RevokedCertificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revokedCertificates := s.findBySerialNumber(serialnumber)
+	if len(revokedCertificates) == 0 {
+		return nil, nil
+	}
+	return revokedCertificates[0], nil
+}
+
+// FindRevokedCertificatesBySerialNumber finds the revoked certificates with the serial number on all stored CRLs
+func (s *MemoryStorage) FindRevokedCertificatesBySerialNumber(_ context.Context, serialnumber string) ([]*crl.RevokedCertificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.findBySerialNumber(serialnumber), nil
+}
+
+// findBySerialNumber copies the revoked certificates with the serial number ordered by CRL and sets the CRL that revoked them
+func (s *MemoryStorage) findBySerialNumber(serialnumber string) []*crl.RevokedCertificate {
+	revokedCertificates := make([]*crl.RevokedCertificate, 0, 1)
+	for key, stored := range s.revokedCertificates {
+		if key.serialNumber != serialnumber {
+			continue
+		}
+		revokedCertificate := *stored
+		revokedCertificate.RevokedBy = s.revocationLists[stored.RevocationListID].Name
+		revokedCertificates = append(revokedCertificates, &revokedCertificate)
+	}
+	slices.SortFunc(revokedCertificates, func(a, b *crl.RevokedCertificate) int {
+		return cmp.Compare(a.RevocationListID, b.RevocationListID)
+	})
+	return revokedCertificates
+}
+
+// SaveCertificate inserts a certificate in the inventory, for a known certificate only the source and last seen time are updated
+func (s *MemoryStorage) SaveCertificate(_ context.Context, certificate *crl.StoredCertificate) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored := s.findCertificate(certificate.Fingerprint); stored != nil {
+		stored.Source = certificate.Source
+		stored.LastSeen = certificate.LastSeen
+		return stored.ID, nil
+	}
+
+	stored := *certificate
+	stored.ID = s.newID()
+	stored.RevokedBy = ""
+	s.certificates[stored.ID] = &stored
+
+	log.Printf("certificate with id: %d stored", stored.ID)
+	return stored.ID, nil
+}
+
+// ListCertificates lists the certificates in the inventory ordered by expiry date
+func (s *MemoryStorage) ListCertificates(_ context.Context, filter crl.CertificateFilter) ([]*crl.StoredCertificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	certificates := make([]*crl.StoredCertificate, 0)
+	for _, stored := range s.certificates {
+		certificate := s.withRevocation(stored)
+		if filter.Matches(certificate) {
+			certificates = append(certificates, certificate)
+		}
+	}
+	slices.SortFunc(certificates, func(a, b *crl.StoredCertificate) int {
+		return cmp.Or(a.NotAfter.Compare(b.NotAfter), cmp.Compare(a.ID, b.ID))
+	})
+
+	return certificates, nil
+}
+
+// FindCertificate finds a certificate in the inventory by its SHA-256 fingerprint, nil is returned when the certificate is not stored
+func (s *MemoryStorage) FindCertificate(_ context.Context, fingerprint string) (*crl.StoredCertificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored := s.findCertificate(fingerprint)
+	if stored == nil {
+		return nil, nil
+	}
+
+	return s.withRevocation(stored), nil
+}
+
+func (s *MemoryStorage) findCertificate(fingerprint string) *crl.StoredCertificate {
+	for _, certificate := range s.certificates {
+		if certificate.Fingerprint == fingerprint {
+			return certificate
+		}
+	}
+	return nil
+}
+
+// withRevocation copies a stored certificate and sets the first CRL that revoked it
+func (s *MemoryStorage) withRevocation(stored *crl.StoredCertificate) *crl.StoredCertificate {
+	certificate := *stored
+	if revokedCertificates := s.findBySerialNumber(stored.SerialNumber); len(revokedCertificates) > 0 {
+		certificate.RevokedBy = revokedCertificates[0].RevokedBy
+	}
+	return &certificate
+}
+
+// DeleteCertificate removes a certificate from the inventory
+func (s *MemoryStorage) DeleteCertificate(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.certificates, id)
+	return nil
+}
+
+// SaveAuditEntry appends a revocation check to the audit log
+func (s *MemoryStorage) SaveAuditEntry(_ context.Context, entry *crl.AuditEntry) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *entry
+	saved.ID = s.newID()
+	s.auditEntries = append(s.auditEntries, &saved)
+	return saved.ID, nil
+}
+
+// ListAuditEntries lists the audit entries that match the filter, the most recent first
+func (s *MemoryStorage) ListAuditEntries(_ context.Context, filter crl.AuditFilter) ([]*crl.AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]*crl.AuditEntry, 0)
+	for _, stored := range s.auditEntries {
+		if filter.Matches(stored) {
+			entry := *stored
+			entries = append(entries, &entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b *crl.AuditEntry) int {
+		return cmp.Or(b.CheckedAt.Compare(a.CheckedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
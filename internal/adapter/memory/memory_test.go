@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/pimg/certguard/internal/adapter/storagetest"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) crl.Repository {
+		return NewMemoryStorage()
+	})
+}
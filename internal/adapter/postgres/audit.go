@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pimg/certguard/internal/adapter/postgres/queries"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// SaveAuditEntry appends a revocation check to the audit log
+func (s *PostgresStorage) SaveAuditEntry(ctx context.Context, entry *crl.AuditEntry) (int64, error) {
+	params := queries.CreateAuditEntryParams{
+		CheckedAt:    entry.CheckedAt,
+		Method:       string(entry.Method),
+		Serialnumber: entry.SerialNumber,
+		Subject:      entry.Subject,
+		Issuer:       entry.Issuer,
+		Source:       entry.Source,
+		Result:       string(entry.Result),
+		Detail:       entry.Detail,
+		Origin:       entry.Origin,
+	}
+	if !entry.SourceThisUpdate.IsZero() {
+		params.SourceThisUpdate = sql.NullTime{Time: entry.SourceThisUpdate, Valid: true}
+	}
+
+	id, err := s.Queries.CreateAuditEntry(ctx, params)
+	if err != nil {
+		return 0, errors.Join(errors.New("could not save audit entry"), err)
+	}
+
+	return id, nil
+}
+
+// ListAuditEntries lists the audit entries that match the filter, the most recent first
+func (s *PostgresStorage) ListAuditEntries(ctx context.Context, filter crl.AuditFilter) ([]*crl.AuditEntry, error) {
+	params := queries.ListAuditEntriesParams{
+		CheckedFrom:  sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		CheckedTo:    sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()},
+		Method:       sql.NullString{String: string(filter.Method), Valid: filter.Method != ""},
+		Serialnumber: sql.NullString{String: filter.SerialNumber, Valid: filter.SerialNumber != ""},
+		Lim:          sql.NullInt32{Int32: int32(filter.Limit), Valid: filter.Limit > 0},
+	}
+
+	dbEntries, err := s.Queries.ListAuditEntries(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*crl.AuditEntry, len(dbEntries))
+	for i, dbEntry := range dbEntries {
+		entries[i] = &crl.AuditEntry{
+			ID:               dbEntry.ID,
+			CheckedAt:        dbEntry.CheckedAt,
+			Method:           crl.AuditMethod(dbEntry.Method),
+			SerialNumber:     dbEntry.Serialnumber,
+			Subject:          dbEntry.Subject,
+			Issuer:           dbEntry.Issuer,
+			Source:           dbEntry.Source,
+			SourceThisUpdate: dbEntry.SourceThisUpdate.Time,
+			Result:           crl.AuditResult(dbEntry.Result),
+			Detail:           dbEntry.Detail,
+			Origin:           dbEntry.Origin,
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/pimg/certguard/internal/adapter/postgres/queries"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// SaveCertificate inserts a certificate in the inventory, for a known certificate only the source and last seen time are updated
+func (s *PostgresStorage) SaveCertificate(ctx context.Context, certificate *crl.StoredCertificate) (int64, error) {
+	id, err := s.Queries.CreateCertificate(ctx, queries.CreateCertificateParams{
+		Fingerprint:  certificate.Fingerprint,
+		Subject:      certificate.Subject,
+		Issuer:       certificate.Issuer,
+		Serialnumber: certificate.SerialNumber,
+		NotBefore:    certificate.NotBefore,
+		NotAfter:     certificate.NotAfter,
+		Raw:          certificate.Raw,
+		Source:       certificate.Source,
+		FirstSeen:    certificate.FirstSeen,
+		LastSeen:     certificate.LastSeen,
+	})
+	if err != nil {
+		log.Println("could not save certificate")
+		return 0, errors.Join(errors.New("could not save certificate"), err)
+	}
+
+	log.Printf("certificate with id: %d stored", id)
+	return id, nil
+}
+
+// ListCertificates lists the certificates in the inventory ordered by expiry date
+func (s *PostgresStorage) ListCertificates(ctx context.Context, filter crl.CertificateFilter) ([]*crl.StoredCertificate, error) {
+	params := queries.ListCertificatesParams{}
+	if filter.Issuer != "" {
+		params.Issuer = sql.NullString{String: filter.Issuer, Valid: true}
+	}
+	if !filter.ExpiresBefore.IsZero() {
+		params.ExpiresBefore = sql.NullTime{Time: filter.ExpiresBefore, Valid: true}
+	}
+	switch filter.Revocation {
+	case crl.RevocationFilterRevoked:
+		params.Revoked = sql.NullBool{Bool: true, Valid: true}
+	case crl.RevocationFilterNotRevoked:
+		params.Revoked = sql.NullBool{Bool: false, Valid: true}
+	}
+
+	dbCertificates, err := s.Queries.ListCertificates(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]*crl.StoredCertificate, len(dbCertificates))
+	for i, dbCertificate := range dbCertificates {
+		certificates[i] = storedCertificate(queries.GetCertificateRow(dbCertificate))
+	}
+
+	return certificates, nil
+}
+
+// FindCertificate finds a certificate in the inventory by its SHA-256 fingerprint, nil is returned when the certificate is not stored
+func (s *PostgresStorage) FindCertificate(ctx context.Context, fingerprint string) (*crl.StoredCertificate, error) {
+	dbCertificate, err := s.Queries.GetCertificate(ctx, fingerprint)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return storedCertificate(dbCertificate), nil
+}
+
+// DeleteCertificate removes a certificate from the inventory
+func (s *PostgresStorage) DeleteCertificate(ctx context.Context, id int64) error {
+	err := s.Queries.DeleteCertificate(ctx, id)
+	if err != nil {
+		return errors.Join(errors.New("could not delete certificate from storage"), err)
+	}
+
+	return nil
+}
+
+func storedCertificate(dbCertificate queries.GetCertificateRow) *crl.StoredCertificate {
+	return &crl.StoredCertificate{
+		ID:           dbCertificate.ID,
+		Fingerprint:  dbCertificate.Fingerprint,
+		Subject:      dbCertificate.Subject,
+		Issuer:       dbCertificate.Issuer,
+		SerialNumber: dbCertificate.Serialnumber,
+		NotBefore:    dbCertificate.NotBefore,
+		NotAfter:     dbCertificate.NotAfter,
+		Raw:          dbCertificate.Raw,
+		Source:       dbCertificate.Source,
+		FirstSeen:    dbCertificate.FirstSeen,
+		LastSeen:     dbCertificate.LastSeen,
+		RevokedBy:    dbCertificate.RevokedBy.String,
+	}
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/pimg/certguard/internal/adapter/postgres/queries"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// Save inserts a version of a certificate revocation list, a known version with the same name and this update is replaced except for its ID and URL.
+// The revoked certificates of a replaced version are removed, they are saved again with SaveRevokedCertificates.
+// nolint: errcheck // checking err in defer results in panic
+func (s *PostgresStorage) Save(ctx context.Context, crl *crl.CertificateRevocationList) (int64, error) {
+	params := queries.CreateCertificateRevocationListParams{
+		Name:       crl.Name,
+		Signature:  crl.Signature,
+		ThisUpdate: crl.ThisUpdate,
+		NextUpdate: sql.NullTime{
+			Time:  crl.NextUpdate,
+			Valid: true,
+		},
+		Raw:      crl.Raw,
+		Unsigned: crl.Unsigned,
+	}
+
+	if !crl.LastRefreshed.IsZero() {
+		params.LastRefreshed = sql.NullTime{
+			Time:  crl.LastRefreshed,
+			Valid: true,
+		}
+	}
+
+	if crl.URL != nil {
+		params.Url = sql.NullString{
+			String: crl.URL.String(),
+			Valid:  true,
+		}
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := s.Queries.WithTx(tx)
+	id, err := qtx.CreateCertificateRevocationList(ctx, params)
+	if err != nil {
+		log.Println("could not create certificate revocation list")
+		return 0, err
+	}
+
+	err = qtx.DeleteRevokedCertificatesByRevocationList(ctx, id)
+	if err != nil {
+		return 0, errors.Join(errors.New("could not remove the revoked certificates of the replaced certificate revocation list"), err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	log.Printf("crl with id: %d stored\n", id)
+	return id, nil
+}
+
+// Find the latest version of a Certificate Revocation List
+func (s *PostgresStorage) Find(ctx context.Context, name string) (*crl.CertificateRevocationList, error) {
+	dbCrl, err := s.Queries.GetCertificateRevocationList(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", crl.ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return revocationList(dbCrl)
+}
+
+// List all Certificate Revocation Lists
+func (s *PostgresStorage) List(ctx context.Context) ([]*crl.CertificateRevocationList, error) {
+	dbCrls, err := s.Queries.ListCertificateRevocationLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cRLs := make([]*crl.CertificateRevocationList, len(dbCrls))
+	for i, dbCrl := range dbCrls {
+		cRLs[i], err = revocationList(queries.CertificateRevocationList(dbCrl))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cRLs, nil
+}
+
+// revokedCertificatesBatchSize is the number of rows in a multi-row insert, 5 parameters per row stay well below the PostgreSQL limit of 65535 parameters
+const revokedCertificatesBatchSize = 1000
+
+// SaveRevokedCertificates saves all entries or none with multi-row inserts, serial numbers that are already stored are skipped
+// nolint: errcheck // checking err in defer results in panic
+func (s *PostgresStorage) SaveRevokedCertificates(ctx context.Context, revocationListId int64, revokedCertificates []x509.RevocationListEntry) (int, error) {
+	if len(revokedCertificates) == 0 {
+		return 0, nil
+	}
+
+	reasons := make([]string, len(revokedCertificates))
+	for i, revokedCertificateEntry := range revokedCertificates {
+		reason, ok := crl.RevocationReasons[revokedCertificateEntry.ReasonCode]
+		if !ok {
+			return 0, errors.New("invalid ReasonCode on revoked certificate")
+		}
+		reasons[i] = reason.String()
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	preparedRows := min(len(revokedCertificates), revokedCertificatesBatchSize)
+	batchStatement, err := tx.PrepareContext(ctx, insertRevokedCertificatesQuery(preparedRows))
+	if err != nil {
+		return 0, errors.Join(errors.New("could not prepare insert of revoked certificates"), err)
+	}
+	defer batchStatement.Close()
+
+	args := make([]any, 0, 5*revokedCertificatesBatchSize)
+	for start := 0; start < len(revokedCertificates); start += revokedCertificatesBatchSize {
+		end := min(start+revokedCertificatesBatchSize, len(revokedCertificates))
+
+		args = args[:0]
+		for i, revokedCertificateEntry := range revokedCertificates[start:end] {
+			args = append(args, revokedCertificateEntry.SerialNumber.String(), revokedCertificateEntry.SerialNumber.Text(16), revokedCertificateEntry.RevocationTime, reasons[start+i], revocationListId)
+		}
+
+		if end-start == preparedRows {
+			_, err = batchStatement.ExecContext(ctx, args...)
+		} else {
+			_, err = tx.ExecContext(ctx, insertRevokedCertificatesQuery(end-start), args...)
+		}
+		if err != nil {
+			return 0, errors.Join(errors.New("could not save certificate revocation list entries"), err)
+		}
+	}
+
+	return len(revokedCertificates), tx.Commit()
+}
+
+// insertRevokedCertificatesQuery creates a multi-row insert, sqlc cannot generate queries with a variable number of rows
+func insertRevokedCertificatesQuery(rows int) string {
+	var query strings.Builder
+	query.WriteString("INSERT INTO revoked_certificate(serialnumber, serialnumber_hex, revocation_date, reason, revocation_list) VALUES ")
+	for i := range rows {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		fmt.Fprintf(&query, "($%d,$%d,$%d,$%d,$%d)", 5*i+1, 5*i+2, 5*i+3, 5*i+4, 5*i+5)
+	}
+	query.WriteString(" ON CONFLICT DO NOTHING")
+	return query.String()
+}
+
+// Delete removes a certificate revocation list, its revoked certificates are removed by the foreign key
+func (s *PostgresStorage) Delete(ctx context.Context, revocationListId int64) error {
+	err := s.Queries.DeleteCertificateRevocationList(ctx, revocationListId)
+	if err != nil {
+		return errors.Join(errors.New("could not Delete CRL from storage "), err)
+	}
+
+	return nil
+}
+
+func revocationList(dbCrl queries.CertificateRevocationList) (*crl.CertificateRevocationList, error) {
+	revocationList := &crl.CertificateRevocationList{
+		ID:            dbCrl.ID,
+		Name:          dbCrl.Name,
+		Signature:     dbCrl.Signature,
+		ThisUpdate:    dbCrl.ThisUpdate,
+		NextUpdate:    dbCrl.NextUpdate.Time,
+		Raw:           dbCrl.Raw,
+		LastRefreshed: dbCrl.LastRefreshed.Time,
+		Unsigned:      dbCrl.Unsigned,
+	}
+
+	if dbCrl.Url.Valid {
+		URL, err := url.Parse(dbCrl.Url.String)
+		if err != nil {
+			return nil, errors.Join(errors.New("invalid CRL URL"), err)
+		}
+		revocationList.URL = URL
+	}
+
+	return revocationList, nil
+}
@@ -0,0 +1,24 @@
+-- name: CreateAuditEntry :one
+INSERT INTO audit_log(
+    checked_at,
+    method,
+    serialnumber,
+    subject,
+    issuer,
+    source,
+    source_this_update,
+    result,
+    detail,
+    origin
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+RETURNING id;
+
+-- name: ListAuditEntries :many
+SELECT id, checked_at, method, serialnumber, subject, issuer, source, source_this_update, result, detail, origin
+FROM audit_log
+WHERE (sqlc.narg(checked_from)::timestamptz IS NULL OR checked_at >= sqlc.narg(checked_from)::timestamptz)
+  AND (sqlc.narg(checked_to)::timestamptz IS NULL OR checked_at < sqlc.narg(checked_to)::timestamptz)
+  AND (sqlc.narg(method)::text IS NULL OR method = sqlc.narg(method)::text)
+  AND (sqlc.narg(serialnumber)::text IS NULL OR serialnumber = sqlc.narg(serialnumber)::text)
+ORDER BY checked_at DESC, id DESC
+LIMIT sqlc.narg(lim)::integer;
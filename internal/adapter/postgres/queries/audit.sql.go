@@ -0,0 +1,119 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: audit.sql
+
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createAuditEntry = `-- name: CreateAuditEntry :one
+INSERT INTO audit_log(
+    checked_at,
+    method,
+    serialnumber,
+    subject,
+    issuer,
+    source,
+    source_this_update,
+    result,
+    detail,
+    origin
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+RETURNING id
+`
+
+type CreateAuditEntryParams struct {
+	CheckedAt        time.Time
+	Method           string
+	Serialnumber     string
+	Subject          string
+	Issuer           string
+	Source           string
+	SourceThisUpdate sql.NullTime
+	Result           string
+	Detail           string
+	Origin           string
+}
+
+func (q *Queries) CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createAuditEntry,
+		arg.CheckedAt,
+		arg.Method,
+		arg.Serialnumber,
+		arg.Subject,
+		arg.Issuer,
+		arg.Source,
+		arg.SourceThisUpdate,
+		arg.Result,
+		arg.Detail,
+		arg.Origin,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listAuditEntries = `-- name: ListAuditEntries :many
+SELECT id, checked_at, method, serialnumber, subject, issuer, source, source_this_update, result, detail, origin
+FROM audit_log
+WHERE ($1::timestamptz IS NULL OR checked_at >= $1::timestamptz)
+  AND ($2::timestamptz IS NULL OR checked_at < $2::timestamptz)
+  AND ($3::text IS NULL OR method = $3::text)
+  AND ($4::text IS NULL OR serialnumber = $4::text)
+ORDER BY checked_at DESC, id DESC
+LIMIT $5::integer
+`
+
+type ListAuditEntriesParams struct {
+	CheckedFrom  sql.NullTime
+	CheckedTo    sql.NullTime
+	Method       sql.NullString
+	Serialnumber sql.NullString
+	Lim          sql.NullInt32
+}
+
+func (q *Queries) ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditEntries,
+		arg.CheckedFrom,
+		arg.CheckedTo,
+		arg.Method,
+		arg.Serialnumber,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.CheckedAt,
+			&i.Method,
+			&i.Serialnumber,
+			&i.Subject,
+			&i.Issuer,
+			&i.Source,
+			&i.SourceThisUpdate,
+			&i.Result,
+			&i.Detail,
+			&i.Origin,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
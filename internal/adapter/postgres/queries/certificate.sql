@@ -0,0 +1,46 @@
+-- name: CreateCertificate :one
+INSERT INTO certificate(
+    fingerprint,
+    subject,
+    issuer,
+    serialnumber,
+    not_before,
+    not_after,
+    raw,
+    source,
+    first_seen,
+    last_seen
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+  ON CONFLICT (fingerprint) DO UPDATE SET
+    source = excluded.source,
+    last_seen = excluded.last_seen
+RETURNING id;
+
+-- name: GetCertificate :one
+SELECT cert.id, cert.fingerprint, cert.subject, cert.issuer, cert.serialnumber, cert.not_before, cert.not_after,
+       cert.raw, cert.source, cert.first_seen, cert.last_seen, crl.name AS revoked_by
+FROM certificate AS cert
+-- a serial number on more than one CRL is shown with the first CRL that revoked it
+LEFT JOIN revoked_certificate AS revoked ON revoked.id = (
+    SELECT earliest.id FROM revoked_certificate AS earliest WHERE earliest.serialnumber = cert.serialnumber ORDER BY earliest.revocation_list LIMIT 1
+)
+LEFT JOIN certificate_revocation_list AS crl ON crl.id = revoked.revocation_list
+WHERE cert.fingerprint = $1;
+
+-- name: ListCertificates :many
+SELECT cert.id, cert.fingerprint, cert.subject, cert.issuer, cert.serialnumber, cert.not_before, cert.not_after,
+       cert.raw, cert.source, cert.first_seen, cert.last_seen, crl.name AS revoked_by
+FROM certificate AS cert
+-- a serial number on more than one CRL is shown with the first CRL that revoked it
+LEFT JOIN revoked_certificate AS revoked ON revoked.id = (
+    SELECT earliest.id FROM revoked_certificate AS earliest WHERE earliest.serialnumber = cert.serialnumber ORDER BY earliest.revocation_list LIMIT 1
+)
+LEFT JOIN certificate_revocation_list AS crl ON crl.id = revoked.revocation_list
+WHERE (sqlc.narg(issuer)::text IS NULL OR cert.issuer ILIKE '%' || sqlc.narg(issuer)::text || '%')
+  AND (sqlc.narg(expires_before)::timestamptz IS NULL OR cert.not_after <= sqlc.narg(expires_before)::timestamptz)
+  AND (sqlc.narg(revoked)::boolean IS NULL OR (crl.name IS NOT NULL) = sqlc.narg(revoked)::boolean)
+ORDER BY cert.not_after, cert.id;
+
+-- name: DeleteCertificate :exec
+DELETE FROM certificate
+WHERE id = $1;
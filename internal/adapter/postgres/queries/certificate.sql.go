@@ -0,0 +1,191 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: certificate.sql
+
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createCertificate = `-- name: CreateCertificate :one
+INSERT INTO certificate(
+    fingerprint,
+    subject,
+    issuer,
+    serialnumber,
+    not_before,
+    not_after,
+    raw,
+    source,
+    first_seen,
+    last_seen
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+  ON CONFLICT (fingerprint) DO UPDATE SET
+    source = excluded.source,
+    last_seen = excluded.last_seen
+RETURNING id
+`
+
+type CreateCertificateParams struct {
+	Fingerprint  string
+	Subject      string
+	Issuer       string
+	Serialnumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	Raw          []byte
+	Source       string
+	FirstSeen    time.Time
+	LastSeen     time.Time
+}
+
+func (q *Queries) CreateCertificate(ctx context.Context, arg CreateCertificateParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createCertificate,
+		arg.Fingerprint,
+		arg.Subject,
+		arg.Issuer,
+		arg.Serialnumber,
+		arg.NotBefore,
+		arg.NotAfter,
+		arg.Raw,
+		arg.Source,
+		arg.FirstSeen,
+		arg.LastSeen,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteCertificate = `-- name: DeleteCertificate :exec
+DELETE FROM certificate
+WHERE id = $1
+`
+
+func (q *Queries) DeleteCertificate(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCertificate, id)
+	return err
+}
+
+const getCertificate = `-- name: GetCertificate :one
+SELECT cert.id, cert.fingerprint, cert.subject, cert.issuer, cert.serialnumber, cert.not_before, cert.not_after,
+       cert.raw, cert.source, cert.first_seen, cert.last_seen, crl.name AS revoked_by
+FROM certificate AS cert
+LEFT JOIN revoked_certificate AS revoked ON revoked.id = (
+    SELECT earliest.id FROM revoked_certificate AS earliest WHERE earliest.serialnumber = cert.serialnumber ORDER BY earliest.revocation_list LIMIT 1
+)
+LEFT JOIN certificate_revocation_list AS crl ON crl.id = revoked.revocation_list
+WHERE cert.fingerprint = $1
+`
+
+type GetCertificateRow struct {
+	ID           int64
+	Fingerprint  string
+	Subject      string
+	Issuer       string
+	Serialnumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	Raw          []byte
+	Source       string
+	FirstSeen    time.Time
+	LastSeen     time.Time
+	RevokedBy    sql.NullString
+}
+
+// a serial number on more than one CRL is shown with the first CRL that revoked it
+func (q *Queries) GetCertificate(ctx context.Context, fingerprint string) (GetCertificateRow, error) {
+	row := q.db.QueryRowContext(ctx, getCertificate, fingerprint)
+	var i GetCertificateRow
+	err := row.Scan(
+		&i.ID,
+		&i.Fingerprint,
+		&i.Subject,
+		&i.Issuer,
+		&i.Serialnumber,
+		&i.NotBefore,
+		&i.NotAfter,
+		&i.Raw,
+		&i.Source,
+		&i.FirstSeen,
+		&i.LastSeen,
+		&i.RevokedBy,
+	)
+	return i, err
+}
+
+const listCertificates = `-- name: ListCertificates :many
+SELECT cert.id, cert.fingerprint, cert.subject, cert.issuer, cert.serialnumber, cert.not_before, cert.not_after,
+       cert.raw, cert.source, cert.first_seen, cert.last_seen, crl.name AS revoked_by
+FROM certificate AS cert
+LEFT JOIN revoked_certificate AS revoked ON revoked.id = (
+    SELECT earliest.id FROM revoked_certificate AS earliest WHERE earliest.serialnumber = cert.serialnumber ORDER BY earliest.revocation_list LIMIT 1
+)
+LEFT JOIN certificate_revocation_list AS crl ON crl.id = revoked.revocation_list
+WHERE ($1::text IS NULL OR cert.issuer ILIKE '%' || $1::text || '%')
+  AND ($2::timestamptz IS NULL OR cert.not_after <= $2::timestamptz)
+  AND ($3::boolean IS NULL OR (crl.name IS NOT NULL) = $3::boolean)
+ORDER BY cert.not_after, cert.id
+`
+
+type ListCertificatesParams struct {
+	Issuer        sql.NullString
+	ExpiresBefore sql.NullTime
+	Revoked       sql.NullBool
+}
+
+type ListCertificatesRow struct {
+	ID           int64
+	Fingerprint  string
+	Subject      string
+	Issuer       string
+	Serialnumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	Raw          []byte
+	Source       string
+	FirstSeen    time.Time
+	LastSeen     time.Time
+	RevokedBy    sql.NullString
+}
+
+// a serial number on more than one CRL is shown with the first CRL that revoked it
+func (q *Queries) ListCertificates(ctx context.Context, arg ListCertificatesParams) ([]ListCertificatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCertificates, arg.Issuer, arg.ExpiresBefore, arg.Revoked)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCertificatesRow
+	for rows.Next() {
+		var i ListCertificatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Fingerprint,
+			&i.Subject,
+			&i.Issuer,
+			&i.Serialnumber,
+			&i.NotBefore,
+			&i.NotAfter,
+			&i.Raw,
+			&i.Source,
+			&i.FirstSeen,
+			&i.LastSeen,
+			&i.RevokedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
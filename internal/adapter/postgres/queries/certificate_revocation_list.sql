@@ -0,0 +1,32 @@
+-- name: CreateCertificateRevocationList :one
+INSERT INTO certificate_revocation_list(
+    name,
+    signature,
+    this_update,
+    next_update,
+    url,
+    raw,
+    last_refreshed,
+    unsigned
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+  ON CONFLICT (name, this_update) DO UPDATE SET
+    signature = excluded.signature,
+    next_update = excluded.next_update,
+    raw = excluded.raw,
+    last_refreshed = excluded.last_refreshed,
+    unsigned = excluded.unsigned
+RETURNING id;
+
+-- name: GetCertificateRevocationList :one
+SELECT id, name, signature, this_update, next_update, url, raw, last_refreshed, unsigned FROM certificate_revocation_list
+WHERE name = $1
+ORDER BY this_update DESC, id DESC
+LIMIT 1;
+
+-- name: ListCertificateRevocationLists :many
+SELECT id, name, signature, this_update, next_update, url, raw, last_refreshed, unsigned FROM certificate_revocation_list
+ORDER BY id;
+
+-- name: DeleteCertificateRevocationList :exec
+DELETE FROM certificate_revocation_list
+WHERE id = $1;
@@ -0,0 +1,131 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: certificate_revocation_list.sql
+
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createCertificateRevocationList = `-- name: CreateCertificateRevocationList :one
+INSERT INTO certificate_revocation_list(
+    name,
+    signature,
+    this_update,
+    next_update,
+    url,
+    raw,
+    last_refreshed,
+    unsigned
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+  ON CONFLICT (name, this_update) DO UPDATE SET
+    signature = excluded.signature,
+    next_update = excluded.next_update,
+    raw = excluded.raw,
+    last_refreshed = excluded.last_refreshed,
+    unsigned = excluded.unsigned
+RETURNING id
+`
+
+type CreateCertificateRevocationListParams struct {
+	Name          string
+	Signature     []byte
+	ThisUpdate    time.Time
+	NextUpdate    sql.NullTime
+	Url           sql.NullString
+	Raw           []byte
+	LastRefreshed sql.NullTime
+	Unsigned      bool
+}
+
+func (q *Queries) CreateCertificateRevocationList(ctx context.Context, arg CreateCertificateRevocationListParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createCertificateRevocationList,
+		arg.Name,
+		arg.Signature,
+		arg.ThisUpdate,
+		arg.NextUpdate,
+		arg.Url,
+		arg.Raw,
+		arg.LastRefreshed,
+		arg.Unsigned,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteCertificateRevocationList = `-- name: DeleteCertificateRevocationList :exec
+DELETE FROM certificate_revocation_list
+WHERE id = $1
+`
+
+func (q *Queries) DeleteCertificateRevocationList(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCertificateRevocationList, id)
+	return err
+}
+
+const getCertificateRevocationList = `-- name: GetCertificateRevocationList :one
+SELECT id, name, signature, this_update, next_update, url, raw, last_refreshed, unsigned FROM certificate_revocation_list
+WHERE name = $1
+ORDER BY this_update DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetCertificateRevocationList(ctx context.Context, name string) (CertificateRevocationList, error) {
+	row := q.db.QueryRowContext(ctx, getCertificateRevocationList, name)
+	var i CertificateRevocationList
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Signature,
+		&i.ThisUpdate,
+		&i.NextUpdate,
+		&i.Url,
+		&i.Raw,
+		&i.LastRefreshed,
+		&i.Unsigned,
+	)
+	return i, err
+}
+
+const listCertificateRevocationLists = `-- name: ListCertificateRevocationLists :many
+SELECT id, name, signature, this_update, next_update, url, raw, last_refreshed, unsigned FROM certificate_revocation_list
+ORDER BY id
+`
+
+func (q *Queries) ListCertificateRevocationLists(ctx context.Context) ([]CertificateRevocationList, error) {
+	rows, err := q.db.QueryContext(ctx, listCertificateRevocationLists)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CertificateRevocationList
+	for rows.Next() {
+		var i CertificateRevocationList
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Signature,
+			&i.ThisUpdate,
+			&i.NextUpdate,
+			&i.Url,
+			&i.Raw,
+			&i.LastRefreshed,
+			&i.Unsigned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
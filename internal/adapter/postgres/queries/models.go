@@ -0,0 +1,59 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+type AuditLog struct {
+	ID               int64
+	CheckedAt        time.Time
+	Method           string
+	Serialnumber     string
+	Subject          string
+	Issuer           string
+	Source           string
+	SourceThisUpdate sql.NullTime
+	Result           string
+	Detail           string
+	Origin           string
+}
+
+type Certificate struct {
+	ID           int64
+	Fingerprint  string
+	Subject      string
+	Issuer       string
+	Serialnumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	Raw          []byte
+	Source       string
+	FirstSeen    time.Time
+	LastSeen     time.Time
+}
+
+type CertificateRevocationList struct {
+	ID            int64
+	Name          string
+	Signature     []byte
+	ThisUpdate    time.Time
+	NextUpdate    sql.NullTime
+	Url           sql.NullString
+	Raw           []byte
+	LastRefreshed sql.NullTime
+	Unsigned      bool
+}
+
+type RevokedCertificate struct {
+	ID              int64
+	Serialnumber    string
+	RevocationDate  time.Time
+	Reason          string
+	RevocationList  int64
+	SerialnumberHex sql.NullString
+}
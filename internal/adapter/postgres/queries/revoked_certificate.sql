@@ -0,0 +1,69 @@
+-- name: CreateRevokedCertificates :exec
+INSERT INTO revoked_certificate(
+    serialnumber,
+    serialnumber_hex,
+    revocation_date,
+    reason,
+    revocation_list
+) VALUES (
+    $1,$2,$3,$4,$5
+)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteRevokedCertificatesByRevocationList :exec
+DELETE FROM revoked_certificate
+WHERE revocation_list = $1;
+
+-- name: GetRevokedCertificatesByRevocationList :many
+SELECT id, serialnumber, revocation_date, reason, revocation_list
+FROM revoked_certificate
+WHERE revocation_list = $1
+ORDER BY revocation_date;
+
+-- name: GetRevokedCertificate :one
+SELECT cert.id, cert.serialnumber, cert.reason, cert.revocation_date, cert.revocation_list, crl.name AS revoked_by
+FROM revoked_certificate as cert
+JOIN certificate_revocation_list AS crl ON crl.id = cert.revocation_list
+WHERE serialnumber = $1
+ORDER BY cert.revocation_list
+LIMIT 1;
+
+-- name: ListRevokedCertificates :many
+SELECT id, serialnumber, revocation_date, reason, revocation_list
+FROM revoked_certificate
+WHERE revocation_list = sqlc.arg(revocation_list)
+  AND (sqlc.narg(serialnumber_prefix)::text IS NULL OR serialnumber LIKE sqlc.narg(serialnumber_prefix)::text || '%')
+  AND (sqlc.narg(serialnumber_hex_prefix)::text IS NULL OR serialnumber_hex LIKE sqlc.narg(serialnumber_hex_prefix)::text || '%')
+  AND (sqlc.narg(reason)::text IS NULL OR reason = sqlc.narg(reason)::text)
+  AND (sqlc.narg(revoked_after)::timestamptz IS NULL OR revocation_date >= sqlc.narg(revoked_after)::timestamptz)
+  AND (sqlc.narg(revoked_before)::timestamptz IS NULL OR revocation_date < sqlc.narg(revoked_before)::timestamptz)
+ORDER BY revocation_date, id
+LIMIT sqlc.arg(lim) OFFSET sqlc.arg(off);
+
+-- name: CountRevokedCertificates :one
+SELECT COUNT(*)
+FROM revoked_certificate
+WHERE revocation_list = sqlc.arg(revocation_list)
+  AND (sqlc.narg(serialnumber_prefix)::text IS NULL OR serialnumber LIKE sqlc.narg(serialnumber_prefix)::text || '%')
+  AND (sqlc.narg(serialnumber_hex_prefix)::text IS NULL OR serialnumber_hex LIKE sqlc.narg(serialnumber_hex_prefix)::text || '%')
+  AND (sqlc.narg(reason)::text IS NULL OR reason = sqlc.narg(reason)::text)
+  AND (sqlc.narg(revoked_after)::timestamptz IS NULL OR revocation_date >= sqlc.narg(revoked_after)::timestamptz)
+  AND (sqlc.narg(revoked_before)::timestamptz IS NULL OR revocation_date < sqlc.narg(revoked_before)::timestamptz);
+
+-- name: ListRevokedCertificatesWithoutHex :many
+SELECT id, serialnumber
+FROM revoked_certificate
+WHERE serialnumber_hex IS NULL
+LIMIT $1;
+
+-- name: UpdateRevokedCertificateHex :exec
+UPDATE revoked_certificate
+SET serialnumber_hex = $1
+WHERE id = $2;
+
+-- name: GetRevokedCertificatesBySerialNumber :many
+SELECT cert.id, cert.serialnumber, cert.reason, cert.revocation_date, cert.revocation_list, crl.name AS revoked_by
+FROM revoked_certificate as cert
+JOIN certificate_revocation_list AS crl ON crl.id = cert.revocation_list
+WHERE serialnumber = $1
+ORDER BY cert.revocation_list;
@@ -0,0 +1,332 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: revoked_certificate.sql
+
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const countRevokedCertificates = `-- name: CountRevokedCertificates :one
+SELECT COUNT(*)
+FROM revoked_certificate
+WHERE revocation_list = $1
+  AND ($2::text IS NULL OR serialnumber LIKE $2::text || '%')
+  AND ($3::text IS NULL OR serialnumber_hex LIKE $3::text || '%')
+  AND ($4::text IS NULL OR reason = $4::text)
+  AND ($5::timestamptz IS NULL OR revocation_date >= $5::timestamptz)
+  AND ($6::timestamptz IS NULL OR revocation_date < $6::timestamptz)
+`
+
+type CountRevokedCertificatesParams struct {
+	RevocationList        int64
+	SerialnumberPrefix    sql.NullString
+	SerialnumberHexPrefix sql.NullString
+	Reason                sql.NullString
+	RevokedAfter          sql.NullTime
+	RevokedBefore         sql.NullTime
+}
+
+func (q *Queries) CountRevokedCertificates(ctx context.Context, arg CountRevokedCertificatesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRevokedCertificates,
+		arg.RevocationList,
+		arg.SerialnumberPrefix,
+		arg.SerialnumberHexPrefix,
+		arg.Reason,
+		arg.RevokedAfter,
+		arg.RevokedBefore,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createRevokedCertificates = `-- name: CreateRevokedCertificates :exec
+INSERT INTO revoked_certificate(
+    serialnumber,
+    serialnumber_hex,
+    revocation_date,
+    reason,
+    revocation_list
+) VALUES (
+    $1,$2,$3,$4,$5
+)
+ON CONFLICT DO NOTHING
+`
+
+type CreateRevokedCertificatesParams struct {
+	Serialnumber    string
+	SerialnumberHex sql.NullString
+	RevocationDate  time.Time
+	Reason          string
+	RevocationList  int64
+}
+
+func (q *Queries) CreateRevokedCertificates(ctx context.Context, arg CreateRevokedCertificatesParams) error {
+	_, err := q.db.ExecContext(ctx, createRevokedCertificates,
+		arg.Serialnumber,
+		arg.SerialnumberHex,
+		arg.RevocationDate,
+		arg.Reason,
+		arg.RevocationList,
+	)
+	return err
+}
+
+const deleteRevokedCertificatesByRevocationList = `-- name: DeleteRevokedCertificatesByRevocationList :exec
+DELETE FROM revoked_certificate
+WHERE revocation_list = $1
+`
+
+func (q *Queries) DeleteRevokedCertificatesByRevocationList(ctx context.Context, revocationList int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRevokedCertificatesByRevocationList, revocationList)
+	return err
+}
+
+const getRevokedCertificate = `-- name: GetRevokedCertificate :one
+SELECT cert.id, cert.serialnumber, cert.reason, cert.revocation_date, cert.revocation_list, crl.name AS revoked_by
+FROM revoked_certificate as cert
+JOIN certificate_revocation_list AS crl ON crl.id = cert.revocation_list
+WHERE serialnumber = $1
+ORDER BY cert.revocation_list
+LIMIT 1
+`
+
+type GetRevokedCertificateRow struct {
+	ID             int64
+	Serialnumber   string
+	Reason         string
+	RevocationDate time.Time
+	RevocationList int64
+	RevokedBy      string
+}
+
+func (q *Queries) GetRevokedCertificate(ctx context.Context, serialnumber string) (GetRevokedCertificateRow, error) {
+	row := q.db.QueryRowContext(ctx, getRevokedCertificate, serialnumber)
+	var i GetRevokedCertificateRow
+	err := row.Scan(
+		&i.ID,
+		&i.Serialnumber,
+		&i.Reason,
+		&i.RevocationDate,
+		&i.RevocationList,
+		&i.RevokedBy,
+	)
+	return i, err
+}
+
+const getRevokedCertificatesByRevocationList = `-- name: GetRevokedCertificatesByRevocationList :many
+SELECT id, serialnumber, revocation_date, reason, revocation_list
+FROM revoked_certificate
+WHERE revocation_list = $1
+ORDER BY revocation_date
+`
+
+type GetRevokedCertificatesByRevocationListRow struct {
+	ID             int64
+	Serialnumber   string
+	RevocationDate time.Time
+	Reason         string
+	RevocationList int64
+}
+
+func (q *Queries) GetRevokedCertificatesByRevocationList(ctx context.Context, revocationList int64) ([]GetRevokedCertificatesByRevocationListRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRevokedCertificatesByRevocationList, revocationList)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRevokedCertificatesByRevocationListRow
+	for rows.Next() {
+		var i GetRevokedCertificatesByRevocationListRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Serialnumber,
+			&i.RevocationDate,
+			&i.Reason,
+			&i.RevocationList,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRevokedCertificatesBySerialNumber = `-- name: GetRevokedCertificatesBySerialNumber :many
+SELECT cert.id, cert.serialnumber, cert.reason, cert.revocation_date, cert.revocation_list, crl.name AS revoked_by
+FROM revoked_certificate as cert
+JOIN certificate_revocation_list AS crl ON crl.id = cert.revocation_list
+WHERE serialnumber = $1
+ORDER BY cert.revocation_list
+`
+
+type GetRevokedCertificatesBySerialNumberRow struct {
+	ID             int64
+	Serialnumber   string
+	Reason         string
+	RevocationDate time.Time
+	RevocationList int64
+	RevokedBy      string
+}
+
+func (q *Queries) GetRevokedCertificatesBySerialNumber(ctx context.Context, serialnumber string) ([]GetRevokedCertificatesBySerialNumberRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRevokedCertificatesBySerialNumber, serialnumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRevokedCertificatesBySerialNumberRow
+	for rows.Next() {
+		var i GetRevokedCertificatesBySerialNumberRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Serialnumber,
+			&i.Reason,
+			&i.RevocationDate,
+			&i.RevocationList,
+			&i.RevokedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRevokedCertificates = `-- name: ListRevokedCertificates :many
+SELECT id, serialnumber, revocation_date, reason, revocation_list
+FROM revoked_certificate
+WHERE revocation_list = $1
+  AND ($2::text IS NULL OR serialnumber LIKE $2::text || '%')
+  AND ($3::text IS NULL OR serialnumber_hex LIKE $3::text || '%')
+  AND ($4::text IS NULL OR reason = $4::text)
+  AND ($5::timestamptz IS NULL OR revocation_date >= $5::timestamptz)
+  AND ($6::timestamptz IS NULL OR revocation_date < $6::timestamptz)
+ORDER BY revocation_date, id
+LIMIT $8 OFFSET $7
+`
+
+type ListRevokedCertificatesParams struct {
+	RevocationList        int64
+	SerialnumberPrefix    sql.NullString
+	SerialnumberHexPrefix sql.NullString
+	Reason                sql.NullString
+	RevokedAfter          sql.NullTime
+	RevokedBefore         sql.NullTime
+	Off                   int32
+	Lim                   int32
+}
+
+type ListRevokedCertificatesRow struct {
+	ID             int64
+	Serialnumber   string
+	RevocationDate time.Time
+	Reason         string
+	RevocationList int64
+}
+
+func (q *Queries) ListRevokedCertificates(ctx context.Context, arg ListRevokedCertificatesParams) ([]ListRevokedCertificatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRevokedCertificates,
+		arg.RevocationList,
+		arg.SerialnumberPrefix,
+		arg.SerialnumberHexPrefix,
+		arg.Reason,
+		arg.RevokedAfter,
+		arg.RevokedBefore,
+		arg.Off,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRevokedCertificatesRow
+	for rows.Next() {
+		var i ListRevokedCertificatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Serialnumber,
+			&i.RevocationDate,
+			&i.Reason,
+			&i.RevocationList,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRevokedCertificatesWithoutHex = `-- name: ListRevokedCertificatesWithoutHex :many
+SELECT id, serialnumber
+FROM revoked_certificate
+WHERE serialnumber_hex IS NULL
+LIMIT $1
+`
+
+type ListRevokedCertificatesWithoutHexRow struct {
+	ID           int64
+	Serialnumber string
+}
+
+func (q *Queries) ListRevokedCertificatesWithoutHex(ctx context.Context, limit int32) ([]ListRevokedCertificatesWithoutHexRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRevokedCertificatesWithoutHex, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRevokedCertificatesWithoutHexRow
+	for rows.Next() {
+		var i ListRevokedCertificatesWithoutHexRow
+		if err := rows.Scan(&i.ID, &i.Serialnumber); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateRevokedCertificateHex = `-- name: UpdateRevokedCertificateHex :exec
+UPDATE revoked_certificate
+SET serialnumber_hex = $1
+WHERE id = $2
+`
+
+type UpdateRevokedCertificateHexParams struct {
+	SerialnumberHex sql.NullString
+	ID              int64
+}
+
+func (q *Queries) UpdateRevokedCertificateHex(ctx context.Context, arg UpdateRevokedCertificateHexParams) error {
+	_, err := q.db.ExecContext(ctx, updateRevokedCertificateHex, arg.SerialnumberHex, arg.ID)
+	return err
+}
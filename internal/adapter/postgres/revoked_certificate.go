@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/pimg/certguard/internal/adapter/postgres/queries"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// FindRevokedCertificates finds all revoked certificates in a CRL ordered by revocation date
+func (s *PostgresStorage) FindRevokedCertificates(ctx context.Context, revocationListID int64) ([]*crl.RevokedCertificate, error) {
+	log.Printf("find revoked certificates in CRL: %d", revocationListID)
+	dbRevCerts, err := s.Queries.GetRevokedCertificatesByRevocationList(ctx, revocationListID)
+	if err != nil {
+		return nil, err
+	}
+
+	revokedCertificates := make([]*crl.RevokedCertificate, len(dbRevCerts))
+	for i, revokedCertificate := range dbRevCerts {
+		revokedCertificates[i] = &crl.RevokedCertificate{
+			SerialNumber:     revokedCertificate.Serialnumber,
+			RevocationReason: crl.RevocationReason(revokedCertificate.Reason),
+			RevocationDate:   revokedCertificate.RevocationDate,
+			RevocationListID: revokedCertificate.RevocationList,
+		}
+	}
+
+	return revokedCertificates, nil
+}
+
+// ListRevokedCertificates lists a page of the revoked certificates in a CRL that match the filter, ordered by revocation date
+func (s *PostgresStorage) ListRevokedCertificates(ctx context.Context, revocationListID int64, filter crl.RevokedCertificateFilter, offset, limit int) ([]*crl.RevokedCertificate, error) {
+	params := queries.ListRevokedCertificatesParams{
+		RevocationList: revocationListID,
+		Off:            int32(offset),
+		Lim:            int32(limit),
+	}
+	params.SerialnumberPrefix, params.SerialnumberHexPrefix, params.Reason, params.RevokedAfter, params.RevokedBefore = revokedCertificateFilterParams(filter)
+
+	dbRevCerts, err := s.Queries.ListRevokedCertificates(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	revokedCertificates := make([]*crl.RevokedCertificate, len(dbRevCerts))
+	for i, revokedCertificate := range dbRevCerts {
+		revokedCertificates[i] = &crl.RevokedCertificate{
+			SerialNumber:     revokedCertificate.Serialnumber,
+			RevocationReason: crl.RevocationReason(revokedCertificate.Reason),
+			RevocationDate:   revokedCertificate.RevocationDate,
+			RevocationListID: revokedCertificate.RevocationList,
+		}
+	}
+
+	return revokedCertificates, nil
+}
+
+// CountRevokedCertificates counts the revoked certificates in a CRL that match the filter
+func (s *PostgresStorage) CountRevokedCertificates(ctx context.Context, revocationListID int64, filter crl.RevokedCertificateFilter) (int, error) {
+	params := queries.CountRevokedCertificatesParams{RevocationList: revocationListID}
+	params.SerialnumberPrefix, params.SerialnumberHexPrefix, params.Reason, params.RevokedAfter, params.RevokedBefore = revokedCertificateFilterParams(filter)
+
+	count, err := s.Queries.CountRevokedCertificates(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// revokedCertificateFilterParams converts the filter to query parameters, invalid parameters are not filtered on
+func revokedCertificateFilterParams(filter crl.RevokedCertificateFilter) (serialNumberPrefix, serialNumberHexPrefix, reason sql.NullString, revokedAfter, revokedBefore sql.NullTime) {
+	if filter.SerialNumberPrefix != "" {
+		if filter.SerialNumberHex {
+			serialNumberHexPrefix = sql.NullString{String: filter.SerialNumberPrefix, Valid: true}
+		} else {
+			serialNumberPrefix = sql.NullString{String: filter.SerialNumberPrefix, Valid: true}
+		}
+	}
+	if filter.Reason != "" {
+		reason = sql.NullString{String: filter.Reason.String(), Valid: true}
+	}
+	if !filter.RevokedAfter.IsZero() {
+		revokedAfter = sql.NullTime{Time: filter.RevokedAfter, Valid: true}
+	}
+	if !filter.RevokedBefore.IsZero() {
+		revokedBefore = sql.NullTime{Time: filter.RevokedBefore, Valid: true}
+	}
+	return serialNumberPrefix, serialNumberHexPrefix, reason, revokedAfter, revokedBefore
+}
+
+// FindRevokedCertificate finds a revoked certificate by serial number, nil is returned when the certificate is not revoked
+func (s *PostgresStorage) FindRevokedCertificate(ctx context.Context, serialnumber string) (*crl.RevokedCertificate, error) {
+	log.Printf("find revoked certificate by serial number: %s", serialnumber)
+	dbRevokedCertificate, err := s.Queries.GetRevokedCertificate(ctx, serialnumber)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &crl.RevokedCertificate{
+		SerialNumber:     dbRevokedCertificate.Serialnumber,
+		RevocationReason: crl.RevocationReason(dbRevokedCertificate.Reason),
+		RevocationDate:   dbRevokedCertificate.RevocationDate,
+		RevocationListID: dbRevokedCertificate.RevocationList,
+		RevokedBy:        dbRevokedCertificate.RevokedBy,
+	}, nil
+}
+
+// FindRevokedCertificatesBySerialNumber finds the revoked certificates with the serial number on all stored CRLs
+func (s *PostgresStorage) FindRevokedCertificatesBySerialNumber(ctx context.Context, serialnumber string) ([]*crl.RevokedCertificate, error) {
+	log.Printf("find revoked certificates by serial number: %s", serialnumber)
+	dbRevCerts, err := s.Queries.GetRevokedCertificatesBySerialNumber(ctx, serialnumber)
+	if err != nil {
+		return nil, err
+	}
+
+	revokedCertificates := make([]*crl.RevokedCertificate, len(dbRevCerts))
+	for i, revokedCertificate := range dbRevCerts {
+		revokedCertificates[i] = &crl.RevokedCertificate{
+			SerialNumber:     revokedCertificate.Serialnumber,
+			RevocationReason: crl.RevocationReason(revokedCertificate.Reason),
+			RevocationDate:   revokedCertificate.RevocationDate,
+			RevocationListID: revokedCertificate.RevocationList,
+			RevokedBy:        revokedCertificate.RevokedBy,
+		}
+	}
+
+	return revokedCertificates, nil
+}
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS certificate_revocation_list (
+    id bigserial primary key,
+    name text unique not null,
+    signature bytea unique not null,
+    this_update timestamptz not null,
+    next_update timestamptz,
+    url text,
+    raw bytea
+);
+
+CREATE TABLE IF NOT EXISTS revoked_certificate (
+    id bigserial primary key,
+    serialnumber text unique not null,
+    revocation_date timestamptz not null,
+    reason text not null,
+    revocation_list bigint not null,
+    foreign key (revocation_list) references certificate_revocation_list(id)
+       ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_revocation_list
+    ON revoked_certificate(revocation_list);
+
+-- +migrate Down
+DROP TABLE revoked_certificate;
+
+DROP TABLE certificate_revocation_list;
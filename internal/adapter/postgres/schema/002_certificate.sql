@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS certificate (
+    id bigserial primary key,
+    fingerprint text unique not null,
+    subject text not null,
+    issuer text not null,
+    serialnumber text not null,
+    not_before timestamptz not null,
+    not_after timestamptz not null,
+    raw bytea not null,
+    source text not null,
+    first_seen timestamptz not null,
+    last_seen timestamptz not null
+);
+
+CREATE INDEX IF NOT EXISTS idx_certificate_serialnumber
+    ON certificate(serialnumber);
+
+CREATE INDEX IF NOT EXISTS idx_certificate_not_after
+    ON certificate(not_after);
+
+-- +migrate Down
+DROP TABLE certificate;
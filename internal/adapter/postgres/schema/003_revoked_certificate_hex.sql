@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE revoked_certificate ADD COLUMN IF NOT EXISTS serialnumber_hex text;
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber_hex
+    ON revoked_certificate(serialnumber_hex text_pattern_ops);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_revocation_list_date
+    ON revoked_certificate(revocation_list, revocation_date);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_revoked_certificate_revocation_list_date;
+
+DROP INDEX IF EXISTS idx_revoked_certificate_serialnumber_hex;
+
+ALTER TABLE revoked_certificate DROP COLUMN IF EXISTS serialnumber_hex;
@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE certificate_revocation_list ADD COLUMN IF NOT EXISTS last_refreshed timestamptz;
+
+-- CRLs stored before the column existed count as refreshed when the column is added, so they are not pruned right away
+UPDATE certificate_revocation_list SET last_refreshed = now() WHERE last_refreshed IS NULL;
+
+-- +migrate Down
+ALTER TABLE certificate_revocation_list DROP COLUMN IF EXISTS last_refreshed;
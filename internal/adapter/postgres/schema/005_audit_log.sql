@@ -0,0 +1,41 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS audit_log (
+    id bigserial primary key,
+    checked_at timestamptz not null,
+    method text not null,
+    serialnumber text not null,
+    subject text not null,
+    issuer text not null,
+    source text not null,
+    source_this_update timestamptz,
+    result text not null,
+    detail text not null,
+    origin text not null
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_checked_at
+    ON audit_log(checked_at);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_serialnumber
+    ON audit_log(serialnumber);
+
+-- the audit log is append-only
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+
+DROP FUNCTION IF EXISTS audit_log_append_only();
+
+DROP TABLE IF EXISTS audit_log;
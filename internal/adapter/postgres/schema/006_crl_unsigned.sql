@@ -0,0 +1,6 @@
+-- +migrate Up
+-- unsigned marks a pseudo-CRL imported from a CA database, its revoked certificates are not signed by the CA
+ALTER TABLE certificate_revocation_list ADD COLUMN IF NOT EXISTS unsigned boolean NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE certificate_revocation_list DROP COLUMN IF EXISTS unsigned;
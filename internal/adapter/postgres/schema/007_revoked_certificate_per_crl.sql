@@ -0,0 +1,21 @@
+-- +migrate Up
+-- a serial number is unique per CRL instead of across all CRLs, CAs can revoke certificates with the same serial number
+ALTER TABLE revoked_certificate DROP CONSTRAINT IF EXISTS revoked_certificate_serialnumber_key;
+
+ALTER TABLE revoked_certificate ADD CONSTRAINT revoked_certificate_revocation_list_serialnumber_key
+    UNIQUE (revocation_list, serialnumber);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_certificate_serialnumber
+    ON revoked_certificate(serialnumber);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_revoked_certificate_serialnumber;
+
+ALTER TABLE revoked_certificate DROP CONSTRAINT IF EXISTS revoked_certificate_revocation_list_serialnumber_key;
+
+-- serial numbers that are on more than one CRL keep the revocation of the first CRL
+DELETE FROM revoked_certificate AS later
+USING revoked_certificate AS earlier
+WHERE later.serialnumber = earlier.serialnumber AND later.revocation_list > earlier.revocation_list;
+
+ALTER TABLE revoked_certificate ADD CONSTRAINT revoked_certificate_serialnumber_key UNIQUE (serialnumber);
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- a CRL is stored once per version, the name and this update of a CRL are unique instead of the name
+ALTER TABLE certificate_revocation_list DROP CONSTRAINT IF EXISTS certificate_revocation_list_name_key;
+
+ALTER TABLE certificate_revocation_list ADD CONSTRAINT certificate_revocation_list_name_this_update_key
+    UNIQUE (name, this_update);
+
+-- +migrate Down
+ALTER TABLE certificate_revocation_list DROP CONSTRAINT IF EXISTS certificate_revocation_list_name_this_update_key;
+
+-- only the latest version of every CRL is kept, the revoked certificates of the older versions are removed with them
+DELETE FROM certificate_revocation_list AS older
+USING certificate_revocation_list AS newer
+WHERE newer.name = older.name
+  AND (newer.this_update > older.this_update OR (newer.this_update = older.this_update AND newer.id > older.id));
+
+ALTER TABLE certificate_revocation_list ADD CONSTRAINT certificate_revocation_list_name_key UNIQUE (name);
@@ -0,0 +1,589 @@
+// Package storagetest contains the conformance tests every storage backend of crl.Repository has to pass
+package storagetest
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+// NewRepository creates an empty repository for a single test, the repository is cleaned up by the test
+type NewRepository func(t *testing.T) crl.Repository
+
+// Run runs the conformance tests against repositories created by newRepository
+func Run(t *testing.T, newRepository NewRepository) {
+	t.Helper()
+	tests := map[string]func(t *testing.T, repository crl.Repository){
+		"SaveAndFindCRL":          testSaveAndFindCRL,
+		"CRLVersions":             testCRLVersions,
+		"ListAndDeleteCRLs":       testListAndDeleteCRLs,
+		"UnsignedCRL":             testUnsignedCRL,
+		"RevokedCertificates":     testRevokedCertificates,
+		"ResavedCRLEntries":       testResavedCRLEntries,
+		"SerialNumberOnTwoCRLs":   testSerialNumberOnTwoCRLs,
+		"ListRevokedCertificates": testListRevokedCertificates,
+		"InvalidRevocationReason": testInvalidRevocationReason,
+		"SaveCertificate":         testSaveCertificate,
+		"ListCertificates":        testListCertificates,
+		"DeleteCertificate":       testDeleteCertificate,
+		"AuditLog":                testAuditLog,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, newRepository(t))
+		})
+	}
+}
+
+// now is truncated to seconds, the precision of the libsql backends
+var now = time.Now().UTC().Truncate(time.Second)
+
+func revocationList(name string) *crl.CertificateRevocationList {
+	URL, _ := url.Parse("http://crl.example.com/" + name + ".crl")
+	return &crl.CertificateRevocationList{
+		Name:          name,
+		Signature:     []byte("signature of " + name),
+		ThisUpdate:    now.Add(-time.Hour),
+		NextUpdate:    now.Add(24 * time.Hour),
+		Raw:           []byte("raw " + name),
+		URL:           URL,
+		LastRefreshed: now.Add(-time.Minute),
+	}
+}
+
+func storedCertificate(fingerprint, issuer string, serialNumber int64, notAfter time.Time) *crl.StoredCertificate {
+	return &crl.StoredCertificate{
+		Fingerprint:  fingerprint,
+		Subject:      "CN=" + fingerprint,
+		Issuer:       issuer,
+		SerialNumber: big.NewInt(serialNumber).String(),
+		NotBefore:    now.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+		Raw:          []byte("raw " + fingerprint),
+		Source:       "input",
+		FirstSeen:    now,
+		LastSeen:     now,
+	}
+}
+
+func revocationEntries(reason int, serialNumbers ...int64) []x509.RevocationListEntry {
+	entries := make([]x509.RevocationListEntry, len(serialNumbers))
+	for i, serialNumber := range serialNumbers {
+		entries[i] = x509.RevocationListEntry{
+			SerialNumber:   big.NewInt(serialNumber),
+			RevocationTime: now.Add(-time.Duration(len(serialNumbers)-i) * time.Hour),
+			ReasonCode:     reason,
+		}
+	}
+	return entries
+}
+
+func testSaveAndFindCRL(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	saved := revocationList("ca")
+
+	id, err := repository.Save(ctx, saved)
+	assert.NoError(t, err)
+	assert.Positive(t, id)
+
+	found, err := repository.Find(ctx, "ca")
+	assert.NoError(t, err)
+	assert.Equal(t, id, found.ID)
+	assert.Equal(t, saved.Name, found.Name)
+	assert.Equal(t, saved.Signature, found.Signature)
+	assert.Equal(t, saved.Raw, found.Raw)
+	assert.WithinDuration(t, saved.ThisUpdate, found.ThisUpdate, time.Second)
+	assert.WithinDuration(t, saved.NextUpdate, found.NextUpdate, time.Second)
+	assert.WithinDuration(t, saved.LastRefreshed, found.LastRefreshed, time.Second)
+	assert.False(t, found.Unsigned)
+
+	// saving a known version of a CRL replaces the stored version, the raw CRL that is exported is the latest CRL
+	updated := revocationList("ca")
+	updated.Signature = []byte("signature of the next ca")
+	updated.Raw = []byte("raw next ca")
+	updated.Unsigned = true
+	updated.NextUpdate = now.Add(48 * time.Hour)
+	updated.LastRefreshed = now
+	updatedID, err := repository.Save(ctx, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, id, updatedID)
+
+	found, err = repository.Find(ctx, "ca")
+	assert.NoError(t, err)
+	assert.WithinDuration(t, updated.ThisUpdate, found.ThisUpdate, time.Second)
+	assert.WithinDuration(t, updated.NextUpdate, found.NextUpdate, time.Second)
+	assert.WithinDuration(t, updated.LastRefreshed, found.LastRefreshed, time.Second)
+	assert.Equal(t, updated.Signature, found.Signature)
+	assert.Equal(t, updated.Raw, found.Raw)
+	assert.True(t, found.Unsigned)
+
+	_, err = repository.Find(ctx, "unknown")
+	assert.ErrorIs(t, err, crl.ErrNotFound)
+}
+
+func testCRLVersions(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	previous := revocationList("ca")
+	previousID, err := repository.Save(ctx, previous)
+	assert.NoError(t, err)
+
+	// a CRL with a new this update is stored as a new version, the previous version is kept for the retention policy
+	latest := revocationList("ca")
+	latest.Signature = []byte("signature of the next ca")
+	latest.ThisUpdate = now
+	latestID, err := repository.Save(ctx, latest)
+	assert.NoError(t, err)
+	assert.NotEqual(t, previousID, latestID)
+
+	found, err := repository.Find(ctx, "ca")
+	assert.NoError(t, err)
+	assert.Equal(t, latestID, found.ID)
+	assert.Equal(t, latest.Signature, found.Signature)
+
+	revocationLists, err := repository.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, revocationLists, 2)
+
+	// without the latest version the previous version is found
+	assert.NoError(t, repository.Delete(ctx, latestID))
+	found, err = repository.Find(ctx, "ca")
+	assert.NoError(t, err)
+	assert.Equal(t, previousID, found.ID)
+}
+
+func testListAndDeleteCRLs(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	ids := make([]int64, 3)
+	for i := range ids {
+		id, err := repository.Save(ctx, revocationList(fmt.Sprintf("ca-%d", i)))
+		assert.NoError(t, err)
+		ids[i] = id
+	}
+
+	revocationLists, err := repository.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, revocationLists, 3)
+	for i, revocationList := range revocationLists {
+		assert.Equal(t, ids[i], revocationList.ID)
+		assert.Equal(t, fmt.Sprintf("ca-%d", i), revocationList.Name)
+		assert.Equal(t, fmt.Sprintf("http://crl.example.com/ca-%d.crl", i), revocationList.URL.String())
+		assert.WithinDuration(t, now.Add(-time.Minute), revocationList.LastRefreshed, time.Second)
+	}
+
+	assert.NoError(t, repository.Delete(ctx, ids[1]))
+
+	revocationLists, err = repository.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, revocationLists, 2)
+	assert.Equal(t, "ca-0", revocationLists[0].Name)
+	assert.Equal(t, "ca-2", revocationLists[1].Name)
+}
+
+func testUnsignedCRL(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	unsigned := revocationList("openssl-ca")
+	unsigned.Unsigned = true
+	unsigned.Raw = nil
+	unsigned.URL = nil
+	_, err := repository.Save(ctx, unsigned)
+	assert.NoError(t, err)
+
+	found, err := repository.Find(ctx, "openssl-ca")
+	assert.NoError(t, err)
+	assert.True(t, found.Unsigned)
+	assert.Empty(t, found.Raw)
+
+	revocationLists, err := repository.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, revocationLists, 1)
+	assert.True(t, revocationLists[0].Unsigned)
+}
+
+func testRevokedCertificates(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	id, err := repository.Save(ctx, revocationList("ca"))
+	assert.NoError(t, err)
+
+	saved, err := repository.SaveRevokedCertificates(ctx, id, revocationEntries(1, 10, 11, 12))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, saved)
+
+	revokedCertificates, err := repository.FindRevokedCertificates(ctx, id)
+	assert.NoError(t, err)
+	assert.Len(t, revokedCertificates, 3)
+	for i, revokedCertificate := range revokedCertificates {
+		assert.Equal(t, big.NewInt(int64(10+i)).String(), revokedCertificate.SerialNumber)
+		assert.Equal(t, crl.RevocationReasonKeyCompromise, revokedCertificate.RevocationReason)
+		assert.Equal(t, id, revokedCertificate.RevocationListID)
+	}
+
+	revokedCertificate, err := repository.FindRevokedCertificate(ctx, "11")
+	assert.NoError(t, err)
+	assert.Equal(t, "11", revokedCertificate.SerialNumber)
+	assert.Equal(t, "ca", revokedCertificate.RevokedBy)
+	assert.Equal(t, id, revokedCertificate.RevocationListID)
+	assert.Equal(t, crl.RevocationReasonKeyCompromise, revokedCertificate.RevocationReason)
+	assert.WithinDuration(t, now.Add(-2*time.Hour), revokedCertificate.RevocationDate, time.Second)
+
+	revokedCertificate, err = repository.FindRevokedCertificate(ctx, "99")
+	assert.NoError(t, err)
+	assert.Nil(t, revokedCertificate)
+
+	revokedCertificates, err = repository.FindRevokedCertificatesBySerialNumber(ctx, "12")
+	assert.NoError(t, err)
+	assert.Len(t, revokedCertificates, 1)
+	assert.Equal(t, "12", revokedCertificates[0].SerialNumber)
+	assert.Equal(t, "ca", revokedCertificates[0].RevokedBy)
+	assert.Equal(t, id, revokedCertificates[0].RevocationListID)
+	assert.WithinDuration(t, now.Add(-time.Hour), revokedCertificates[0].RevocationDate, time.Second)
+
+	revokedCertificates, err = repository.FindRevokedCertificatesBySerialNumber(ctx, "99")
+	assert.NoError(t, err)
+	assert.Empty(t, revokedCertificates)
+
+	// revoked certificates are no longer found once their CRL is deleted
+	assert.NoError(t, repository.Delete(ctx, id))
+	revokedCertificate, err = repository.FindRevokedCertificate(ctx, "11")
+	assert.NoError(t, err)
+	assert.Nil(t, revokedCertificate)
+}
+
+func testResavedCRLEntries(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	id, err := repository.Save(ctx, revocationList("ca"))
+	assert.NoError(t, err)
+	_, err = repository.SaveRevokedCertificates(ctx, id, revocationEntries(1, 42, 43))
+	assert.NoError(t, err)
+
+	resavedID, err := repository.Save(ctx, revocationList("ca"))
+	assert.NoError(t, err)
+	assert.Equal(t, id, resavedID)
+	_, err = repository.SaveRevokedCertificates(ctx, resavedID, revocationEntries(3, 43))
+	assert.NoError(t, err)
+
+	revokedCertificates, err := repository.FindRevokedCertificates(ctx, id)
+	assert.NoError(t, err)
+	assert.Len(t, revokedCertificates, 1)
+	assert.Equal(t, "43", revokedCertificates[0].SerialNumber)
+	assert.Equal(t, crl.RevocationReasonAffiliationChanged, revokedCertificates[0].RevocationReason)
+
+	revokedCertificates, err = repository.FindRevokedCertificatesBySerialNumber(ctx, "42")
+	assert.NoError(t, err)
+	assert.Empty(t, revokedCertificates)
+}
+
+func testSerialNumberOnTwoCRLs(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	id, err := repository.Save(ctx, revocationList("ca"))
+	assert.NoError(t, err)
+	otherID, err := repository.Save(ctx, revocationList("other"))
+	assert.NoError(t, err)
+
+	// two CAs revoked a certificate with the same serial number
+	_, err = repository.SaveRevokedCertificates(ctx, id, revocationEntries(1, 42))
+	assert.NoError(t, err)
+	_, err = repository.SaveRevokedCertificates(ctx, otherID, revocationEntries(4, 42))
+	assert.NoError(t, err)
+	// a serial number is stored once per CRL
+	_, err = repository.SaveRevokedCertificates(ctx, otherID, revocationEntries(4, 42))
+	assert.NoError(t, err)
+
+	revokedCertificates, err := repository.FindRevokedCertificatesBySerialNumber(ctx, "42")
+	assert.NoError(t, err)
+	assert.Len(t, revokedCertificates, 2)
+	assert.Equal(t, "ca", revokedCertificates[0].RevokedBy)
+	assert.Equal(t, crl.RevocationReasonKeyCompromise, revokedCertificates[0].RevocationReason)
+	assert.Equal(t, "other", revokedCertificates[1].RevokedBy)
+	assert.Equal(t, otherID, revokedCertificates[1].RevocationListID)
+	assert.Equal(t, crl.RevocationReasonSuperseded, revokedCertificates[1].RevocationReason)
+
+	for _, revocationListID := range []int64{id, otherID} {
+		count, err := repository.CountRevokedCertificates(ctx, revocationListID, crl.RevokedCertificateFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	}
+
+	revokedCertificate, err := repository.FindRevokedCertificate(ctx, "42")
+	assert.NoError(t, err)
+	assert.Equal(t, "ca", revokedCertificate.RevokedBy)
+
+	// the certificate is listed once in the inventory
+	_, err = repository.SaveCertificate(ctx, storedCertificate("shared", "CN=ca", 42, now.Add(24*time.Hour)))
+	assert.NoError(t, err)
+	certificates, err := repository.ListCertificates(ctx, crl.CertificateFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 1)
+	assert.Equal(t, "ca", certificates[0].RevokedBy)
+
+	// deleting one CRL keeps the revocation of the other CRL
+	assert.NoError(t, repository.Delete(ctx, id))
+	revokedCertificates, err = repository.FindRevokedCertificatesBySerialNumber(ctx, "42")
+	assert.NoError(t, err)
+	assert.Len(t, revokedCertificates, 1)
+	assert.Equal(t, "other", revokedCertificates[0].RevokedBy)
+}
+
+func testListRevokedCertificates(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	id, err := repository.Save(ctx, revocationList("ca"))
+	assert.NoError(t, err)
+	otherID, err := repository.Save(ctx, revocationList("other"))
+	assert.NoError(t, err)
+
+	// serial numbers in order of revocation, with their hex notation: a, b, ff, 1000, 3e8, c
+	entries := []x509.RevocationListEntry{
+		{SerialNumber: big.NewInt(10), RevocationTime: now.Add(-6 * time.Hour), ReasonCode: 1},
+		{SerialNumber: big.NewInt(11), RevocationTime: now.Add(-5 * time.Hour), ReasonCode: 1},
+		{SerialNumber: big.NewInt(255), RevocationTime: now.Add(-4 * time.Hour), ReasonCode: 4},
+		{SerialNumber: big.NewInt(4096), RevocationTime: now.Add(-3 * time.Hour), ReasonCode: 1},
+		{SerialNumber: big.NewInt(1000), RevocationTime: now.Add(-2 * time.Hour), ReasonCode: 4},
+		{SerialNumber: big.NewInt(12), RevocationTime: now.Add(-1 * time.Hour), ReasonCode: 1},
+	}
+	_, err = repository.SaveRevokedCertificates(ctx, id, entries)
+	assert.NoError(t, err)
+	_, err = repository.SaveRevokedCertificates(ctx, otherID, revocationEntries(1, 100, 101))
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		filter crl.RevokedCertificateFilter
+		want   []string
+	}{
+		"all":            {filter: crl.RevokedCertificateFilter{}, want: []string{"10", "11", "255", "4096", "1000", "12"}},
+		"decimal prefix": {filter: crl.RevokedCertificateFilter{SerialNumberPrefix: "1"}, want: []string{"10", "11", "1000", "12"}},
+		"hex prefix":     {filter: crl.RevokedCertificateFilter{SerialNumberPrefix: "1", SerialNumberHex: true}, want: []string{"4096"}},
+		"hex serial":     {filter: crl.RevokedCertificateFilter{SerialNumberPrefix: "ff", SerialNumberHex: true}, want: []string{"255"}},
+		"reason":         {filter: crl.RevokedCertificateFilter{Reason: crl.RevocationReasonSuperseded}, want: []string{"255", "1000"}},
+		"revoked after":  {filter: crl.RevokedCertificateFilter{RevokedAfter: now.Add(-3 * time.Hour)}, want: []string{"4096", "1000", "12"}},
+		"revoked before": {filter: crl.RevokedCertificateFilter{RevokedBefore: now.Add(-4 * time.Hour)}, want: []string{"10", "11"}},
+		"combined":       {filter: crl.RevokedCertificateFilter{SerialNumberPrefix: "1", Reason: crl.RevocationReasonKeyCompromise, RevokedAfter: now.Add(-5 * time.Hour), RevokedBefore: now.Add(-time.Hour)}, want: []string{"11"}},
+		"no match":       {filter: crl.RevokedCertificateFilter{SerialNumberPrefix: "9"}, want: []string{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			revokedCertificates, err := repository.ListRevokedCertificates(ctx, id, tt.filter, 0, 100)
+			assert.NoError(t, err)
+
+			serialNumbers := make([]string, len(revokedCertificates))
+			for i, revokedCertificate := range revokedCertificates {
+				serialNumbers[i] = revokedCertificate.SerialNumber
+				assert.Equal(t, id, revokedCertificate.RevocationListID)
+				assert.True(t, tt.filter.Matches(revokedCertificate))
+			}
+			assert.Equal(t, tt.want, serialNumbers)
+
+			count, err := repository.CountRevokedCertificates(ctx, id, tt.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.want), count)
+		})
+	}
+
+	pages := map[string]struct {
+		offset, limit int
+		want          []string
+	}{
+		"first page":  {offset: 0, limit: 2, want: []string{"10", "11"}},
+		"middle page": {offset: 2, limit: 2, want: []string{"255", "4096"}},
+		"last page":   {offset: 4, limit: 4, want: []string{"1000", "12"}},
+		"past end":    {offset: 6, limit: 2, want: []string{}},
+	}
+
+	for name, tt := range pages {
+		t.Run(name, func(t *testing.T) {
+			revokedCertificates, err := repository.ListRevokedCertificates(ctx, id, crl.RevokedCertificateFilter{}, tt.offset, tt.limit)
+			assert.NoError(t, err)
+
+			serialNumbers := make([]string, len(revokedCertificates))
+			for i, revokedCertificate := range revokedCertificates {
+				serialNumbers[i] = revokedCertificate.SerialNumber
+			}
+			assert.Equal(t, tt.want, serialNumbers)
+		})
+	}
+}
+
+func testInvalidRevocationReason(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	id, err := repository.Save(ctx, revocationList("ca"))
+	assert.NoError(t, err)
+
+	// reason code 7 is not used by RFC 5280
+	_, err = repository.SaveRevokedCertificates(ctx, id, revocationEntries(7, 10))
+	assert.Error(t, err)
+
+	revokedCertificate, err := repository.FindRevokedCertificate(ctx, "10")
+	assert.NoError(t, err)
+	assert.Nil(t, revokedCertificate)
+}
+
+func testSaveCertificate(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	saved := storedCertificate("aa", "CN=Issuing CA", 1, now.Add(24*time.Hour))
+
+	id, err := repository.SaveCertificate(ctx, saved)
+	assert.NoError(t, err)
+	assert.Positive(t, id)
+
+	found, err := repository.FindCertificate(ctx, "aa")
+	assert.NoError(t, err)
+	assert.Equal(t, id, found.ID)
+	assert.Equal(t, saved.Subject, found.Subject)
+	assert.Equal(t, saved.Issuer, found.Issuer)
+	assert.Equal(t, saved.SerialNumber, found.SerialNumber)
+	assert.Equal(t, saved.Raw, found.Raw)
+	assert.Equal(t, saved.Source, found.Source)
+	assert.WithinDuration(t, saved.NotBefore, found.NotBefore, time.Second)
+	assert.WithinDuration(t, saved.NotAfter, found.NotAfter, time.Second)
+	assert.False(t, found.Revoked())
+
+	// saving a known certificate only updates where and when it was last seen
+	seenAgain := storedCertificate("aa", "CN=Issuing CA", 1, now.Add(24*time.Hour))
+	seenAgain.Source = "file:leaf.pem"
+	seenAgain.FirstSeen = now.Add(time.Hour)
+	seenAgain.LastSeen = now.Add(time.Hour)
+	updatedID, err := repository.SaveCertificate(ctx, seenAgain)
+	assert.NoError(t, err)
+	assert.Equal(t, id, updatedID)
+
+	found, err = repository.FindCertificate(ctx, "aa")
+	assert.NoError(t, err)
+	assert.Equal(t, "file:leaf.pem", found.Source)
+	assert.WithinDuration(t, now, found.FirstSeen, time.Second)
+	assert.WithinDuration(t, now.Add(time.Hour), found.LastSeen, time.Second)
+
+	found, err = repository.FindCertificate(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func testListCertificates(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	for _, certificate := range []*crl.StoredCertificate{
+		storedCertificate("later", "CN=Issuing CA", 1, now.Add(90*24*time.Hour)),
+		storedCertificate("expired", "CN=Issuing CA", 2, now.Add(-24*time.Hour)),
+		storedCertificate("soon", "CN=Other CA", 3, now.Add(7*24*time.Hour)),
+	} {
+		_, err := repository.SaveCertificate(ctx, certificate)
+		assert.NoError(t, err)
+	}
+
+	id, err := repository.Save(ctx, revocationList("issuing"))
+	assert.NoError(t, err)
+	_, err = repository.SaveRevokedCertificates(ctx, id, revocationEntries(0, 2))
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		filter crl.CertificateFilter
+		want   []string
+	}{
+		"all":             {filter: crl.CertificateFilter{}, want: []string{"expired", "soon", "later"}},
+		"issuer":          {filter: crl.CertificateFilter{Issuer: "issuing"}, want: []string{"expired", "later"}},
+		"expires before":  {filter: crl.CertificateFilter{ExpiresBefore: now.Add(30 * 24 * time.Hour)}, want: []string{"expired", "soon"}},
+		"revoked":         {filter: crl.CertificateFilter{Revocation: crl.RevocationFilterRevoked}, want: []string{"expired"}},
+		"not revoked":     {filter: crl.CertificateFilter{Revocation: crl.RevocationFilterNotRevoked}, want: []string{"soon", "later"}},
+		"combined":        {filter: crl.CertificateFilter{Issuer: "Issuing CA", ExpiresBefore: now.Add(30 * 24 * time.Hour), Revocation: crl.RevocationFilterNotRevoked}, want: []string{}},
+		"unknown issuer":  {filter: crl.CertificateFilter{Issuer: "unknown"}, want: []string{}},
+		"expires exactly": {filter: crl.CertificateFilter{ExpiresBefore: now.Add(7 * 24 * time.Hour)}, want: []string{"expired", "soon"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			certificates, err := repository.ListCertificates(ctx, tt.filter)
+			assert.NoError(t, err)
+
+			fingerprints := make([]string, len(certificates))
+			for i, certificate := range certificates {
+				fingerprints[i] = certificate.Fingerprint
+				assert.True(t, tt.filter.Matches(certificate))
+			}
+			assert.Equal(t, tt.want, fingerprints)
+		})
+	}
+
+	revoked, err := repository.FindCertificate(ctx, "expired")
+	assert.NoError(t, err)
+	assert.Equal(t, "issuing", revoked.RevokedBy)
+}
+
+func testDeleteCertificate(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	id, err := repository.SaveCertificate(ctx, storedCertificate("aa", "CN=Issuing CA", 1, now))
+	assert.NoError(t, err)
+	_, err = repository.SaveCertificate(ctx, storedCertificate("bb", "CN=Issuing CA", 2, now))
+	assert.NoError(t, err)
+
+	assert.NoError(t, repository.DeleteCertificate(ctx, id))
+
+	found, err := repository.FindCertificate(ctx, "aa")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	certificates, err := repository.ListCertificates(ctx, crl.CertificateFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 1)
+	assert.Equal(t, "bb", certificates[0].Fingerprint)
+}
+
+func testAuditLog(t *testing.T, repository crl.Repository) {
+	ctx := context.Background()
+	entries := []*crl.AuditEntry{
+		{CheckedAt: now.Add(-48 * time.Hour), Method: crl.AuditMethodCRL, SerialNumber: "10", Subject: "CN=leaf", Issuer: "CN=ca", Source: "ca", SourceThisUpdate: now.Add(-72 * time.Hour), Result: crl.AuditResultRevoked, Detail: "keyCompromise", Origin: "certificate view"},
+		{CheckedAt: now.Add(-time.Hour), Method: crl.AuditMethodOCSP, SerialNumber: "10", Subject: "CN=leaf", Issuer: "CN=ca", Source: "http://ocsp.example.com", SourceThisUpdate: now.Add(-2 * time.Hour), Result: crl.AuditResultGood, Origin: "scan-dir"},
+		{CheckedAt: now, Method: crl.AuditMethodCRL, SerialNumber: "11", Subject: "CN=other", Issuer: "CN=unknown", Result: crl.AuditResultNotRevoked, Origin: "keystore"},
+	}
+	for _, entry := range entries {
+		id, err := repository.SaveAuditEntry(ctx, entry)
+		assert.NoError(t, err)
+		assert.Positive(t, id)
+	}
+
+	all, err := repository.ListAuditEntries(ctx, crl.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+	// the most recent check is listed first
+	assert.Equal(t, "11", all[0].SerialNumber)
+	assert.WithinDuration(t, now, all[0].CheckedAt, time.Second)
+	assert.True(t, all[0].SourceThisUpdate.IsZero())
+
+	revoked := all[2]
+	assert.Equal(t, crl.AuditMethodCRL, revoked.Method)
+	assert.Equal(t, "CN=leaf", revoked.Subject)
+	assert.Equal(t, "CN=ca", revoked.Issuer)
+	assert.Equal(t, "ca", revoked.Source)
+	assert.WithinDuration(t, now.Add(-72*time.Hour), revoked.SourceThisUpdate, time.Second)
+	assert.Equal(t, crl.AuditResultRevoked, revoked.Result)
+	assert.Equal(t, "keyCompromise", revoked.Detail)
+	assert.Equal(t, "certificate view", revoked.Origin)
+
+	tests := map[string]struct {
+		filter crl.AuditFilter
+		want   []crl.AuditResult
+	}{
+		"from":          {filter: crl.AuditFilter{From: now.Add(-2 * time.Hour)}, want: []crl.AuditResult{crl.AuditResultNotRevoked, crl.AuditResultGood}},
+		"to":            {filter: crl.AuditFilter{To: now.Add(-time.Hour)}, want: []crl.AuditResult{crl.AuditResultRevoked}},
+		"method":        {filter: crl.AuditFilter{Method: crl.AuditMethodOCSP}, want: []crl.AuditResult{crl.AuditResultGood}},
+		"serial number": {filter: crl.AuditFilter{SerialNumber: "10"}, want: []crl.AuditResult{crl.AuditResultGood, crl.AuditResultRevoked}},
+		"limit":         {filter: crl.AuditFilter{Limit: 2}, want: []crl.AuditResult{crl.AuditResultNotRevoked, crl.AuditResultGood}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := repository.ListAuditEntries(ctx, tt.filter)
+			assert.NoError(t, err)
+
+			results := make([]crl.AuditResult, len(entries))
+			for i, entry := range entries {
+				results[i] = entry.Result
+			}
+			assert.Equal(t, tt.want, results)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// auditHistoryLimit is the number of most recent checks shown in the history view, the full audit log is exported with certguard audit export
+const auditHistoryLimit = 1000
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type auditKeyMap struct {
+	table.KeyMap
+	Back   key.Binding
+	Quit   key.Binding
+	Enter  key.Binding
+	Filter key.Binding
+	Clear  key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *auditKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Quit, k.Filter, k.Clear}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *auditKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Back, k.Quit},
+		{k.LineUp, k.LineDown},
+		{k.GotoTop, k.GotoBottom},
+		{k.Filter, k.Enter, k.Clear},
+	}
+}
+
+var auditKeys = auditKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to main view"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "apply the filter"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter on serial number, method:, after: and before:"),
+	),
+	Clear: key.NewBinding(
+		key.WithKeys("ctrl+q"),
+		key.WithHelp("ctrl+q", "clear the filter"),
+	),
+	KeyMap: table.DefaultKeyMap(),
+}
+
+// AuditModel shows the history of revocation checks in the audit log
+type AuditModel struct {
+	keys     auditKeyMap
+	table    table.Model
+	query    textinput.Model
+	filter   crl.AuditFilter
+	entries  []*crl.AuditEntry
+	errorMsg string
+	styles   *styles.Styles
+	commands *commands.Commands
+}
+
+func NewAuditModel(height int, cmds *commands.Commands) *AuditModel {
+	columns := []table.Column{
+		{Title: "Checked", Width: 19},
+		{Title: "Method", Width: 6},
+		{Title: "Serial number", Width: 40},
+		{Title: "Subject", Width: 30},
+		{Title: "Source", Width: 30},
+		{Title: "Version", Width: 10},
+		{Title: "Result", Width: 11},
+		{Title: "Origin", Width: 16},
+	}
+
+	tbl := table.New(table.WithColumns(columns), table.WithFocused(true), table.WithHeight(height-12), table.WithWidth(180))
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(styles.Theme.ListComponentTitle).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(styles.Theme.FilePickerCurrent.GetForeground()).
+		Background(styles.Theme.BaseText.GetBackground()).
+		Bold(false)
+	tbl.SetStyles(s)
+
+	query := textinput.New()
+	query.Placeholder = "4096 method:ocsp after:2026-01-01 before:2026-02-01"
+	query.Prompt = "Filter: "
+	query.Width = 60
+
+	return &AuditModel{
+		keys:     auditKeys,
+		table:    tbl,
+		query:    query,
+		filter:   crl.AuditFilter{Limit: auditHistoryLimit},
+		styles:   styles.Theme,
+		commands: cmds,
+	}
+}
+
+func (m *AuditModel) Init() tea.Cmd {
+	return m.commands.AuditLog(m.filter)
+}
+
+// filtering is true while the filter is being edited
+func (m *AuditModel) filtering() bool {
+	return m.query.Focused()
+}
+
+func (m *AuditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case messages.AuditLogMsg:
+		m.filter = msg.Filter
+		m.entries = msg.Entries
+		m.errorMsg = ""
+		m.setRows()
+		return m, nil
+	case messages.ErrorMsg:
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Clear) {
+			m.query.Reset()
+			m.query.Blur()
+			m.table.Focus()
+			return m, m.commands.AuditLog(crl.AuditFilter{Limit: auditHistoryLimit})
+		}
+
+		if m.filtering() {
+			if key.Matches(msg, m.keys.Enter) {
+				filter, err := crl.ParseAuditFilter(m.query.Value())
+				if err != nil {
+					m.errorMsg = err.Error()
+					return m, nil
+				}
+				filter.Limit = auditHistoryLimit
+				m.query.Blur()
+				m.table.Focus()
+				return m, m.commands.AuditLog(filter)
+			}
+			m.query, cmd = m.query.Update(msg)
+			return m, cmd
+		}
+
+		if key.Matches(msg, m.keys.Filter) {
+			m.table.Blur()
+			return m, m.query.Focus()
+		}
+	}
+
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m *AuditModel) setRows() {
+	rows := make([]table.Row, len(m.entries))
+	for i, entry := range m.entries {
+		version := ""
+		if !entry.SourceThisUpdate.IsZero() {
+			version = entry.SourceThisUpdate.Format(time.DateOnly)
+		}
+
+		rows[i] = table.Row{
+			entry.CheckedAt.Local().Format(time.DateTime),
+			string(entry.Method),
+			entry.SerialNumber,
+			entry.Subject,
+			entry.Source,
+			version,
+			string(entry.Result),
+			entry.Origin,
+		}
+	}
+	m.table.SetRows(rows)
+}
+
+func (m *AuditModel) View() string {
+	var s strings.Builder
+
+	s.WriteString("\n " + m.query.View())
+
+	if m.errorMsg != "" {
+		s.WriteString(m.styles.WarningText.Render("\n\n " + m.errorMsg))
+	}
+
+	shown := fmt.Sprintf("%d checks", len(m.entries))
+	if len(m.entries) == m.filter.Limit {
+		shown = fmt.Sprintf("the %d most recent checks, use certguard audit export for the full audit log", len(m.entries))
+	}
+	s.WriteString("\n\n " + m.styles.Text.Render("Showing: ") + shown)
+
+	if selected := m.selected(); selected != nil && selected.Detail != "" {
+		s.WriteString("\n " + m.styles.Text.Render("Detail: ") + selected.Detail)
+	}
+
+	s.WriteString("\n\n" + m.table.View())
+	return s.String()
+}
+
+func (m *AuditModel) selected() *crl.AuditEntry {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.entries) {
+		return nil
+	}
+	return m.entries[cursor]
+}
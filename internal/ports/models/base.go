@@ -1,6 +1,7 @@
 package models
 
 import (
+	"crypto/x509"
 	"fmt"
 	"strings"
 
@@ -27,6 +28,18 @@ const (
 	importPemView
 	inputPemView
 	certificateView
+	inputPasswordView
+	certificateRequestView
+	compareView
+	inputScanView
+	scanDirView
+	reportView
+	browseCertificatesView
+	expiryView
+	keystoreView
+	searchView
+	auditView
+	exportView
 )
 
 var titles = map[sessionState]string{
@@ -39,20 +52,38 @@ var titles = map[sessionState]string{
 	importPemView:          "Import a PEM certificate",
 	inputPemView:           "Input a PEM certificate",
 	certificateView:        "view a parsed certificate",
+	inputPasswordView:      "Enter the password of the PKCS#12 bundle or keystore",
+	certificateRequestView: "view a parsed certificate request",
+	compareView:            "compare a certificate with its renewal",
+	inputScanView:          "Fetch the certificate chain of a TLS endpoint",
+	scanDirView:            "Scan a directory for certificates",
+	reportView:             "Certificates found in the directory",
+	browseCertificatesView: "Browse all inspected certificates from storage",
+	expiryView:             "Certificates expiring within 90 days",
+	keystoreView:           "Pick an entry from the keystore to inspect",
+	searchView:             "Search stored CRLs for a serial number",
+	auditView:              "History of revocation checks",
+	exportView:             "Export a stored CRL",
 }
 
 // keyMap defines a set of keybindings. To work for help it must satisfy
 // key.Map. It could also very easily be a map[string]key.Binding.
 type keyMap struct {
-	Help      key.Binding
-	Download  key.Binding
-	Back      key.Binding
-	Home      key.Binding
-	Import    key.Binding
-	Browse    key.Binding
-	InputPem  key.Binding
-	ImportPem key.Binding
-	Quit      key.Binding
+	Help               key.Binding
+	Download           key.Binding
+	Back               key.Binding
+	Home               key.Binding
+	Import             key.Binding
+	Browse             key.Binding
+	BrowseCertificates key.Binding
+	Expiry             key.Binding
+	Search             key.Binding
+	Audit              key.Binding
+	InputPem           key.Binding
+	ImportPem          key.Binding
+	Scan               key.Binding
+	ScanDir            key.Binding
+	Quit               key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -65,7 +96,7 @@ func (k *keyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k *keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Download, k.Import, k.Home},
+		{k.Download, k.Import, k.Scan, k.ScanDir, k.Home},
 		{k.Back, k.Help, k.Quit},
 	}
 }
@@ -91,10 +122,34 @@ var keys = keyMap{
 		key.WithKeys("b"),
 		key.WithHelp("b", "browseModel all loaded CRL's from storage"),
 	),
+	BrowseCertificates: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "browse all inspected certificates from storage"),
+	),
+	Expiry: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "show certificates expiring within 90 days"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search stored CRLs for a serial number"),
+	),
+	Audit: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "show the history of revocation checks"),
+	),
 	InputPem: key.NewBinding(
 		key.WithKeys("p"),
 		key.WithHelp("p", "inputModel a PEM certificate"),
 	),
+	Scan: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "fetch the certificate chain of a TLS endpoint"),
+	),
+	ScanDir: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "scan a directory for certificates"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "toggle help"),
@@ -106,22 +161,40 @@ var keys = keyMap{
 }
 
 type BaseModel struct {
-	title            string
-	state            sessionState
-	prevState        sessionState
-	keys             keyMap
-	help             help.Model
-	styles           *styles.Styles
-	commands         *commands.Commands
-	inputModel       *InputModel
-	browseModel      *BrowseModel
-	listModel        *ListModel
-	importModel      *ImportModel
-	inputPemModel    *InputPemModel
-	certificateModel *CertificateModel
-	err              error
-	width            int
-	height           int
+	title                   string
+	state                   sessionState
+	prevState               sessionState
+	keys                    keyMap
+	help                    help.Model
+	styles                  *styles.Styles
+	commands                *commands.Commands
+	inputModel              *InputModel
+	browseModel             *BrowseModel
+	listModel               *ListModel
+	importModel             *ImportModel
+	inputPemModel           *InputPemModel
+	certificateModel        *CertificateModel
+	passwordModel           *InputPasswordModel
+	csrModel                *CertificateRequestModel
+	compareModel            *CompareModel
+	scanModel               *InputScanModel
+	scanDirModel            *ScanDirModel
+	reportModel             *ReportModel
+	browseCertificatesModel *BrowseCertificatesModel
+	expiryModel             *ExpiryModel
+	keystoreModel           *KeystoreModel
+	searchModel             *SearchModel
+	auditModel              *AuditModel
+	exportModel             *ExportModel
+	// startupCmd is run when the program starts, e.g. to open a chain fetched with certguard scan
+	startupCmd tea.Cmd
+	// compareCertificate is compared with the next certificate that is parsed
+	compareCertificate *x509.Certificate
+	// crlProgress is the progress of the CRL that is being stored, nil when no CRL is being stored
+	crlProgress *messages.CRLProgressMsg
+	err         error
+	width       int
+	height      int
 }
 
 func NewBaseModel(cmds *commands.Commands) BaseModel {
@@ -136,8 +209,14 @@ func NewBaseModel(cmds *commands.Commands) BaseModel {
 	}
 }
 
+// WithStartupCommand runs the command when the program starts
+func (m BaseModel) WithStartupCommand(cmd tea.Cmd) BaseModel {
+	m.startupCmd = cmd
+	return m
+}
+
 func (m BaseModel) Init() tea.Cmd {
-	return tea.EnterAltScreen
+	return tea.Batch(tea.EnterAltScreen, m.startupCmd)
 }
 
 func (m BaseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -150,11 +229,11 @@ func (m BaseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.help.Width = msg.Width
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, m.keys.Quit) && m.state != inputView && m.state != inputPemView: // inputModel view has it's own quit keybinding since we cannot use "q"
+		case key.Matches(msg, m.keys.Quit) && !m.isInputState(): // inputModel view has it's own quit keybinding since we cannot use "q"
 			return m, tea.Quit
 		case key.Matches(msg, m.keys.Help):
 			m.help.ShowAll = !m.help.ShowAll
-		case key.Matches(msg, m.keys.Home) && m.state != inputView && m.state != inputPemView:
+		case key.Matches(msg, m.keys.Home) && !m.isInputState():
 			m.prevState = m.state
 			m.state = baseView
 			m.title = titles[baseView]
@@ -165,16 +244,82 @@ func (m BaseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.prevState = state
 			m.title = titles[m.state]
 		}
+	case messages.CRLProgressMsg:
+		m.crlProgress = &msg
+		return m, m.commands.WaitForCRLIngest()
+	case messages.ErrorMsg:
+		m.crlProgress = nil
 	case messages.CRLResponseMsg:
+		m.crlProgress = nil
 		m.prevState = m.state
 		m.state = listView
 		m.title = titles[listView]
-		m.listModel = NewListModel(msg.RevocationList, msg.URL, m.width, m.height, m.commands)
+		m.listModel = NewListModel(msg, m.width, m.height, m.commands)
+		return m, m.listModel.Init()
+	case messages.RevokedCertificatesPageMsg:
+		// pages that are read after leaving the list view are still added to the list
+		if m.state != listView && m.listModel != nil {
+			m.listModel.Update(msg)
+		}
+	case messages.ExportRevocationListMsg:
+		m.prevState = m.state
+		m.state = exportView
+		m.title = titles[exportView]
+		m.exportModel = NewExportModel(msg.Name, m.commands)
+		return m, m.exportModel.Init()
+	case messages.PasswordRequiredMsg:
+		m.prevState = m.state
+		m.state = inputPasswordView
+		m.title = titles[inputPasswordView]
+		m.passwordModel = NewInputPasswordModel(msg, m.commands)
+		return m, m.passwordModel.Init()
 	case messages.PemCertificateMsg:
+		if m.state == inputPasswordView {
+			// the password prompt is skipped when navigating back from the certificate view
+			m.state = m.prevState
+			m.passwordModel = nil
+		}
+		if m.compareCertificate != nil {
+			old := m.compareCertificate
+			m.compareCertificate = nil
+			return m, m.commands.CompareCertificates(old, msg.Certificate)
+		}
 		m.prevState = m.state
 		m.state = certificateView
 		m.title = titles[certificateView]
-		m.certificateModel = NewCertificateModel(msg.Certificate, msg.CertificateChain, m.commands)
+		m.certificateModel = NewCertificateModel(msg, m.height, m.commands)
+	case messages.KeystoreMsg:
+		if m.state == inputPasswordView {
+			m.state = m.prevState
+			m.passwordModel = nil
+		}
+		m.prevState = m.state
+		m.state = keystoreView
+		m.title = titles[keystoreView]
+		m.keystoreModel = NewKeystoreModel(msg.Report, m.width, m.height, m.commands)
+	case messages.CompareCertificateMsg:
+		m.compareCertificate = msg.Certificate
+		m.prevState = m.state
+		m.state = baseView
+		m.title = titles[baseView]
+	case messages.CertificateComparisonMsg:
+		m.prevState = m.state
+		m.state = compareView
+		m.title = titles[compareView]
+		m.compareModel = NewCompareModel(msg.Comparison, m.height)
+	case messages.DirectoryReportMsg:
+		if m.scanDirModel != nil {
+			m.scanDirModel.scanning = ""
+		}
+		m.prevState = m.state
+		m.state = reportView
+		m.title = titles[reportView]
+		m.reportModel = NewReportModel(msg.Report, m.height, m.commands)
+	case messages.CertificateRequestMsg:
+		m.prevState = m.state
+		m.state = certificateRequestView
+		m.title = titles[certificateRequestView]
+		m.csrModel = NewCertificateRequestModel(msg, m.height)
 	}
 
 	return m.handleStates(msg)
@@ -215,10 +360,58 @@ func (m BaseModel) handleStates(msg tea.Msg) (tea.Model, tea.Cmd) {
 		inputPemModel, inputPemCmd := m.inputPemModel.Update(msg)
 		m.inputPemModel = inputPemModel.(*InputPemModel)
 		cmd = append(cmd, inputPemCmd)
+	case inputPasswordView:
+		passwordModel, passwordCmd := m.passwordModel.Update(msg)
+		m.passwordModel = passwordModel.(*InputPasswordModel)
+		cmd = append(cmd, passwordCmd)
 	case certificateView:
 		certificateModel, certificateCmd := m.certificateModel.Update(msg)
 		m.certificateModel = certificateModel.(*CertificateModel)
 		cmd = append(cmd, certificateCmd)
+	case certificateRequestView:
+		csrModel, csrCmd := m.csrModel.Update(msg)
+		m.csrModel = csrModel.(*CertificateRequestModel)
+		cmd = append(cmd, csrCmd)
+	case inputScanView:
+		scanModel, scanCmd := m.scanModel.Update(msg)
+		m.scanModel = scanModel.(*InputScanModel)
+		cmd = append(cmd, scanCmd)
+	case scanDirView:
+		scanDirModel, scanDirCmd := m.scanDirModel.Update(msg)
+		m.scanDirModel = scanDirModel.(*ScanDirModel)
+		cmd = append(cmd, scanDirCmd)
+	case browseCertificatesView:
+		browseCertificatesModel, browseCertificatesCmd := m.browseCertificatesModel.Update(msg)
+		m.browseCertificatesModel = browseCertificatesModel.(*BrowseCertificatesModel)
+		cmd = append(cmd, browseCertificatesCmd)
+	case keystoreView:
+		keystoreModel, keystoreCmd := m.keystoreModel.Update(msg)
+		m.keystoreModel = keystoreModel.(*KeystoreModel)
+		cmd = append(cmd, keystoreCmd)
+	case searchView:
+		searchModel, searchCmd := m.searchModel.Update(msg)
+		m.searchModel = searchModel.(*SearchModel)
+		cmd = append(cmd, searchCmd)
+	case exportView:
+		exportModel, exportCmd := m.exportModel.Update(msg)
+		m.exportModel = exportModel.(*ExportModel)
+		cmd = append(cmd, exportCmd)
+	case auditView:
+		auditModel, auditCmd := m.auditModel.Update(msg)
+		m.auditModel = auditModel.(*AuditModel)
+		cmd = append(cmd, auditCmd)
+	case expiryView:
+		expiryModel, expiryCmd := m.expiryModel.Update(msg)
+		m.expiryModel = expiryModel.(*ExpiryModel)
+		cmd = append(cmd, expiryCmd)
+	case reportView:
+		reportModel, reportCmd := m.reportModel.Update(msg)
+		m.reportModel = reportModel.(*ReportModel)
+		cmd = append(cmd, reportCmd)
+	case compareView:
+		compareModel, compareCmd := m.compareModel.Update(msg)
+		m.compareModel = compareModel.(*CompareModel)
+		cmd = append(cmd, compareCmd)
 	case baseView:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
@@ -243,6 +436,48 @@ func (m BaseModel) handleStates(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.browseModel = NewBrowseModel(m.height, m.commands)
 				return m, m.browseModel.Init()
 			}
+			if key.Matches(msg, m.keys.BrowseCertificates) {
+				m.prevState = m.state
+				m.state = browseCertificatesView
+				m.title = titles[m.state]
+				m.browseCertificatesModel = NewBrowseCertificatesModel(m.height, m.commands)
+				return m, m.browseCertificatesModel.Init()
+			}
+			if key.Matches(msg, m.keys.Expiry) {
+				m.prevState = m.state
+				m.state = expiryView
+				m.title = titles[m.state]
+				m.expiryModel = NewExpiryModel(m.height, m.commands)
+				return m, m.expiryModel.Init()
+			}
+			if key.Matches(msg, m.keys.Search) {
+				m.prevState = m.state
+				m.state = searchView
+				m.title = titles[m.state]
+				m.searchModel = NewSearchModel(m.height, m.commands)
+				return m, m.searchModel.Init()
+			}
+			if key.Matches(msg, m.keys.Audit) {
+				m.prevState = m.state
+				m.state = auditView
+				m.title = titles[m.state]
+				m.auditModel = NewAuditModel(m.height, m.commands)
+				return m, m.auditModel.Init()
+			}
+			if key.Matches(msg, m.keys.Scan) {
+				m.prevState = m.state
+				m.state = inputScanView
+				m.title = titles[m.state]
+				m.scanModel = NewInputScanModel(m.commands)
+				return m, m.scanModel.Init()
+			}
+			if key.Matches(msg, m.keys.ScanDir) {
+				m.prevState = m.state
+				m.state = scanDirView
+				m.title = titles[m.state]
+				m.scanDirModel = NewScanDirModel(m.commands, m.height)
+				return m, m.scanDirModel.Init()
+			}
 			if key.Matches(msg, m.keys.InputPem) {
 				m.prevState = m.state
 				m.state = inputPemView
@@ -256,13 +491,43 @@ func (m BaseModel) handleStates(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmd...)
 }
 
+// crlProgressView shows how many revoked certificates of a downloaded or imported CRL are stored
+func (m BaseModel) crlProgressView() string {
+	if m.crlProgress == nil {
+		return ""
+	}
+
+	return m.styles.CRLText.Render("Storing CRL: ") + fmt.Sprintf("%s, %d of %d revoked certificates (%d%%)",
+		m.crlProgress.Name, m.crlProgress.Stored, m.crlProgress.Total, 100*m.crlProgress.Stored/max(m.crlProgress.Total, 1))
+}
+
+// isInputState returns true for states that capture text input, in these states single character keybindings are disabled
+func (m BaseModel) isInputState() bool {
+	if m.state == browseCertificatesView && m.browseCertificatesModel.filtering() {
+		return true
+	}
+	if m.state == keystoreView && m.keystoreModel.filtering() {
+		return true
+	}
+	if m.state == listView && m.listModel.filtering() {
+		return true
+	}
+	if m.state == searchView && m.searchModel.searching() {
+		return true
+	}
+	if m.state == auditView && m.auditModel.filtering() {
+		return true
+	}
+	return m.state == inputView || m.state == inputPemView || m.state == inputPasswordView || m.state == inputScanView || m.state == exportView
+}
+
 func (m BaseModel) View() string {
 	errorMsg := ""
 
 	switch m.state {
 	case inputView:
 		title := m.styles.Title.Render(m.title)
-		inputBox := m.inputModel.View()
+		inputBox := lipgloss.JoinVertical(lipgloss.Top, m.inputModel.View(), m.crlProgressView())
 		helpMenu := m.help.View(&inputKeys)
 		height := strings.Count(inputBox, "\n") + strings.Count(title, "\n")
 		return lipgloss.JoinVertical(lipgloss.Top, title, inputBox) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
@@ -278,7 +543,7 @@ func (m BaseModel) View() string {
 		return lipgloss.JoinVertical(lipgloss.Top, title, revokedCertificateDetails) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
 	case importView:
 		title := m.styles.Title.Render(m.title)
-		listInfo := m.importModel.View()
+		listInfo := lipgloss.JoinVertical(lipgloss.Top, m.importModel.View(), m.crlProgressView())
 		helpMenu := m.help.View(&listKeys)
 		height := strings.Count(listInfo, "\n") + strings.Count(title, "\n")
 		return lipgloss.JoinVertical(lipgloss.Top, title, listInfo) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
@@ -294,29 +559,108 @@ func (m BaseModel) View() string {
 		helpMenu := m.help.View(&inputPemKeys)
 		height := strings.Count(textArea, "\n") + strings.Count(title, "\n")
 		return lipgloss.JoinVertical(lipgloss.Top, title, textArea) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case inputPasswordView:
+		title := m.styles.Title.Render(m.title)
+		inputBox := m.passwordModel.View()
+		helpMenu := m.help.View(&inputPasswordKeys)
+		height := strings.Count(inputBox, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, inputBox) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
 	case certificateView:
 		title := m.styles.Title.Render(m.title)
 		certInfo := m.certificateModel.View()
 		helpMenu := m.help.View(&certificateKeys)
 		height := strings.Count(certInfo, "\n") + strings.Count(title, "\n")
 		return lipgloss.JoinVertical(lipgloss.Top, title, certInfo) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case certificateRequestView:
+		title := m.styles.Title.Render(m.title)
+		csrInfo := m.csrModel.View()
+		helpMenu := m.help.View(&certificateRequestKeys)
+		height := strings.Count(csrInfo, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, csrInfo) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case inputScanView:
+		title := m.styles.Title.Render(m.title)
+		form := m.scanModel.View()
+		helpMenu := m.help.View(&inputScanKeys)
+		height := strings.Count(form, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, form) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case scanDirView:
+		title := m.styles.Title.Render(m.title)
+		picker := m.scanDirModel.View()
+		helpMenu := m.help.View(&scanDirKeys)
+		height := strings.Count(picker, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, picker) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case browseCertificatesView:
+		title := m.styles.Title.Render(m.title)
+		table := m.browseCertificatesModel.View()
+		helpMenu := m.help.View(&browseCertificatesKeys)
+		height := strings.Count(table, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, table) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case keystoreView:
+		title := m.styles.Title.Render(m.title)
+		keystoreInfo := m.keystoreModel.View()
+		return lipgloss.JoinVertical(lipgloss.Top, title, keystoreInfo)
+	case searchView:
+		title := m.styles.Title.Render(m.title)
+		search := m.searchModel.View()
+		helpMenu := m.help.View(&searchKeys)
+		height := strings.Count(search, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, search) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case exportView:
+		title := m.styles.Title.Render(m.title)
+		form := m.exportModel.View()
+		helpMenu := m.help.View(&exportKeys)
+		height := strings.Count(form, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, form) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case auditView:
+		title := m.styles.Title.Render(m.title)
+		history := m.auditModel.View()
+		helpMenu := m.help.View(&auditKeys)
+		height := strings.Count(history, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, history) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case expiryView:
+		title := m.styles.Title.Render(m.title)
+		dashboard := m.expiryModel.View()
+		helpMenu := m.help.View(&expiryKeys)
+		height := strings.Count(dashboard, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, dashboard) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case reportView:
+		title := m.styles.Title.Render(m.title)
+		table := m.reportModel.View()
+		helpMenu := m.help.View(&reportKeys)
+		height := strings.Count(table, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, table) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
+	case compareView:
+		title := m.styles.Title.Render(m.title)
+		diff := m.compareModel.View()
+		helpMenu := m.help.View(&compareKeys)
+		height := strings.Count(diff, "\n") + strings.Count(title, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, diff) + lipgloss.Place(m.width, m.height-height-1, lipgloss.Left, lipgloss.Bottom, helpMenu)
 	default:
 		title := m.styles.Title.Render(m.title)
 		if m.err != nil {
 			errorMsg = m.err.Error()
 		}
 		downloadHelp := m.styles.BaseMenuText.Render("Download a CRL file: ") + "d"
-		importHelp := m.styles.BaseMenuText.Render("Import a CRL, or PEM Cert from import directory: ") + "i"
+		importHelp := m.styles.BaseMenuText.Render("Import a CRL, Certificate, CSR or keystore from import directory: ") + "i"
 		browseHelp := m.styles.BaseMenuText.Render("Browse all loaded CRL's from storage") + "b"
-		mainMenu := fmt.Sprintf("%s\n%s\n%s", downloadHelp, importHelp, browseHelp)
+		browseCertificatesHelp := m.styles.BaseMenuText.Render("Browse all inspected certificates from storage") + "c"
+		expiryHelp := m.styles.BaseMenuText.Render("Show certificates expiring within 90 days") + "e"
+		searchHelp := m.styles.BaseMenuText.Render("Search stored CRLs for a serial number") + "/"
+		auditHelp := m.styles.BaseMenuText.Render("Show the history of revocation checks") + "a"
+		mainMenu := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s\n%s", downloadHelp, importHelp, browseHelp, browseCertificatesHelp, expiryHelp, searchHelp, auditHelp)
 
-		inputPemHelp := m.styles.BaseMenuText.Render("Input a Certificate in PEM format") + "p"
-		pemMenu := fmt.Sprintf("%s\n", inputPemHelp)
+		inputPemHelp := m.styles.BaseMenuText.Render("Input a Certificate or CSR in PEM format") + "p"
+		scanHelp := m.styles.BaseMenuText.Render("Fetch the certificate chain of a TLS endpoint") + "t"
+		scanDirHelp := m.styles.BaseMenuText.Render("Scan a directory for certificates") + "s"
+		pemMenu := fmt.Sprintf("%s\n%s\n%s\n", inputPemHelp, scanHelp, scanDirHelp)
 
 		menu := fmt.Sprintf("%s\n\n%s", mainMenu, pemMenu)
+		if m.compareCertificate != nil {
+			menu += "\n" + m.styles.WarningText.Render(fmt.Sprintf("Input or import a certificate to compare with: %s", m.compareCertificate.Subject.CommonName))
+		}
 
 		helpMenu := m.help.View(&keys)
-		height := strings.Count(title, "\n")
-		return lipgloss.JoinVertical(lipgloss.Top, title, errorMsg, menu) + lipgloss.Place(m.width, m.height-height-7, lipgloss.Left, lipgloss.Bottom, helpMenu)
+		height := strings.Count(title, "\n") + strings.Count(menu, "\n")
+		return lipgloss.JoinVertical(lipgloss.Top, title, errorMsg, menu) + lipgloss.Place(m.width, m.height-height-2, lipgloss.Left, lipgloss.Bottom, helpMenu)
 	}
 }
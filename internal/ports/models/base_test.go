@@ -1,8 +1,13 @@
 package models
 
 import (
+	"context"
 	"crypto/x509"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,6 +15,7 @@ import (
 	"github.com/pimg/certguard/internal/ports/models/messages"
 	"github.com/pimg/certguard/internal/ports/models/styles"
 	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/scan"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -78,6 +84,138 @@ func TestSwitchToHome(t *testing.T) {
 	assert.Equal(t, titles[baseView], updatedModel.(BaseModel).title)
 }
 
+func TestCompareCertificates(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	commands := cmds.NewCommands(storage)
+	baseModel := NewBaseModel(commands)
+
+	rawPem, err := os.ReadFile(filepath.Join("..", "..", "..", "testing", "pki", "github.com-chain.pem"))
+	assert.NoError(t, err)
+	pemMsg := commands.ParsePemCertficate(string(rawPem))().(messages.PemCertificateMsg)
+
+	updatedModel, _ := baseModel.Update(messages.CompareCertificateMsg{Certificate: pemMsg.CertificateChain[0]})
+	assert.Equal(t, baseView, updatedModel.(BaseModel).state)
+
+	updatedModel, cmd := updatedModel.Update(pemMsg)
+	assert.Equal(t, baseView, updatedModel.(BaseModel).state)
+	assert.Nil(t, updatedModel.(BaseModel).compareCertificate)
+
+	updatedModel, _ = updatedModel.Update(cmd())
+	assert.Equal(t, compareView, updatedModel.(BaseModel).state)
+	assert.Equal(t, titles[compareView], updatedModel.(BaseModel).title)
+	assert.Equal(t, "github.com", updatedModel.(BaseModel).compareModel.comparison.New.CommonName)
+}
+
+func TestSwitchToReportModel(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	baseModel := NewBaseModel(cmds.NewCommands(storage))
+
+	updatedModel, _ := baseModel.Update(keyBindingToKeyMsg(keys.ScanDir))
+	assert.Equal(t, scanDirView, updatedModel.(BaseModel).state)
+	assert.Equal(t, titles[scanDirView], updatedModel.(BaseModel).title)
+
+	report := &scan.Report{Root: "certs", Entries: []scan.ReportEntry{{Path: "certs/leaf.pem", Subject: "leaf"}}}
+	updatedModel, _ = updatedModel.Update(messages.DirectoryReportMsg{Report: report})
+	assert.Equal(t, reportView, updatedModel.(BaseModel).state)
+	assert.Equal(t, titles[reportView], updatedModel.(BaseModel).title)
+	assert.Len(t, updatedModel.(BaseModel).reportModel.table.Rows(), 1)
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(reportKeys.Sort))
+	assert.Equal(t, scan.SortByExpiry, updatedModel.(BaseModel).reportModel.sortedBy)
+}
+
+func TestBrowseCertificatesFilter(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	baseModel := NewBaseModel(cmds.NewCommands(storage))
+
+	updatedModel, _ := baseModel.Update(keyBindingToKeyMsg(keys.BrowseCertificates))
+	assert.Equal(t, browseCertificatesView, updatedModel.(BaseModel).state)
+	assert.Equal(t, titles[browseCertificatesView], updatedModel.(BaseModel).title)
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(browseCertificatesKeys.Issuer))
+	assert.True(t, updatedModel.(BaseModel).isInputState())
+
+	// single key bindings are typed in the filter instead of switching views
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(keys.Home))
+	assert.Equal(t, browseCertificatesView, updatedModel.(BaseModel).state)
+	assert.Equal(t, "h", updatedModel.(BaseModel).browseCertificatesModel.issuer.Value())
+
+	updatedModel, cmd := updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, updatedModel.(BaseModel).isInputState())
+	assert.Equal(t, "h", updatedModel.(BaseModel).browseCertificatesModel.filter(time.Now()).Issuer)
+	assert.IsType(t, messages.ListCertificatesResponseMsg{}, cmd())
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(browseCertificatesKeys.Revocation))
+	assert.Equal(t, crl.RevocationFilterRevoked, updatedModel.(BaseModel).browseCertificatesModel.filter(time.Now()).Revocation)
+}
+
+func TestExpiryDashboardOpenCertificate(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	cmds := cmds.NewCommands(storage)
+
+	path := filepath.Join("..", "..", "..", "testing", "pki", "github.com-chain.pem")
+	_ = cmds.ImportFile(path)()
+
+	baseModel := NewBaseModel(cmds)
+	updatedModel, cmd := baseModel.Update(keyBindingToKeyMsg(keys.Expiry))
+	assert.Equal(t, expiryView, updatedModel.(BaseModel).state)
+	assert.Equal(t, titles[expiryView], updatedModel.(BaseModel).title)
+
+	// the github.com leaf certificate in the test chain is expired
+	updatedModel, _ = updatedModel.Update(cmd())
+	assert.Equal(t, crl.Expired, updatedModel.(BaseModel).expiryModel.bucket)
+	assert.Len(t, updatedModel.(BaseModel).expiryModel.table.Rows(), 1)
+
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyRight})
+	assert.Equal(t, crl.ExpiresWithin7Days, updatedModel.(BaseModel).expiryModel.bucket)
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	assert.Equal(t, crl.Expired, updatedModel.(BaseModel).expiryModel.bucket)
+
+	updatedModel, cmd = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updatedModel, _ = updatedModel.Update(cmd())
+	assert.Equal(t, certificateView, updatedModel.(BaseModel).state)
+	assert.Equal(t, "github.com", updatedModel.(BaseModel).certificateModel.certificate.CommonName)
+}
+
+func TestOpenKeystoreWithPassword(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	cmds := cmds.NewCommands(storage)
+
+	baseModel := NewBaseModel(cmds)
+	updatedModel, _ := baseModel.Update(cmds.ImportFile(filepath.Join("..", "..", "..", "testing", "pki", "truststore.jks"))())
+	assert.Equal(t, inputPasswordView, updatedModel.(BaseModel).state)
+
+	for _, r := range "certguard" {
+		updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	updatedModel, cmd := updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updatedModel, _ = updatedModel.Update(cmd())
+	assert.Equal(t, keystoreView, updatedModel.(BaseModel).state)
+	assert.Equal(t, baseView, updatedModel.(BaseModel).prevState)
+	assert.Len(t, updatedModel.(BaseModel).keystoreModel.list.Items(), 4)
+
+	updatedModel, cmd = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updatedModel, _ = updatedModel.Update(cmd())
+	assert.Equal(t, certificateView, updatedModel.(BaseModel).state)
+	assert.Equal(t, "Sectigo ECC Domain Validation Secure Server CA", updatedModel.(BaseModel).certificateModel.certificate.CommonName)
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(keys.Back))
+	assert.Equal(t, keystoreView, updatedModel.(BaseModel).state)
+}
+
 func keyBindingToKeyMsg(keyBinding key.Binding) tea.KeyMsg {
 	stringsSlice := keyBinding.Keys()
 	var runesSlice []rune
@@ -93,3 +231,181 @@ func keyBindingToKeyMsg(keyBinding key.Binding) tea.KeyMsg {
 		Alt:   false,
 	}
 }
+
+func TestCRLProgress(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	baseModel := NewBaseModel(cmds.NewCommands(storage))
+
+	updatedModel, _ := baseModel.Update(keyBindingToKeyMsg(keys.Import))
+	assert.Equal(t, importView, updatedModel.(BaseModel).state)
+
+	updatedModel, cmd := updatedModel.Update(messages.CRLProgressMsg{Name: "Generated CA", Stored: 10_000, Total: 40_000})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, importView, updatedModel.(BaseModel).state)
+	assert.Contains(t, updatedModel.View(), "Generated CA, 10000 of 40000 revoked certificates (25%)")
+
+	updatedModel, _ = updatedModel.Update(messages.CRLResponseMsg{RevocationList: &x509.RevocationList{}})
+	assert.Equal(t, listView, updatedModel.(BaseModel).state)
+	assert.Nil(t, updatedModel.(BaseModel).crlProgress)
+}
+
+func TestRevokedCertificatesPaging(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	entries := 2*revokedCertificatesPageSize + 10
+	revoked := make([]x509.RevocationListEntry, entries)
+	for i := range revoked {
+		revoked[i] = x509.RevocationListEntry{SerialNumber: big.NewInt(int64(i + 1)), RevocationTime: time.Now()}
+	}
+	_, err = storage.Repository.SaveRevokedCertificates(context.Background(), 1, revoked)
+	assert.NoError(t, err)
+
+	baseModel := NewBaseModel(cmds.NewCommands(storage))
+	updatedModel, cmd := baseModel.Update(messages.CRLResponseMsg{ID: 1, RevocationList: &x509.RevocationList{}, Count: entries})
+	assert.Equal(t, listView, updatedModel.(BaseModel).state)
+
+	firstPage := pageMsg(cmd)
+	updatedModel, _ = updatedModel.Update(firstPage)
+	assert.Len(t, updatedModel.(BaseModel).listModel.list.Items(), revokedCertificatesPageSize)
+
+	// the next page is read when scrolling to the end of the list
+	updatedModel, cmd = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	updatedModel, _ = updatedModel.Update(pageMsg(cmd))
+	assert.Len(t, updatedModel.(BaseModel).listModel.list.Items(), 2*revokedCertificatesPageSize)
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(listKeys.Filter))
+	assert.True(t, updatedModel.(BaseModel).isInputState())
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0xff")})
+	updatedModel, cmd = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, updatedModel.(BaseModel).isInputState())
+
+	updatedModel, _ = updatedModel.Update(pageMsg(cmd))
+	listModel := updatedModel.(BaseModel).listModel
+	assert.Equal(t, 1, listModel.matches)
+	assert.Len(t, listModel.list.Items(), 1)
+	assert.Equal(t, "255", listModel.list.Items()[0].(item).serialnumber)
+
+	// pages of the previous filter are dropped
+	updatedModel, _ = updatedModel.Update(firstPage)
+	assert.Len(t, updatedModel.(BaseModel).listModel.list.Items(), 1)
+}
+
+// pageMsg runs the commands returned by the list view until a page of revoked certificates is read
+func pageMsg(cmd tea.Cmd) tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	switch msg := cmd().(type) {
+	case messages.RevokedCertificatesPageMsg:
+		return msg
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			if page := pageMsg(cmd); page != nil {
+				return page
+			}
+		}
+	}
+	return nil
+}
+
+func TestSearchSerialNumber(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	_, err = storage.Repository.SaveRevokedCertificates(context.Background(), 1, []x509.RevocationListEntry{{SerialNumber: big.NewInt(4096), RevocationTime: time.Now()}})
+	assert.NoError(t, err)
+
+	baseModel := NewBaseModel(cmds.NewCommands(storage))
+
+	updatedModel, _ := baseModel.Update(keyBindingToKeyMsg(keys.Search))
+	assert.Equal(t, searchView, updatedModel.(BaseModel).state)
+	assert.Equal(t, titles[searchView], updatedModel.(BaseModel).title)
+	assert.True(t, updatedModel.(BaseModel).isInputState())
+
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("10:00")})
+	updatedModel, cmd := updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updatedModel, _ = updatedModel.Update(cmd())
+	assert.False(t, updatedModel.(BaseModel).isInputState())
+	assert.Len(t, updatedModel.(BaseModel).searchModel.table.Rows(), 1)
+	assert.Contains(t, updatedModel.View(), "1 revoked certificate(s) found")
+}
+
+func TestListHexSerialNumbers(t *testing.T) {
+	styles.NewStyles("default")
+	baseModel := NewBaseModel(nil)
+
+	updatedModel, _ := baseModel.Update(messages.CRLResponseMsg{ID: 1, RevocationList: &x509.RevocationList{}, Count: 1})
+	updatedModel, _ = updatedModel.Update(messages.RevokedCertificatesPageMsg{
+		RevocationListID:    1,
+		Total:               1,
+		RevokedCertificates: []*crl.RevokedCertificate{{SerialNumber: "4096", RevocationReason: crl.RevocationReasonSuperseded}},
+	})
+	assert.Equal(t, "4096", updatedModel.(BaseModel).listModel.list.Items()[0].(item).Title())
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(listKeys.Hex))
+	assert.Equal(t, "10:00", updatedModel.(BaseModel).listModel.list.Items()[0].(item).Title())
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(listKeys.Hex))
+	assert.Equal(t, "4096", updatedModel.(BaseModel).listModel.list.Items()[0].(item).Title())
+}
+
+func TestAuditHistory(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	for _, serialNumber := range []string{"255", "4096"} {
+		_, err = storage.Repository.SaveAuditEntry(context.Background(), &crl.AuditEntry{CheckedAt: time.Now(), Method: crl.AuditMethodCRL, SerialNumber: serialNumber, Result: crl.AuditResultNotRevoked})
+		assert.NoError(t, err)
+	}
+
+	baseModel := NewBaseModel(cmds.NewCommands(storage))
+
+	updatedModel, cmd := baseModel.Update(keyBindingToKeyMsg(keys.Audit))
+	assert.Equal(t, auditView, updatedModel.(BaseModel).state)
+	assert.Equal(t, titles[auditView], updatedModel.(BaseModel).title)
+	updatedModel, _ = updatedModel.Update(cmd())
+	assert.Len(t, updatedModel.(BaseModel).auditModel.table.Rows(), 2)
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(auditKeys.Filter))
+	assert.True(t, updatedModel.(BaseModel).isInputState())
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("10:00")})
+	updatedModel, cmd = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updatedModel, _ = updatedModel.Update(cmd())
+	assert.False(t, updatedModel.(BaseModel).isInputState())
+	assert.Len(t, updatedModel.(BaseModel).auditModel.table.Rows(), 1)
+	assert.Equal(t, "4096", updatedModel.(BaseModel).auditModel.table.Rows()[0][2])
+}
+
+func TestExportRevocationList(t *testing.T) {
+	styles.NewStyles("default")
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	baseModel := NewBaseModel(cmds.NewCommands(storage))
+	updatedModel, _ := baseModel.Update(keyBindingToKeyMsg(keys.Browse))
+	assert.Equal(t, browseView, updatedModel.(BaseModel).state)
+
+	updatedModel, _ = updatedModel.Update(messages.ExportRevocationListMsg{Name: "Example CA"})
+	assert.Equal(t, exportView, updatedModel.(BaseModel).state)
+	assert.Equal(t, titles[exportView], updatedModel.(BaseModel).title)
+	assert.True(t, updatedModel.(BaseModel).isInputState())
+	assert.Equal(t, "Example_CA.csv", updatedModel.(BaseModel).exportModel.path.Value())
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(exportKeys.Format))
+	assert.Equal(t, "Example_CA.json", updatedModel.(BaseModel).exportModel.path.Value())
+
+	path := filepath.Join(t.TempDir(), "example.json")
+	updatedModel.(BaseModel).exportModel.path.SetValue(path)
+	updatedModel, cmd := updatedModel.Update(keyBindingToKeyMsg(exportKeys.Enter))
+	updatedModel, _ = updatedModel.Update(cmd())
+	assert.Contains(t, updatedModel.View(), "exported 0 revoked certificates")
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+
+	updatedModel, _ = updatedModel.Update(keyBindingToKeyMsg(keys.Back))
+	assert.Equal(t, browseView, updatedModel.(BaseModel).state)
+}
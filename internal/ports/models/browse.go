@@ -22,6 +22,7 @@ type browseKeyMap struct {
 	Quit   key.Binding
 	Enter  key.Binding
 	Delete key.Binding
+	Export key.Binding
 	Y      key.Binding
 	N      key.Binding
 }
@@ -29,7 +30,7 @@ type browseKeyMap struct {
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
 // of the key.Map interface.
 func (k *browseKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Back, k.Quit, k.LineUp, k.LineDown, k.Enter, k.Delete}
+	return []key.Binding{k.Back, k.Quit, k.LineUp, k.LineDown, k.Enter, k.Delete, k.Export}
 }
 
 // FullHelp returns keybindings for the expanded help view. It's part of the
@@ -39,7 +40,7 @@ func (k *browseKeyMap) FullHelp() [][]key.Binding {
 		{k.Back, k.Quit},
 		{k.LineUp, k.LineDown},
 		{k.GotoTop, k.GotoBottom},
-		{k.Enter, k.Delete},
+		{k.Enter, k.Delete, k.Export},
 	}
 }
 
@@ -60,6 +61,10 @@ var browseKeys = browseKeyMap{
 		key.WithKeys("delete"),
 		key.WithHelp("delete", "marks a CRL for deletion"),
 	),
+	Export: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export a CRL"),
+	),
 	Y: key.NewBinding(
 		key.WithKeys("y"),
 		key.WithHelp("y", "confirm deletion"),
@@ -82,10 +87,11 @@ type BrowseModel struct {
 func NewBrowseModel(height int, cmds *commands.Commands) *BrowseModel {
 	columns := []table.Column{
 		{Title: "ID", Width: 2},
-		{Title: "Name", Width: 30},
+		{Title: "Name", Width: 24},
 		{Title: "This Update", Width: 11},
 		{Title: "Next Update", Width: 11},
-		{Title: "Url", Width: 15},
+		{Title: "Url", Width: 14},
+		{Title: "Signed", Width: 6},
 	}
 
 	tbl := table.New(table.WithColumns(columns), table.WithFocused(true), table.WithHeight(height-10), table.WithWidth(80))
@@ -118,12 +124,18 @@ func (m *BrowseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.ListCRLsResponseMsg:
 		rows := make([]table.Row, len(msg.CRLs))
 		for i, CRL := range msg.CRLs {
+			// pseudo-CRLs imported from a CA database are not signed by the CA
+			signed := "yes"
+			if CRL.Unsigned {
+				signed = "no"
+			}
 			rows[i] = table.Row{
 				strconv.Itoa(int(CRL.ID)),
 				CRL.Name,
 				CRL.ThisUpdate.Format(time.DateOnly),
 				CRL.NextUpdate.Format(time.DateOnly),
 				CRL.URL.String(),
+				signed,
 			}
 		}
 		m.table.SetRows(rows)
@@ -143,10 +155,15 @@ func (m *BrowseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				ThisUpdate: m.table.SelectedRow()[2],
 				NextUpdate: m.table.SelectedRow()[3],
 				URL:        m.table.SelectedRow()[4],
+				Unsigned:   m.table.SelectedRow()[5] == "no",
 			})
 			return m, cmd
 		case "delete":
 			m.markedForDeletion = m.table.SelectedRow()[0]
+		case "e":
+			if row := m.table.SelectedRow(); row != nil {
+				return m, m.commands.SelectForExport(row[1])
+			}
 		case "n":
 			m.markedForDeletion = ""
 		case "y":
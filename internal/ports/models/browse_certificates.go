@@ -0,0 +1,302 @@
+package models
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type browseCertificatesKeyMap struct {
+	table.KeyMap
+	Back       key.Binding
+	Quit       key.Binding
+	Enter      key.Binding
+	Delete     key.Binding
+	Y          key.Binding
+	N          key.Binding
+	Issuer     key.Binding
+	Expiry     key.Binding
+	Revocation key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *browseCertificatesKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Quit, k.Enter, k.Issuer, k.Expiry, k.Revocation}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *browseCertificatesKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Back, k.Quit},
+		{k.LineUp, k.LineDown},
+		{k.GotoTop, k.GotoBottom},
+		{k.Enter, k.Delete},
+		{k.Issuer, k.Expiry, k.Revocation},
+	}
+}
+
+var browseCertificatesKeys = browseCertificatesKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to main view"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open certificate"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("delete"),
+		key.WithHelp("delete", "marks a certificate for deletion"),
+	),
+	Y: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "confirm deletion"),
+	),
+	N: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "cancel deletion"),
+	),
+	Issuer: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter on issuer"),
+	),
+	Expiry: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "change the expiry window"),
+	),
+	Revocation: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "change the revocation filter"),
+	),
+	KeyMap: table.DefaultKeyMap(),
+}
+
+// expiryWindow filters certificates expiring within the window, a zero window shows all certificates and a negative window only expired certificates
+type expiryWindow struct {
+	name   string
+	window time.Duration
+}
+
+var expiryWindows = []expiryWindow{
+	{"all", 0},
+	{"expired", -1},
+	{"7 days", 7 * 24 * time.Hour},
+	{"30 days", 30 * 24 * time.Hour},
+	{"90 days", 90 * 24 * time.Hour},
+}
+
+var revocationFilters = []crl.RevocationFilter{crl.RevocationFilterAll, crl.RevocationFilterRevoked, crl.RevocationFilterNotRevoked}
+
+// BrowseCertificatesModel browses the certificates in the inventory
+type BrowseCertificatesModel struct {
+	keys              browseCertificatesKeyMap
+	table             table.Model
+	issuer            textinput.Model
+	certificates      []*crl.StoredCertificate
+	expiry            int
+	revocation        int
+	markedForDeletion string
+	errorMsg          string
+	styles            *styles.Styles
+	commands          *commands.Commands
+}
+
+func NewBrowseCertificatesModel(height int, cmds *commands.Commands) *BrowseCertificatesModel {
+	columns := []table.Column{
+		{Title: "ID", Width: 4},
+		{Title: "Subject", Width: 30},
+		{Title: "Issuer", Width: 30},
+		{Title: "Not After", Width: 10},
+		{Title: "Revoked", Width: 16},
+		{Title: "Source", Width: 24},
+		{Title: "Last Seen", Width: 10},
+	}
+
+	tbl := table.New(table.WithColumns(columns), table.WithFocused(true), table.WithHeight(height-12), table.WithWidth(140))
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(styles.Theme.ListComponentTitle).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(styles.Theme.FilePickerCurrent.GetForeground()).
+		Background(styles.Theme.BaseText.GetBackground()).
+		Bold(false)
+	tbl.SetStyles(s)
+
+	issuer := textinput.New()
+	issuer.Placeholder = "issuer"
+	issuer.Prompt = "Issuer: "
+	issuer.Width = 40
+
+	return &BrowseCertificatesModel{
+		keys:     browseCertificatesKeys,
+		table:    tbl,
+		issuer:   issuer,
+		styles:   styles.Theme,
+		commands: cmds,
+	}
+}
+
+func (m *BrowseCertificatesModel) Init() tea.Cmd {
+	return m.commands.ListStoredCertificates(m.filter(time.Now()))
+}
+
+// filtering is true while the issuer filter is being edited
+func (m *BrowseCertificatesModel) filtering() bool {
+	return m.issuer.Focused()
+}
+
+func (m *BrowseCertificatesModel) filter(now time.Time) crl.CertificateFilter {
+	filter := crl.CertificateFilter{
+		Issuer:     strings.TrimSpace(m.issuer.Value()),
+		Revocation: revocationFilters[m.revocation],
+	}
+
+	switch window := expiryWindows[m.expiry].window; {
+	case window < 0:
+		filter.ExpiresBefore = now
+	case window > 0:
+		filter.ExpiresBefore = now.Add(window)
+	}
+
+	return filter
+}
+
+func (m *BrowseCertificatesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case messages.ListCertificatesResponseMsg:
+		m.certificates = msg.Certificates
+		m.setRows()
+	case messages.CertificateDeleteConfirmationMsg:
+		if msg.DeletionSuccessful {
+			m.deleteFromRows()
+		}
+	case messages.ErrorMsg:
+		m.errorMsg = msg.Err.Error()
+		m.markedForDeletion = ""
+	case tea.KeyMsg:
+		if m.filtering() {
+			if msg.Type == tea.KeyEnter {
+				m.issuer.Blur()
+				m.table.Focus()
+				return m, m.commands.ListStoredCertificates(m.filter(time.Now()))
+			}
+			m.issuer, cmd = m.issuer.Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Issuer):
+			m.table.Blur()
+			return m, m.issuer.Focus()
+		case key.Matches(msg, m.keys.Expiry):
+			m.expiry = (m.expiry + 1) % len(expiryWindows)
+			return m, m.commands.ListStoredCertificates(m.filter(time.Now()))
+		case key.Matches(msg, m.keys.Revocation):
+			m.revocation = (m.revocation + 1) % len(revocationFilters)
+			return m, m.commands.ListStoredCertificates(m.filter(time.Now()))
+		case key.Matches(msg, m.keys.Enter):
+			if selected := m.selected(); selected != nil {
+				return m, m.commands.OpenStoredCertificate(selected.Fingerprint)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Delete):
+			if selected := m.selected(); selected != nil {
+				m.markedForDeletion = strconv.FormatInt(selected.ID, 10)
+			}
+		case key.Matches(msg, m.keys.N):
+			m.markedForDeletion = ""
+		case key.Matches(msg, m.keys.Y):
+			if m.markedForDeletion != "" {
+				return m, m.commands.DeleteStoredCertificate(m.markedForDeletion)
+			}
+		}
+	}
+	m.table, cmd = m.table.Update(msg)
+
+	return m, cmd
+}
+
+func (m *BrowseCertificatesModel) selected() *crl.StoredCertificate {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.certificates) {
+		return nil
+	}
+	return m.certificates[cursor]
+}
+
+func (m *BrowseCertificatesModel) setRows() {
+	rows := make([]table.Row, len(m.certificates))
+	for i, certificate := range m.certificates {
+		revoked := "no"
+		if certificate.Revoked() {
+			revoked = certificate.RevokedBy
+		}
+
+		rows[i] = table.Row{
+			strconv.FormatInt(certificate.ID, 10),
+			certificate.Subject,
+			certificate.Issuer,
+			certificate.NotAfter.Format(time.DateOnly),
+			revoked,
+			certificate.Source,
+			certificate.LastSeen.Format(time.DateOnly),
+		}
+	}
+	m.table.SetRows(rows)
+}
+
+func (m *BrowseCertificatesModel) deleteFromRows() {
+	m.certificates = slices.DeleteFunc(m.certificates, func(certificate *crl.StoredCertificate) bool {
+		return strconv.FormatInt(certificate.ID, 10) == m.markedForDeletion
+	})
+	m.setRows()
+	m.markedForDeletion = ""
+}
+
+func (m *BrowseCertificatesModel) View() string {
+	var s strings.Builder
+
+	s.WriteString("\n " + m.issuer.View())
+	s.WriteString("\n " + m.styles.Text.Render("Expires: ") + expiryWindows[m.expiry].name + "  " + m.styles.Text.Render("Revoked: ") + revocationFilterName(revocationFilters[m.revocation]))
+
+	if m.markedForDeletion != "" {
+		s.WriteString(m.styles.WarningText.Render("\n\n Do you want to delete certificate : " + m.markedForDeletion + " y(es) n(o)"))
+	}
+
+	if m.errorMsg != "" {
+		s.WriteString(m.styles.WarningText.Render("\n\n" + m.errorMsg))
+	}
+
+	s.WriteString("\n\n" + m.table.View())
+	return s.String()
+}
+
+func revocationFilterName(filter crl.RevocationFilter) string {
+	if filter == crl.RevocationFilterAll {
+		return "all"
+	}
+	return string(filter)
+}
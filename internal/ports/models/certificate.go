@@ -7,31 +7,44 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/tree"
 	"github.com/pimg/certguard/internal/ports/models/commands"
 	"github.com/pimg/certguard/internal/ports/models/messages"
 	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/certificate"
 	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/domain/ct"
+	"github.com/pimg/certguard/pkg/domain/ocsp"
+	"github.com/pimg/certguard/pkg/scan"
 )
 
 type certificateKeyMap struct {
-	Back   key.Binding
-	Quit   key.Binding
-	Home   key.Binding
-	Search key.Binding
-	OSCP   key.Binding
+	Back     key.Binding
+	Quit     key.Binding
+	Home     key.Binding
+	Search   key.Binding
+	OSCP     key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	Details  key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Compare  key.Binding
 }
 
 func (k *certificateKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Search, k.OSCP, k.Back, k.Home}
+	return []key.Binding{k.Search, k.OSCP, k.Details, k.Compare, k.Back, k.Home}
 }
 
 func (k *certificateKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Search, k.Home},
 		{k.OSCP},
+		{k.Up, k.Down, k.Details, k.Compare},
+		{k.PageUp, k.PageDown},
 		{k.Back, k.Quit},
 	}
 }
@@ -57,15 +70,51 @@ var certificateKeys = certificateKeyMap{
 		key.WithKeys("o"),
 		key.WithHelp("o", "perform OCSP request"),
 	),
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "select previous certificate in chain"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "select next certificate in chain"),
+	),
+	Details: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "toggle details of selected certificate"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "scroll details up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdn", "scroll details down"),
+	),
+	Compare: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "compare selected certificate with another certificate"),
+	),
 }
 
+const CERTIFICATE_DETAILS_WIDTH = 80
+
 type CertificateModel struct {
 	keys                 certificateKeyMap
 	styles               *styles.Styles
-	certificate          *x509.Certificate // TODO create custom struct and move parsing to domain model to do advanced parsing, also consider removing certificate as this can be obtained from the chiain
-	certificateChain     []*x509.Certificate
+	certificate          *certificate.Certificate
+	certificateChain     []*certificate.Certificate
+	selected             int
+	showDetails          bool
+	details              viewport.Model
+	warnings             []string
+	transparency         *ct.Report
+	findings             [][]certificate.Finding
+	lintProfile          string
+	endpoint             *scan.Result
+	staple               *ocsp.Staple
 	revocationInfo       *crl.RevokedCertificate
 	foundOnCRL           *bool
+	unsignedCRL          bool
 	errorMsg             string
 	OCSPStatus           string
 	OCSPRevocationDate   time.Time
@@ -73,12 +122,31 @@ type CertificateModel struct {
 	commands             *commands.Commands
 }
 
-func NewCertificateModel(cert *x509.Certificate, certificateChain []*x509.Certificate, cmds *commands.Commands) *CertificateModel {
+func NewCertificateModel(msg messages.PemCertificateMsg, height int, cmds *commands.Commands) *CertificateModel {
+	chain := make([]*certificate.Certificate, len(msg.CertificateChain))
+	for i, c := range msg.CertificateChain {
+		chain[i] = certificate.FromX509Certificate(c)
+	}
+
+	details := viewport.New(CERTIFICATE_DETAILS_WIDTH, max(height-TOP_INFO_HEIGHT, 10))
+	details.KeyMap = viewport.KeyMap{
+		PageUp:   certificateKeys.PageUp,
+		PageDown: certificateKeys.PageDown,
+	}
+
 	return &CertificateModel{
 		keys:             certificateKeys,
 		styles:           styles.Theme,
-		certificate:      cert,
-		certificateChain: certificateChain,
+		certificate:      certificate.FromX509Certificate(msg.Certificate),
+		certificateChain: chain,
+		selected:         len(chain) - 1,
+		details:          details,
+		warnings:         msg.Warnings,
+		transparency:     msg.Transparency,
+		findings:         msg.Findings,
+		lintProfile:      msg.LintProfile,
+		endpoint:         msg.Endpoint,
+		staple:           msg.Staple,
 		commands:         cmds,
 	}
 }
@@ -92,26 +160,47 @@ func (c *CertificateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case messages.ErrorMsg:
 		c.errorMsg = msg.Err.Error()
+	case tea.WindowSizeMsg:
+		c.details.Height = max(msg.Height-TOP_INFO_HEIGHT, 10)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "s":
-			cmd = c.commands.Search(c.certificate.SerialNumber.String())
+		switch {
+		case key.Matches(msg, c.keys.Search):
+			cmd = c.commands.Search(c.certificate.X509)
 			return c, cmd
-		case "o":
-			if len(c.certificate.OCSPServer) == 0 {
+		case key.Matches(msg, c.keys.OSCP):
+			if len(c.certificate.OCSPServers) == 0 {
 				c.errorMsg = "Certificate does not contain a OCSP Server"
 				return c, cmd
 			}
 
-			if len(c.certificateChain) <= 1 {
+			issuer := certificate.FindIssuer(c.certificate.X509, c.x509Chain())
+			if issuer == nil {
 				c.errorMsg = "Certificate does not contain a certificate chain, Issuer certificate missing"
 				return c, cmd
 			}
-			cmd = c.commands.OCSPRequest(c.certificate, c.certificateChain[1], c.certificate.OCSPServer[0])
+			cmd = c.commands.OCSPRequest(c.certificate.X509, issuer, c.certificate.OCSPServers[0])
+		case key.Matches(msg, c.keys.Up):
+			if c.selected > 0 {
+				c.selected--
+				c.refreshDetails()
+			}
+		case key.Matches(msg, c.keys.Down):
+			if c.selected < len(c.certificateChain)-1 {
+				c.selected++
+				c.refreshDetails()
+			}
+		case key.Matches(msg, c.keys.Details):
+			c.showDetails = !c.showDetails
+			c.refreshDetails()
+		case key.Matches(msg, c.keys.Compare):
+			cmd = c.commands.SelectForComparison(c.certificateChain[c.selected].X509)
+		default:
+			c.details, cmd = c.details.Update(msg)
 		}
 	case messages.GetRevokedCertificateMsg:
 		c.revocationInfo = msg.RevokedCertificate
 		c.foundOnCRL = &msg.Found
+		c.unsignedCRL = msg.Unsigned
 	case messages.OCSPResponseMsg:
 		c.OCSPStatus = msg.Status
 		c.OCSPRevocationDate = msg.RevocationDate
@@ -122,8 +211,15 @@ func (c *CertificateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (c *CertificateModel) View() string {
 	var s strings.Builder
+	if c.endpoint != nil {
+		s.WriteString(c.renderEndpoint() + "\n")
+	}
 	s.WriteString(c.renderCertificateChain())
 
+	for _, warning := range c.warnings {
+		s.WriteString("\n" + c.styles.WarningText.Render(warning))
+	}
+
 	if c.errorMsg != "" {
 		s.WriteString("\n\n\n" + c.errorMsg)
 	}
@@ -135,6 +231,9 @@ func (c *CertificateModel) View() string {
 			s.WriteString(c.styles.WarningText.Render("Revocation Reason: ") + c.revocationInfo.RevocationReason.String() + "\n")
 			s.WriteString(c.styles.WarningText.Render("Revocation Date: ") + c.revocationInfo.RevocationDate.String() + "\n")
 			s.WriteString(c.styles.WarningText.Render("Revoked by: ") + c.revocationInfo.RevokedBy + "\n")
+			if c.unsignedCRL {
+				s.WriteString(c.styles.WarningText.Render("Signed: ") + "no, the CRL is imported from a CA database\n")
+			}
 		}
 
 		if !*c.foundOnCRL {
@@ -153,38 +252,258 @@ func (c *CertificateModel) View() string {
 
 	certInfo := c.styles.CertificateChain.Render(s.String())
 
+	if c.showDetails {
+		return lipgloss.JoinHorizontal(lipgloss.Top, certInfo, c.styles.CertificateDetails.Render(c.details.View()))
+	}
+
 	return lipgloss.JoinVertical(lipgloss.Top, certInfo)
 }
 
+func (c *CertificateModel) x509Chain() []*x509.Certificate {
+	chain := make([]*x509.Certificate, len(c.certificateChain))
+	for i, cert := range c.certificateChain {
+		chain[i] = cert.X509
+	}
+	return chain
+}
+
+func (c *CertificateModel) refreshDetails() {
+	if len(c.certificateChain) == 0 {
+		return
+	}
+	details := renderCertificateDetails(c.styles, c.certificateChain[c.selected])
+	if c.certificateChain[c.selected].X509 == c.certificate.X509 && c.transparency != nil {
+		details += "\n" + renderTransparency(c.styles, c.transparency)
+	}
+	if c.selected < len(c.findings) {
+		details += "\n" + renderFindings(c.styles, c.lintProfile, c.findings[c.selected])
+	}
+	c.details.SetContent(details)
+	c.details.GotoTop()
+}
+
+func (c *CertificateModel) renderEndpoint() string {
+	serverName := c.endpoint.ServerName
+	if serverName == "" {
+		serverName = "none"
+	}
+
+	stapled := "not stapled"
+	if len(c.endpoint.StapledOCSP) > 0 {
+		stapled = fmt.Sprintf("stapled (%d bytes)", len(c.endpoint.StapledOCSP))
+	}
+
+	return c.styles.CertificateText.Render("Endpoint: ") + fmt.Sprintf("%s (SNI: %s)", c.endpoint.Address, serverName) + "\n" +
+		c.styles.CertificateText.Render("Connection: ") + fmt.Sprintf("%s, %s", c.endpoint.TLSVersion, c.endpoint.CipherSuite) + "\n" +
+		c.styles.CertificateText.Render("OCSP response: ") + stapled + "\n" +
+		c.renderStaple()
+}
+
+func (c *CertificateModel) renderStaple() string {
+	if c.staple == nil {
+		return ""
+	}
+
+	var s strings.Builder
+	status := c.styles.LintFailed.Render(string(c.staple.Status))
+	if c.staple.Status == ocsp.StapleValid {
+		status = c.styles.LintPassed.Render(string(c.staple.Status))
+	}
+	s.WriteString(c.styles.CertificateText.Render("OCSP staple: ") + status)
+
+	if c.staple.Response != nil {
+		nextUpdate := "none"
+		if !c.staple.Response.NextUpdate.IsZero() {
+			nextUpdate = c.staple.Response.NextUpdate.Format(time.RFC3339)
+		}
+		s.WriteString(fmt.Sprintf(" (%s, this update: %s, next update: %s)", ocsp.StatusName(c.staple.Response.Status), c.staple.Response.ThisUpdate.Format(time.RFC3339), nextUpdate))
+	}
+
+	if c.staple.Fresh != nil {
+		s.WriteString("\n" + c.styles.CertificateText.Render("OCSP responder: ") + ocsp.StatusName(c.staple.Fresh.Status))
+	}
+	s.WriteString("\n")
+
+	for _, problem := range c.staple.Problems {
+		s.WriteString(c.styles.WarningText.Render(problem) + "\n")
+	}
+
+	return s.String()
+}
+
 func (c *CertificateModel) renderCertificateChain() string {
-	certificate := c.certificateChain[0]
-	t := tree.Root(c.styles.CertificateTitle.Render(certificate.Subject.CommonName)).
-		Child(c.styles.CertificateText.Render("CommonName: ") + certificate.Subject.CommonName).
-		Child(c.styles.CertificateText.Render("Serialnumber: ") + certificate.SerialNumber.String()).
-		Child(c.styles.CertificateText.Render("DN: ") + parseDN(c.styles, certificate.Subject.String())).
-		Child(parseCountry(c.styles, certificate.Subject.Country)).
-		Child(c.styles.CertificateText.Render("Issuer: ") + certificate.Issuer.String()).
-		Child(c.styles.CertificateText.Render("NotBefore: ") + certificate.NotBefore.String()).
-		Child(c.styles.CertificateText.Render("NotAfter: ") + certificate.NotAfter.String())
-	buildCertificateTree(c.styles, t, c.certificateChain[1:])
+	if len(c.certificateChain) == 0 {
+		return ""
+	}
+
+	t := c.certificateBranch(0)
+	c.buildCertificateTree(t, 1)
 	return fmt.Sprint(t)
 }
 
-func buildCertificateTree(s *styles.Styles, t *tree.Tree, certificateChain []*x509.Certificate) {
-	if len(certificateChain) == 0 {
+func (c *CertificateModel) buildCertificateTree(t *tree.Tree, i int) {
+	if i >= len(c.certificateChain) {
 		return
 	}
-	certificate := certificateChain[0]
-	branch := tree.Root(s.CertificateTitle.Render(certificate.Subject.CommonName)).
-		Child(s.CertificateText.Render("CommonName: ") + certificate.Subject.CommonName).
-		Child(s.CertificateText.Render("Serialnumber: ") + certificate.SerialNumber.String()).
-		Child(s.CertificateText.Render("DN: ") + parseDN(s, certificate.Subject.String())).
-		Child(parseCountry(s, certificate.Subject.Country)).
-		Child(s.CertificateText.Render("Issuer: ") + certificate.Issuer.String()).
+	branch := c.certificateBranch(i)
+	t.Child(branch)
+	c.buildCertificateTree(branch, i+1)
+}
+
+// certificateBranch renders the certificate at position i of the chain with a summary of its lint findings
+func (c *CertificateModel) certificateBranch(i int) *tree.Tree {
+	branch := certificateBranch(c.styles, c.certificateChain[i], c.selected == i)
+	if i < len(c.findings) && len(c.findings[i]) > 0 {
+		errorCount := certificate.CountSeverity(c.findings[i], certificate.SeverityError)
+		warningCount := certificate.CountSeverity(c.findings[i], certificate.SeverityWarning)
+		summary := fmt.Sprintf("%d errors, %d warnings, %d notices", errorCount, warningCount, len(c.findings[i])-errorCount-warningCount)
+		if errorCount > 0 {
+			summary = c.styles.LintFailed.Render(summary)
+		}
+		branch.Child(c.styles.CertificateText.Render("Lint: ") + summary)
+	}
+	return branch
+}
+
+func certificateBranch(s *styles.Styles, certificate *certificate.Certificate, selected bool) *tree.Tree {
+	title := s.CertificateTitle.Render(certificate.CommonName)
+	if selected {
+		title = s.CertificateSelected.Render(certificate.CommonName)
+	}
+
+	return tree.Root(title).
+		Child(s.CertificateText.Render("CommonName: ") + certificate.CommonName).
+		Child(s.CertificateText.Render("Serialnumber: ") + certificate.SerialNumber).
+		Child(s.CertificateText.Render("DN: ") + parseDN(s, certificate.Subject)).
+		Child(parseCountry(s, certificate.X509.Subject.Country)).
+		Child(s.CertificateText.Render("Issuer: ") + certificate.Issuer).
 		Child(s.CertificateText.Render("NotBefore: ") + certificate.NotBefore.String()).
 		Child(s.CertificateText.Render("NotAfter: ") + certificate.NotAfter.String())
-	t.Child(branch)
-	buildCertificateTree(s, branch, certificateChain[1:])
+}
+
+func renderCertificateDetails(s *styles.Styles, cert *certificate.Certificate) string {
+	var str strings.Builder
+
+	str.WriteString(s.CertificateTitle.Render(cert.CommonName) + "\n\n")
+
+	writeDetail(s, &str, "Subject", cert.Subject)
+	writeDetail(s, &str, "Issuer", cert.Issuer)
+	writeDetail(s, &str, "Serialnumber", cert.SerialNumber)
+	writeDetail(s, &str, "NotBefore", cert.NotBefore.Format(time.RFC3339))
+	writeDetail(s, &str, "NotAfter", cert.NotAfter.Format(time.RFC3339))
+	writeDetail(s, &str, "Public Key", fmt.Sprintf("%s (%d bit)", cert.PublicKeyAlgorithm, cert.PublicKeySize))
+	writeDetail(s, &str, "Signature", cert.SignatureAlgorithm)
+	writeDetails(s, &str, "SANs", cert.SubjectAltNames)
+	writeDetails(s, &str, "Key Usage", withCritical(cert.KeyUsage, cert.KeyUsageCritical))
+	writeDetails(s, &str, "Ext Key Usage", cert.ExtKeyUsage)
+
+	if cert.BasicConstraints != nil {
+		writeDetails(s, &str, "Basic Constraints", withCritical([]string{cert.BasicConstraints.String()}, cert.BasicConstraints.Critical))
+	}
+
+	writeDetail(s, &str, "SKI", cert.SubjectKeyID)
+	writeDetail(s, &str, "AKI", cert.AuthorityKeyID)
+	writeDetails(s, &str, "Policies", cert.Policies)
+	writeDetails(s, &str, "OCSP", cert.OCSPServers)
+	writeDetails(s, &str, "CA Issuers", cert.IssuingCertificateURLs)
+	writeDetails(s, &str, "CRL DPs", cert.CRLDistributionPoints)
+
+	if cert.NameConstraints != nil {
+		writeDetails(s, &str, "Permitted Names", withCritical(cert.NameConstraints.Permitted, cert.NameConstraints.Critical))
+		writeDetails(s, &str, "Excluded Names", withCritical(cert.NameConstraints.Excluded, cert.NameConstraints.Critical))
+	}
+
+	writeDetail(s, &str, "SHA-1", cert.FingerprintSHA1)
+	writeDetail(s, &str, "SHA-256", cert.FingerprintSHA256)
+
+	return str.String()
+}
+
+func renderTransparency(s *styles.Styles, report *ct.Report) string {
+	var str strings.Builder
+
+	str.WriteString(s.CertificateTitle.Render("Certificate Transparency") + "\n\n")
+
+	if len(report.SCTs) == 0 {
+		str.WriteString(s.CertificateWarning.Render("Certificate does not contain embedded SCTs") + "\n")
+	}
+
+	for _, sct := range report.SCTs {
+		logName := "unknown log"
+		if sct.Log != nil {
+			logName = sct.Log.Description
+		}
+
+		status := "verified"
+		if sct.Err != nil {
+			status = sct.Err.Error()
+		}
+
+		writeDetail(s, &str, "Log", logName)
+		writeDetail(s, &str, "Log ID", certificate.FormatHex(sct.SCT.LogID))
+		writeDetail(s, &str, "Timestamp", sct.SCT.Timestamp.Format(time.RFC3339))
+		writeDetail(s, &str, "Signature", sct.SCT.SignatureAlgorithmName())
+		if sct.Valid() {
+			writeDetail(s, &str, "Status", status)
+		} else {
+			writeDetail(s, &str, "Status", s.WarningText.Render(status))
+		}
+		str.WriteString("\n")
+	}
+
+	policy := report.Policy
+	summary := fmt.Sprintf("%d valid SCTs from %d operators, %d SCTs from %d operators required", policy.ValidSCTs, policy.Operators, policy.RequiredSCTs, policy.MinOperators)
+	if policy.Compliant {
+		writeDetail(s, &str, "CT Policy", "compliant, "+summary)
+	} else {
+		writeDetail(s, &str, "CT Policy", s.WarningText.Render("not compliant, "+summary))
+	}
+
+	return str.String()
+}
+
+func renderFindings(s *styles.Styles, profile string, findings []certificate.Finding) string {
+	var str strings.Builder
+
+	str.WriteString(s.CertificateTitle.Render(fmt.Sprintf("Lint (%s)", profile)) + "\n\n")
+
+	if len(findings) == 0 {
+		str.WriteString(s.LintPassed.Render("no findings") + "\n")
+	}
+
+	for _, finding := range findings {
+		message := fmt.Sprintf("[%s] %s", finding.Severity, finding.Message)
+		switch finding.Severity {
+		case certificate.SeverityError:
+			message = s.LintFailed.Render(message)
+		case certificate.SeverityWarning:
+			message = s.WarningText.Render(message)
+		}
+		writeDetail(s, &str, finding.Rule, message)
+	}
+
+	return str.String()
+}
+
+func writeDetail(s *styles.Styles, str *strings.Builder, label, value string) {
+	if value == "" {
+		return
+	}
+	str.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, s.CertificateDetailLabel.Render(label+": "), s.CertificateDetailValue.Render(value)) + "\n")
+}
+
+func writeDetails(s *styles.Styles, str *strings.Builder, label string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	writeDetail(s, str, label, strings.Join(values, "\n"))
+}
+
+func withCritical(values []string, critical bool) []string {
+	if critical && len(values) > 0 {
+		return append([]string{"critical"}, values...)
+	}
+	return values
 }
 
 func parseDN(s *styles.Styles, dn string) string {
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/certificate"
+)
+
+type certificateRequestKeyMap struct {
+	Back     key.Binding
+	Quit     key.Binding
+	Home     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+}
+
+func (k *certificateRequestKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.PageUp, k.PageDown, k.Back, k.Home}
+}
+
+func (k *certificateRequestKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.PageUp, k.PageDown},
+		{k.Back, k.Home, k.Quit},
+	}
+}
+
+var certificateRequestKeys = certificateRequestKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to previous view"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	Home: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "back to the main view"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "scroll up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdn", "scroll down"),
+	),
+}
+
+// CertificateRequestModel shows the contents of a PKCS#10 CSR and the result of linting it against the CSR policy
+type CertificateRequestModel struct {
+	keys     certificateRequestKeyMap
+	styles   *styles.Styles
+	request  *certificate.CertificateRequest
+	lint     []certificate.LintResult
+	warnings []string
+	details  viewport.Model
+}
+
+func NewCertificateRequestModel(msg messages.CertificateRequestMsg, height int) *CertificateRequestModel {
+	details := viewport.New(CERTIFICATE_DETAILS_WIDTH, max(height-TOP_INFO_HEIGHT, 10))
+	details.KeyMap = viewport.KeyMap{
+		PageUp:   certificateRequestKeys.PageUp,
+		PageDown: certificateRequestKeys.PageDown,
+	}
+
+	c := &CertificateRequestModel{
+		keys:     certificateRequestKeys,
+		styles:   styles.Theme,
+		request:  msg.CertificateRequest,
+		lint:     msg.Lint,
+		warnings: msg.Warnings,
+		details:  details,
+	}
+	c.details.SetContent(c.renderDetails())
+
+	return c
+}
+
+func (c *CertificateRequestModel) Init() tea.Cmd {
+	return nil
+}
+
+func (c *CertificateRequestModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.details.Height = max(msg.Height-TOP_INFO_HEIGHT, 10)
+	case tea.KeyMsg:
+		c.details, cmd = c.details.Update(msg)
+	}
+	return c, cmd
+}
+
+func (c *CertificateRequestModel) View() string {
+	var s strings.Builder
+	for _, warning := range c.warnings {
+		s.WriteString(c.styles.WarningText.Render(warning) + "\n")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top, s.String(), c.styles.CertificateDetails.Render(c.details.View()))
+}
+
+func (c *CertificateRequestModel) renderDetails() string {
+	var str strings.Builder
+	s := c.styles
+	csr := c.request
+
+	str.WriteString(s.CertificateTitle.Render(csr.CommonName) + "\n\n")
+
+	writeDetail(s, &str, "Subject", csr.Subject)
+	writeDetails(s, &str, "SANs", csr.SubjectAltNames)
+	writeDetail(s, &str, "Public Key", fmt.Sprintf("%s (%d bit)", csr.PublicKeyAlgorithm, csr.PublicKeySize))
+	writeDetail(s, &str, "Signature", csr.SignatureAlgorithm)
+	if csr.SignatureValid() {
+		writeDetail(s, &str, "Signature Status", "valid")
+	} else {
+		writeDetail(s, &str, "Signature Status", s.WarningText.Render(csr.SignatureError.Error()))
+	}
+	writeDetails(s, &str, "Key Usage", withCritical(csr.KeyUsage, csr.KeyUsageCritical))
+	writeDetails(s, &str, "Ext Key Usage", csr.ExtKeyUsage)
+
+	if csr.BasicConstraints != nil {
+		writeDetails(s, &str, "Basic Constraints", withCritical([]string{csr.BasicConstraints.String()}, csr.BasicConstraints.Critical))
+	}
+
+	writeDetails(s, &str, "Other Extensions", csr.OtherExtensions)
+
+	str.WriteString("\n" + s.CertificateTitle.Render("Policy") + "\n\n")
+	for _, result := range c.lint {
+		if result.Passed {
+			writeDetail(s, &str, result.Rule, s.LintPassed.Render("✓ "+result.Message))
+		} else {
+			writeDetail(s, &str, result.Rule, s.LintFailed.Render("✗ "+result.Message))
+		}
+	}
+
+	return str.String()
+}
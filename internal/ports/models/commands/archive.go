@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/crl"
+	domain_crl "github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// ExportArchive writes the stored CRLs and the certificate inventory to a portable archive that can be imported in every storage backend
+func (c *Commands) ExportArchive(path string) tea.Cmd {
+	ctx := context.Background()
+	return func() tea.Msg {
+		revocationLists, err := c.storage.Repository.List(ctx)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not list the stored CRLs"), err),
+			}
+		}
+
+		// an unsigned CRL has no original CRL to archive, its CA database is imported again instead
+		revocationLists = slices.DeleteFunc(revocationLists, func(revocationList *domain_crl.CertificateRevocationList) bool {
+			return revocationList.Unsigned
+		})
+
+		certificates, err := c.storage.Repository.ListCertificates(ctx, domain_crl.CertificateFilter{})
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not list the certificates"), err),
+			}
+		}
+
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not create the archive"), err),
+			}
+		}
+
+		archive := &domain_crl.Archive{
+			Created:         time.Now(),
+			RevocationLists: revocationLists,
+			Certificates:    certificates,
+		}
+		err = archive.Write(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(path)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not write the archive"), err),
+			}
+		}
+
+		log.Printf("exported %d CRLs and %d certificates to %s", len(revocationLists), len(certificates), path)
+		return messages.ArchiveExportedMsg{
+			Path:            path,
+			RevocationLists: len(revocationLists),
+			Certificates:    len(certificates),
+		}
+	}
+}
+
+// ImportArchive stores the CRLs and certificates of an archive, CRLs are skipped when the stored CRL with the same name is as recent
+// nolint: errcheck // checking err in defer results in panic
+func (c *Commands) ImportArchive(path string) tea.Cmd {
+	ctx := context.Background()
+	return func() tea.Msg {
+		file, err := os.Open(path)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not open the archive"), err),
+			}
+		}
+		defer file.Close()
+
+		archive, err := domain_crl.ReadArchive(file)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(fmt.Errorf("could not read the archive %s", path), err),
+			}
+		}
+
+		stored, err := c.storage.Repository.List(ctx)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not list the stored CRLs"), err),
+			}
+		}
+		storedByName := domain_crl.LatestVersions(stored)
+
+		result := messages.ArchiveImportedMsg{Path: path}
+		for _, archived := range archive.RevocationLists {
+			if current, ok := storedByName[archived.Name]; ok && !current.ThisUpdate.Before(archived.ThisUpdate) {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("CRL %s: the stored CRL is as recent as the archived CRL", archived.Name))
+				continue
+			}
+
+			stream, err := crl.ParseRevocationListStream(archived.Raw)
+			if err != nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("CRL %s: %s", archived.Name, err))
+				continue
+			}
+
+			revocationList := &domain_crl.CertificateRevocationList{
+				Name:       stream.Issuer.CommonName,
+				Signature:  stream.Signature,
+				ThisUpdate: stream.ThisUpdate,
+				NextUpdate: stream.NextUpdate,
+				Raw:        archived.Raw,
+				URL:        archived.URL,
+			}
+			_, err = domain_crl.Ingest(ctx, revocationList, stream.Entries(), c.storage, nil)
+			if err != nil {
+				return messages.ErrorMsg{
+					Err: errors.Join(fmt.Errorf("could not store CRL %s", archived.Name), err),
+				}
+			}
+			result.RevocationLists++
+			result.RevokedCertificates += stream.Count
+		}
+
+		for _, certificate := range archive.Certificates {
+			_, err := c.storage.Repository.SaveCertificate(ctx, certificate)
+			if err != nil {
+				return messages.ErrorMsg{
+					Err: errors.Join(fmt.Errorf("could not store certificate %s", certificate.Subject), err),
+				}
+			}
+			result.Certificates++
+		}
+
+		log.Printf("imported %d CRLs and %d certificates from %s", result.RevocationLists, result.Certificates, path)
+		return result
+	}
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/adapter/memory"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMemoryCommands(t *testing.T) *Commands {
+	t.Helper()
+	storage, err := crl.NewStorage(memory.NewMemoryStorage(), t.TempDir(), t.TempDir())
+	assert.NoError(t, err)
+	return NewCommands(storage)
+}
+
+func TestExportImportArchive(t *testing.T) {
+	ctx := context.Background()
+	source := newMemoryCommands(t)
+
+	_, ok := source.ImportFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "ca.crl"))().(messages.CRLResponseMsg)
+	assert.True(t, ok)
+	_, err := source.storage.Repository.SaveCertificate(ctx, &crl.StoredCertificate{Fingerprint: "aa", Subject: "CN=leaf", SerialNumber: "10", NotAfter: time.Now(), Source: "input", FirstSeen: time.Now(), LastSeen: time.Now()})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "certguard.tar.gz")
+	exported := source.ExportArchive(path)().(messages.ArchiveExportedMsg)
+	assert.Equal(t, messages.ArchiveExportedMsg{Path: path, RevocationLists: 1, Certificates: 1}, exported)
+
+	_, ok = source.ExportArchive(path)().(messages.ErrorMsg)
+	assert.True(t, ok, "an existing archive is not overwritten")
+
+	target := newMemoryCommands(t)
+	imported := target.ImportArchive(path)().(messages.ArchiveImportedMsg)
+	assert.Equal(t, 1, imported.RevocationLists)
+	assert.Equal(t, 1, imported.RevokedCertificates)
+	assert.Equal(t, 1, imported.Certificates)
+	assert.Empty(t, imported.Skipped)
+
+	revocationLists, err := target.storage.Repository.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, revocationLists, 1)
+	count, err := target.storage.Repository.CountRevokedCertificates(ctx, revocationLists[0].ID, crl.RevokedCertificateFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	certificate, err := target.storage.Repository.FindCertificate(ctx, "aa")
+	assert.NoError(t, err)
+	assert.Equal(t, "CN=leaf", certificate.Subject)
+
+	// the stored CRL is as recent as the archived CRL
+	imported = target.ImportArchive(path)().(messages.ArchiveImportedMsg)
+	assert.Equal(t, 0, imported.RevocationLists)
+	assert.Len(t, imported.Skipped, 1)
+}
+
+func TestImportArchiveInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.tar.gz")
+	assert.NoError(t, os.WriteFile(path, []byte("not an archive"), 0o644))
+
+	msg, ok := newMemoryCommands(t).ImportArchive(path)().(messages.ErrorMsg)
+	assert.True(t, ok)
+	assert.ErrorContains(t, msg.Err, "not gzip compressed")
+}
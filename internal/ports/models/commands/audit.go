@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"golang.org/x/crypto/ocsp"
+)
+
+// origins of the revocation checks in the audit log
+const (
+	originCertificateView = "certificate view"
+	originScanDir         = "scan-dir"
+	originKeystore        = "keystore"
+	originTLSScan         = "tls scan"
+)
+
+// findRevokedCertificate searches the stored CRL of the issuer of cert for its serial number and records the check in the audit log.
+// The CRL of a CA is stored under the common name of the CA, like the OCSP responder a revocation on the CRL of another CA is ignored.
+// A certificate of an issuer without a stored CRL is recorded as not revoked, a failing lookup of the CRL is recorded as an error and returned.
+func (c *Commands) findRevokedCertificate(ctx context.Context, cert *x509.Certificate, origin string) (*crl.RevokedCertificate, error) {
+	entry := newAuditEntry(cert, crl.AuditMethodCRL, origin)
+	entry.Result = crl.AuditResultNotRevoked
+
+	revocationList, err := c.storage.Repository.Find(ctx, cert.Issuer.CommonName)
+	if errors.Is(err, crl.ErrNotFound) || (err == nil && (revocationList == nil || revocationList.ID == 0)) {
+		entry.Detail = "no CRL of the issuer is stored"
+		c.audit(ctx, entry)
+		return nil, nil
+	}
+	if err != nil {
+		entry.Result = crl.AuditResultError
+		entry.Detail = flattenError(err)
+		c.audit(ctx, entry)
+		return nil, err
+	}
+
+	// the version of the CRL is its this update
+	entry.Source = revocationList.Name
+	entry.SourceThisUpdate = revocationList.ThisUpdate
+
+	revokedCertificate, err := crl.FindRevocation(ctx, c.storage.Repository, revocationList.ID, cert.SerialNumber.String())
+	switch {
+	case err != nil:
+		entry.Result = crl.AuditResultError
+		entry.Detail = flattenError(err)
+	case revokedCertificate != nil:
+		revokedCertificate.RevokedBy = revocationList.Name
+		entry.Result = crl.AuditResultRevoked
+		entry.Detail = revokedCertificate.RevocationReason.String()
+	}
+	if err == nil && revocationList.Unsigned {
+		entry.Detail = strings.TrimPrefix(entry.Detail+", unsigned CRL imported from a CA database", ", ")
+	}
+
+	c.audit(ctx, entry)
+	return revokedCertificate, err
+}
+
+// requestOCSP queries the OCSP responder for cert and records the check in the audit log
+func (c *Commands) requestOCSP(ctx context.Context, cert, issuer *x509.Certificate, responder, origin string) (*ocsp.Response, error) {
+	response, err := queryOCSP(ctx, cert, issuer, responder)
+	if cert == nil {
+		return response, err
+	}
+
+	entry := newAuditEntry(cert, crl.AuditMethodOCSP, origin)
+	entry.Source = responder
+	switch {
+	case err != nil:
+		entry.Result = crl.AuditResultError
+		entry.Detail = flattenError(err)
+	case response.Status == ocsp.Good:
+		entry.Result = crl.AuditResultGood
+	case response.Status == ocsp.Revoked:
+		entry.Result = crl.AuditResultRevoked
+		entry.Detail = parseRevocationReason(response.RevocationReason)
+	default:
+		entry.Result = crl.AuditResultUnknown
+	}
+	if response != nil {
+		entry.SourceThisUpdate = response.ThisUpdate
+	}
+
+	c.audit(ctx, entry)
+	return response, err
+}
+
+func newAuditEntry(cert *x509.Certificate, method crl.AuditMethod, origin string) *crl.AuditEntry {
+	return &crl.AuditEntry{
+		CheckedAt:    time.Now(),
+		Method:       method,
+		SerialNumber: cert.SerialNumber.String(),
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		Origin:       origin,
+	}
+}
+
+// audit appends the entry to the audit log, a failure is logged and does not fail the revocation check
+func (c *Commands) audit(ctx context.Context, entry *crl.AuditEntry) {
+	_, err := c.storage.Repository.SaveAuditEntry(ctx, entry)
+	if err != nil {
+		log.Printf("could not write the audit log for serialnumber: %s, err: %v", entry.SerialNumber, err)
+	}
+}
+
+// AuditLog lists the revocation checks in the audit log that match the filter, the most recent first
+func (c *Commands) AuditLog(filter crl.AuditFilter) tea.Cmd {
+	ctx := context.Background()
+	return func() tea.Msg {
+		entries, err := c.storage.Repository.ListAuditEntries(ctx, filter)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not read the audit log"), err),
+			}
+		}
+
+		return messages.AuditLogMsg{
+			Filter:  filter,
+			Entries: entries,
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/adapter/memory"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+// readChain returns the github.com leaf certificate and its issuer
+func readChain(t *testing.T, cmds *Commands) (*x509.Certificate, *x509.Certificate) {
+	t.Helper()
+	certRaw, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "github.com-chain.pem"))
+	assert.NoError(t, err)
+	chain := cmds.ParsePemCertficate(string(certRaw))().(messages.PemCertificateMsg).CertificateChain
+
+	var leaf, issuer *x509.Certificate
+	for _, cert := range chain {
+		switch cert.Subject.CommonName {
+		case "github.com":
+			leaf = cert
+		case "Sectigo ECC Domain Validation Secure Server CA":
+			issuer = cert
+		}
+	}
+	assert.NotNil(t, leaf)
+	assert.NotNil(t, issuer)
+	return leaf, issuer
+}
+
+func TestSearchIsAudited(t *testing.T) {
+	ctx := context.Background()
+	cmds := newMemoryCommands(t)
+	leaf, intermediate := readChain(t, cmds)
+
+	thisUpdate := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	id, err := cmds.storage.Repository.Save(ctx, &crl.CertificateRevocationList{Name: leaf.Issuer.CommonName, ThisUpdate: thisUpdate, NextUpdate: thisUpdate.AddDate(0, 0, 7)})
+	assert.NoError(t, err)
+	_, err = cmds.storage.Repository.SaveRevokedCertificates(ctx, id, []x509.RevocationListEntry{{SerialNumber: leaf.SerialNumber, RevocationTime: thisUpdate, ReasonCode: 1}})
+	assert.NoError(t, err)
+
+	msg := cmds.Search(leaf)().(messages.GetRevokedCertificateMsg)
+	assert.True(t, msg.Found)
+	msg = cmds.Search(intermediate)().(messages.GetRevokedCertificateMsg)
+	assert.False(t, msg.Found)
+
+	entries, err := cmds.storage.Repository.ListAuditEntries(ctx, crl.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	notRevoked, revoked := entries[0], entries[1]
+	assert.Equal(t, crl.AuditMethodCRL, revoked.Method)
+	assert.Equal(t, leaf.SerialNumber.String(), revoked.SerialNumber)
+	assert.Equal(t, crl.AuditResultRevoked, revoked.Result)
+	assert.Equal(t, leaf.Issuer.CommonName, revoked.Source)
+	assert.True(t, thisUpdate.Equal(revoked.SourceThisUpdate))
+	assert.Equal(t, "keyCompromise", revoked.Detail)
+	assert.Equal(t, originCertificateView, revoked.Origin)
+
+	assert.Equal(t, crl.AuditResultNotRevoked, notRevoked.Result)
+	assert.Empty(t, notRevoked.Source, "no CRL of the issuer of the intermediate is stored")
+	assert.Equal(t, "no CRL of the issuer is stored", notRevoked.Detail)
+}
+
+func TestSearchMatchesTheCRLOfTheIssuer(t *testing.T) {
+	ctx := context.Background()
+	cmds := newMemoryCommands(t)
+	leaf, _ := readChain(t, cmds)
+
+	// another CA revoked a certificate with the serial number of the leaf, serial numbers are only unique per CA
+	thisUpdate := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	otherID, err := cmds.storage.Repository.Save(ctx, &crl.CertificateRevocationList{Name: "Other CA", Signature: []byte("other"), ThisUpdate: thisUpdate, NextUpdate: thisUpdate.AddDate(0, 0, 7)})
+	assert.NoError(t, err)
+	_, err = cmds.storage.Repository.SaveRevokedCertificates(ctx, otherID, []x509.RevocationListEntry{{SerialNumber: leaf.SerialNumber, RevocationTime: thisUpdate, ReasonCode: 1}})
+	assert.NoError(t, err)
+	_, err = cmds.storage.Repository.Save(ctx, &crl.CertificateRevocationList{Name: leaf.Issuer.CommonName, Signature: []byte("issuer"), ThisUpdate: thisUpdate, NextUpdate: thisUpdate.AddDate(0, 0, 7)})
+	assert.NoError(t, err)
+
+	msg := cmds.Search(leaf)().(messages.GetRevokedCertificateMsg)
+	assert.False(t, msg.Found)
+
+	entries, err := cmds.storage.Repository.ListAuditEntries(ctx, crl.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, crl.AuditResultNotRevoked, entries[0].Result)
+	assert.Equal(t, leaf.Issuer.CommonName, entries[0].Source)
+	assert.True(t, thisUpdate.Equal(entries[0].SourceThisUpdate))
+}
+
+// failingRepository fails to read the stored CRLs, like a locked or unreachable database
+type failingRepository struct {
+	*memory.MemoryStorage
+}
+
+func (r failingRepository) Find(_ context.Context, _ string) (*crl.CertificateRevocationList, error) {
+	return nil, errors.New("database is locked")
+}
+
+func TestSearchFailingRepositoryIsAudited(t *testing.T) {
+	ctx := context.Background()
+	repository := failingRepository{memory.NewMemoryStorage()}
+	storage, err := crl.NewStorage(repository, t.TempDir(), t.TempDir())
+	assert.NoError(t, err)
+	cmds := NewCommands(storage)
+	leaf, _ := readChain(t, cmds)
+
+	// a failing lookup is not recorded as not revoked, the certificate may be revoked on the CRL that could not be read
+	msg, ok := cmds.Search(leaf)().(messages.ErrorMsg)
+	assert.True(t, ok)
+	assert.ErrorContains(t, msg.Err, "database is locked")
+
+	entries, err := repository.ListAuditEntries(ctx, crl.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, crl.AuditResultError, entries[0].Result)
+	assert.Equal(t, "database is locked", entries[0].Detail)
+}
+
+func TestOCSPRequestIsAudited(t *testing.T) {
+	ctx := context.Background()
+	cmds := newMemoryCommands(t)
+	leaf, issuer := readChain(t, cmds)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "Invalid response")
+	}))
+	defer ts.Close()
+
+	_, ok := cmds.OCSPRequest(leaf, issuer, ts.URL)().(messages.ErrorMsg)
+	assert.True(t, ok)
+
+	entries, err := cmds.storage.Repository.ListAuditEntries(ctx, crl.AuditFilter{Method: crl.AuditMethodOCSP})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, ts.URL, entries[0].Source)
+	assert.Equal(t, crl.AuditResultError, entries[0].Result)
+	assert.Contains(t, entries[0].Detail, "could not parse OCSP response")
+	assert.NotContains(t, entries[0].Detail, "\n")
+}
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+	cmds := newMemoryCommands(t)
+	now := time.Now()
+	for i, method := range []crl.AuditMethod{crl.AuditMethodCRL, crl.AuditMethodOCSP, crl.AuditMethodCRL} {
+		_, err := cmds.storage.Repository.SaveAuditEntry(ctx, &crl.AuditEntry{CheckedAt: now.Add(time.Duration(i) * time.Minute), Method: method, SerialNumber: "4096", Result: crl.AuditResultNotRevoked})
+		assert.NoError(t, err)
+	}
+
+	filter := crl.AuditFilter{Method: crl.AuditMethodCRL, Limit: 1}
+	msg := cmds.AuditLog(filter)().(messages.AuditLogMsg)
+	assert.Equal(t, filter, msg.Filter)
+	assert.Len(t, msg.Entries, 1)
+	assert.True(t, now.Add(2*time.Minute).Equal(msg.Entries[0].CheckedAt), "the most recent check is returned first")
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// ImportCADatabase stores the revoked certificates of an OpenSSL index.txt, or of a JSON export of step-ca or EJBCA, as an unsigned pseudo-CRL.
+// The pseudo-CRL is named like the CRL of the CA would be, so the certificates the CA issued are searched on it, and is current for validity.
+// nolint: errcheck // checking err in defer results in panic
+func (c *Commands) ImportCADatabase(path, name string, validity time.Duration) tea.Cmd {
+	ctx := context.Background()
+	return func() tea.Msg {
+		file, err := os.Open(path)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not open the CA database"), err),
+			}
+		}
+		defer file.Close()
+
+		database, err := crl.ReadCADatabase(file)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(fmt.Errorf("could not read the CA database %s", path), err),
+			}
+		}
+
+		// a signed CRL is never replaced by the unsigned revocations of a CA database
+		if stored, err := c.storage.Repository.Find(ctx, name); err == nil && stored.ID != 0 && !stored.Unsigned {
+			return messages.ErrorMsg{
+				Err: fmt.Errorf("a signed CRL named %s is stored, import the CA database under another name", name),
+			}
+		}
+
+		revocationList := database.RevocationList(name, time.Now(), validity)
+		_, err = crl.Ingest(ctx, revocationList, database.Entries(), c.storage, nil)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(fmt.Errorf("could not store the CA database as CRL %s", name), err),
+			}
+		}
+
+		log.Printf("imported %d revoked certificates from %s database %s as unsigned CRL %s", len(database.RevokedCertificates), database.Format, path, name)
+		return messages.CADatabaseImportedMsg{
+			Name:                name,
+			Path:                path,
+			Format:              database.Format,
+			RevokedCertificates: len(database.RevokedCertificates),
+			Skipped:             database.Skipped,
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportCADatabase(t *testing.T) {
+	ctx := context.Background()
+	cmds := newMemoryCommands(t)
+	leaf, intermediate := readChain(t, cmds)
+
+	index := fmt.Sprintf("V\t270101000000Z\t\t1000\tunknown\t/CN=valid.example.com\n"+
+		"R\t270101000000Z\t260110120000Z,superseded\t%s\tunknown\t/CN=github.com\n", strings.ToUpper(leaf.SerialNumber.Text(16)))
+	path := filepath.Join(t.TempDir(), "index.txt")
+	assert.NoError(t, os.WriteFile(path, []byte(index), 0o600))
+
+	imported := cmds.ImportCADatabase(path, leaf.Issuer.CommonName, crl.DefaultCADatabaseValidity)().(messages.CADatabaseImportedMsg)
+	assert.Equal(t, messages.CADatabaseImportedMsg{Name: leaf.Issuer.CommonName, Path: path, Format: crl.CADatabaseOpenSSL, RevokedCertificates: 1, Skipped: 1}, imported)
+
+	revocationList, err := cmds.storage.Repository.Find(ctx, leaf.Issuer.CommonName)
+	assert.NoError(t, err)
+	assert.True(t, revocationList.Unsigned)
+	assert.WithinDuration(t, time.Now().Add(crl.DefaultCADatabaseValidity), revocationList.NextUpdate, time.Minute)
+
+	msg := cmds.Search(leaf)().(messages.GetRevokedCertificateMsg)
+	assert.True(t, msg.Found)
+	assert.True(t, msg.Unsigned)
+	assert.Equal(t, crl.RevocationReasonSuperseded, msg.RevokedCertificate.RevocationReason)
+
+	entries, err := cmds.storage.Repository.ListAuditEntries(ctx, crl.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, "superseded, unsigned CRL imported from a CA database", entries[0].Detail)
+
+	// importing the CA database again keeps the stored revocations
+	_, ok := cmds.ImportCADatabase(path, leaf.Issuer.CommonName, crl.DefaultCADatabaseValidity)().(messages.CADatabaseImportedMsg)
+	assert.True(t, ok)
+	msg = cmds.Search(leaf)().(messages.GetRevokedCertificateMsg)
+	assert.True(t, msg.Found)
+
+	// a revocation that is removed from the CA database is no longer found after importing it again
+	index = "V\t270101000000Z\t\t1000\tunknown\t/CN=valid.example.com\n"
+	assert.NoError(t, os.WriteFile(path, []byte(index), 0o600))
+	imported = cmds.ImportCADatabase(path, leaf.Issuer.CommonName, crl.DefaultCADatabaseValidity)().(messages.CADatabaseImportedMsg)
+	assert.Equal(t, 0, imported.RevokedCertificates)
+	msg = cmds.Search(leaf)().(messages.GetRevokedCertificateMsg)
+	assert.False(t, msg.Found)
+
+	// a signed CRL is not replaced by a CA database
+	_, err = cmds.storage.Repository.Save(ctx, &crl.CertificateRevocationList{Name: intermediate.Issuer.CommonName, Signature: []byte{1}, ThisUpdate: time.Now(), NextUpdate: time.Now()})
+	assert.NoError(t, err)
+	errorMsg, ok := cmds.ImportCADatabase(path, intermediate.Issuer.CommonName, crl.DefaultCADatabaseValidity)().(messages.ErrorMsg)
+	assert.True(t, ok)
+	assert.ErrorContains(t, errorMsg.Err, "a signed CRL named")
+
+	_, ok = cmds.ImportCADatabase(filepath.Join(t.TempDir(), "missing.txt"), "Internal CA", crl.DefaultCADatabaseValidity)().(messages.ErrorMsg)
+	assert.True(t, ok)
+}
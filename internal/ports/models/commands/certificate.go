@@ -1,30 +1,189 @@
 package commands
 
 import (
+	"context"
+	"crypto/x509"
 	"errors"
+	"fmt"
 	"log"
 	"slices"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pimg/certguard/internal/ports/models/messages"
 	"github.com/pimg/certguard/pkg/domain/certificate"
+	"github.com/pimg/certguard/pkg/domain/ct"
+)
+
+// source values describe where a certificate in the inventory was seen
+const (
+	sourceInput = "input"
+	sourceFile  = "file:"
+	sourceTLS   = "tls:"
 )
 
 func (c *Commands) ParsePemCertficate(pem string) tea.Cmd {
 	return func() tea.Msg {
-		certificateChain, err := certificate.ParsePEMCertificate([]byte(pem))
+		return c.parseCertificates([]byte(pem), sourceInput)
+	}
+}
+
+// ParseCertificatesWithPassword opens a password protected PKCS#12 bundle, the private key in the bundle is never stored
+func (c *Commands) ParseCertificatesWithPassword(data []byte, password, source string) tea.Cmd {
+	return func() tea.Msg {
+		certificateChain, err := certificate.ParseCertificates(data, password)
 		if err != nil {
-			log.Printf("failed to parse certificate: %s", err)
+			log.Printf("failed to open PKCS#12 bundle: %s", err)
 			return messages.ErrorMsg{
-				Err: errors.New("failed to parse certificate"),
+				Err: errors.Join(errors.New("failed to open PKCS#12 bundle"), err),
 			}
 		}
 
-		slices.Reverse(certificateChain)
-		log.Println("reversed certificate chain")
-		return messages.PemCertificateMsg{
-			Certificate:      certificateChain[len(certificateChain)-1],
-			CertificateChain: certificateChain,
+		return c.certificateChainMsg(certificateChain, source)
+	}
+}
+
+// parseCertificates detects the format of the certificate data and parses it, a password is requested for protected PKCS#12 bundles.
+// PEM bundles without certificates are routed to the CRL view when they contain a CRL, or to the CSR view when they contain a CSR.
+// The certificates are stored in the inventory with the source they were read from.
+func (c *Commands) parseCertificates(data []byte, source string) tea.Msg {
+	bundle, err := certificate.ParseBundle(data, "")
+	if errors.Is(err, certificate.ErrPasswordRequired) {
+		log.Println("PKCS#12 bundle requires a password")
+		return messages.PasswordRequiredMsg{
+			Data:   data,
+			Source: source,
+		}
+	}
+
+	if err != nil {
+		log.Printf("failed to parse certificate: %s", err)
+		return messages.ErrorMsg{
+			Err: errors.New("failed to parse certificate"),
+		}
+	}
+
+	notices := bundle.Notices()
+	for _, notice := range notices {
+		log.Println(notice)
+	}
+
+	if len(bundle.Certificates) == 0 && len(bundle.RevocationLists) > 0 {
+		if len(bundle.RevocationLists) > 1 {
+			log.Printf("PEM bundle contains %d CRLs, only the first CRL is imported", len(bundle.RevocationLists))
+		}
+		return c.ingestRevocationList(context.Background(), nil, bundle.RevocationLists[0].Raw)
+	}
+
+	if len(bundle.Certificates) == 0 && len(bundle.CertificateRequests) > 0 {
+		if len(bundle.CertificateRequests) > 1 {
+			notices = append(notices, fmt.Sprintf("%d certificate requests in the bundle, only the first is shown", len(bundle.CertificateRequests)))
+		}
+		return c.certificateRequestMsg(bundle.CertificateRequests[0], notices)
+	}
+
+	if len(bundle.Certificates) == 0 {
+		log.Println("PEM bundle does not contain certificates, CRLs or certificate requests")
+		return messages.ErrorMsg{
+			Err: errors.Join(errors.New("no certificates, CRLs or certificate requests found"), errors.New(strings.Join(notices, "\n"))),
+		}
+	}
+
+	if len(bundle.RevocationLists) > 0 {
+		notices = append(notices, fmt.Sprintf("%d CRL(s) in the bundle are not shown, import them separately", len(bundle.RevocationLists)))
+	}
+	if len(bundle.CertificateRequests) > 0 {
+		notices = append(notices, fmt.Sprintf("%d certificate request(s) in the bundle are not shown", len(bundle.CertificateRequests)))
+	}
+	if len(bundle.PublicKeys) > 0 {
+		notices = append(notices, fmt.Sprintf("%d public key(s) in the bundle are not shown", len(bundle.PublicKeys)))
+	}
+
+	return c.certificateChainMsg(bundle.Certificates, source, notices...)
+}
+
+// certificateChainMsg orders the certificates into a chain and returns it root first, as it is rendered in the certificate view
+// notices are shown to the user as warnings before any warnings about the chain itself.
+// The certificates are saved in the inventory, unless the source is empty.
+func (c *Commands) certificateChainMsg(certificates []*x509.Certificate, source string, notices ...string) tea.Msg {
+	chain, err := certificate.OrderChain(certificates)
+	if err != nil {
+		log.Printf("failed to order certificate chain: %s", err)
+		return messages.ErrorMsg{
+			Err: errors.Join(errors.New("failed to order certificate chain"), err),
+		}
+	}
+
+	for _, warning := range chain.Warnings {
+		log.Println(warning)
+	}
+	chain.Warnings = append(notices, chain.Warnings...)
+
+	leaf := chain.Leaf()
+	transparency, err := ct.Check(leaf, certificate.FindIssuer(leaf, chain.Certificates), c.ctLogs, c.ctPolicy)
+	if err != nil {
+		log.Printf("could not check certificate transparency: %s", err)
+		chain.Warnings = append(chain.Warnings, "could not parse the embedded SCTs")
+	}
+
+	if source != "" {
+		if err := c.saveCertificates(context.Background(), chain.Certificates, source); err != nil {
+			log.Printf("could not save certificates in the inventory: %s", err)
+			chain.Warnings = append(chain.Warnings, "could not save the certificates in the inventory")
+		}
+	}
+
+	certificateChain := slices.Clone(chain.Certificates)
+	slices.Reverse(certificateChain)
+	log.Println("ordered certificate chain")
+
+	findings := make([][]certificate.Finding, len(certificateChain))
+	for i, cert := range certificateChain {
+		findings[i] = c.lintProfile.Lint(cert, cert == leaf)
+	}
+
+	return messages.PemCertificateMsg{
+		Certificate:      leaf,
+		CertificateChain: certificateChain,
+		Warnings:         chain.Warnings,
+		Transparency:     transparency,
+		Findings:         findings,
+		LintProfile:      c.lintProfile.Name,
+	}
+}
+
+// certificateRequestMsg lints the CSR against the configured CSR policy
+func (c *Commands) certificateRequestMsg(csr *x509.CertificateRequest, notices []string) tea.Msg {
+	request := certificate.FromX509CertificateRequest(csr)
+	if !request.SignatureValid() {
+		log.Printf("invalid CSR signature: %s", request.SignatureError)
+	}
+
+	log.Println("parsed certificate request")
+	return messages.CertificateRequestMsg{
+		CertificateRequest: request,
+		Lint:               c.csrPolicy.Lint(request),
+		Warnings:           notices,
+	}
+}
+
+// SelectForComparison marks a certificate as the old certificate, it is compared with the next certificate that is parsed
+func (c *Commands) SelectForComparison(cert *x509.Certificate) tea.Cmd {
+	return func() tea.Msg {
+		log.Printf("selected certificate for comparison: %s", cert.Subject)
+		return messages.CompareCertificateMsg{
+			Certificate: cert,
+		}
+	}
+}
+
+// CompareCertificates compares a certificate with its renewal
+func (c *Commands) CompareCertificates(old, new *x509.Certificate) tea.Cmd {
+	return func() tea.Msg {
+		comparison := certificate.Compare(certificate.FromX509Certificate(old), certificate.FromX509Certificate(new))
+		log.Printf("compared certificates, %d unexpected differences", comparison.Differences())
+		return messages.CertificateComparisonMsg{
+			Comparison: comparison,
 		}
 	}
 }
@@ -1,19 +1,48 @@
 package commands
 
 import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/pkg/domain/certificate"
 	domain_crl "github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/domain/ct"
 )
 
 type Commands struct {
-	storage *domain_crl.Storage
+	storage     *domain_crl.Storage
+	ctLogs      *ct.LogList
+	ctPolicy    ct.Policy
+	csrPolicy   certificate.CSRPolicy
+	lintProfile *certificate.Profile
+	// crlIngest delivers the progress and result of the CRL that is being stored
+	crlIngest <-chan tea.Msg
 }
 
 func NewCommands(storage *domain_crl.Storage) *Commands {
 	return &Commands{
-		storage: storage,
+		storage:     storage,
+		ctLogs:      ct.BundledLogList(),
+		ctPolicy:    ct.DefaultPolicy(),
+		csrPolicy:   certificate.DefaultCSRPolicy(),
+		lintProfile: certificate.DefaultProfile(),
 	}
 }
 
+// SetCertificateTransparency overrides the bundled CT log list and the default CT policy
+func (c *Commands) SetCertificateTransparency(logs *ct.LogList, policy ct.Policy) {
+	c.ctLogs = logs
+	c.ctPolicy = policy
+}
+
+// SetCSRPolicy overrides the default policy CSRs are linted against
+func (c *Commands) SetCSRPolicy(policy certificate.CSRPolicy) {
+	c.csrPolicy = policy
+}
+
+// SetLintProfile overrides the bundled CA/Browser Forum lint profile
+func (c *Commands) SetLintProfile(profile *certificate.Profile) {
+	c.lintProfile = profile
+}
+
 func (c *Commands) CacheDir() string {
 	return c.storage.CacheDir()
 }
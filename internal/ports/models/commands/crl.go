@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"log"
@@ -20,7 +21,7 @@ func (c *Commands) GetCRL(url *url.URL) tea.Cmd {
 	ctx := context.Background()
 	return func() tea.Msg {
 		revocationListURL := strings.TrimSpace(url.String())
-		revocationList, err := crl.FetchRevocationList(revocationListURL)
+		rawCRL, err := crl.DownloadRevocationList(revocationListURL)
 		if err != nil {
 			errorMsg := fmt.Errorf("could not download CRL with provided URL: %s", url.String())
 			log.Println(errorMsg.Error())
@@ -29,15 +30,91 @@ func (c *Commands) GetCRL(url *url.URL) tea.Cmd {
 			}
 		}
 
-		err = domain_crl.Process(ctx, url, revocationList, c.storage)
+		return c.ingestRevocationList(ctx, url, rawCRL)
+	}
+}
+
+// ingestRevocationList parses and stores a DER encoded CRL in the background. CRLs with more than one batch of revoked certificates
+// report their progress with CRLProgressMsg, WaitForCRLIngest returns the next update until the CRL is stored.
+func (c *Commands) ingestRevocationList(ctx context.Context, URL *url.URL, rawCRL []byte) tea.Msg {
+	stream, err := crl.ParseRevocationListStream(rawCRL)
+	if err != nil {
+		log.Println("could not parse CRL")
+		return messages.ErrorMsg{
+			Err: errors.Join(errors.New("could not parse CRL"), err),
+		}
+	}
+	// a delta CRL has the name of its base CRL, storing it would replace the base CRL with the changes since the base CRL
+	if stream.Delta {
+		log.Printf("delta CRL of %s is not stored", stream.Issuer.CommonName)
+		return messages.ErrorMsg{
+			Err: fmt.Errorf("%s is a delta CRL, only base CRLs are stored, import the base CRL of the issuer", stream.Issuer.CommonName),
+		}
+	}
+
+	revocationList := &domain_crl.CertificateRevocationList{
+		Name:       stream.Issuer.CommonName,
+		Signature:  stream.Signature,
+		ThisUpdate: stream.ThisUpdate,
+		NextUpdate: stream.NextUpdate,
+		Raw:        rawCRL,
+		URL:        URL,
+	}
+
+	// progress updates are dropped while the previous update has not been read, the result is always delivered
+	updates := make(chan tea.Msg, 1)
+	c.crlIngest = updates
+	go func() {
+		defer close(updates)
+		id, err := domain_crl.Ingest(ctx, revocationList, stream.Entries(), c.storage, func(stored int) {
+			if stored == stream.Count {
+				return
+			}
+			select {
+			case updates <- messages.CRLProgressMsg{Name: revocationList.Name, Stored: stored, Total: stream.Count}:
+			default:
+			}
+		})
 		if err != nil {
-			return nil
+			log.Printf("could not store CRL: %s", err)
+			updates <- messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not store CRL"), err),
+			}
+			return
 		}
 
-		return messages.CRLResponseMsg{
-			RevocationList: revocationList,
-			URL:            url,
+		log.Printf("stored %d revoked certificates of CRL: %s", stream.Count, revocationList.Name)
+		updates <- c.storedRevocationListMsg(id, stream, URL)
+	}()
+
+	return <-updates
+}
+
+// WaitForCRLIngest returns the next progress update or the result of the CRL that is being stored
+func (c *Commands) WaitForCRLIngest() tea.Cmd {
+	updates := c.crlIngest
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
 		}
+		return msg
+	}
+}
+
+// storedRevocationListMsg returns the header of an ingested CRL for the CRL view, the view lists its revoked certificates per page
+func (c *Commands) storedRevocationListMsg(id int64, stream *crl.RevocationListStream, URL *url.URL) tea.Msg {
+	return messages.CRLResponseMsg{
+		ID: id,
+		RevocationList: &x509.RevocationList{
+			Issuer:     stream.Issuer,
+			Signature:  stream.Signature,
+			ThisUpdate: stream.ThisUpdate,
+			NextUpdate: stream.NextUpdate,
+			Raw:        stream.Raw,
+		},
+		URL:   URL,
+		Count: stream.Count,
 	}
 }
 
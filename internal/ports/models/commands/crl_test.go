@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/testpki"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportCRLProgress(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	cmds := NewCommands(storage)
+
+	entries := 2*crl.IngestBatchSize + 1
+	revocations := make([]testpki.Revocation, entries)
+	for i := range revocations {
+		revocations[i] = testpki.Revocation{SerialNumber: big.NewInt(int64(i + 1))}
+	}
+	pki, err := testpki.Generate(testpki.Options{Name: "Generated", Revocations: revocations})
+	assert.NoError(t, err)
+	der := pki.BaseCRL
+
+	path := filepath.Join(t.TempDir(), "generated.crl")
+	assert.NoError(t, os.WriteFile(path, der, 0o600))
+
+	msg := cmds.ImportFile(path)()
+	progress, ok := msg.(messages.CRLProgressMsg)
+	assert.True(t, ok)
+	assert.Equal(t, "Generated Intermediate CA", progress.Name)
+	assert.Equal(t, crl.IngestBatchSize, progress.Stored)
+	assert.Equal(t, entries, progress.Total)
+
+	for ok {
+		msg = cmds.WaitForCRLIngest()()
+		_, ok = msg.(messages.CRLProgressMsg)
+	}
+
+	crlMsg := msg.(messages.CRLResponseMsg)
+	assert.Equal(t, "Generated Intermediate CA", crlMsg.RevocationList.Issuer.CommonName)
+	assert.Equal(t, entries, crlMsg.Count)
+
+	page := cmds.ListRevokedCertificates(crlMsg.ID, crl.RevokedCertificateFilter{}, crl.IngestBatchSize, 100)().(messages.RevokedCertificatesPageMsg)
+	assert.Equal(t, entries, page.Total)
+	assert.Equal(t, crl.IngestBatchSize, page.Offset)
+	assert.Len(t, page.RevokedCertificates, 100)
+	assert.Equal(t, strconv.Itoa(crl.IngestBatchSize+1), page.RevokedCertificates[0].SerialNumber)
+}
+
+func TestImportGeneratedPKI(t *testing.T) {
+	cmds := newMemoryCommands(t)
+	pki, err := testpki.Generate(testpki.Options{
+		Leaves: []string{"base.certguard.test", "delta.certguard.test", "good.certguard.test"},
+		Revocations: []testpki.Revocation{
+			{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber), ReasonCode: 1},
+			{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber + 1), ReasonCode: 4, Delta: true},
+		},
+	})
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	_, err = pki.Write(dir)
+	assert.NoError(t, err)
+
+	msg, ok := cmds.ImportFile(filepath.Join(dir, "intermediate.crl"))().(messages.CRLResponseMsg)
+	assert.True(t, ok)
+	assert.Equal(t, pki.Intermediate.Certificate.Subject.CommonName, msg.RevocationList.Issuer.CommonName)
+
+	// the delta CRL has the name of the base CRL, it is refused instead of replacing the base CRL
+	errMsg, ok := cmds.ImportFile(filepath.Join(dir, "intermediate-delta.crl"))().(messages.ErrorMsg)
+	assert.True(t, ok)
+	assert.ErrorContains(t, errMsg.Err, "delta CRL")
+
+	stored, err := cmds.storage.Repository.Find(context.Background(), pki.Intermediate.Certificate.Subject.CommonName)
+	assert.NoError(t, err)
+	assert.Equal(t, pki.BaseCRL, stored.Raw)
+
+	base := cmds.Search(pki.Leaves[0].Certificate)().(messages.GetRevokedCertificateMsg)
+	assert.True(t, base.Found)
+	assert.Equal(t, crl.RevocationReasonKeyCompromise, base.RevokedCertificate.RevocationReason)
+	assert.False(t, cmds.Search(pki.Leaves[1].Certificate)().(messages.GetRevokedCertificateMsg).Found)
+	assert.False(t, cmds.Search(pki.Leaves[2].Certificate)().(messages.GetRevokedCertificateMsg).Found)
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	domain_crl "github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// dashboardWindow is the largest expiry bucket on the dashboard
+const dashboardWindow = 90 * 24 * time.Hour
+
+// ExpiryDashboard groups the certificates in the inventory on the time left until they expire
+func (c *Commands) ExpiryDashboard() tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		certificates, err := c.storage.Repository.ListCertificates(context.Background(), domain_crl.CertificateFilter{ExpiresBefore: now.Add(dashboardWindow)})
+		if err != nil {
+			log.Printf("could not list stored certificates: %v", err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not list stored certificates"), err),
+			}
+		}
+
+		return messages.ExpiryDashboardMsg{
+			Dashboard: domain_crl.NewExpiryDashboard(certificates, now),
+		}
+	}
+}
+
+// ExpiringCertificates lists the certificates in the inventory that are expired or expire within the given duration, ordered by expiry
+func (c *Commands) ExpiringCertificates(within time.Duration) tea.Cmd {
+	return c.ListStoredCertificates(domain_crl.CertificateFilter{ExpiresBefore: time.Now().Add(within)})
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiryDashboard(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	cmds := NewCommands(storage)
+
+	now := time.Now()
+	repository := storage.Repository.(*crl.MockRepository)
+	for fingerprint, certificate := range map[string]*crl.StoredCertificate{
+		"expired":  {Issuer: "CN=A", NotAfter: now.Add(-time.Hour)},
+		"3days":    {Issuer: "CN=A", NotAfter: now.Add(3 * 24 * time.Hour)},
+		"20days":   {Issuer: "CN=B", NotAfter: now.Add(20 * 24 * time.Hour)},
+		"60days":   {Issuer: "CN=A", NotAfter: now.Add(60 * 24 * time.Hour)},
+		"61days":   {Issuer: "CN=B", NotAfter: now.Add(61 * 24 * time.Hour)},
+		"200days":  {Issuer: "CN=C", NotAfter: now.Add(200 * 24 * time.Hour)},
+		"1000days": {Issuer: "CN=C", NotAfter: now.Add(1000 * 24 * time.Hour)},
+	} {
+		certificate.Fingerprint = fingerprint
+		_, err := repository.SaveCertificate(t.Context(), certificate)
+		assert.NoError(t, err)
+	}
+
+	dashboard := cmds.ExpiryDashboard()().(messages.ExpiryDashboardMsg).Dashboard
+	assert.Equal(t, 1, dashboard.Count(crl.Expired))
+	assert.Equal(t, 1, dashboard.Count(crl.ExpiresWithin7Days))
+	assert.Equal(t, 1, dashboard.Count(crl.ExpiresWithin30Days))
+	assert.Equal(t, 2, dashboard.Count(crl.ExpiresWithin90Days))
+	assert.Equal(t, "60days", dashboard.Certificates[crl.ExpiresWithin90Days][0].Fingerprint)
+
+	assert.Len(t, dashboard.Issuers, 2)
+	assert.Equal(t, crl.IssuerExpiry{Issuer: "CN=A", Counts: [crl.ExpiresLater]int{1, 1, 0, 1}}, dashboard.Issuers[0])
+	assert.Equal(t, crl.IssuerExpiry{Issuer: "CN=B", Counts: [crl.ExpiresLater]int{0, 0, 1, 1}}, dashboard.Issuers[1])
+
+	listMsg := cmds.ExpiringCertificates(30 * 24 * time.Hour)().(messages.ListCertificatesResponseMsg)
+	assert.Len(t, listMsg.Certificates, 3)
+	assert.Equal(t, "expired", listMsg.Certificates[0].Fingerprint)
+}
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		duration string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{name: "days", duration: "30d", want: 30 * 24 * time.Hour},
+		{name: "weeks", duration: "2w", want: 14 * 24 * time.Hour},
+		{name: "go duration", duration: "12h", want: 12 * time.Hour},
+		{name: "invalid days", duration: "xd", wantErr: true},
+		{name: "negative", duration: "-1d", wantErr: true},
+		{name: "unknown unit", duration: "3x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := crl.ParseDuration(tt.duration)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/crl"
+	domain_crl "github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// ExportToStdout is the path that exports a CRL to stdout instead of a file
+const ExportToStdout = "-"
+
+// SelectForExport opens the export of a stored CRL, the path and format are chosen in the export view
+func (c *Commands) SelectForExport(name string) tea.Cmd {
+	return func() tea.Msg {
+		log.Printf("selected CRL for export: %s", name)
+		return messages.ExportRevocationListMsg{
+			Name: name,
+		}
+	}
+}
+
+// ExportRevocationList writes a stored CRL and its revoked certificates to a new file in the format, or to stdout when the path is ExportToStdout.
+// The revoked certificates are read from the original CRL when it is stored, including their entry extensions.
+// nolint: errcheck // checking err in defer results in panic
+func (c *Commands) ExportRevocationList(name, path string, format domain_crl.ExportFormat) tea.Cmd {
+	ctx := context.Background()
+	return func() tea.Msg {
+		revocationList, err := c.storage.Repository.Find(ctx, name)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(fmt.Errorf("could not find CRL %s", name), err),
+			}
+		}
+
+		entries, err := c.revokedCertificateEntries(ctx, revocationList, format)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(fmt.Errorf("could not read the revoked certificates of CRL %s", name), err),
+			}
+		}
+
+		var out io.Writer = os.Stdout
+		if path != ExportToStdout {
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+			if err != nil {
+				return messages.ErrorMsg{
+					Err: errors.Join(errors.New("could not create the export file"), err),
+				}
+			}
+			defer file.Close()
+			out = file
+		}
+
+		count, err := domain_crl.ExportRevocationList(out, revocationList, entries, format)
+		if err != nil {
+			if path != ExportToStdout {
+				_ = os.Remove(path)
+			}
+			return messages.ErrorMsg{
+				Err: errors.Join(fmt.Errorf("could not export CRL %s", name), err),
+			}
+		}
+
+		log.Printf("exported CRL %s with %d revoked certificates as %s to %s", name, count, format, path)
+		return messages.RevocationListExportedMsg{
+			Name:                name,
+			Path:                path,
+			Format:              format,
+			RevokedCertificates: count,
+		}
+	}
+}
+
+// revokedCertificateEntries streams the revoked certificates from the original CRL, or reads them from the repository when the original CRL is not stored
+func (c *Commands) revokedCertificateEntries(ctx context.Context, revocationList *domain_crl.CertificateRevocationList, format domain_crl.ExportFormat) (iter.Seq2[x509.RevocationListEntry, error], error) {
+	if format.Raw() {
+		return nil, nil
+	}
+
+	if len(revocationList.Raw) > 0 {
+		stream, err := crl.ParseRevocationListStream(revocationList.Raw)
+		if err != nil {
+			return nil, err
+		}
+		return stream.Entries(), nil
+	}
+
+	revokedCertificates, err := c.storage.Repository.FindRevokedCertificates(ctx, revocationList.ID)
+	if err != nil {
+		return nil, err
+	}
+	return domain_crl.RevokedCertificateEntries(revokedCertificates), nil
+}
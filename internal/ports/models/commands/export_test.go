@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/testpki"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportRevocationList(t *testing.T) {
+	crlFile := filepath.Join("..", "..", "..", "..", "testing", "pki", "ca.crl")
+	cmds := newMemoryCommands(t)
+	_, ok := cmds.ImportFile(crlFile)().(messages.CRLResponseMsg)
+	assert.True(t, ok)
+
+	path := filepath.Join(t.TempDir(), "ca.csv")
+	exported := cmds.ExportRevocationList("NLX Intermediate CA", path, crl.ExportCSV)().(messages.RevocationListExportedMsg)
+	assert.Equal(t, messages.RevocationListExportedMsg{Name: "NLX Intermediate CA", Path: path, Format: crl.ExportCSV, RevokedCertificates: 1}, exported)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "30:a4:73:50:5b:62:b3:ae:5d:dc:89:c0:9c:4d:2c:0d:1f:28:62:d2")
+
+	_, ok = cmds.ExportRevocationList("NLX Intermediate CA", path, crl.ExportCSV)().(messages.ErrorMsg)
+	assert.True(t, ok, "an existing file is not overwritten")
+
+	path = filepath.Join(t.TempDir(), "ca.crl")
+	_, ok = cmds.ExportRevocationList("NLX Intermediate CA", path, crl.ExportDER)().(messages.RevocationListExportedMsg)
+	assert.True(t, ok)
+	original, err := os.ReadFile(crlFile)
+	assert.NoError(t, err)
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, original, data)
+
+	_, ok = cmds.ExportRevocationList("Unknown CA", filepath.Join(t.TempDir(), "unknown.csv"), crl.ExportCSV)().(messages.ErrorMsg)
+	assert.True(t, ok)
+}
+
+func TestExportRevocationListWithoutOriginal(t *testing.T) {
+	ctx := context.Background()
+	cmds := newMemoryCommands(t)
+	id, err := cmds.storage.Repository.Save(ctx, &crl.CertificateRevocationList{Name: "Example CA", ThisUpdate: time.Now(), NextUpdate: time.Now()})
+	assert.NoError(t, err)
+	_, err = cmds.storage.Repository.SaveRevokedCertificates(ctx, id, []x509.RevocationListEntry{{SerialNumber: big.NewInt(4096), RevocationTime: time.Now(), ReasonCode: 4}})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "index.txt")
+	exported := cmds.ExportRevocationList("Example CA", path, crl.ExportIndex)().(messages.RevocationListExportedMsg)
+	assert.Equal(t, 1, exported.RevokedCertificates)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(data), ",superseded\t1000\tunknown\tunknown\n"))
+
+	path = filepath.Join(t.TempDir(), "example.pem")
+	msg := cmds.ExportRevocationList("Example CA", path, crl.ExportPEM)().(messages.ErrorMsg)
+	assert.ErrorContains(t, msg.Err, "the original CRL of Example CA is not stored")
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "a failed export is removed")
+}
+
+func TestExportReplacedRevocationList(t *testing.T) {
+	// the second CRL is the same version of the CRL of the intermediate CA, it no longer revokes the first leaf
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	leaves := []string{"first.certguard.test", "second.certguard.test"}
+	first, err := testpki.Generate(testpki.Options{Now: now, Leaves: leaves, Revocations: []testpki.Revocation{
+		{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber), ReasonCode: 1},
+		{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber + 1), ReasonCode: 1},
+	}})
+	assert.NoError(t, err)
+	second, err := testpki.Generate(testpki.Options{Now: now, Leaves: leaves, Revocations: []testpki.Revocation{
+		{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber + 1), ReasonCode: 4},
+	}})
+	assert.NoError(t, err)
+
+	cmds := newMemoryCommands(t)
+	dir := t.TempDir()
+	for i, der := range [][]byte{first.BaseCRL, second.BaseCRL} {
+		path := filepath.Join(dir, fmt.Sprintf("intermediate-%d.crl", i))
+		assert.NoError(t, os.WriteFile(path, der, 0o644))
+		_, ok := cmds.ImportFile(path)().(messages.CRLResponseMsg)
+		assert.True(t, ok)
+	}
+
+	// the exports stream the stored raw CRL, the stored revoked certificates the CRL view lists are those of the raw CRL as well
+	name := second.Intermediate.Certificate.Subject.CommonName
+	stored, err := cmds.storage.Repository.Find(context.Background(), name)
+	assert.NoError(t, err)
+	revokedCertificates, err := cmds.storage.Repository.FindRevokedCertificates(context.Background(), stored.ID)
+	assert.NoError(t, err)
+	assert.Len(t, revokedCertificates, 1)
+	assert.Equal(t, "4097", revokedCertificates[0].SerialNumber)
+	assert.Equal(t, crl.RevocationReasonSuperseded, revokedCertificates[0].RevocationReason)
+
+	path := filepath.Join(dir, "export.crl")
+	_, ok := cmds.ExportRevocationList(name, path, crl.ExportDER)().(messages.RevocationListExportedMsg)
+	assert.True(t, ok)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, second.BaseCRL, data)
+
+	path = filepath.Join(dir, "index.txt")
+	exported := cmds.ExportRevocationList(name, path, crl.ExportIndex)().(messages.RevocationListExportedMsg)
+	assert.Equal(t, 1, exported.RevokedCertificates)
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+	assert.Contains(t, string(data), ",superseded\t1001\t")
+}
@@ -8,12 +8,12 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pimg/certguard/internal/ports/models/messages"
-	"github.com/pimg/certguard/pkg/crl"
 	"github.com/pimg/certguard/pkg/domain/certificate"
-	domain_crl "github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/scan"
 )
 
 func (c *Commands) ImportFile(path string) tea.Cmd {
@@ -32,37 +32,15 @@ func (c *Commands) ImportFile(path string) tea.Cmd {
 		switch filepath.Ext(path) {
 		case ".crl":
 			log.Println("importing CRL based on file extension")
-			revocationList, err := crl.ParseRevocationList(rawFile)
-			if err != nil {
-				log.Println("could not parse CRL")
-				return messages.ErrorMsg{
-					Err: errors.Join(errors.New("could not parse CRL"), err),
-				}
-			}
-
-			err = domain_crl.Process(ctx, nil, revocationList, c.storage)
-			if err != nil {
-				return nil
-			}
-
-			return messages.CRLResponseMsg{
-				RevocationList: revocationList,
-			}
+			return c.ingestRevocationList(ctx, nil, rawFile)
 		default:
-			log.Println("importing Certificate based on file extension")
-			certificateChain, err := certificate.ParsePEMCertificate(rawFile)
-			if err != nil {
-				log.Printf("failed to parse certificate: %s", err)
-				return messages.ErrorMsg{
-					Err: errors.New("failed to parse certificate"),
-				}
+			if format := certificate.DetectFormat(rawFile); slices.Contains(scan.KeystoreExtensions, strings.ToLower(filepath.Ext(path))) || format == certificate.FormatJKS || format == certificate.FormatJCEKS {
+				log.Println("importing keystore")
+				return c.keystoreMsg(ctx, rawFile, "", sourceFile+path)
 			}
 
-			slices.Reverse(certificateChain)
-			return messages.PemCertificateMsg{
-				Certificate:      certificateChain[len(certificateChain)-1],
-				CertificateChain: certificateChain,
-			}
+			log.Println("importing Certificate based on file extension")
+			return c.parseCertificates(rawFile, sourceFile+path)
 		}
 	}
 }
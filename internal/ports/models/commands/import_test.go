@@ -1,6 +1,13 @@
 package commands
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -20,7 +27,11 @@ func TestImportCRL(t *testing.T) {
 
 	crlMsg := msg.(messages.CRLResponseMsg)
 
-	assert.Len(t, crlMsg.RevocationList.RevokedCertificateEntries, 1)
+	assert.Equal(t, 1, crlMsg.Count)
+
+	page := cmds.ListRevokedCertificates(crlMsg.ID, crl.RevokedCertificateFilter{}, 0, 10)().(messages.RevokedCertificatesPageMsg)
+	assert.Equal(t, 1, page.Total)
+	assert.Len(t, page.RevokedCertificates, 1)
 }
 
 func TestImportPEM(t *testing.T) {
@@ -71,7 +82,7 @@ func TestImportMalformedCRL(t *testing.T) {
 	msg := cmds.ImportFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "malformed.crl"))()
 
 	errMsg := msg.(messages.ErrorMsg)
-	assert.ErrorContains(t, errMsg.Err, "could not parse CRL\nx509: malformed crl\ncannot parse CRL from")
+	assert.ErrorContains(t, errMsg.Err, "could not parse CRL\nmalformed crl")
 }
 
 func TestGetRevokedCertificatesNoRevokedCertificatesFound(t *testing.T) {
@@ -90,3 +101,97 @@ func TestGetRevokedCertificatesNoRevokedCertificatesFound(t *testing.T) {
 
 	assert.NotNil(t, msg)
 }
+
+func TestImportDER(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+
+	msg := cmds.ImportFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "github.com.der"))()
+
+	pemMsg := msg.(messages.PemCertificateMsg)
+	assert.Equal(t, "github.com", pemMsg.Certificate.Subject.CommonName)
+}
+
+func TestImportPKCS7(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+
+	msg := cmds.ImportFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "github.com-chain.p7b"))()
+
+	pemMsg := msg.(messages.PemCertificateMsg)
+	assert.Len(t, pemMsg.CertificateChain, 3)
+	assert.Equal(t, "github.com", pemMsg.Certificate.Subject.CommonName)
+}
+
+func TestImportPKCS12(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+
+	msg := cmds.ImportFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "leaf.certguard.test.p12"))()
+
+	passwordMsg := msg.(messages.PasswordRequiredMsg)
+	assert.NotEmpty(t, passwordMsg.Data)
+
+	errMsg := cmds.ParseCertificatesWithPassword(passwordMsg.Data, "wrong", passwordMsg.Source)().(messages.ErrorMsg)
+	assert.ErrorContains(t, errMsg.Err, "failed to open PKCS#12 bundle")
+
+	pemMsg := cmds.ParseCertificatesWithPassword(passwordMsg.Data, "certguard", passwordMsg.Source)().(messages.PemCertificateMsg)
+	assert.Len(t, pemMsg.CertificateChain, 2)
+	assert.Equal(t, "leaf.certguard.test", pemMsg.Certificate.Subject.CommonName)
+}
+
+func TestParsePemBundleWithCRL(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+
+	rawCRL, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "ca.crl"))
+	assert.NoError(t, err)
+
+	msg := cmds.ParsePemCertficate(string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: rawCRL})))()
+
+	crlMsg := msg.(messages.CRLResponseMsg)
+	assert.NotNil(t, crlMsg.RevocationList)
+}
+
+func TestParsePemBundleSkippedBlocks(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+
+	rawPem, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "github.com-chain.pem"))
+	assert.NoError(t, err)
+	rawPem = append(rawPem, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("secret")})...)
+
+	msg := cmds.ParsePemCertficate(string(rawPem))()
+
+	pemMsg := msg.(messages.PemCertificateMsg)
+	assert.Equal(t, []string{"skipped PEM block 4 (RSA PRIVATE KEY): private keys are not read"}, pemMsg.Warnings)
+}
+
+func TestParsePemCertificateRequest(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "csr.certguard.test"}}, key)
+	assert.NoError(t, err)
+
+	msg := cmds.ParsePemCertficate(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})))()
+
+	csrMsg := msg.(messages.CertificateRequestMsg)
+	assert.Equal(t, "csr.certguard.test", csrMsg.CertificateRequest.CommonName)
+	assert.True(t, csrMsg.CertificateRequest.SignatureValid())
+	assert.NotEmpty(t, csrMsg.Lint)
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/certificate"
+	domain_crl "github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// maxChainLength limits the number of issuers that are looked up in the inventory when opening a stored certificate
+const maxChainLength = 10
+
+// saveCertificates stores the certificates in the inventory, certificates that are already stored get a new last seen time
+func (c *Commands) saveCertificates(ctx context.Context, certificates []*x509.Certificate, source string) error {
+	seen := time.Now()
+	for _, cert := range certificates {
+		if _, err := c.storage.Repository.SaveCertificate(ctx, domain_crl.NewStoredCertificate(cert, source, seen)); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("saved %d certificate(s) from %s in the inventory", len(certificates), source)
+	return nil
+}
+
+// ListStoredCertificates lists the certificates in the inventory that match the filter
+func (c *Commands) ListStoredCertificates(filter domain_crl.CertificateFilter) tea.Cmd {
+	return func() tea.Msg {
+		log.Printf("requesting certificates from store, filter: %+v", filter)
+		certificates, err := c.storage.Repository.ListCertificates(context.Background(), filter)
+		if err != nil {
+			log.Printf("could not list stored certificates: %v", err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not list stored certificates"), err),
+			}
+		}
+
+		return messages.ListCertificatesResponseMsg{
+			Certificates: certificates,
+		}
+	}
+}
+
+// DeleteStoredCertificate removes a certificate from the inventory
+func (c *Commands) DeleteStoredCertificate(id string) tea.Cmd {
+	log.Printf("deleting certificate from store: %s", id)
+	return func() tea.Msg {
+		dbID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			log.Println("could not parse certificate ID, to be used for deletion")
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not parse certificate ID, to be used for deletion"), err),
+			}
+		}
+
+		err = c.storage.Repository.DeleteCertificate(context.Background(), dbID)
+		if err != nil {
+			log.Println("could not delete certificate")
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not delete certificate"), err),
+			}
+		}
+
+		log.Printf("deleted certificate from store: %s", id)
+		return messages.CertificateDeleteConfirmationMsg{
+			DeletionSuccessful: true,
+		}
+	}
+}
+
+// OpenStoredCertificate opens a certificate from the inventory in the certificate view, its issuers are added to the chain when they are stored as well
+func (c *Commands) OpenStoredCertificate(fingerprint string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		stored, err := c.storage.Repository.FindCertificate(ctx, fingerprint)
+		if err == nil && stored == nil {
+			err = errors.New("certificate not found")
+		}
+		if err != nil {
+			log.Printf("could not find stored certificate: %s, err: %v", fingerprint, err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not find stored certificate"), err),
+			}
+		}
+
+		cert, err := stored.Certificate()
+		if err != nil {
+			log.Printf("could not parse stored certificate: %s, err: %v", fingerprint, err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not parse stored certificate"), err),
+			}
+		}
+
+		return c.certificateChainMsg(c.storedChain(ctx, cert), "")
+	}
+}
+
+// storedChain looks up the issuers of the certificate in the inventory
+func (c *Commands) storedChain(ctx context.Context, cert *x509.Certificate) []*x509.Certificate {
+	chain := []*x509.Certificate{cert}
+	candidates, err := c.storage.Repository.ListCertificates(ctx, domain_crl.CertificateFilter{})
+	if err != nil {
+		log.Printf("could not list stored certificates to find the issuers: %v", err)
+		return chain
+	}
+
+	issuers := make([]*x509.Certificate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if parsed, err := candidate.Certificate(); err == nil {
+			issuers = append(issuers, parsed)
+		}
+	}
+
+	current := cert
+	for range maxChainLength {
+		if current.CheckSignatureFrom(current) == nil {
+			break
+		}
+
+		issuer := certificate.FindIssuer(current, issuers)
+		if issuer == nil {
+			break
+		}
+		chain = append(chain, issuer)
+		current = issuer
+	}
+
+	return chain
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInventory(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	cmds := NewCommands(storage)
+
+	path := filepath.Join("..", "..", "..", "..", "testing", "pki", "github.com-chain.pem")
+	rawPem, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	_ = cmds.ParsePemCertficate(string(rawPem))()
+	pemMsg := cmds.ImportFile(path)().(messages.PemCertificateMsg)
+
+	listMsg := cmds.ListStoredCertificates(crl.CertificateFilter{})().(messages.ListCertificatesResponseMsg)
+	assert.Len(t, listMsg.Certificates, 3)
+	for _, stored := range listMsg.Certificates {
+		assert.Equal(t, "file:"+path, stored.Source)
+	}
+
+	listMsg = cmds.ListStoredCertificates(crl.CertificateFilter{Issuer: "sectigo"})().(messages.ListCertificatesResponseMsg)
+	assert.Len(t, listMsg.Certificates, 1)
+	leaf := listMsg.Certificates[0]
+	assert.Equal(t, crl.Fingerprint(pemMsg.Certificate), leaf.Fingerprint)
+
+	openMsg := cmds.OpenStoredCertificate(leaf.Fingerprint)().(messages.PemCertificateMsg)
+	assert.Equal(t, pemMsg.Certificate.Raw, openMsg.Certificate.Raw)
+	assert.Len(t, openMsg.CertificateChain, 3)
+	assert.Equal(t, leaf.LastSeen, storage.Repository.(*crl.MockRepository).Certificates[leaf.Fingerprint].LastSeen)
+
+	deleteMsg := cmds.DeleteStoredCertificate(strconv.FormatInt(leaf.ID, 10))().(messages.CertificateDeleteConfirmationMsg)
+	assert.True(t, deleteMsg.DeletionSuccessful)
+
+	errMsg := cmds.OpenStoredCertificate(leaf.Fingerprint)().(messages.ErrorMsg)
+	assert.ErrorContains(t, errMsg.Err, "certificate not found")
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/certificate"
+	"github.com/pimg/certguard/pkg/scan"
+)
+
+// OpenKeystore lists the entries of a JKS, JCEKS or PKCS#12 keystore, a password is requested when the keystore is opened without one
+func (c *Commands) OpenKeystore(path string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("could not read keystore: %s, err: %v", path, err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not read keystore"), err),
+			}
+		}
+
+		return c.keystoreMsg(context.Background(), data, "", sourceFile+path)
+	}
+}
+
+// OpenKeystoreWithPassword opens a password protected keystore, private and secret keys in the keystore are never decrypted
+func (c *Commands) OpenKeystoreWithPassword(data []byte, password, source string) tea.Cmd {
+	return func() tea.Msg {
+		return c.keystoreMsg(context.Background(), data, password, source)
+	}
+}
+
+// ViewCertificateChain opens the certificates of a keystore entry in the certificate view, the certificates are already stored in the inventory
+func (c *Commands) ViewCertificateChain(entry *scan.KeystoreEntry) tea.Cmd {
+	return func() tea.Msg {
+		return c.certificateChainMsg(entry.Certificates, "")
+	}
+}
+
+// keystoreMsg checks the certificate of every keystore entry against the stored CRLs, the leaf certificates of private key entries are checked with their OCSP responder as well.
+// Trusted certificate entries are trust anchors and are not checked with OCSP.
+func (c *Commands) keystoreMsg(ctx context.Context, data []byte, password, source string) tea.Msg {
+	keystore, err := certificate.ParseKeystore(data, password)
+	if errors.Is(err, certificate.ErrPasswordRequired) {
+		log.Println("keystore requires a password")
+		return messages.PasswordRequiredMsg{
+			Data:     data,
+			Source:   source,
+			Keystore: true,
+		}
+	}
+
+	if err != nil {
+		log.Printf("failed to open keystore: %s", err)
+		return messages.ErrorMsg{
+			Err: errors.Join(errors.New("failed to open keystore"), err),
+		}
+	}
+
+	path := strings.TrimPrefix(source, sourceFile)
+	report := &scan.KeystoreReport{Path: path, Format: keystore.Format, Entries: make([]scan.KeystoreEntry, len(keystore.Entries)), Notices: keystore.Notices}
+	certificates := keystore.Certificates()
+	if err := c.saveCertificates(ctx, certificates, source); err != nil {
+		log.Printf("could not save certificates in the inventory: %s", err)
+		report.Notices = append(report.Notices, "could not save the certificates in the inventory")
+	}
+
+	var wg sync.WaitGroup
+	ocspRequests := make(chan struct{}, maxOCSPRequests)
+	for i, keystoreEntry := range keystore.Entries {
+		report.Entries[i] = scan.KeystoreEntry{KeystoreEntry: keystoreEntry, ReportEntry: scan.ReportEntry{Path: path}}
+		cert := keystoreEntry.Certificate()
+		if cert == nil {
+			continue
+		}
+
+		entry := &report.Entries[i].ReportEntry
+		entry.Subject = displayName(cert.Subject.CommonName, cert.Subject.String())
+		entry.Issuer = displayName(cert.Issuer.CommonName, cert.Issuer.String())
+		entry.SerialNumber = cert.SerialNumber.String()
+		entry.NotAfter = cert.NotAfter
+		c.checkStoredCRLs(ctx, entry, cert, originKeystore)
+
+		if keystoreEntry.Type != certificate.PrivateKeyEntry || entry.Revocation == scan.RevocationRevoked || len(cert.OCSPServer) == 0 {
+			continue
+		}
+
+		issuer := certificate.FindIssuer(cert, certificates)
+		if issuer == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ocspRequests <- struct{}{}
+			defer func() { <-ocspRequests }()
+			c.checkOCSPResponder(ctx, entry, cert, issuer, originKeystore)
+		}()
+	}
+	wg.Wait()
+
+	log.Printf("opened %s keystore with %d entries, %d revoked", report.Format, len(report.Entries), report.Revoked())
+	return messages.KeystoreMsg{Report: report}
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/certificate"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/scan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenKeystore(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	cmds := NewCommands(storage)
+
+	path := filepath.Join("..", "..", "..", "..", "testing", "pki", "truststore.jks")
+	passwordMsg := cmds.OpenKeystore(path)().(messages.PasswordRequiredMsg)
+	assert.True(t, passwordMsg.Keystore)
+	assert.Equal(t, "file:"+path, passwordMsg.Source)
+
+	errMsg := cmds.OpenKeystoreWithPassword(passwordMsg.Data, "wrong", passwordMsg.Source)().(messages.ErrorMsg)
+	assert.ErrorIs(t, errMsg.Err, certificate.ErrKeystoreIntegrity)
+
+	report := cmds.OpenKeystoreWithPassword(passwordMsg.Data, "certguard", passwordMsg.Source)().(messages.KeystoreMsg).Report
+	assert.Equal(t, path, report.Path)
+	assert.Equal(t, certificate.FormatJKS, report.Format)
+	assert.Len(t, report.Entries, 4)
+	for _, entry := range report.Entries {
+		assert.Equal(t, path, entry.Path)
+		assert.Equal(t, scan.RevocationNotListed, entry.Revocation)
+		assert.Equal(t, "CRL", entry.RevocationSource)
+	}
+	assert.Equal(t, "inway", report.Entries[2].Alias)
+	assert.Equal(t, "inway.test-crl", report.Entries[2].Subject)
+	assert.Len(t, storage.Repository.(*crl.MockRepository).Certificates, 5)
+
+	pemMsg := cmds.ViewCertificateChain(&report.Entries[3])().(messages.PemCertificateMsg)
+	assert.Equal(t, "leaf.certguard.test", pemMsg.Certificate.Subject.CommonName)
+	assert.Len(t, pemMsg.CertificateChain, 2)
+}
+
+func TestImportKeystore(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+	cmds := NewCommands(storage)
+
+	passwordMsg := cmds.ImportFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "keystore.jceks"))().(messages.PasswordRequiredMsg)
+	assert.True(t, passwordMsg.Keystore)
+
+	report := cmds.OpenKeystoreWithPassword(passwordMsg.Data, "certguard", passwordMsg.Source)().(messages.KeystoreMsg).Report
+	assert.Equal(t, certificate.FormatJCEKS, report.Format)
+	assert.Equal(t, []string{"certguard", "github.com"}, []string{report.Entries[0].Alias, report.Entries[1].Alias})
+}
@@ -2,12 +2,14 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/x509"
 	"errors"
 	"io"
 	"log"
 	"net/http"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pimg/certguard/internal/ports/models/messages"
@@ -16,75 +18,12 @@ import (
 )
 
 func (c *Commands) OCSPRequest(cert, issuerCert *x509.Certificate, ocspServerURL string) tea.Cmd {
-	opts := ocsp.RequestOptions{
-		Hash: crypto.SHA256,
-	}
-
+	ctx := context.Background()
 	return func() tea.Msg {
-		if cert == nil {
-			log.Printf("certificate is nil")
-			return messages.ErrorMsg{
-				Err: errors.New("certificate is nil"),
-			}
-		}
-
-		if issuerCert == nil {
-			log.Printf("certificate Issuer is nil")
-			return messages.ErrorMsg{
-				Err: errors.New("certificate Issuer is nil"),
-			}
-		}
-
-		OCSPServerURL, err := uri.ValidateURI(ocspServerURL)
-		if err != nil {
-			log.Printf("could not validate OCSP server URL: %s, err: %v", ocspServerURL, err)
-			return messages.ErrorMsg{
-				Err: errors.Join(errors.New("could not validate OCSP server URL"), err),
-			}
-		}
-
-		log.Printf("Querying OCSP server URL: %s, for certificate: %s", ocspServerURL, cert.SerialNumber.String())
-
-		buffer, err := ocsp.CreateRequest(cert, issuerCert, &opts)
-		if err != nil {
-			log.Printf("could not create OCSP request for certificate: %s", cert.SerialNumber.String())
-			return messages.ErrorMsg{
-				Err: errors.Join(errors.New("could not create OCSP request"), err),
-			}
-		}
-
-		httpRequest, err := http.NewRequest(http.MethodPost, OCSPServerURL.String(), bytes.NewReader(buffer))
-		if err != nil {
-			log.Printf("could not create OCSP request for certificate: %s, err: %v", cert.SerialNumber.String(), err)
-			return errors.Join(errors.New("could not create OCSP request"), err)
-		}
-
-		httpRequest.Header.Add("Content-Type", "application/ocsp-request")
-		httpRequest.Header.Add("Accept", "application/ocsp-response")
-		httpRequest.Header.Add("Host", OCSPServerURL.Hostname())
-
-		httpClient := &http.Client{}
-		httpResponse, err := httpClient.Do(httpRequest)
-		if err != nil {
-			log.Printf("could not send OCSP request for certificate: %s, err: %v", cert.SerialNumber.String(), err)
-			return messages.ErrorMsg{
-				Err: errors.Join(errors.New("could not send OCSP request"), err),
-			}
-		}
-		defer httpResponse.Body.Close()
-		OCSPResponseRaw, err := io.ReadAll(httpResponse.Body)
+		OCSPResponse, err := c.requestOCSP(ctx, cert, issuerCert, ocspServerURL, originCertificateView)
 		if err != nil {
-			log.Printf("could not read OCSP response for certificate: %s, err: %v", cert.SerialNumber.String(), err)
 			return messages.ErrorMsg{
-				Err: errors.Join(errors.New("could not read OCSP response for certificate"), err),
-			}
-		}
-
-		OCSPResponse, err := ocsp.ParseResponseForCert(OCSPResponseRaw, cert, issuerCert)
-		if err != nil {
-			log.Printf("could not parse OCSP response for certificate: %s, err: %v", cert.SerialNumber, err)
-			return messages.ErrorMsg{
-				Err: errors.Join(errors.New("could not parse OCSP response for certificate"), err),
+				Err: err,
 			}
 		}
 
@@ -102,6 +41,76 @@ func (c *Commands) OCSPRequest(cert, issuerCert *x509.Certificate, ocspServerURL
 	}
 }
 
+// ocspTimeout limits an OCSP request including reading the response, an unresponsive OCSP server does not block the check
+const ocspTimeout = 10 * time.Second
+
+// queryOCSP sends an OCSP request for cert to the OCSP server and returns the verified response
+func queryOCSP(ctx context.Context, cert, issuerCert *x509.Certificate, ocspServerURL string) (*ocsp.Response, error) {
+	opts := ocsp.RequestOptions{
+		Hash: crypto.SHA256,
+	}
+
+	if cert == nil {
+		log.Printf("certificate is nil")
+		return nil, errors.New("certificate is nil")
+	}
+
+	if issuerCert == nil {
+		log.Printf("certificate Issuer is nil")
+		return nil, errors.New("certificate Issuer is nil")
+	}
+
+	OCSPServerURL, err := uri.ValidateURI(ocspServerURL)
+	if err != nil {
+		log.Printf("could not validate OCSP server URL: %s, err: %v", ocspServerURL, err)
+		return nil, errors.Join(errors.New("could not validate OCSP server URL"), err)
+	}
+
+	log.Printf("Querying OCSP server URL: %s, for certificate: %s", ocspServerURL, cert.SerialNumber.String())
+
+	buffer, err := ocsp.CreateRequest(cert, issuerCert, &opts)
+	if err != nil {
+		log.Printf("could not create OCSP request for certificate: %s", cert.SerialNumber.String())
+		return nil, errors.Join(errors.New("could not create OCSP request"), err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, OCSPServerURL.String(), bytes.NewReader(buffer))
+	if err != nil {
+		log.Printf("could not create OCSP request for certificate: %s, err: %v", cert.SerialNumber.String(), err)
+		return nil, errors.Join(errors.New("could not create OCSP request"), err)
+	}
+
+	httpRequest.Header.Add("Content-Type", "application/ocsp-request")
+	httpRequest.Header.Add("Accept", "application/ocsp-response")
+	httpRequest.Header.Add("Host", OCSPServerURL.Hostname())
+
+	httpClient := &http.Client{Timeout: ocspTimeout}
+	httpResponse, err := httpClient.Do(httpRequest)
+	if err != nil {
+		log.Printf("could not send OCSP request for certificate: %s, err: %v", cert.SerialNumber.String(), err)
+		return nil, errors.Join(errors.New("could not send OCSP request"), err)
+	}
+	defer httpResponse.Body.Close()
+	OCSPResponseRaw, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		log.Printf("could not read OCSP response for certificate: %s, err: %v", cert.SerialNumber.String(), err)
+		return nil, errors.Join(errors.New("could not read OCSP response for certificate"), err)
+	}
+
+	return parseOCSPResponse(OCSPResponseRaw, cert, issuerCert)
+}
+
+// parseOCSPResponse parses an OCSP response for cert and verifies its signature, used for responder and stapled responses
+func parseOCSPResponse(raw []byte, cert, issuerCert *x509.Certificate) (*ocsp.Response, error) {
+	OCSPResponse, err := ocsp.ParseResponseForCert(raw, cert, issuerCert)
+	if err != nil {
+		log.Printf("could not parse OCSP response for certificate: %s, err: %v", cert.SerialNumber, err)
+		return nil, errors.Join(errors.New("could not parse OCSP response for certificate"), err)
+	}
+
+	return OCSPResponse, nil
+}
+
 func parseRevocationReason(reason int) string {
 	switch reason {
 	case ocsp.Unspecified:
@@ -1,16 +1,22 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/pimg/certguard/internal/ports/models/messages"
 	"github.com/pimg/certguard/pkg/domain/crl"
+	domain_ocsp "github.com/pimg/certguard/pkg/domain/ocsp"
+	"github.com/pimg/certguard/pkg/testpki"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
 )
 
 func TestInvalidOCSPRequestCertificateIsNil(t *testing.T) {
@@ -127,3 +133,56 @@ func TestOCSPInvalidResponse(t *testing.T) {
 
 	assert.ErrorContains(t, errMsg.Err, "could not parse OCSP response for certificate")
 }
+
+func TestOCSPRequestStoredCRLResponder(t *testing.T) {
+	cmds := newMemoryCommands(t)
+	pki, err := testpki.Generate(testpki.Options{
+		Leaves:      []string{"good.certguard.test", "revoked.certguard.test"},
+		Revocations: []testpki.Revocation{{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber + 1), ReasonCode: ocsp.KeyCompromise}},
+	})
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	_, err = pki.Write(dir)
+	assert.NoError(t, err)
+	_, ok := cmds.ImportFile(filepath.Join(dir, "intermediate.crl"))().(messages.CRLResponseMsg)
+	assert.True(t, ok)
+
+	responder, err := domain_ocsp.NewResponder(cmds.storage.Repository, []domain_ocsp.ResponderIssuer{{
+		Certificate:          pki.Intermediate.Certificate,
+		ResponderCertificate: pki.Responder.Certificate,
+		ResponderKey:         pki.Responder.Key,
+	}})
+	assert.NoError(t, err)
+	ts := httptest.NewServer(responder)
+	defer ts.Close()
+
+	good := cmds.OCSPRequest(pki.Leaves[0].Certificate, pki.Intermediate.Certificate, ts.URL)().(messages.OCSPResponseMsg)
+	assert.Equal(t, "Good", good.Status)
+
+	revoked := cmds.OCSPRequest(pki.Leaves[1].Certificate, pki.Intermediate.Certificate, ts.URL)().(messages.OCSPResponseMsg)
+	assert.Equal(t, "Revoked", revoked.Status)
+	assert.Equal(t, "key compromise", revoked.RevocationReason)
+	assert.Equal(t, pki.Revocations[0].RevokedAt, revoked.RevocationDate)
+}
+
+func TestOCSPRequestUnresponsiveServer(t *testing.T) {
+	pki, err := testpki.Generate(testpki.Options{})
+	assert.NoError(t, err)
+
+	// the server answers when the test ends, after the request gave up
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	_, err = queryOCSP(ctx, pki.Leaves[0].Certificate, pki.Intermediate.Certificate, ts.URL)
+	assert.ErrorContains(t, err, "could not send OCSP request")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), ocspTimeout)
+}
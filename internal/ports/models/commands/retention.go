@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	domain_crl "github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// PruneRevocationLists removes the stored CRLs selected by the retention policy, a dry run only reports the CRLs that would be removed
+func (c *Commands) PruneRevocationLists(policy domain_crl.RetentionPolicy, dryRun bool) tea.Cmd {
+	ctx := context.Background()
+	return func() tea.Msg {
+		pruned, err := domain_crl.Prune(ctx, c.storage, policy, time.Now(), dryRun)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not prune the stored CRLs"), err),
+			}
+		}
+
+		for _, revocationList := range pruned {
+			log.Printf("pruned CRL %s (dry run: %t): %s", revocationList.RevocationList.Name, dryRun, revocationList.Reason)
+		}
+		return messages.PrunedRevocationListsMsg{
+			DryRun: dryRun,
+			Pruned: pruned,
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruneRevocationLists(t *testing.T) {
+	ctx := context.Background()
+	cmds := newMemoryCommands(t)
+
+	now := time.Now()
+	for name, nextUpdate := range map[string]time.Time{"Current CA": now.AddDate(0, 0, 7), "Expired CA": now.AddDate(0, 0, -40)} {
+		_, err := cmds.storage.Repository.Save(ctx, &crl.CertificateRevocationList{Name: name, ThisUpdate: now.AddDate(0, 0, -50), NextUpdate: nextUpdate, LastRefreshed: now})
+		assert.NoError(t, err)
+	}
+	policy := crl.RetentionPolicy{ExpiredFor: 30 * 24 * time.Hour}
+
+	msg := cmds.PruneRevocationLists(policy, true)().(messages.PrunedRevocationListsMsg)
+	assert.True(t, msg.DryRun)
+	assert.Len(t, msg.Pruned, 1)
+	assert.Equal(t, "Expired CA", msg.Pruned[0].RevocationList.Name)
+
+	revocationLists, err := cmds.storage.Repository.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, revocationLists, 2, "a dry run does not remove CRLs")
+
+	msg = cmds.PruneRevocationLists(policy, false)().(messages.PrunedRevocationListsMsg)
+	assert.False(t, msg.DryRun)
+	assert.Len(t, msg.Pruned, 1)
+
+	revocationLists, err = cmds.storage.Repository.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, revocationLists, 1)
+	assert.Equal(t, "Current CA", revocationLists[0].Name)
+}
@@ -6,7 +6,6 @@ import (
 	"crypto/x509/pkix"
 	"errors"
 	"log"
-	"math/big"
 	"net/url"
 	"strconv"
 	"time"
@@ -22,6 +21,8 @@ type GetRevokedCertificatesArgs struct {
 	ThisUpdate string
 	NextUpdate string
 	URL        string
+	// Unsigned is true for a pseudo-CRL imported from a CA database
+	Unsigned bool
 }
 
 func (c *Commands) GetRevokedCertificates(args *GetRevokedCertificatesArgs) tea.Cmd {
@@ -63,51 +64,66 @@ func (c *Commands) GetRevokedCertificates(args *GetRevokedCertificatesArgs) tea.
 			}
 		}
 
-		certificates, err := c.storage.Repository.FindRevokedCertificates(ctx, ID)
+		count, err := c.storage.Repository.CountRevokedCertificates(ctx, ID, crl.RevokedCertificateFilter{})
 		if err != nil {
-			log.Printf("could not retrieve revoked certificates: %v", err)
+			log.Printf("could not count revoked certificates: %v", err)
 			return messages.ErrorMsg{
-				Err: errors.Join(errors.New("could not parse CRL"), err),
+				Err: errors.Join(errors.New("could not count revoked certificates"), err),
 			}
 		}
 
-		revokedCertificates := make([]x509.RevocationListEntry, len(certificates))
-		for i, cert := range certificates {
-			serialNumber := new(big.Int)
-			serialNumber, ok := serialNumber.SetString(cert.SerialNumber, 10)
-			if !ok {
-				log.Printf("could not parse serialNumber: %v", cert)
-				return messages.ErrorMsg{
-					Err: errors.Join(errors.New("could not parse serialNumber"), err),
-				}
-			}
+		return messages.CRLResponseMsg{
+			ID: ID,
+			RevocationList: &x509.RevocationList{
+				Issuer:     pkix.Name{CommonName: args.CN},
+				ThisUpdate: thisUpdate,
+				NextUpdate: nextUpdate,
+			},
+			URL:      URL,
+			Count:    count,
+			Unsigned: args.Unsigned,
+		}
+	}
+}
 
-			reasonCode := convertReasonCode(cert.RevocationReason)
+// ListRevokedCertificates reads a page of the revoked certificates of a stored CRL that match the filter
+func (c *Commands) ListRevokedCertificates(revocationListID int64, filter crl.RevokedCertificateFilter, offset, limit int) tea.Cmd {
+	log.Printf("listing revoked certificates of CRL: %d from offset: %d", revocationListID, offset)
+	ctx := context.Background()
+	return func() tea.Msg {
+		total, err := c.storage.Repository.CountRevokedCertificates(ctx, revocationListID, filter)
+		if err != nil {
+			log.Printf("could not count revoked certificates: %v", err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not count revoked certificates"), err),
+			}
+		}
 
-			revokedCertificates[i] = x509.RevocationListEntry{
-				SerialNumber:   serialNumber,
-				RevocationTime: cert.RevocationDate,
-				ReasonCode:     reasonCode,
+		revokedCertificates, err := c.storage.Repository.ListRevokedCertificates(ctx, revocationListID, filter, offset, limit)
+		if err != nil {
+			log.Printf("could not list revoked certificates: %v", err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not list revoked certificates"), err),
 			}
 		}
 
-		return messages.CRLResponseMsg{
-			RevocationList: &x509.RevocationList{
-				Issuer:                    pkix.Name{CommonName: args.CN},
-				ThisUpdate:                thisUpdate,
-				NextUpdate:                nextUpdate,
-				RevokedCertificateEntries: revokedCertificates,
-			},
-			URL: URL,
+		return messages.RevokedCertificatesPageMsg{
+			RevocationListID:    revocationListID,
+			Filter:              filter,
+			Offset:              offset,
+			Total:               total,
+			RevokedCertificates: revokedCertificates,
 		}
 	}
 }
 
-func (c *Commands) Search(serialnumber string) tea.Cmd {
+// Search searches the stored CRLs for the serial number of cert, the check is recorded in the audit log
+func (c *Commands) Search(cert *x509.Certificate) tea.Cmd {
+	serialnumber := cert.SerialNumber.String()
 	log.Printf("search stored CRLs for serialnumber: %s", serialnumber)
 	ctx := context.Background()
 	return func() tea.Msg {
-		revokedCertificate, err := c.storage.Repository.FindRevokedCertificate(ctx, serialnumber)
+		revokedCertificate, err := c.findRevokedCertificate(ctx, cert, originCertificateView)
 		if err != nil {
 			log.Printf("could not perform find action on serialnumber: %s", serialnumber)
 			return messages.ErrorMsg{
@@ -122,36 +138,58 @@ func (c *Commands) Search(serialnumber string) tea.Cmd {
 			}
 		}
 
+		// revocations imported from a CA database are not signed by the CA
+		revocationList, err := c.storage.Repository.Find(ctx, revokedCertificate.RevokedBy)
 		return messages.GetRevokedCertificateMsg{
 			RevokedCertificate: revokedCertificate,
 			Found:              true,
+			Unsigned:           err == nil && revocationList.Unsigned,
 		}
 	}
 }
 
-func convertReasonCode(reason crl.RevocationReason) int {
-	switch reason {
-	case crl.RevocationReasonUnspecified:
-		return 0
-	case crl.RevocationReasonKeyCompromise:
-		return 1
-	case crl.RevocationReasonCACompromise:
-		return 2
-	case crl.RevocationReasonAffiliationChanged:
-		return 3
-	case crl.RevocationReasonSuperseded:
-		return 4
-	case crl.RevocationReasonCessationOfOperation:
-		return 5
-	case crl.RevocationReasonCertificateHold:
-		return 6
-	case crl.RevocationReasonRemoveFromCRL:
-		return 8
-	case crl.RevocationReasonPriviledgeWithdrawn:
-		return 9
-	case crl.RevocationReasonAACompromise:
-		return 10
-	default:
-		return 0
+// SearchSerialNumber searches all stored CRLs for a serial number in decimal, hex or colon separated hex notation
+func (c *Commands) SearchSerialNumber(query string) tea.Cmd {
+	log.Printf("search stored CRLs for serial number: %s", query)
+	ctx := context.Background()
+	return func() tea.Msg {
+		serialNumbers, err := crl.ParseSerialNumber(query)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not parse serial number"), err),
+			}
+		}
+
+		revocationLists, err := c.storage.Repository.List(ctx)
+		if err != nil {
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not list the stored CRLs"), err),
+			}
+		}
+		latest := crl.LatestVersions(revocationLists)
+
+		revokedCertificates := make([]*crl.RevokedCertificate, 0)
+		for _, serialNumber := range serialNumbers {
+			found, err := c.storage.Repository.FindRevokedCertificatesBySerialNumber(ctx, serialNumber)
+			if err != nil {
+				log.Printf("could not perform find action on serialnumber: %s", serialNumber)
+				return messages.ErrorMsg{
+					Err: errors.Join(errors.New("could not perform find action on serial number"), err),
+				}
+			}
+			// the older versions of a CRL are kept for the retention policy, their revocations are not reported
+			for _, revoked := range found {
+				if current, ok := latest[revoked.RevokedBy]; ok && current.ID != revoked.RevocationListID {
+					continue
+				}
+				revokedCertificates = append(revokedCertificates, revoked)
+			}
+		}
+
+		return messages.SerialNumberSearchMsg{
+			Query:               query,
+			SerialNumbers:       serialNumbers,
+			RevokedCertificates: revokedCertificates,
+		}
 	}
 }
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchSerialNumber(t *testing.T) {
+	ctx := context.Background()
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	for id, name := range map[int64]string{1: "Root CA", 2: "Issuing CA"} {
+		_, err = storage.Repository.Save(ctx, &crl.CertificateRevocationList{ID: id, Name: name})
+		assert.NoError(t, err)
+	}
+	_, err = storage.Repository.SaveRevokedCertificates(ctx, 1, []x509.RevocationListEntry{{SerialNumber: big.NewInt(4096), RevocationTime: time.Now(), ReasonCode: 1}})
+	assert.NoError(t, err)
+	_, err = storage.Repository.SaveRevokedCertificates(ctx, 2, []x509.RevocationListEntry{{SerialNumber: big.NewInt(0x4096), RevocationTime: time.Now(), ReasonCode: 4}})
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+
+	tests := map[string]struct {
+		query string
+		want  []string
+	}{
+		"decimal and hex": {query: "4096", want: []string{"Root CA", "Issuing CA"}},
+		"hex":             {query: "0x1000", want: []string{"Root CA"}},
+		"colon separated": {query: "40:96", want: []string{"Issuing CA"}},
+		"not revoked":     {query: "ff", want: []string{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			msg := cmds.SearchSerialNumber(tt.query)().(messages.SerialNumberSearchMsg)
+			assert.Equal(t, tt.query, msg.Query)
+
+			revokedBy := make([]string, len(msg.RevokedCertificates))
+			for i, revokedCertificate := range msg.RevokedCertificates {
+				revokedBy[i] = revokedCertificate.RevokedBy
+			}
+			assert.Equal(t, tt.want, revokedBy)
+		})
+	}
+
+	msg := cmds.SearchSerialNumber("12g4")()
+	assert.ErrorContains(t, msg.(messages.ErrorMsg).Err, "could not parse serial number")
+}
+
+func TestSearchSerialNumberLatestVersion(t *testing.T) {
+	ctx := context.Background()
+	cmds := newMemoryCommands(t)
+
+	// the serial number is revoked on the previous version of the CRL only
+	thisUpdate := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	previousID, err := cmds.storage.Repository.Save(ctx, &crl.CertificateRevocationList{Name: "Issuing CA", Signature: []byte("previous"), ThisUpdate: thisUpdate, NextUpdate: thisUpdate.AddDate(0, 0, 7)})
+	assert.NoError(t, err)
+	_, err = cmds.storage.Repository.SaveRevokedCertificates(ctx, previousID, []x509.RevocationListEntry{{SerialNumber: big.NewInt(4096), RevocationTime: thisUpdate, ReasonCode: 6}})
+	assert.NoError(t, err)
+	latestID, err := cmds.storage.Repository.Save(ctx, &crl.CertificateRevocationList{Name: "Issuing CA", Signature: []byte("latest"), ThisUpdate: thisUpdate.AddDate(0, 0, 1), NextUpdate: thisUpdate.AddDate(0, 0, 8)})
+	assert.NoError(t, err)
+	assert.NotEqual(t, previousID, latestID)
+
+	msg := cmds.SearchSerialNumber("4096")().(messages.SerialNumberSearchMsg)
+	assert.Empty(t, msg.RevokedCertificates)
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/certificate"
+	domain_ocsp "github.com/pimg/certguard/pkg/domain/ocsp"
+	"github.com/pimg/certguard/pkg/scan"
+	"golang.org/x/crypto/ocsp"
+)
+
+// ScanEndpoint fetches the certificate chain presented by a TLS endpoint, an untrusted chain is shown with a warning
+func (c *Commands) ScanEndpoint(opts scan.Options) tea.Cmd {
+	return func() tea.Msg {
+		log.Printf("scanning TLS endpoint: %s", opts.Address)
+		result, err := scan.Scan(context.Background(), opts)
+		if err != nil {
+			log.Printf("could not fetch certificate chain: %s", err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not fetch certificate chain"), err),
+			}
+		}
+
+		notices := make([]string, 0)
+		if result.VerifyError != nil {
+			notices = append(notices, fmt.Sprintf("presented chain is not trusted: %s", result.VerifyError))
+		}
+
+		msg := c.certificateChainMsg(result.Certificates, sourceTLS+result.Address, notices...)
+		pemMsg, ok := msg.(messages.PemCertificateMsg)
+		if !ok {
+			return msg
+		}
+
+		pemMsg.Endpoint = result
+		pemMsg.Staple = c.checkStaple(result)
+		return pemMsg
+	}
+}
+
+// checkStaple validates the OCSP response stapled to the handshake and compares it with a fresh response from the OCSP responder of the leaf certificate,
+// nil is returned when the leaf certificate does not use OCSP
+func (c *Commands) checkStaple(result *scan.Result) *domain_ocsp.Staple {
+	leaf := result.Certificates[0]
+	if len(result.StapledOCSP) == 0 && len(leaf.OCSPServer) == 0 {
+		return nil
+	}
+
+	issuer := certificate.FindIssuer(leaf, result.Certificates)
+
+	if len(result.StapledOCSP) > 0 && issuer == nil {
+		return &domain_ocsp.Staple{Status: domain_ocsp.StapleInvalid, Problems: []string{"stapled OCSP response cannot be verified, the issuer is not part of the presented chain"}}
+	}
+
+	var response *ocsp.Response
+	var err error
+	if len(result.StapledOCSP) > 0 {
+		response, err = parseOCSPResponse(result.StapledOCSP, leaf, issuer)
+	}
+
+	staple := domain_ocsp.CheckStaple(result.StapledOCSP, response, err, time.Now())
+	if issuer == nil || len(leaf.OCSPServer) == 0 {
+		return staple
+	}
+
+	fresh, err := c.requestOCSP(context.Background(), leaf, issuer, leaf.OCSPServer[0], originTLSScan)
+	if err != nil {
+		staple.Problems = append(staple.Problems, fmt.Sprintf("could not query the OCSP responder: %s", err))
+		return staple
+	}
+
+	staple.Compare(fresh)
+	return staple
+}
+
+// ScanOptions validates the STARTTLS protocol and loads the custom CA file, when set
+func ScanOptions(address, serverName, startTLS, caFile string) (scan.Options, error) {
+	protocol, err := scan.ParseStartTLS(startTLS)
+	if err != nil {
+		return scan.Options{}, err
+	}
+
+	opts := scan.Options{
+		Address:    address,
+		ServerName: serverName,
+		StartTLS:   protocol,
+	}
+
+	if caFile != "" {
+		opts.RootCAs, err = scan.LoadCertPool(caFile)
+		if err != nil {
+			return scan.Options{}, err
+		}
+	}
+
+	return opts, nil
+}
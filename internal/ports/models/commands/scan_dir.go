@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/certificate"
+	"github.com/pimg/certguard/pkg/scan"
+	"golang.org/x/crypto/ocsp"
+)
+
+// maxOCSPRequests limits the number of concurrent OCSP requests during a directory scan
+const maxOCSPRequests = 8
+
+// ScanDirectory discovers certificates below root and checks each of them against the stored CRLs, the OCSP responders are queried as well when checkOCSP is set
+func (c *Commands) ScanDirectory(root, password string, checkOCSP bool) tea.Cmd {
+	return func() tea.Msg {
+		log.Printf("scanning directory: %s", root)
+		report, err := c.directoryReport(context.Background(), root, password, checkOCSP)
+		if err != nil {
+			log.Printf("could not scan directory: %s, err: %v", root, err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not scan directory"), err),
+			}
+		}
+
+		return messages.DirectoryReportMsg{Report: report}
+	}
+}
+
+// ExportReport writes the report as CSV or JSON to the reports directory in the cache directory
+func (c *Commands) ExportReport(report *scan.Report, format string) tea.Cmd {
+	return func() tea.Msg {
+		dir := filepath.Join(c.CacheDir(), "reports")
+		if err := os.MkdirAll(dir, 0o775); err != nil {
+			log.Printf("could not create reports directory: %v", err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not create reports directory"), err),
+			}
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("scan-%s.%s", report.Created.Format("20060102-150405"), format))
+		f, err := os.Create(path)
+		if err != nil {
+			log.Printf("could not create report file: %s, err: %v", path, err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not create report file"), err),
+			}
+		}
+		defer f.Close()
+
+		switch format {
+		case "csv":
+			err = report.WriteCSV(f)
+		case "json":
+			err = report.WriteJSON(f)
+		default:
+			err = fmt.Errorf("unsupported format: %s", format)
+		}
+		if err != nil {
+			log.Printf("could not write report: %s, err: %v", path, err)
+			return messages.ErrorMsg{
+				Err: errors.Join(errors.New("could not write report"), err),
+			}
+		}
+
+		return messages.ReportExportedMsg{Path: path}
+	}
+}
+
+func (c *Commands) directoryReport(ctx context.Context, root, password string, checkOCSP bool) (*scan.Report, error) {
+	files, fileErrors, err := scan.Directory(root, password)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]*x509.Certificate, len(files))
+	for i, file := range files {
+		certificates[i] = file.Certificate
+	}
+
+	report := &scan.Report{Root: root, Created: time.Now(), Entries: make([]scan.ReportEntry, len(files))}
+	var wg sync.WaitGroup
+	ocspRequests := make(chan struct{}, maxOCSPRequests)
+	for i, file := range files {
+		cert := file.Certificate
+		report.Entries[i] = scan.ReportEntry{
+			Path:         file.Path,
+			Subject:      displayName(cert.Subject.CommonName, cert.Subject.String()),
+			Issuer:       displayName(cert.Issuer.CommonName, cert.Issuer.String()),
+			SerialNumber: cert.SerialNumber.String(),
+			NotAfter:     cert.NotAfter,
+		}
+		c.checkStoredCRLs(ctx, &report.Entries[i], cert, originScanDir)
+
+		if !checkOCSP || report.Entries[i].Revocation == scan.RevocationRevoked || len(cert.OCSPServer) == 0 {
+			continue
+		}
+
+		issuer := certificate.FindIssuer(cert, certificates)
+		if issuer == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(entry *scan.ReportEntry) {
+			defer wg.Done()
+			ocspRequests <- struct{}{}
+			defer func() { <-ocspRequests }()
+			c.checkOCSPResponder(ctx, entry, cert, issuer, originScanDir)
+		}(&report.Entries[i])
+	}
+	wg.Wait()
+
+	for _, fileError := range fileErrors {
+		report.Entries = append(report.Entries, scan.ReportEntry{Path: fileError.Path, Error: flattenError(fileError.Err)})
+	}
+
+	report.Sort(scan.SortByPath)
+	return report, nil
+}
+
+func (c *Commands) checkStoredCRLs(ctx context.Context, entry *scan.ReportEntry, cert *x509.Certificate, origin string) {
+	revokedCertificate, err := c.findRevokedCertificate(ctx, cert, origin)
+	switch {
+	case err != nil:
+		log.Printf("could not search stored CRLs for serialnumber: %s, err: %v", cert.SerialNumber, err)
+		entry.Revocation = scan.RevocationUnknown
+		entry.Error = "could not search stored CRLs: " + flattenError(err)
+	case revokedCertificate != nil:
+		entry.Revocation = scan.RevocationRevoked
+		entry.RevocationSource = "CRL"
+	default:
+		entry.Revocation = scan.RevocationNotListed
+		entry.RevocationSource = "CRL"
+	}
+}
+
+func (c *Commands) checkOCSPResponder(ctx context.Context, entry *scan.ReportEntry, cert, issuer *x509.Certificate, origin string) {
+	response, err := c.requestOCSP(ctx, cert, issuer, cert.OCSPServer[0], origin)
+	if err != nil {
+		entry.Error = "OCSP request failed: " + flattenError(err)
+		return
+	}
+
+	entry.RevocationSource = "OCSP"
+	switch response.Status {
+	case ocsp.Good:
+		entry.Revocation = scan.RevocationGood
+	case ocsp.Revoked:
+		entry.Revocation = scan.RevocationRevoked
+	default:
+		entry.Revocation = scan.RevocationUnknown
+	}
+}
+
+// displayName returns the common name, or the full distinguished name for names without a common name
+func displayName(commonName, distinguishedName string) string {
+	if commonName != "" {
+		return commonName
+	}
+	return distinguishedName
+}
+
+// flattenError joins the lines of errors created with errors.Join, so they fit in a single table cell or CSV field
+func flattenError(err error) string {
+	return strings.ReplaceAll(err.Error(), "\n", ": ")
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/scan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanDirectory(t *testing.T) {
+	root := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "testing", "pki", "github.com-chain.pem"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "chain.pem"), data, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "broken.crt"), []byte("-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n"), 0o644))
+
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	msg := NewCommands(storage).ScanDirectory(root, "", false)()
+
+	report := msg.(messages.DirectoryReportMsg).Report
+	assert.Equal(t, root, report.Root)
+	assert.Len(t, report.Entries, 4)
+	assert.Equal(t, filepath.Join(root, "broken.crt"), report.Entries[0].Path)
+	assert.NotEmpty(t, report.Entries[0].Error)
+	assert.NotContains(t, report.Entries[0].Error, "\n")
+
+	for _, entry := range report.Entries[1:] {
+		assert.Equal(t, filepath.Join(root, "chain.pem"), entry.Path)
+		assert.Equal(t, scan.RevocationNotListed, entry.Revocation)
+		assert.Equal(t, "CRL", entry.RevocationSource)
+	}
+	assert.Equal(t, "github.com", report.Entries[1].Subject)
+}
+
+func TestScanDirectoryNotFound(t *testing.T) {
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	msg := NewCommands(storage).ScanDirectory(filepath.Join(t.TempDir(), "does-not-exist"), "", false)()
+
+	assert.ErrorContains(t, msg.(messages.ErrorMsg).Err, "could not scan directory")
+}
+
+func TestExportReport(t *testing.T) {
+	cacheDir := t.TempDir()
+	storage, err := crl.NewStorage(&crl.MockRepository{
+		CRLs:                      make(map[int64]*crl.CertificateRevocationList),
+		RevokedCertificateEntries: make(map[int64][]x509.RevocationListEntry),
+	}, cacheDir, filepath.Join(cacheDir, "import"))
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+	report := &scan.Report{Root: "certs", Entries: []scan.ReportEntry{{Path: "certs/leaf.pem", Subject: "leaf"}}}
+
+	for _, format := range []string{"csv", "json"} {
+		msg := cmds.ExportReport(report, format)()
+
+		path := msg.(messages.ReportExportedMsg).Path
+		assert.Equal(t, filepath.Join(cacheDir, "reports"), filepath.Dir(path))
+		assert.Equal(t, "."+format, filepath.Ext(path))
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "certs/leaf.pem")
+	}
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	domain_ocsp "github.com/pimg/certguard/pkg/domain/ocsp"
+	"github.com/pimg/certguard/pkg/scan"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestScanEndpoint(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	msg := cmds.ScanEndpoint(scan.Options{Address: server.Listener.Addr().String(), RootCAs: roots})()
+
+	pemMsg := msg.(messages.PemCertificateMsg)
+	assert.Equal(t, server.Certificate().Raw, pemMsg.Certificate.Raw)
+	assert.Equal(t, server.Listener.Addr().String(), pemMsg.Endpoint.Address)
+	assert.Empty(t, pemMsg.Warnings)
+}
+
+func TestScanEndpointUntrusted(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	storage, err := crl.NewMockStorage()
+	assert.NoError(t, err)
+
+	cmds := NewCommands(storage)
+
+	msg := cmds.ScanEndpoint(scan.Options{Address: server.Listener.Addr().String()})()
+
+	pemMsg := msg.(messages.PemCertificateMsg)
+	assert.True(t, strings.HasPrefix(pemMsg.Warnings[0], "presented chain is not trusted"))
+}
+
+func TestScanEndpointStaple(t *testing.T) {
+	tests := []struct {
+		name            string
+		stapledStatus   int
+		stapleAge       time.Duration
+		responderStatus int
+		expected        domain_ocsp.StapleStatus
+	}{
+		{"valid", ocsp.Good, 0, ocsp.Good, domain_ocsp.StapleValid},
+		{"stale", ocsp.Good, 72 * time.Hour, ocsp.Good, domain_ocsp.StapleStale},
+		{"mismatch", ocsp.Good, 0, ocsp.Revoked, domain_ocsp.StapleMismatch},
+		{"missing", -1, 0, ocsp.Good, domain_ocsp.StapleMissing},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			storage, err := crl.NewMockStorage()
+			assert.NoError(t, err)
+
+			address := newStaplingServer(t, test.stapledStatus, test.stapleAge, test.responderStatus)
+			msg := NewCommands(storage).ScanEndpoint(scan.Options{Address: address})()
+
+			pemMsg := msg.(messages.PemCertificateMsg)
+			assert.Equal(t, test.expected, pemMsg.Staple.Status)
+		})
+	}
+}
+
+// newStaplingServer starts a TLS server that staples an OCSP response with stapledStatus, or no response when stapledStatus is negative,
+// the OCSP responder of the leaf certificate answers with responderStatus
+func newStaplingServer(t *testing.T, stapledStatus int, stapleAge time.Duration, responderStatus int) string {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "CertGuard OCSP CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	assert.NoError(t, err)
+
+	createResponse := func(status int, thisUpdate time.Time) []byte {
+		response, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+			Status:       status,
+			SerialNumber: big.NewInt(2),
+			ThisUpdate:   thisUpdate,
+			NextUpdate:   thisUpdate.Add(24 * time.Hour),
+			RevokedAt:    thisUpdate,
+		}, caKey)
+		assert.NoError(t, err)
+		return response
+	}
+
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		_, _ = w.Write(createResponse(responderStatus, time.Now().Add(-time.Minute)))
+	}))
+	t.Cleanup(responder.Close)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		OCSPServer:   []string{responder.URL},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, &leafKey.PublicKey, caKey)
+	assert.NoError(t, err)
+
+	var staple []byte
+	if stapledStatus >= 0 {
+		staple = createResponse(stapledStatus, time.Now().Add(-time.Minute-stapleAge))
+	}
+
+	server := httptest.NewUnstartedServer(http.NotFoundHandler())
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{leafDER, caDER},
+			PrivateKey:  leafKey,
+			OCSPStaple:  staple,
+		}},
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server.Listener.Addr().String()
+}
+
+func TestScanOptions(t *testing.T) {
+	opts, err := ScanOptions("mail.example.com:587", "", "smtp", "")
+	assert.NoError(t, err)
+	assert.Equal(t, scan.StartTLSSMTP, opts.StartTLS)
+	assert.Nil(t, opts.RootCAs)
+
+	_, err = ScanOptions("mail.example.com:587", "", "", "does-not-exist.pem")
+	assert.ErrorContains(t, err, "could not read CA file: does-not-exist.pem")
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/certificate"
+)
+
+type compareKeyMap struct {
+	Back     key.Binding
+	Quit     key.Binding
+	Home     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+}
+
+func (k *compareKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.PageUp, k.PageDown, k.Back, k.Home}
+}
+
+func (k *compareKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.PageUp, k.PageDown},
+		{k.Back, k.Home, k.Quit},
+	}
+}
+
+var compareKeys = compareKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to previous view"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	Home: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "back to the main view"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "scroll up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdn", "scroll down"),
+	),
+}
+
+const COMPARE_WIDTH = 104
+
+// CompareModel shows a field by field comparison of a certificate and its renewal
+type CompareModel struct {
+	keys       compareKeyMap
+	styles     *styles.Styles
+	comparison *certificate.Comparison
+	diff       viewport.Model
+}
+
+func NewCompareModel(comparison *certificate.Comparison, height int) *CompareModel {
+	diff := viewport.New(COMPARE_WIDTH, max(height-TOP_INFO_HEIGHT, 10))
+	diff.KeyMap = viewport.KeyMap{
+		PageUp:   compareKeys.PageUp,
+		PageDown: compareKeys.PageDown,
+	}
+
+	c := &CompareModel{
+		keys:       compareKeys,
+		styles:     styles.Theme,
+		comparison: comparison,
+		diff:       diff,
+	}
+	c.diff.SetContent(c.renderComparison())
+
+	return c
+}
+
+func (c *CompareModel) Init() tea.Cmd {
+	return nil
+}
+
+func (c *CompareModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.diff.Height = max(msg.Height-TOP_INFO_HEIGHT, 10)
+	case tea.KeyMsg:
+		c.diff, cmd = c.diff.Update(msg)
+	}
+	return c, cmd
+}
+
+func (c *CompareModel) View() string {
+	var s strings.Builder
+
+	differences := c.comparison.Differences()
+	if differences == 0 {
+		s.WriteString(c.styles.LintPassed.Render("all fields match the expectations for a renewal") + "\n")
+	} else {
+		s.WriteString(c.styles.LintFailed.Render(fmt.Sprintf("%d fields do not match the expectations for a renewal", differences)) + "\n")
+	}
+
+	for _, warning := range c.comparison.Warnings {
+		s.WriteString(c.styles.WarningText.Render(warning) + "\n")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top, s.String(), c.styles.CertificateDetails.Render(c.diff.View()))
+}
+
+func (c *CompareModel) renderComparison() string {
+	var str strings.Builder
+	s := c.styles
+
+	str.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+		s.CertificateDetailLabel.Render(""),
+		s.CompareValue.Render(s.CertificateTitle.Render("old: "+c.comparison.Old.CommonName)),
+		s.CompareValue.Render(s.CertificateTitle.Render("new: "+c.comparison.New.CommonName)),
+	) + "\n\n")
+
+	for _, field := range c.comparison.Fields {
+		if len(field.Old) == 0 && len(field.New) == 0 {
+			continue
+		}
+
+		label := s.CertificateDetailLabel.Render(field.Field + ": ")
+		oldValue := strings.Join(field.Old, "\n")
+		newValue := strings.Join(field.New, "\n")
+
+		if !field.Equal && !field.ExpectChange {
+			oldValue = renderChangedValues(s, field.Old, field.Removed(), "- ")
+			newValue = renderChangedValues(s, field.New, field.Added(), "+ ")
+		}
+
+		if !field.Expected() {
+			label = s.LintFailed.Render(s.CertificateDetailLabel.Render(field.Field + ": "))
+		}
+
+		str.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, label, s.CompareValue.Render(oldValue), s.CompareValue.Render(newValue)) + "\n")
+	}
+
+	return str.String()
+}
+
+// renderChangedValues highlights the values that are only present in one of the certificates
+func renderChangedValues(s *styles.Styles, values, changed []string, prefix string) string {
+	lines := make([]string, 0, len(values))
+	for _, value := range values {
+		if slices.Contains(changed, value) {
+			lines = append(lines, s.LintFailed.Render(prefix+value))
+			continue
+		}
+		lines = append(lines, "  "+value)
+	}
+	if len(lines) == 0 {
+		return s.LintFailed.Render(prefix + "none")
+	}
+	return strings.Join(lines, "\n")
+}
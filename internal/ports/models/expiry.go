@@ -0,0 +1,231 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type expiryKeyMap struct {
+	table.KeyMap
+	Back       key.Binding
+	Quit       key.Binding
+	Home       key.Binding
+	Enter      key.Binding
+	NextBucket key.Binding
+	PrevBucket key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *expiryKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Quit, k.Enter, k.PrevBucket, k.NextBucket}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *expiryKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Back, k.Home, k.Quit},
+		{k.LineUp, k.LineDown},
+		{k.PrevBucket, k.NextBucket},
+		{k.Enter},
+	}
+}
+
+var expiryKeys = expiryKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to previous view"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	Home: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "back to the main view"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open certificate"),
+	),
+	NextBucket: key.NewBinding(
+		key.WithKeys("right", "tab", "l"),
+		key.WithHelp("→/tab", "next expiry group"),
+	),
+	PrevBucket: key.NewBinding(
+		key.WithKeys("left", "shift+tab"),
+		key.WithHelp("←", "previous expiry group"),
+	),
+	KeyMap: table.DefaultKeyMap(),
+}
+
+// ExpiryModel is a dashboard of the certificates in the inventory that expire within 90 days
+type ExpiryModel struct {
+	keys      expiryKeyMap
+	table     table.Model
+	dashboard *crl.ExpiryDashboard
+	bucket    crl.ExpiryBucket
+	errorMsg  string
+	styles    *styles.Styles
+	commands  *commands.Commands
+}
+
+func NewExpiryModel(height int, cmds *commands.Commands) *ExpiryModel {
+	columns := []table.Column{
+		{Title: "Subject", Width: 36},
+		{Title: "Issuer", Width: 36},
+		{Title: "Not After", Width: 10},
+		{Title: "Days", Width: 5},
+		{Title: "Source", Width: 24},
+	}
+
+	tbl := table.New(table.WithColumns(columns), table.WithFocused(true), table.WithHeight(max(height/2-6, 5)), table.WithWidth(120))
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(styles.Theme.ListComponentTitle).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(styles.Theme.FilePickerCurrent.GetForeground()).
+		Background(styles.Theme.BaseText.GetBackground()).
+		Bold(false)
+	tbl.SetStyles(s)
+
+	return &ExpiryModel{
+		keys:     expiryKeys,
+		table:    tbl,
+		styles:   styles.Theme,
+		commands: cmds,
+	}
+}
+
+func (m *ExpiryModel) Init() tea.Cmd {
+	return m.commands.ExpiryDashboard()
+}
+
+func (m *ExpiryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case messages.ExpiryDashboardMsg:
+		m.dashboard = msg.Dashboard
+		m.bucket = crl.Expired
+		for _, bucket := range crl.ExpiryBuckets {
+			if m.dashboard.Count(bucket) > 0 {
+				m.bucket = bucket
+				break
+			}
+		}
+		m.setRows()
+	case messages.ErrorMsg:
+		m.errorMsg = msg.Err.Error()
+	case tea.KeyMsg:
+		if m.dashboard == nil {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.keys.NextBucket):
+			m.bucket = (m.bucket + 1) % crl.ExpiresLater
+			m.setRows()
+			return m, nil
+		case key.Matches(msg, m.keys.PrevBucket):
+			m.bucket = (m.bucket + crl.ExpiresLater - 1) % crl.ExpiresLater
+			m.setRows()
+			return m, nil
+		case key.Matches(msg, m.keys.Enter):
+			certificates := m.dashboard.Certificates[m.bucket]
+			if cursor := m.table.Cursor(); cursor >= 0 && cursor < len(certificates) {
+				return m, m.commands.OpenStoredCertificate(certificates[cursor].Fingerprint)
+			}
+			return m, nil
+		}
+	}
+	m.table, cmd = m.table.Update(msg)
+
+	return m, cmd
+}
+
+func (m *ExpiryModel) setRows() {
+	certificates := m.dashboard.Certificates[m.bucket]
+	rows := make([]table.Row, len(certificates))
+	for i, certificate := range certificates {
+		rows[i] = table.Row{
+			certificate.Subject,
+			certificate.Issuer,
+			certificate.NotAfter.Format(time.DateOnly),
+			strconv.Itoa(int(certificate.NotAfter.Sub(m.dashboard.Created).Hours() / 24)),
+			certificate.Source,
+		}
+	}
+	m.table.SetRows(rows)
+	m.table.GotoTop()
+}
+
+func (m *ExpiryModel) View() string {
+	var s strings.Builder
+
+	if m.errorMsg != "" {
+		s.WriteString(m.styles.WarningText.Render("\n\n" + m.errorMsg))
+	}
+
+	if m.dashboard == nil {
+		s.WriteString("\n Loading certificates from storage ...")
+		return s.String()
+	}
+
+	buckets := make([]string, len(crl.ExpiryBuckets))
+	for i, bucket := range crl.ExpiryBuckets {
+		label := fmt.Sprintf(" %s: %d ", bucket, m.dashboard.Count(bucket))
+		if bucket == m.bucket {
+			buckets[i] = m.styles.CertificateSelected.Render(label)
+			continue
+		}
+		buckets[i] = m.styles.Text.Render(label)
+	}
+	s.WriteString("\n " + strings.Join(buckets, "  "))
+	s.WriteString("\n\n" + m.table.View())
+	s.WriteString("\n\n" + m.renderIssuers())
+	return s.String()
+}
+
+// renderIssuers shows the number of expiring certificates per issuer
+func (m *ExpiryModel) renderIssuers() string {
+	if len(m.dashboard.Issuers) == 0 {
+		return m.styles.Text.Render(" No certificates in the inventory expire within 90 days")
+	}
+
+	var s strings.Builder
+	header := fmt.Sprintf(" %-40s", "Issuer")
+	for _, bucket := range crl.ExpiryBuckets {
+		header += fmt.Sprintf(" %10s", bucket)
+	}
+	s.WriteString(m.styles.CertificateTitle.Render(header))
+
+	for _, issuer := range m.dashboard.Issuers {
+		name := issuer.Issuer
+		if len(name) > 40 {
+			name = name[:39] + "…"
+		}
+		line := fmt.Sprintf(" %-40s", name)
+		for _, count := range issuer.Counts {
+			line += fmt.Sprintf(" %10d", count)
+		}
+		s.WriteString("\n" + line)
+	}
+
+	return s.String()
+}
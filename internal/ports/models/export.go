@@ -0,0 +1,159 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type exportKeyMap struct {
+	Back   key.Binding
+	Enter  key.Binding
+	Format key.Binding
+	Quit   key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *exportKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Enter, k.Format, k.Quit}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *exportKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Back, k.Enter, k.Format, k.Quit},
+	}
+}
+
+var exportKeys = exportKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to previous view"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "export the CRL"),
+	),
+	Format: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "change the format"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
+
+// ExportModel exports a stored CRL to a file at a chosen path
+type ExportModel struct {
+	keys     exportKeyMap
+	name     string
+	format   int
+	path     textinput.Model
+	exported *messages.RevocationListExportedMsg
+	errorMsg string
+	styles   *styles.Styles
+	commands *commands.Commands
+}
+
+func NewExportModel(name string, cmds *commands.Commands) *ExportModel {
+	path := textinput.New()
+	path.Prompt = "Path: "
+	path.Width = 80
+	path.SetValue(exportFileName(name, crl.ExportFormats[0]))
+	path.Focus()
+
+	return &ExportModel{
+		keys:     exportKeys,
+		name:     name,
+		path:     path,
+		styles:   styles.Theme,
+		commands: cmds,
+	}
+}
+
+// exportFileName is the default path of an export, a file in the working directory named after the CRL
+func exportFileName(name string, format crl.ExportFormat) string {
+	fileName := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '/' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, name)
+	return fileName + "." + format.FileExtension()
+}
+
+func (m *ExportModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *ExportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case messages.RevocationListExportedMsg:
+		m.exported = &msg
+		m.errorMsg = ""
+		return m, nil
+	case messages.ErrorMsg:
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Format):
+			previous := crl.ExportFormats[m.format]
+			m.format = (m.format + 1) % len(crl.ExportFormats)
+			// the extension follows the format, unless the path is changed to another extension
+			if path, found := strings.CutSuffix(m.path.Value(), "."+previous.FileExtension()); found {
+				m.path.SetValue(path + "." + crl.ExportFormats[m.format].FileExtension())
+				m.path.CursorEnd()
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Enter):
+			path := strings.TrimSpace(m.path.Value())
+			if path == "" || path == commands.ExportToStdout {
+				m.errorMsg = "enter the path of the export file"
+				return m, nil
+			}
+			m.exported = nil
+			return m, m.commands.ExportRevocationList(m.name, path, crl.ExportFormats[m.format])
+		}
+	}
+
+	m.path, cmd = m.path.Update(msg)
+	return m, cmd
+}
+
+func (m *ExportModel) View() string {
+	var s strings.Builder
+
+	s.WriteString("\n " + m.styles.Text.Render("CRL: ") + m.name)
+	s.WriteString("\n " + m.styles.Text.Render("Format: ") + string(crl.ExportFormats[m.format]))
+	s.WriteString("\n\n" + m.styles.InputField.Render(m.path.View()))
+
+	if m.errorMsg != "" {
+		s.WriteString("\n" + m.styles.ErrorMessages.Render(m.errorMsg))
+	}
+
+	if m.exported != nil {
+		exported := fmt.Sprintf("exported %d revoked certificates to %s", m.exported.RevokedCertificates, m.exported.Path)
+		if m.exported.Format.Raw() {
+			exported = "exported the original CRL to " + m.exported.Path
+		}
+		s.WriteString("\n " + m.styles.Text.Render(exported))
+	}
+
+	return s.String()
+}
@@ -10,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pimg/certguard/internal/ports/models/commands"
 	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/scan"
 )
 
 // keyMap defines a set of keybindings. To work for help it must satisfy
@@ -62,7 +63,7 @@ type ImportModel struct {
 func NewImportModel(cmds *commands.Commands, height int) *ImportModel {
 	browseStyle := styles.Theme
 	fp := filepicker.New()
-	fp.AllowedTypes = []string{".crl", ".pem", ".crt"}
+	fp.AllowedTypes = append([]string{".crl", ".pem", ".crt", ".der", ".cer", ".p7b", ".p7c", ".p12", ".pfx", ".csr", ".req"}, scan.KeystoreExtensions...)
 	fp.ShowPermissions = false
 	fp.Styles.File = browseStyle.FilePickerFile
 	fp.Styles.Selected = browseStyle.FilePickerCurrent
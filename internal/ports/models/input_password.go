@@ -0,0 +1,119 @@
+package models
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+)
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type inputPasswordKeyMap struct {
+	Back  key.Binding
+	Enter key.Binding
+	Quit  key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *inputPasswordKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Enter, k.Quit}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *inputPasswordKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Back, k.Enter, k.Quit},
+	}
+}
+
+var inputPasswordKeys = inputPasswordKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to previous view"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open the PKCS#12 bundle or keystore"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
+
+// InputPasswordModel prompts for the password of a PKCS#12 bundle or keystore, the password is only kept in memory until the bundle is opened
+type InputPasswordModel struct {
+	keys      inputPasswordKeyMap
+	textinput textinput.Model
+	styles    *styles.Styles
+	data      []byte
+	source    string
+	keystore  bool
+	commands  *commands.Commands
+}
+
+func NewInputPasswordModel(msg messages.PasswordRequiredMsg, cmds *commands.Commands) *InputPasswordModel {
+	input := textinput.New()
+	input.Placeholder = "Enter the password of the PKCS#12 bundle"
+	if msg.Keystore {
+		input.Placeholder = "Enter the password of the keystore"
+	}
+	input.EchoMode = textinput.EchoPassword
+	input.EchoCharacter = '*'
+	input.Focus()
+
+	return &InputPasswordModel{
+		keys:      inputPasswordKeys,
+		textinput: input,
+		styles:    styles.Theme,
+		data:      msg.Data,
+		source:    msg.Source,
+		keystore:  msg.Keystore,
+		commands:  cmds,
+	}
+}
+
+func (i *InputPasswordModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (i *InputPasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		i.textinput.Err = nil
+		switch {
+		case key.Matches(msg, i.keys.Quit):
+			return i, tea.Quit
+		case key.Matches(msg, i.keys.Enter):
+			password := i.textinput.Value()
+			i.textinput.Reset()
+			if i.keystore {
+				return i, i.commands.OpenKeystoreWithPassword(i.data, password, i.source)
+			}
+			cmd = i.commands.ParseCertificatesWithPassword(i.data, password, i.source)
+			return i, cmd
+		}
+	case messages.ErrorMsg:
+		i.textinput.Err = msg.Err
+		return i, cmd
+	}
+
+	i.textinput, cmd = i.textinput.Update(msg)
+	return i, cmd
+}
+
+func (i *InputPasswordModel) View() string {
+	if i.textinput.Err != nil {
+		return lipgloss.JoinVertical(lipgloss.Top, i.styles.InputField.Render(i.textinput.View()), i.styles.ErrorMessages.Render(i.textinput.Err.Error()))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top, i.styles.InputField.Render(i.textinput.View()))
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+)
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type inputScanKeyMap struct {
+	Back  key.Binding
+	Next  key.Binding
+	Prev  key.Binding
+	Enter key.Binding
+	Quit  key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *inputScanKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Enter, k.Back, k.Quit}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *inputScanKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Next, k.Prev},
+		{k.Back, k.Enter, k.Quit},
+	}
+}
+
+var inputScanKeys = inputScanKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to main view"),
+	),
+	Next: key.NewBinding(
+		key.WithKeys("tab", "down"),
+		key.WithHelp("tab", "next field"),
+	),
+	Prev: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+		key.WithHelp("shift+tab", "previous field"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "fetch the certificate chain"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
+
+const (
+	scanAddressField = iota
+	scanServerNameField
+	scanStartTLSField
+	scanCAFileField
+)
+
+// InputScanModel is a form to fetch the certificate chain of a TLS endpoint
+type InputScanModel struct {
+	keys     inputScanKeyMap
+	inputs   []textinput.Model
+	focused  int
+	err      error
+	styles   *styles.Styles
+	commands *commands.Commands
+}
+
+func NewInputScanModel(cmds *commands.Commands) *InputScanModel {
+	placeholders := []string{
+		"host:port, e.g. github.com:443",
+		"SNI server name (optional, defaults to host)",
+		"STARTTLS protocol: smtp, imap or ldap (optional)",
+		"path to a PEM CA file (optional, defaults to system roots)",
+	}
+
+	inputs := make([]textinput.Model, len(placeholders))
+	for i, placeholder := range placeholders {
+		inputs[i] = textinput.New()
+		inputs[i].Placeholder = placeholder
+	}
+	inputs[scanAddressField].Focus()
+
+	return &InputScanModel{
+		keys:     inputScanKeys,
+		inputs:   inputs,
+		styles:   styles.Theme,
+		commands: cmds,
+	}
+}
+
+func (i *InputScanModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (i *InputScanModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		i.err = nil
+		switch {
+		case key.Matches(msg, i.keys.Quit):
+			return i, tea.Quit
+		case key.Matches(msg, i.keys.Next):
+			return i, i.focus((i.focused + 1) % len(i.inputs))
+		case key.Matches(msg, i.keys.Prev):
+			return i, i.focus((i.focused + len(i.inputs) - 1) % len(i.inputs))
+		case key.Matches(msg, i.keys.Enter):
+			opts, err := commands.ScanOptions(
+				strings.TrimSpace(i.inputs[scanAddressField].Value()),
+				strings.TrimSpace(i.inputs[scanServerNameField].Value()),
+				strings.TrimSpace(i.inputs[scanStartTLSField].Value()),
+				strings.TrimSpace(i.inputs[scanCAFileField].Value()),
+			)
+			if err != nil {
+				i.err = err
+				return i, nil
+			}
+			cmd = i.commands.ScanEndpoint(opts)
+			return i, cmd
+		}
+	case messages.ErrorMsg:
+		i.err = msg.Err
+		return i, cmd
+	}
+
+	i.inputs[i.focused], cmd = i.inputs[i.focused].Update(msg)
+	return i, cmd
+}
+
+func (i *InputScanModel) focus(field int) tea.Cmd {
+	i.inputs[i.focused].Blur()
+	i.focused = field
+	return i.inputs[i.focused].Focus()
+}
+
+func (i *InputScanModel) View() string {
+	fields := make([]string, 0, len(i.inputs)+1)
+	for _, input := range i.inputs {
+		fields = append(fields, i.styles.InputField.Render(input.View()))
+	}
+
+	if i.err != nil {
+		fields = append(fields, i.styles.ErrorMessages.Render(i.err.Error()))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Top, fields...)
+}
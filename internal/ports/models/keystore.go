@@ -0,0 +1,172 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/scan"
+)
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type keystoreKeyMap struct {
+	list.KeyMap
+	Back   key.Binding
+	Quit   key.Binding
+	Select key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *keystoreKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Quit, k.Select}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *keystoreKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Back, k.Quit},
+	}
+}
+
+var keystoreKeys = keystoreKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to previous view"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	Select: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "view the certificates of the entry"),
+	),
+}
+
+type keystoreItem struct {
+	entry *scan.KeystoreEntry
+}
+
+func (i keystoreItem) Title() string {
+	return fmt.Sprintf("%s (%s)", i.entry.Alias, i.entry.Type)
+}
+
+func (i keystoreItem) Description() string {
+	if i.entry.Certificate() == nil {
+		return "no certificate"
+	}
+
+	revocation := string(i.entry.Revocation)
+	if i.entry.RevocationSource != "" {
+		revocation += " (" + i.entry.RevocationSource + ")"
+	}
+	description := fmt.Sprintf("%s | expires %s | %s", i.entry.Subject, i.entry.NotAfter.Format(time.DateOnly), revocation)
+	if i.entry.Error != "" {
+		description += " | " + i.entry.Error
+	}
+	return description
+}
+
+func (i keystoreItem) FilterValue() string { return i.entry.Alias + " " + i.entry.Subject }
+
+// KeystoreModel lists the entries of a keystore with the revocation status of their certificates
+type KeystoreModel struct {
+	keys     keystoreKeyMap
+	styles   *styles.Styles
+	list     list.Model
+	report   *scan.KeystoreReport
+	commands *commands.Commands
+}
+
+func NewKeystoreModel(report *scan.KeystoreReport, width, height int, cmds *commands.Commands) *KeystoreModel {
+	items := make([]list.Item, len(report.Entries))
+	for i := range report.Entries {
+		items[i] = keystoreItem{entry: &report.Entries[i]}
+	}
+
+	defaultDelegate := list.NewDefaultDelegate()
+	c := styles.Theme.ListComponentTitle
+	defaultDelegate.Styles.SelectedTitle = defaultDelegate.Styles.SelectedTitle.Foreground(c).BorderLeftForeground(c)
+	defaultDelegate.Styles.SelectedDesc = defaultDelegate.Styles.SelectedTitle
+
+	entryList := list.New(items, defaultDelegate, width, height-TOP_INFO_HEIGHT-len(report.Notices))
+	entryList.Title = "Keystore entries"
+	entryList.KeyMap.Quit = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl-c", "quit"))
+	entryList.KeyMap.ClearFilter = key.NewBinding(key.WithKeys("ctrl+q"), key.WithHelp("ctrl-q", "clear"))
+	entryList.KeyMap.CancelWhileFiltering = key.NewBinding(key.WithKeys("ctrl+q"), key.WithHelp("ctrl-q", "clear"))
+	entryList.AdditionalShortHelpKeys = keystoreKeys.ShortHelp
+
+	entryList.Styles.Title = entryList.Styles.Title.Background(c)
+	return &KeystoreModel{
+		keys:     keystoreKeys,
+		styles:   styles.Theme,
+		list:     entryList,
+		report:   report,
+		commands: cmds,
+	}
+}
+
+func (k *KeystoreModel) Init() tea.Cmd {
+	return nil
+}
+
+func (k *KeystoreModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, k.keys.Select) && !k.filtering() {
+			if selected, ok := k.list.SelectedItem().(keystoreItem); ok && selected.entry.Certificate() != nil {
+				return k, k.commands.ViewCertificateChain(selected.entry)
+			}
+			return k, nil
+		}
+	case tea.WindowSizeMsg:
+		k.list.SetSize(msg.Width, msg.Height-TOP_INFO_HEIGHT-len(k.report.Notices))
+	}
+
+	k.list, cmd = k.list.Update(msg)
+	return k, cmd
+}
+
+// filtering returns true while a filter is typed in the list
+func (k *KeystoreModel) filtering() bool {
+	return k.list.FilterState() == list.Filtering
+}
+
+func (k *KeystoreModel) View() string {
+	var s strings.Builder
+
+	path := k.report.Path
+	if len(path) >= 54 {
+		path = "..." + path[len(path)-50:]
+	}
+	s.WriteString(k.styles.CRLText.Render("Keystore: ") + path)
+	s.WriteString(k.styles.CRLText.Render("Format: ") + string(k.report.Format))
+	s.WriteString(k.styles.CRLText.Render("Entries: ") + strconv.Itoa(len(k.report.Entries)))
+
+	revoked := strconv.Itoa(k.report.Revoked())
+	if k.report.Revoked() > 0 {
+		revoked = k.styles.WarningText.Render(revoked)
+	}
+	s.WriteString(k.styles.CRLText.Render("Revoked Certificates: ") + revoked)
+
+	for _, notice := range k.report.Notices {
+		s.WriteString("\n" + k.styles.WarningText.Render(notice))
+	}
+
+	keystoreInfo := k.styles.Text.Render(
+		s.String(),
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Top, keystoreInfo, k.list.View())
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"crypto/x509"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -9,26 +10,33 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
 	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/crl"
 )
 
 // keyMap defines a set of keybindings. To work for help it must satisfy
 // key.Map. It could also very easily be a map[string]key.Binding.
 type listKeyMap struct {
 	list.KeyMap
-	Back    key.Binding
-	Quit    key.Binding
-	Select  key.Binding
-	Refresh key.Binding
+	Back        key.Binding
+	Quit        key.Binding
+	Select      key.Binding
+	Refresh     key.Binding
+	Filter      key.Binding
+	ClearFilter key.Binding
+	Hex         key.Binding
+	Export      key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
 // of the key.Map interface.
 func (k *listKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Back, k.Quit, k.Select, k.Refresh}
+	return []key.Binding{k.Back, k.Quit, k.Select, k.Refresh, k.Filter, k.ClearFilter, k.Hex, k.Export}
 }
 
 // FullHelp returns keybindings for the expanded help view. It's part of the
@@ -56,89 +64,221 @@ var listKeys = listKeyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "redownload the CRL if URL is available"),
 	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter on serial number, reason:, after: and before:"),
+	),
+	ClearFilter: key.NewBinding(
+		key.WithKeys("ctrl+q"),
+		key.WithHelp("ctrl-q", "clear filter"),
+	),
+	Hex: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "toggle hex serial numbers"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export the CRL"),
+	),
 }
 
 type item struct {
 	serialnumber, revocationReason, revocationDate string
+	// hex shows the serial number as colon separated hex bytes
+	hex bool
+}
+
+func (i item) Title() string {
+	if i.hex {
+		return crl.FormatSerialNumberHex(i.serialnumber)
+	}
+	return i.serialnumber
 }
 
-func (i item) Title() string       { return i.serialnumber }
 func (i item) Description() string { return i.revocationDate }
 func (i item) FilterValue() string { return i.serialnumber }
 
 const TOP_INFO_HEIGHT = 12
 
+// revokedCertificatesPageSize is the number of revoked certificates that is read from storage at once
+const revokedCertificatesPageSize = 200
+
+// ListModel lists the revoked certificates of a stored CRL. Revoked certificates are read per page while scrolling
+// and filtered by the repository, so CRLs with millions of entries are never loaded at once.
 type ListModel struct {
-	keys         listKeyMap
-	styles       *styles.Styles
-	list         list.Model
-	crl          *x509.RevocationList
-	crlUrl       *url.URL
-	selectedItem *RevokedCertificateModel
-	itemSelected bool
-	commands     *commands.Commands
+	keys             listKeyMap
+	styles           *styles.Styles
+	list             list.Model
+	crl              *x509.RevocationList
+	crlUrl           *url.URL
+	unsigned         bool
+	revocationListID int64
+	total            int
+	matches          int
+	filter           crl.RevokedCertificateFilter
+	filterInput      textinput.Model
+	filterError      string
+	loading          bool
+	hex              bool
+	selectedItem     *RevokedCertificateModel
+	itemSelected     bool
+	commands         *commands.Commands
 }
 
-func NewListModel(crl *x509.RevocationList, URL *url.URL, width, height int, cmds *commands.Commands) *ListModel {
-	items := revokedCertificatesToItems(crl.RevokedCertificateEntries)
-
+func NewListModel(msg messages.CRLResponseMsg, width, height int, cmds *commands.Commands) *ListModel {
 	defaultDelegate := list.NewDefaultDelegate()
 	c := styles.Theme.ListComponentTitle
 	defaultDelegate.Styles.SelectedTitle = defaultDelegate.Styles.SelectedTitle.Foreground(c).BorderLeftForeground(c)
 	defaultDelegate.Styles.SelectedDesc = defaultDelegate.Styles.SelectedTitle
 
-	revokedList := list.New(items, defaultDelegate, width, height-TOP_INFO_HEIGHT)
+	revokedList := list.New([]list.Item{}, defaultDelegate, width, height-TOP_INFO_HEIGHT)
 	revokedList.Title = "Revoked Certificates"
 	revokedList.KeyMap.Quit = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl-c", "quit"))
-	revokedList.KeyMap.ClearFilter = key.NewBinding(key.WithKeys("ctrl+q"), key.WithHelp("ctrl-q", "clear"))
-	revokedList.KeyMap.CancelWhileFiltering = key.NewBinding(key.WithKeys("ctrl+q"), key.WithHelp("ctrl-q", "clear"))
+	// filtering is done by the repository, the list only holds the pages that are read so far
+	revokedList.SetFilteringEnabled(false)
+	revokedList.SetShowStatusBar(false)
 	revokedList.AdditionalShortHelpKeys = listKeys.ShortHelp
 
 	revokedList.Styles.Title = revokedList.Styles.Title.Background(c)
+
+	filterInput := textinput.New()
+	filterInput.Placeholder = "serial number reason:keyCompromise after:2024-01-01 before:2025-01-01"
+	filterInput.Prompt = "Filter: "
+	filterInput.Width = 80
+
 	return &ListModel{
-		keys:     listKeys,
-		styles:   styles.Theme,
-		list:     revokedList,
-		crl:      crl,
-		crlUrl:   URL,
-		commands: cmds,
+		keys:             listKeys,
+		styles:           styles.Theme,
+		list:             revokedList,
+		crl:              msg.RevocationList,
+		crlUrl:           msg.URL,
+		unsigned:         msg.Unsigned,
+		revocationListID: msg.ID,
+		total:            msg.Count,
+		matches:          msg.Count,
+		filterInput:      filterInput,
+		commands:         cmds,
 	}
 }
 
-func revokedCertificatesToItems(entries []x509.RevocationListEntry) []list.Item {
-	items := make([]list.Item, 0, len(entries))
-	for _, entry := range entries {
+func revokedCertificatesToItems(revokedCertificates []*crl.RevokedCertificate, hex bool) []list.Item {
+	items := make([]list.Item, 0, len(revokedCertificates))
+	for _, revokedCertificate := range revokedCertificates {
 		items = append(items, item{
-			serialnumber:     entry.SerialNumber.String(),
-			revocationReason: strconv.Itoa(entry.ReasonCode),
-			revocationDate:   entry.RevocationTime.String(),
+			serialnumber:     revokedCertificate.SerialNumber,
+			revocationReason: revokedCertificate.RevocationReason.String(),
+			revocationDate:   revokedCertificate.RevocationDate.String(),
+			hex:              hex,
 		})
 	}
 
 	return items
 }
 
+// Init reads the first page of revoked certificates
 func (l *ListModel) Init() tea.Cmd {
-	return nil
+	return l.loadPage(0)
+}
+
+// filtering is true while the filter is being edited
+func (l *ListModel) filtering() bool {
+	return l.filterInput.Focused()
+}
+
+func (l *ListModel) loadPage(offset int) tea.Cmd {
+	l.loading = true
+	return l.commands.ListRevokedCertificates(l.revocationListID, l.filter, offset, revokedCertificatesPageSize)
+}
+
+// nextPage reads the next page when the cursor gets close to the last revoked certificate that is read so far
+func (l *ListModel) nextPage() tea.Cmd {
+	loaded := len(l.list.Items())
+	if l.loading || loaded >= l.matches || l.list.Index() < loaded-revokedCertificatesPageSize/4 {
+		return nil
+	}
+	return l.loadPage(loaded)
+}
+
+func (l *ListModel) applyFilter(filter crl.RevokedCertificateFilter) tea.Cmd {
+	l.filter = filter
+	l.filterError = ""
+	l.list.ResetSelected()
+	return l.loadPage(0)
+}
+
+// toggleHex switches the serial numbers of the list between decimal and hex notation
+func (l *ListModel) toggleHex() tea.Cmd {
+	l.hex = !l.hex
+	items := l.list.Items()
+	for i, listItem := range items {
+		revokedItem := listItem.(item)
+		revokedItem.hex = l.hex
+		items[i] = revokedItem
+	}
+	return l.list.SetItems(items)
 }
 
 func (l *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
+	case messages.RevokedCertificatesPageMsg:
+		// pages of a previous filter, or pages that are already read, are dropped
+		if msg.RevocationListID != l.revocationListID || msg.Filter != l.filter || (msg.Offset != 0 && msg.Offset != len(l.list.Items())) {
+			return l, nil
+		}
+
+		items := revokedCertificatesToItems(msg.RevokedCertificates, l.hex)
+		if msg.Offset != 0 {
+			items = append(l.list.Items(), items...)
+		}
+		l.loading = false
+		l.matches = msg.Total
+		cmd = l.list.SetItems(items)
+		return l, tea.Batch(cmd, l.nextPage())
+	case messages.ErrorMsg:
+		l.loading = false
 	case tea.KeyMsg:
+		if l.filtering() {
+			switch {
+			case msg.Type == tea.KeyEnter:
+				filter, err := crl.ParseRevokedCertificateFilter(l.filterInput.Value())
+				if err != nil {
+					l.filterError = err.Error()
+					return l, nil
+				}
+				l.filterInput.Blur()
+				return l, l.applyFilter(filter)
+			case key.Matches(msg, listKeys.ClearFilter):
+				l.filterInput.Blur()
+				l.filterInput.SetValue("")
+				return l, l.applyFilter(crl.RevokedCertificateFilter{})
+			}
+			l.filterInput, cmd = l.filterInput.Update(msg)
+			return l, cmd
+		}
+
 		switch {
 		case key.Matches(msg, listKeys.Select):
-			if len(l.list.VisibleItems()) != 0 {
-				selectedItem := l.list.SelectedItem().(item)
+			if selectedItem, ok := l.list.SelectedItem().(item); ok {
 				revokedCertificateModel := NewRevokedCertificateModel(selectedItem.serialnumber, selectedItem.revocationReason, selectedItem.revocationDate)
 				l.selectedItem = revokedCertificateModel
 				l.itemSelected = true
 			}
 		case key.Matches(msg, listKeys.Refresh):
-			if l.crl.NextUpdate.Before(time.Now()) {
+			// a pseudo-CRL is refreshed by importing its CA database again
+			if !l.unsigned && l.crl.NextUpdate.Before(time.Now()) {
 				cmd = l.commands.GetCRL(l.crlUrl)
 				return l, cmd
 			}
+		case key.Matches(msg, listKeys.Filter):
+			return l, l.filterInput.Focus()
+		case key.Matches(msg, listKeys.Hex):
+			return l, l.toggleHex()
+		case key.Matches(msg, listKeys.Export):
+			return l, l.commands.SelectForExport(l.crl.Issuer.CommonName)
+		case key.Matches(msg, listKeys.ClearFilter):
+			l.filterInput.SetValue("")
+			return l, l.applyFilter(crl.RevokedCertificateFilter{})
 		default:
 			l.itemSelected = false
 		}
@@ -147,9 +287,8 @@ func (l *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	l.list, cmd = l.list.Update(msg)
-	return l, cmd
+	return l, tea.Batch(cmd, l.nextPage())
 }
-
 func (l *ListModel) View() string {
 	var s strings.Builder
 
@@ -167,9 +306,11 @@ func (l *ListModel) View() string {
 		s.WriteString(l.styles.CRLText.Render("Next Update: ") + l.crl.NextUpdate.String())
 	}
 
-	s.WriteString(l.styles.CRLText.Render("Revoked Certificates: ") + strconv.Itoa(len(l.crl.RevokedCertificateEntries)))
+	s.WriteString(l.styles.CRLText.Render("Revoked Certificates: ") + strconv.Itoa(l.total))
 
-	if l.crlUrl != nil {
+	if l.unsigned {
+		s.WriteString(l.styles.CRLText.Render("Signed: ") + l.styles.WarningText.Render("no, imported from a CA database"))
+	} else if l.crlUrl != nil {
 		crlUrl := l.crlUrl.String()
 
 		if len(l.crlUrl.String()) >= 54 {
@@ -179,10 +320,21 @@ func (l *ListModel) View() string {
 		s.WriteString(l.styles.CRLText.Render("URL: ") + crlUrl)
 	}
 
+	if l.filter != (crl.RevokedCertificateFilter{}) {
+		s.WriteString(l.styles.CRLText.Render("Matches: ") + strconv.Itoa(l.matches))
+	}
+
+	s.WriteString(l.styles.CRLText.Render("Loaded: ") + fmt.Sprintf("%d of %d", len(l.list.Items()), l.matches))
+
 	crlInfo := l.styles.Text.Render(
 		s.String(),
 	)
 
+	filter := "\n " + l.filterInput.View()
+	if l.filterError != "" {
+		filter += l.styles.WarningText.Render("\n " + l.filterError)
+	}
+
 	revokedList := l.list.View()
-	return lipgloss.JoinVertical(lipgloss.Top, crlInfo, revokedList)
+	return lipgloss.JoinVertical(lipgloss.Top, crlInfo, filter, revokedList)
 }
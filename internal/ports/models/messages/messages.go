@@ -5,12 +5,29 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/pimg/certguard/pkg/domain/certificate"
 	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/domain/ct"
+	"github.com/pimg/certguard/pkg/domain/ocsp"
+	"github.com/pimg/certguard/pkg/scan"
 )
 
+// CRLResponseMsg contains the header of a stored CRL, its revoked certificates are listed per page with RevokedCertificatesPageMsg
 type CRLResponseMsg struct {
+	ID             int64
 	RevocationList *x509.RevocationList
 	URL            *url.URL
+	// Count is the number of revoked certificates in the CRL
+	Count int
+	// Unsigned is true for a pseudo-CRL imported from a CA database
+	Unsigned bool
+}
+
+// CRLProgressMsg reports the number of revoked certificates of a CRL that are stored so far
+type CRLProgressMsg struct {
+	Name   string
+	Stored int
+	Total  int
 }
 
 type ErrorMsg struct {
@@ -25,6 +42,75 @@ type RevokedCertificatesMsg struct {
 	RevokedCertificates []x509.RevocationListEntry
 }
 
+// RevokedCertificatesPageMsg contains a page of the revoked certificates of a stored CRL that match the filter
+type RevokedCertificatesPageMsg struct {
+	RevocationListID int64
+	Filter           crl.RevokedCertificateFilter
+	Offset           int
+	// Total is the number of revoked certificates that match the filter
+	Total               int
+	RevokedCertificates []*crl.RevokedCertificate
+}
+
+// SerialNumberSearchMsg contains the revoked certificates on all stored CRLs with one of the serial numbers the query stands for
+type SerialNumberSearchMsg struct {
+	Query string
+	// SerialNumbers are the decimal serial numbers that are searched, a query of only digits is searched as decimal and as hex
+	SerialNumbers       []string
+	RevokedCertificates []*crl.RevokedCertificate
+}
+
+// ArchiveExportedMsg contains the path and the contents of an exported archive
+type ArchiveExportedMsg struct {
+	Path            string
+	RevocationLists int
+	Certificates    int
+}
+
+// ArchiveImportedMsg contains the result of importing an archive, CRLs that are not newer than the stored CRL are skipped
+type ArchiveImportedMsg struct {
+	Path                string
+	RevocationLists     int
+	RevokedCertificates int
+	Certificates        int
+	Skipped             []string
+}
+
+// PrunedRevocationListsMsg contains the CRLs removed by the retention policy, or the CRLs that would be removed on a dry run
+type PrunedRevocationListsMsg struct {
+	DryRun bool
+	Pruned []*crl.PrunedRevocationList
+}
+
+// AuditLogMsg contains the revocation checks in the audit log that match the filter, the most recent first
+type AuditLogMsg struct {
+	Filter  crl.AuditFilter
+	Entries []*crl.AuditEntry
+}
+
+// ExportRevocationListMsg opens the export view of a stored CRL
+type ExportRevocationListMsg struct {
+	Name string
+}
+
+// RevocationListExportedMsg contains the result of exporting a stored CRL
+type RevocationListExportedMsg struct {
+	Name                string
+	Path                string
+	Format              crl.ExportFormat
+	RevokedCertificates int
+}
+
+// CADatabaseImportedMsg contains the result of importing a CA database as an unsigned pseudo-CRL
+type CADatabaseImportedMsg struct {
+	Name                string
+	Path                string
+	Format              crl.CADatabaseFormat
+	RevokedCertificates int
+	// Skipped is the number of certificates in the CA database that are not revoked
+	Skipped int
+}
+
 type CRLDeleteConfirmationMsg struct {
 	DeletionSuccessful bool
 }
@@ -32,11 +118,73 @@ type CRLDeleteConfirmationMsg struct {
 type PemCertificateMsg struct {
 	Certificate      *x509.Certificate
 	CertificateChain []*x509.Certificate
+	Warnings         []string
+	Transparency     *ct.Report
+	// Findings contains the lint findings of every certificate in CertificateChain, in the same order
+	Findings    [][]certificate.Finding
+	LintProfile string
+	// Endpoint is set when the chain was fetched from a TLS endpoint
+	Endpoint *scan.Result
+	// Staple is the validation result of the stapled OCSP response, only set for endpoints whose leaf certificate uses OCSP
+	Staple *ocsp.Staple
+}
+
+type ListCertificatesResponseMsg struct {
+	Certificates []*crl.StoredCertificate
+}
+
+type CertificateDeleteConfirmationMsg struct {
+	DeletionSuccessful bool
+}
+
+// ExpiryDashboardMsg contains the certificates in the inventory that expire within 90 days
+type ExpiryDashboardMsg struct {
+	Dashboard *crl.ExpiryDashboard
+}
+
+// DirectoryReportMsg contains the certificates found in a directory with their revocation status
+type DirectoryReportMsg struct {
+	Report *scan.Report
+}
+
+// ReportExportedMsg contains the path of an exported report
+type ReportExportedMsg struct {
+	Path string
+}
+
+// CompareCertificateMsg selects a certificate as the old certificate of a comparison
+type CompareCertificateMsg struct {
+	Certificate *x509.Certificate
+}
+
+type CertificateComparisonMsg struct {
+	Comparison *certificate.Comparison
+}
+
+type CertificateRequestMsg struct {
+	CertificateRequest *certificate.CertificateRequest
+	Lint               []certificate.LintResult
+	Warnings           []string
+}
+
+type PasswordRequiredMsg struct {
+	Data []byte
+	// Source is stored with the certificates in the inventory once the bundle is opened
+	Source string
+	// Keystore is set when the data is opened in the keystore view
+	Keystore bool
+}
+
+// KeystoreMsg contains the entries of a keystore with the revocation status of their certificates
+type KeystoreMsg struct {
+	Report *scan.KeystoreReport
 }
 
 type GetRevokedCertificateMsg struct {
 	RevokedCertificate *crl.RevokedCertificate
 	Found              bool
+	// Unsigned is true when the certificate is revoked on a pseudo-CRL imported from a CA database
+	Unsigned bool
 }
 
 type OCSPResponseMsg struct {
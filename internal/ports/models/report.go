@@ -0,0 +1,207 @@
+package models
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/scan"
+)
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type reportKeyMap struct {
+	table.KeyMap
+	Back       key.Binding
+	Quit       key.Binding
+	Home       key.Binding
+	Enter      key.Binding
+	Sort       key.Binding
+	ExportCSV  key.Binding
+	ExportJSON key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *reportKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Quit, k.Enter, k.Sort, k.ExportCSV, k.ExportJSON}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *reportKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Back, k.Home, k.Quit},
+		{k.LineUp, k.LineDown},
+		{k.GotoTop, k.GotoBottom},
+		{k.Enter, k.Sort},
+		{k.ExportCSV, k.ExportJSON},
+	}
+}
+
+var reportKeys = reportKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to previous view"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	Home: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "back to the main view"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open the certificate file"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "change the sort column"),
+	),
+	ExportCSV: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "export as CSV"),
+	),
+	ExportJSON: key.NewBinding(
+		key.WithKeys("j"),
+		key.WithHelp("j", "export as JSON"),
+	),
+	KeyMap: table.DefaultKeyMap(),
+}
+
+// ReportModel shows the certificates found by a directory scan
+type ReportModel struct {
+	keys     reportKeyMap
+	table    table.Model
+	report   *scan.Report
+	sortedBy scan.SortField
+	message  string
+	errorMsg string
+	styles   *styles.Styles
+	commands *commands.Commands
+}
+
+func NewReportModel(report *scan.Report, height int, cmds *commands.Commands) *ReportModel {
+	columns := []table.Column{
+		{Title: "Path", Width: 36},
+		{Title: "Subject", Width: 24},
+		{Title: "Issuer", Width: 24},
+		{Title: "Expires", Width: 10},
+		{Title: "Days", Width: 5},
+		{Title: "Revocation", Width: 17},
+		{Title: "Error", Width: 30},
+	}
+
+	tbl := table.New(table.WithColumns(columns), table.WithFocused(true), table.WithHeight(height-12), table.WithWidth(160))
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(styles.Theme.ListComponentTitle).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(styles.Theme.FilePickerCurrent.GetForeground()).
+		Background(styles.Theme.BaseText.GetBackground()).
+		Bold(false)
+	tbl.SetStyles(s)
+
+	m := &ReportModel{
+		keys:     reportKeys,
+		table:    tbl,
+		report:   report,
+		sortedBy: scan.SortByPath,
+		styles:   styles.Theme,
+		commands: cmds,
+	}
+	m.setRows()
+	return m
+}
+
+func (m *ReportModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *ReportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case messages.ErrorMsg:
+		m.errorMsg = msg.Err.Error()
+	case messages.ReportExportedMsg:
+		m.errorMsg = ""
+		m.message = "report exported to: " + msg.Path
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Sort):
+			next := (slices.Index(scan.SortFields, m.sortedBy) + 1) % len(scan.SortFields)
+			m.sortedBy = scan.SortFields[next]
+			m.report.Sort(m.sortedBy)
+			m.setRows()
+			return m, nil
+		case key.Matches(msg, m.keys.ExportCSV):
+			return m, m.commands.ExportReport(m.report, "csv")
+		case key.Matches(msg, m.keys.ExportJSON):
+			return m, m.commands.ExportReport(m.report, "json")
+		case key.Matches(msg, m.keys.Enter):
+			if row := m.table.SelectedRow(); row != nil {
+				return m, m.commands.ImportFile(row[0])
+			}
+			return m, nil
+		}
+	}
+	m.table, cmd = m.table.Update(msg)
+
+	return m, cmd
+}
+
+func (m *ReportModel) setRows() {
+	rows := make([]table.Row, len(m.report.Entries))
+	for i, entry := range m.report.Entries {
+		if entry.NotAfter.IsZero() {
+			rows[i] = table.Row{entry.Path, "", "", "", "", "", entry.Error}
+			continue
+		}
+
+		revocation := string(entry.Revocation)
+		if entry.RevocationSource != "" {
+			revocation += " (" + entry.RevocationSource + ")"
+		}
+
+		rows[i] = table.Row{
+			entry.Path,
+			entry.Subject,
+			entry.Issuer,
+			entry.NotAfter.Format(time.DateOnly),
+			strconv.Itoa(entry.DaysLeft(m.report.Created)),
+			revocation,
+			entry.Error,
+		}
+	}
+	m.table.SetRows(rows)
+}
+
+func (m *ReportModel) View() string {
+	var s strings.Builder
+	s.WriteString("\n " + m.styles.Text.Render("Directory: ") + m.report.Root)
+	s.WriteString("\n " + m.styles.Text.Render("Entries: ") + strconv.Itoa(len(m.report.Entries)) + "  " + m.styles.Text.Render("Sorted by: ") + string(m.sortedBy))
+
+	if m.message != "" {
+		s.WriteString("\n\n " + m.message)
+	}
+
+	if m.errorMsg != "" {
+		s.WriteString(m.styles.WarningText.Render("\n\n " + m.errorMsg))
+	}
+
+	s.WriteString("\n\n" + m.table.View())
+	return s.String()
+}
@@ -2,7 +2,6 @@ package models
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -62,15 +61,10 @@ func (r *RevokedCertificateModel) Update(_ tea.Msg) (tea.Model, tea.Cmd) {
 
 func (r *RevokedCertificateModel) View() string {
 	serialnumber := r.styles.RevokedCertificateText.Render("Serialnumber: ") + r.serialnumber
+	serialnumberHex := r.styles.RevokedCertificateText.Render("Serialnumber hex: ") + crl.FormatSerialNumberHex(r.serialnumber)
 	revocationDate := r.styles.RevokedCertificateText.Render("Revocation date: ") + r.revocationDate
 
-	var revocationReason string
-	revocationReasonCode, err := strconv.Atoi(r.revocationReason)
-	if err != nil {
-		revocationReason = "Unknown revocation reason"
-	} else {
-		revocationReason = r.styles.RevokedCertificateText.Render("Revocation reason: ") + crl.RevocationReasons[revocationReasonCode].String()
-	}
+	revocationReason := r.styles.RevokedCertificateText.Render("Revocation reason: ") + r.revocationReason
 
-	return fmt.Sprintf("%s%s%s", serialnumber, revocationDate, revocationReason)
+	return fmt.Sprintf("%s%s%s%s", serialnumber, serialnumberHex, revocationDate, revocationReason)
 }
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+)
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type scanDirKeyMap struct {
+	filepicker.KeyMap
+	ScanCurrent key.Binding
+	OCSP        key.Binding
+	Back        key.Binding
+	Quit        key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *scanDirKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Select, k.ScanCurrent, k.OCSP, k.Back, k.Quit}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *scanDirKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Back, k.Quit},
+		{k.Up, k.Down},
+		{k.Open, k.Select},
+		{k.ScanCurrent, k.OCSP},
+	}
+}
+
+var scanDirKeys = scanDirKeyMap{
+	ScanCurrent: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "scan the current directory"),
+	),
+	OCSP: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "toggle OCSP requests"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to main view"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+	KeyMap: func() filepicker.KeyMap {
+		keyMap := filepicker.DefaultKeyMap()
+		keyMap.Select = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "scan the selected directory"))
+		keyMap.Back = key.NewBinding(key.WithKeys("h", "backspace", "left"), key.WithHelp("h", "back"))
+		return keyMap
+	}(),
+}
+
+// ScanDirModel picks a directory that is recursively scanned for certificates
+type ScanDirModel struct {
+	keys       scanDirKeyMap
+	styles     *styles.Styles
+	filepicker filepicker.Model
+	checkOCSP  bool
+	scanning   string
+	err        error
+	commands   *commands.Commands
+}
+
+func NewScanDirModel(cmds *commands.Commands, height int) *ScanDirModel {
+	scanDirStyle := styles.Theme
+	fp := filepicker.New()
+	fp.DirAllowed = true
+	fp.FileAllowed = false
+	fp.ShowPermissions = false
+	fp.Styles.File = scanDirStyle.FilePickerFile
+	fp.Styles.Selected = scanDirStyle.FilePickerCurrent
+	fp.Styles.Cursor = scanDirStyle.FilePickerFile
+	fp.SetHeight(height - 12)
+	fp.KeyMap = scanDirKeys.KeyMap
+
+	fp.CurrentDirectory = cmds.ImportDir()
+
+	return &ScanDirModel{
+		keys:       scanDirKeys,
+		styles:     scanDirStyle,
+		filepicker: fp,
+		commands:   cmds,
+	}
+}
+
+func (m *ScanDirModel) Init() tea.Cmd {
+	return m.filepicker.Init()
+}
+
+func (m *ScanDirModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case clearErrorMsg:
+		m.err = nil
+	case messages.ErrorMsg:
+		m.scanning = ""
+		m.err = msg.Err
+		return m, clearErrorAfter(5 * time.Second)
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.OCSP):
+			m.checkOCSP = !m.checkOCSP
+			return m, nil
+		case key.Matches(msg, m.keys.ScanCurrent):
+			return m, m.scan(m.filepicker.CurrentDirectory)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.filepicker, cmd = m.filepicker.Update(msg)
+
+	if didSelect, path := m.filepicker.DidSelectFile(msg); didSelect {
+		m.filepicker.Path = ""
+		return m, tea.Batch(cmd, m.scan(path))
+	}
+
+	return m, cmd
+}
+
+func (m *ScanDirModel) scan(path string) tea.Cmd {
+	if path == "" {
+		m.err = errors.New("no directory selected")
+		return clearErrorAfter(2 * time.Second)
+	}
+
+	m.scanning = path
+	return m.commands.ScanDirectory(path, "", m.checkOCSP)
+}
+
+func (m *ScanDirModel) View() string {
+	var s strings.Builder
+	s.WriteString("\n  ")
+	switch {
+	case m.err != nil:
+		s.WriteString(m.filepicker.Styles.DisabledFile.Render(m.err.Error()))
+	case m.scanning != "":
+		s.WriteString("Scanning " + m.scanning + " ...")
+	default:
+		s.WriteString("Pick a directory to scan for certificates:")
+	}
+
+	ocsp := "off"
+	if m.checkOCSP {
+		ocsp = "on"
+	}
+	s.WriteString("\n  " + m.styles.Text.Render("Current directory: ") + m.filepicker.CurrentDirectory)
+	s.WriteString("\n  " + m.styles.Text.Render("OCSP requests: ") + ocsp)
+	s.WriteString("\n\n" + m.filepicker.View() + "\n")
+	return s.String()
+}
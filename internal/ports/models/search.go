@@ -0,0 +1,197 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pimg/certguard/internal/ports/models/commands"
+	"github.com/pimg/certguard/internal/ports/models/messages"
+	"github.com/pimg/certguard/internal/ports/models/styles"
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// keyMap defines a set of keybindings. To work for help it must satisfy
+// key.Map. It could also very easily be a map[string]key.Binding.
+type searchKeyMap struct {
+	table.KeyMap
+	Back   key.Binding
+	Quit   key.Binding
+	Enter  key.Binding
+	Search key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k *searchKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Back, k.Quit, k.Enter, k.Search}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k *searchKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Back, k.Quit},
+		{k.LineUp, k.LineDown},
+		{k.Enter, k.Search},
+	}
+}
+
+var searchKeys = searchKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back to main view"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+	Enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "search the serial number"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "edit the serial number"),
+	),
+	KeyMap: table.DefaultKeyMap(),
+}
+
+// SearchModel searches all stored CRLs for a serial number in decimal, hex or colon separated hex notation
+type SearchModel struct {
+	keys         searchKeyMap
+	table        table.Model
+	serialNumber textinput.Model
+	result       *messages.SerialNumberSearchMsg
+	errorMsg     string
+	styles       *styles.Styles
+	commands     *commands.Commands
+}
+
+func NewSearchModel(height int, cmds *commands.Commands) *SearchModel {
+	columns := []table.Column{
+		{Title: "Serial number (hex)", Width: 48},
+		{Title: "Serial number", Width: 40},
+		{Title: "Revoked by", Width: 30},
+		{Title: "Reason", Width: 20},
+		{Title: "Revoked", Width: 10},
+	}
+
+	tbl := table.New(table.WithColumns(columns), table.WithHeight(height-14), table.WithWidth(160))
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(styles.Theme.ListComponentTitle).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(styles.Theme.FilePickerCurrent.GetForeground()).
+		Background(styles.Theme.BaseText.GetBackground()).
+		Bold(false)
+	tbl.SetStyles(s)
+
+	serialNumber := textinput.New()
+	serialNumber.Placeholder = "4096, 0x1000 or 10:00"
+	serialNumber.Prompt = "Serial number: "
+	serialNumber.Width = 60
+	serialNumber.Focus()
+
+	return &SearchModel{
+		keys:         searchKeys,
+		table:        tbl,
+		serialNumber: serialNumber,
+		styles:       styles.Theme,
+		commands:     cmds,
+	}
+}
+
+func (m *SearchModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// searching is true while the serial number is being edited
+func (m *SearchModel) searching() bool {
+	return m.serialNumber.Focused()
+}
+
+func (m *SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case messages.SerialNumberSearchMsg:
+		m.result = &msg
+		m.errorMsg = ""
+		m.setRows(msg.RevokedCertificates)
+		m.serialNumber.Blur()
+		m.table.Focus()
+		return m, nil
+	case messages.ErrorMsg:
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Quit) {
+			return m, tea.Quit
+		}
+
+		if m.searching() {
+			if key.Matches(msg, m.keys.Enter) {
+				return m, m.commands.SearchSerialNumber(strings.TrimSpace(m.serialNumber.Value()))
+			}
+			m.serialNumber, cmd = m.serialNumber.Update(msg)
+			return m, cmd
+		}
+
+		if key.Matches(msg, m.keys.Search) {
+			m.table.Blur()
+			return m, m.serialNumber.Focus()
+		}
+	}
+
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m *SearchModel) setRows(revokedCertificates []*crl.RevokedCertificate) {
+	rows := make([]table.Row, len(revokedCertificates))
+	for i, revokedCertificate := range revokedCertificates {
+		rows[i] = table.Row{
+			crl.FormatSerialNumberHex(revokedCertificate.SerialNumber),
+			revokedCertificate.SerialNumber,
+			revokedCertificate.RevokedBy,
+			revokedCertificate.RevocationReason.String(),
+			revokedCertificate.RevocationDate.Format(time.DateOnly),
+		}
+	}
+	m.table.SetRows(rows)
+}
+
+func (m *SearchModel) View() string {
+	var s strings.Builder
+
+	s.WriteString("\n " + m.serialNumber.View())
+
+	if m.errorMsg != "" {
+		s.WriteString(m.styles.WarningText.Render("\n\n " + m.errorMsg))
+	}
+
+	if m.result != nil {
+		searched := make([]string, len(m.result.SerialNumbers))
+		for i, serialNumber := range m.result.SerialNumbers {
+			searched[i] = serialNumber + " (" + crl.FormatSerialNumberHex(serialNumber) + ")"
+		}
+		s.WriteString("\n\n " + m.styles.Text.Render("Searched: ") + strings.Join(searched, ", "))
+
+		if len(m.result.RevokedCertificates) == 0 {
+			s.WriteString("\n\n " + m.styles.Text.Render("The serial number is not revoked on any stored CRL"))
+		} else {
+			s.WriteString("\n\n " + m.styles.WarningText.Render(fmt.Sprintf("%d revoked certificate(s) found", len(m.result.RevokedCertificates))))
+		}
+	}
+
+	s.WriteString("\n\n" + m.table.View())
+	return s.String()
+}
@@ -26,6 +26,13 @@ type Styles struct {
 	CertificateTitle       lipgloss.Style
 	CertificateText        lipgloss.Style
 	CertificateWarning     lipgloss.Style
+	CertificateSelected    lipgloss.Style
+	CertificateDetails     lipgloss.Style
+	CertificateDetailLabel lipgloss.Style
+	CertificateDetailValue lipgloss.Style
+	LintPassed             lipgloss.Style
+	LintFailed             lipgloss.Style
+	CompareValue           lipgloss.Style
 }
 
 func gruvboxTheme() *colors.ThemeColors {
@@ -85,5 +92,12 @@ func NewStyles(themeName string) {
 		CertificateChain:       lipgloss.NewStyle().PaddingTop(1).PaddingLeft(1),
 		CertificateTitle:       lipgloss.NewStyle().Foreground(themeColors.HighlightText),
 		CertificateText:        lipgloss.NewStyle().PaddingLeft(1).Width(20).Foreground(themeColors.MainBanner),
+		CertificateSelected:    lipgloss.NewStyle().Foreground(themeColors.Text).Background(themeColors.MainBanner),
+		CertificateDetails:     lipgloss.NewStyle().BorderForeground(themeColors.MainBanner).BorderStyle(lipgloss.NormalBorder()).MarginTop(1).PaddingLeft(1).PaddingRight(1),
+		CertificateDetailLabel: lipgloss.NewStyle().Width(20).Foreground(themeColors.MainBanner),
+		CertificateDetailValue: lipgloss.NewStyle().Width(58),
+		LintPassed:             lipgloss.NewStyle().Foreground(themeColors.HighlightText),
+		LintFailed:             lipgloss.NewStyle().Foreground(themeColors.ErrorText),
+		CompareValue:           lipgloss.NewStyle().Width(40).PaddingRight(2),
 	}
 }
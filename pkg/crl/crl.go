@@ -24,6 +24,20 @@ var RevocationReasons = map[int]string{
 }
 
 func FetchRevocationList(revocationListURL string) (*x509.RevocationList, error) {
+	rawCRL, err := DownloadRevocationList(revocationListURL)
+	if err != nil {
+		return nil, err
+	}
+
+	revocationList, err := ParseRevocationList(rawCRL)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("cannot parse CRL from %q", revocationListURL))
+	}
+	return revocationList, nil
+}
+
+// DownloadRevocationList downloads a CRL without parsing it, large CRLs are parsed with ParseRevocationListStream
+func DownloadRevocationList(revocationListURL string) ([]byte, error) {
 	client := http.Client{Timeout: 5 * time.Second}
 	response, err := client.Get(revocationListURL)
 	if err != nil {
@@ -40,11 +54,7 @@ func FetchRevocationList(revocationListURL string) (*x509.RevocationList, error)
 		return nil, errors.Join(err, fmt.Errorf("cannot parse HTTP response from %q", revocationListURL))
 	}
 
-	revocationList, err := ParseRevocationList(rawCRL)
-	if err != nil {
-		return nil, errors.Join(err, fmt.Errorf("cannot parse CRL from %q", revocationListURL))
-	}
-	return revocationList, nil
+	return rawCRL, nil
 }
 
 func ParseRevocationList(rawCRL []byte) (*x509.RevocationList, error) {
@@ -0,0 +1,227 @@
+package crl
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"iter"
+	"math/big"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var (
+	// oidExtensionReasonCode is the CRL entry extension that contains the revocation reason
+	oidExtensionReasonCode = asn1.ObjectIdentifier{2, 5, 29, 21}
+	// oidExtensionDeltaCRLIndicator marks a delta CRL, RFC 5280 section 5.2.4
+	oidExtensionDeltaCRLIndicator = asn1.ObjectIdentifier{2, 5, 29, 27}
+)
+
+// RevocationListStream is a DER encoded CRL of which only the header is parsed, the revoked certificates are parsed while they are read
+// so a CRL with millions of entries never holds more than one parsed entry in memory.
+type RevocationListStream struct {
+	Issuer     pkix.Name
+	ThisUpdate time.Time
+	NextUpdate time.Time
+	Signature  []byte
+	Raw        []byte
+	// Count is the number of revoked certificates in the CRL
+	Count int
+	// Delta is true for a delta CRL, it only lists the changes since its base CRL
+	Delta bool
+
+	revokedCertificates cryptobyte.String
+}
+
+// ParseRevocationListStream parses the header of a DER encoded CRL, like x509.ParseRevocationList the signature of the CRL is not verified
+func ParseRevocationListStream(rawCRL []byte) (*RevocationListStream, error) {
+	stream := &RevocationListStream{Raw: rawCRL}
+
+	input := cryptobyte.String(rawCRL)
+	var certificateList, tbs cryptobyte.String
+	if !input.ReadASN1(&certificateList, cryptobyte_asn1.SEQUENCE) || !input.Empty() {
+		return nil, errors.New("malformed crl")
+	}
+	if !certificateList.ReadASN1(&tbs, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("malformed tbs crl")
+	}
+	if !certificateList.SkipASN1(cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("malformed signature algorithm identifier")
+	}
+	var signature asn1.BitString
+	if !certificateList.ReadASN1BitString(&signature) {
+		return nil, errors.New("malformed signature")
+	}
+	stream.Signature = signature.RightAlign()
+
+	if tbs.PeekASN1Tag(cryptobyte_asn1.INTEGER) && !tbs.SkipASN1(cryptobyte_asn1.INTEGER) {
+		return nil, errors.New("malformed crl version")
+	}
+	if !tbs.SkipASN1(cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("malformed algorithm identifier")
+	}
+
+	var rawIssuer cryptobyte.String
+	if !tbs.ReadASN1Element(&rawIssuer, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("malformed issuer")
+	}
+	var issuer pkix.RDNSequence
+	if rest, err := asn1.Unmarshal(rawIssuer, &issuer); err != nil || len(rest) != 0 {
+		return nil, errors.Join(errors.New("malformed issuer"), err)
+	}
+	stream.Issuer.FillFromRDNSequence(&issuer)
+
+	var err error
+	if stream.ThisUpdate, err = readTime(&tbs); err != nil {
+		return nil, errors.Join(errors.New("malformed this update"), err)
+	}
+	if tbs.PeekASN1Tag(cryptobyte_asn1.UTCTime) || tbs.PeekASN1Tag(cryptobyte_asn1.GeneralizedTime) {
+		if stream.NextUpdate, err = readTime(&tbs); err != nil {
+			return nil, errors.Join(errors.New("malformed next update"), err)
+		}
+	}
+
+	if tbs.PeekASN1Tag(cryptobyte_asn1.SEQUENCE) {
+		if !tbs.ReadASN1(&stream.revokedCertificates, cryptobyte_asn1.SEQUENCE) {
+			return nil, errors.New("malformed revoked certificates")
+		}
+
+		// counting only reads the length of every entry, the entries are parsed when they are read
+		entries := stream.revokedCertificates
+		for !entries.Empty() {
+			if !entries.SkipASN1(cryptobyte_asn1.SEQUENCE) {
+				return nil, errors.New("malformed revoked certificates")
+			}
+			stream.Count++
+		}
+	}
+
+	if stream.Delta, err = readDeltaCRLIndicator(&tbs); err != nil {
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+// readDeltaCRLIndicator reports whether the CRL extensions that follow the revoked certificates contain the delta CRL indicator
+func readDeltaCRLIndicator(tbs *cryptobyte.String) (bool, error) {
+	var extensions cryptobyte.String
+	var present bool
+	if !tbs.ReadOptionalASN1(&extensions, &present, cryptobyte_asn1.Tag(0).Constructed().ContextSpecific()) {
+		return false, errors.New("malformed crl extensions")
+	}
+	if !present {
+		return false, nil
+	}
+	if !extensions.ReadASN1(&extensions, cryptobyte_asn1.SEQUENCE) {
+		return false, errors.New("malformed crl extensions")
+	}
+
+	for !extensions.Empty() {
+		var extension cryptobyte.String
+		var oid asn1.ObjectIdentifier
+		if !extensions.ReadASN1(&extension, cryptobyte_asn1.SEQUENCE) || !extension.ReadASN1ObjectIdentifier(&oid) {
+			return false, errors.New("malformed crl extension")
+		}
+		if oid.Equal(oidExtensionDeltaCRLIndicator) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Entries parses the revoked certificates one by one, iteration stops at the first malformed entry
+func (s *RevocationListStream) Entries() iter.Seq2[x509.RevocationListEntry, error] {
+	return func(yield func(x509.RevocationListEntry, error) bool) {
+		entries := s.revokedCertificates
+		for i := 0; !entries.Empty(); i++ {
+			var rawEntry cryptobyte.String
+			if !entries.ReadASN1Element(&rawEntry, cryptobyte_asn1.SEQUENCE) {
+				yield(x509.RevocationListEntry{}, fmt.Errorf("malformed revoked certificate %d", i+1))
+				return
+			}
+
+			entry, err := parseEntry(rawEntry)
+			if err != nil {
+				yield(x509.RevocationListEntry{}, errors.Join(fmt.Errorf("malformed revoked certificate %d", i+1), err))
+				return
+			}
+
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
+
+// parseEntry parses the serial number, revocation date and entry extensions of a revoked certificate, the reason code extension is parsed into ReasonCode
+func parseEntry(rawEntry cryptobyte.String) (x509.RevocationListEntry, error) {
+	entry := x509.RevocationListEntry{Raw: rawEntry}
+
+	var fields cryptobyte.String
+	if !rawEntry.ReadASN1(&fields, cryptobyte_asn1.SEQUENCE) {
+		return entry, errors.New("malformed entry")
+	}
+
+	entry.SerialNumber = new(big.Int)
+	if !fields.ReadASN1Integer(entry.SerialNumber) {
+		return entry, errors.New("malformed serial number")
+	}
+
+	var err error
+	if entry.RevocationTime, err = readTime(&fields); err != nil {
+		return entry, errors.Join(errors.New("malformed revocation date"), err)
+	}
+
+	if fields.Empty() {
+		return entry, nil
+	}
+
+	var extensions cryptobyte.String
+	if !fields.ReadASN1(&extensions, cryptobyte_asn1.SEQUENCE) {
+		return entry, errors.New("malformed extensions")
+	}
+	for !extensions.Empty() {
+		var extension cryptobyte.String
+		var parsed pkix.Extension
+		if !extensions.ReadASN1(&extension, cryptobyte_asn1.SEQUENCE) || !extension.ReadASN1ObjectIdentifier(&parsed.Id) {
+			return entry, errors.New("malformed extension")
+		}
+		if extension.PeekASN1Tag(cryptobyte_asn1.BOOLEAN) && !extension.ReadASN1Boolean(&parsed.Critical) {
+			return entry, errors.New("malformed extension critical field")
+		}
+		var value cryptobyte.String
+		if !extension.ReadASN1(&value, cryptobyte_asn1.OCTET_STRING) {
+			return entry, errors.New("malformed extension value")
+		}
+		parsed.Value = value
+		entry.Extensions = append(entry.Extensions, parsed)
+
+		if parsed.Id.Equal(oidExtensionReasonCode) && !value.ReadASN1Enum(&entry.ReasonCode) {
+			return entry, errors.New("malformed reason code")
+		}
+	}
+
+	return entry, nil
+}
+
+func readTime(input *cryptobyte.String) (time.Time, error) {
+	var t time.Time
+	switch {
+	case input.PeekASN1Tag(cryptobyte_asn1.UTCTime):
+		if !input.ReadASN1UTCTime(&t) {
+			return t, errors.New("malformed UTCTime")
+		}
+	case input.PeekASN1Tag(cryptobyte_asn1.GeneralizedTime):
+		if !input.ReadASN1GeneralizedTime(&t) {
+			return t, errors.New("malformed GeneralizedTime")
+		}
+	default:
+		return t, errors.New("unsupported time format")
+	}
+	return t, nil
+}
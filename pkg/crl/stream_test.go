@@ -0,0 +1,151 @@
+package crl_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/pkg/crl"
+	"github.com/pimg/certguard/pkg/testpki"
+	"github.com/stretchr/testify/assert"
+)
+
+// generateRevocationList creates a DER encoded CRL with the given number of revoked certificates, every entry has a reason code except reason 0
+func generateRevocationList(tb testing.TB, entries int) []byte {
+	tb.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(tb, err)
+
+	issuer := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Generated CA", Organization: []string{"certguard"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCRLSign | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+
+	revoked := make([]x509.RevocationListEntry, entries)
+	revocationTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range revoked {
+		serialNumber := new(big.Int).Lsh(big.NewInt(int64(i+1)), 96)
+		revoked[i] = x509.RevocationListEntry{
+			SerialNumber:   serialNumber.Add(serialNumber, big.NewInt(int64(i))),
+			RevocationTime: revocationTime.Add(time.Duration(i) * time.Second),
+			ReasonCode:     []int{0, 1, 4, 5}[i%4],
+		}
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                revocationTime,
+		NextUpdate:                revocationTime.Add(7 * 24 * time.Hour),
+		RevokedCertificateEntries: revoked,
+	}, issuer, key)
+	assert.NoError(tb, err)
+	return der
+}
+
+func TestParseRevocationListStream(t *testing.T) {
+	raw := generateRevocationList(t, 100)
+	want, err := x509.ParseRevocationList(raw)
+	assert.NoError(t, err)
+
+	stream, err := crl.ParseRevocationListStream(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, want.Issuer.String(), stream.Issuer.String())
+	assert.Equal(t, want.ThisUpdate, stream.ThisUpdate)
+	assert.Equal(t, want.NextUpdate, stream.NextUpdate)
+	assert.Equal(t, want.Signature, stream.Signature)
+	assert.Equal(t, 100, stream.Count)
+
+	i := 0
+	for entry, err := range stream.Entries() {
+		assert.NoError(t, err)
+		assert.Equal(t, want.RevokedCertificateEntries[i].SerialNumber, entry.SerialNumber)
+		assert.Equal(t, want.RevokedCertificateEntries[i].RevocationTime, entry.RevocationTime)
+		assert.Equal(t, want.RevokedCertificateEntries[i].ReasonCode, entry.ReasonCode)
+		assert.Equal(t, want.RevokedCertificateEntries[i].Extensions, entry.Extensions)
+		i++
+	}
+	assert.Equal(t, 100, i)
+}
+
+func TestParseRevocationListStreamEmpty(t *testing.T) {
+	stream, err := crl.ParseRevocationListStream(generateRevocationList(t, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stream.Count)
+
+	for range stream.Entries() {
+		t.Fatal("empty CRL should not contain entries")
+	}
+}
+
+func TestParseRevocationListStreamDelta(t *testing.T) {
+	pki, err := testpki.Generate(testpki.Options{
+		Revocations: []testpki.Revocation{{SerialNumber: big.NewInt(1)}, {SerialNumber: big.NewInt(2), Delta: true}},
+	})
+	assert.NoError(t, err)
+
+	base, err := crl.ParseRevocationListStream(pki.BaseCRL)
+	assert.NoError(t, err)
+	assert.False(t, base.Delta)
+
+	delta, err := crl.ParseRevocationListStream(pki.DeltaCRL)
+	assert.NoError(t, err)
+	assert.True(t, delta.Delta)
+	assert.Equal(t, 1, delta.Count)
+}
+
+func TestParseRevocationListStreamMalformed(t *testing.T) {
+	raw := generateRevocationList(t, 10)
+
+	_, err := crl.ParseRevocationListStream(raw[:len(raw)-10])
+	assert.Error(t, err)
+
+	_, err = crl.ParseRevocationListStream([]byte("not a CRL"))
+	assert.Error(t, err)
+}
+
+func BenchmarkParseRevocationList(b *testing.B) {
+	for _, entries := range []int{10_000, 100_000} {
+		raw := generateRevocationList(b, entries)
+
+		b.Run(fmt.Sprintf("x509/%d", entries), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				revocationList, err := x509.ParseRevocationList(raw)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for _, entry := range revocationList.RevokedCertificateEntries {
+					_ = entry.SerialNumber
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("stream/%d", entries), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				stream, err := crl.ParseRevocationListStream(raw)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for entry, err := range stream.Entries() {
+					if err != nil {
+						b.Fatal(err)
+					}
+					_ = entry.SerialNumber
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package ct
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	domain_ct "github.com/pimg/certguard/pkg/domain/ct"
+)
+
+const LogListURL = "https://www.gstatic.com/ct/log_list/v3/log_list.json"
+
+// FetchLogList downloads a log list in the Google v3 format and validates that it can be parsed
+func FetchLogList(logListURL string) ([]byte, error) {
+	client := http.Client{Timeout: 10 * time.Second}
+	response, err := client.Get(logListURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve CT log list from logListURL: %s", logListURL)
+	}
+	defer response.Body.Close()
+
+	if !strings.HasPrefix(response.Status, "2") {
+		return nil, fmt.Errorf("server responded with a non 2xx status code: %s", response.Status)
+	}
+
+	rawLogList, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("cannot read HTTP response from %q", logListURL))
+	}
+
+	if _, err := domain_ct.ParseLogList(rawLogList); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("cannot parse CT log list from %q", logListURL))
+	}
+
+	return rawLogList, nil
+}
@@ -0,0 +1,117 @@
+package certificate
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Bundle contains all objects found in a PEM bundle, blocks that are not supported are recorded in Skipped
+type Bundle struct {
+	Certificates        []*x509.Certificate
+	RevocationLists     []*x509.RevocationList
+	CertificateRequests []*x509.CertificateRequest
+	PublicKeys          []crypto.PublicKey
+	Skipped             []SkippedBlock
+}
+
+// SkippedBlock describes a PEM block that was not used, the contents of the block are never retained
+type SkippedBlock struct {
+	Index  int
+	Type   string
+	Reason string
+}
+
+func (s SkippedBlock) String() string {
+	return fmt.Sprintf("skipped PEM block %d (%s): %s", s.Index, s.Type, s.Reason)
+}
+
+// Notices returns a human readable notice for every skipped block
+func (b *Bundle) Notices() []string {
+	notices := make([]string, 0, len(b.Skipped))
+	for _, skipped := range b.Skipped {
+		notices = append(notices, skipped.String())
+	}
+	return notices
+}
+
+// ParsePEMBundle classifies every block of PEM encoded data into certificates, CRLs, CSRs and public keys.
+// Private keys are never decoded, their block contents are wiped and only their position in the bundle is recorded.
+func ParsePEMBundle(data []byte) (*Bundle, error) {
+	bundle := &Bundle{}
+	index := 0
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		index++
+		switch {
+		case block.Type == "CERTIFICATE" || block.Type == "X509 CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the PEM certificate: %v", err)
+			}
+			bundle.Certificates = append(bundle.Certificates, cert)
+		case block.Type == "TRUSTED CERTIFICATE":
+			cert, err := parseTrustedCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the PEM trusted certificate: %v", err)
+			}
+			bundle.Certificates = append(bundle.Certificates, cert)
+		case block.Type == "PKCS7":
+			certs, err := ParsePKCS7(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the PEM PKCS#7 structure: %v", err)
+			}
+			bundle.Certificates = append(bundle.Certificates, certs...)
+		case block.Type == "X509 CRL":
+			revocationList, err := x509.ParseRevocationList(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the PEM CRL: %v", err)
+			}
+			bundle.RevocationLists = append(bundle.RevocationLists, revocationList)
+		case block.Type == "CERTIFICATE REQUEST" || block.Type == "NEW CERTIFICATE REQUEST":
+			csr, err := x509.ParseCertificateRequest(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the PEM certificate request: %v", err)
+			}
+			bundle.CertificateRequests = append(bundle.CertificateRequests, csr)
+		case block.Type == "PUBLIC KEY" || block.Type == "RSA PUBLIC KEY":
+			publicKey, err := parsePublicKey(block)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the PEM public key: %v", err)
+			}
+			bundle.PublicKeys = append(bundle.PublicKeys, publicKey)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			clear(block.Bytes)
+			bundle.Skipped = append(bundle.Skipped, SkippedBlock{Index: index, Type: block.Type, Reason: "private keys are not read"})
+		default:
+			bundle.Skipped = append(bundle.Skipped, SkippedBlock{Index: index, Type: block.Type, Reason: "unsupported block type"})
+		}
+	}
+
+	if index == 0 {
+		return nil, errors.New("no PEM blocks found")
+	}
+
+	return bundle, nil
+}
+
+// parseTrustedCertificate parses an OpenSSL TRUSTED CERTIFICATE, which is a DER certificate followed by auxiliary trust settings
+func parseTrustedCertificate(der []byte) (*x509.Certificate, error) {
+	var raw asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &raw); err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(raw.FullBytes)
+}
+
+func parsePublicKey(block *pem.Block) (crypto.PublicKey, error) {
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
@@ -0,0 +1,80 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePEMBundleMixed(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "csr.example.com"}}, key)
+	assert.NoError(t, err)
+
+	data := readTestFile(t, "github.com-chain.pem")
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: readTestFile(t, "ca.crl")})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "DH PARAMETERS", Bytes: []byte{0x30, 0x00}})...)
+
+	bundle, err := ParsePEMBundle(data)
+	assert.NoError(t, err)
+
+	assert.Len(t, bundle.Certificates, 3)
+	assert.Len(t, bundle.CertificateRequests, 1)
+	assert.Equal(t, "csr.example.com", bundle.CertificateRequests[0].Subject.CommonName)
+	assert.Len(t, bundle.PublicKeys, 1)
+	assert.Len(t, bundle.RevocationLists, 1)
+
+	assert.Equal(t, []SkippedBlock{
+		{Index: 4, Type: "PRIVATE KEY", Reason: "private keys are not read"},
+		{Index: 8, Type: "DH PARAMETERS", Reason: "unsupported block type"},
+	}, bundle.Skipped)
+	assert.Equal(t, "skipped PEM block 4 (PRIVATE KEY): private keys are not read", bundle.Notices()[0])
+}
+
+func TestParsePEMCertificateIgnoresPrivateKeys(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	data := append(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), readTestFile(t, "github.com-chain.pem")...)
+
+	certificates, err := ParsePEMCertificate(data)
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 3)
+
+	_, err = ParsePEMCertificate(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	assert.ErrorContains(t, err, "failed to parse the PEM certificate")
+}
+
+func TestParsePEMBundleTrustedCertificate(t *testing.T) {
+	certificates := loadCertificates(t, "github.com-chain.pem")
+
+	// OpenSSL appends the trust settings as an additional SEQUENCE after the certificate
+	trusted := append(append([]byte{}, certificates[2].Raw...), 0x30, 0x00)
+	bundle, err := ParsePEMBundle(pem.EncodeToMemory(&pem.Block{Type: "TRUSTED CERTIFICATE", Bytes: trusted}))
+	assert.NoError(t, err)
+
+	assert.Len(t, bundle.Certificates, 1)
+	assert.Equal(t, certificates[2].Subject.CommonName, bundle.Certificates[0].Subject.CommonName)
+}
+
+func TestParsePEMBundleNoBlocks(t *testing.T) {
+	_, err := ParsePEMBundle([]byte(strings.Repeat("not a PEM block\n", 3)))
+	assert.ErrorContains(t, err, "no PEM blocks found")
+}
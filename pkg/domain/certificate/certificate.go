@@ -1,30 +1,126 @@
 package certificate
 
 import (
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/x509"
-	"encoding/pem"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// ParsePEMCertificate returns all certificates in PEM encoded data, blocks other than certificates are ignored
 func ParsePEMCertificate(data []byte) ([]*x509.Certificate, error) {
-	certificateChain := make([]*x509.Certificate, 0)
-	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
-		switch block.Type {
-		case "CERTIFICATE":
-			cert, err := x509.ParseCertificate(block.Bytes)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse the PEM certificate: %v", err)
-			}
-			certificateChain = append(certificateChain, cert)
-		default:
-			return nil, errors.New("unsupported block type, only CERTIFICATE is supported")
-		}
+	bundle, err := ParsePEMBundle(data)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(certificateChain) == 0 {
+	if len(bundle.Certificates) == 0 {
 		return nil, errors.New("failed to parse the PEM certificate")
 	}
 
-	return certificateChain, nil
+	return bundle.Certificates, nil
+}
+
+// Certificate is the domain representation of a x.509 certificate containing all commonly used fields and extensions in a printable format
+type Certificate struct {
+	X509                   *x509.Certificate
+	CommonName             string
+	SerialNumber           string
+	Subject                string
+	Issuer                 string
+	NotBefore              time.Time
+	NotAfter               time.Time
+	SubjectAltNames        []string
+	KeyUsage               []string
+	KeyUsageCritical       bool
+	ExtKeyUsage            []string
+	BasicConstraints       *BasicConstraints
+	SubjectKeyID           string
+	AuthorityKeyID         string
+	Policies               []string
+	OCSPServers            []string
+	IssuingCertificateURLs []string
+	CRLDistributionPoints  []string
+	NameConstraints        *NameConstraints
+	PublicKeyAlgorithm     string
+	PublicKeySize          int
+	SignatureAlgorithm     string
+	FingerprintSHA1        string
+	FingerprintSHA256      string
+}
+
+type BasicConstraints struct {
+	Critical bool
+	IsCA     bool
+	// MaxPathLen is -1 when no path length constraint is set
+	MaxPathLen int
+}
+
+func (b *BasicConstraints) String() string {
+	pathLen := "unlimited"
+	if b.MaxPathLen >= 0 {
+		pathLen = strconv.Itoa(b.MaxPathLen)
+	}
+	return fmt.Sprintf("CA: %t, path length: %s", b.IsCA, pathLen)
+}
+
+type NameConstraints struct {
+	Critical  bool
+	Permitted []string
+	Excluded  []string
+}
+
+// FromX509Certificate parses all supported fields and extensions from a x.509 certificate
+func FromX509Certificate(cert *x509.Certificate) *Certificate {
+	sha1Fingerprint := sha1.Sum(cert.Raw)
+	sha256Fingerprint := sha256.Sum256(cert.Raw)
+
+	publicKeyAlgorithm, publicKeySize := publicKeyInfo(cert.PublicKey, cert.PublicKeyAlgorithm)
+
+	return &Certificate{
+		X509:                   cert,
+		CommonName:             cert.Subject.CommonName,
+		SerialNumber:           cert.SerialNumber.String(),
+		Subject:                cert.Subject.String(),
+		Issuer:                 cert.Issuer.String(),
+		NotBefore:              cert.NotBefore,
+		NotAfter:               cert.NotAfter,
+		SubjectAltNames:        subjectAltNames(cert.DNSNames, cert.EmailAddresses, cert.IPAddresses, cert.URIs),
+		KeyUsage:               keyUsages(cert.KeyUsage),
+		KeyUsageCritical:       isCritical(cert, oidExtensionKeyUsage),
+		ExtKeyUsage:            extKeyUsages(cert),
+		BasicConstraints:       basicConstraints(cert),
+		SubjectKeyID:           FormatHex(cert.SubjectKeyId),
+		AuthorityKeyID:         FormatHex(cert.AuthorityKeyId),
+		Policies:               policies(cert),
+		OCSPServers:            cert.OCSPServer,
+		IssuingCertificateURLs: cert.IssuingCertificateURL,
+		CRLDistributionPoints:  cert.CRLDistributionPoints,
+		NameConstraints:        nameConstraints(cert),
+		PublicKeyAlgorithm:     publicKeyAlgorithm,
+		PublicKeySize:          publicKeySize,
+		SignatureAlgorithm:     cert.SignatureAlgorithm.String(),
+		FingerprintSHA1:        FormatHex(sha1Fingerprint[:]),
+		FingerprintSHA256:      FormatHex(sha256Fingerprint[:]),
+	}
+}
+
+// FormatHex formats bytes as colon separated uppercase hex, the notation used by most certificate tooling
+func FormatHex(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	encoded := strings.ToUpper(hex.EncodeToString(b))
+	pairs := make([]string, 0, len(b))
+	for i := 0; i < len(encoded); i += 2 {
+		pairs = append(pairs, encoded[i:i+2])
+	}
+
+	return strings.Join(pairs, ":")
 }
@@ -0,0 +1,50 @@
+package certificate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromX509Certificate(t *testing.T) {
+	certificates := loadCertificates(t, "github.com-chain.pem")
+
+	cert := FromX509Certificate(certificates[0])
+
+	assert.Equal(t, "github.com", cert.CommonName)
+	assert.Equal(t, []string{"DNS:github.com", "DNS:www.github.com"}, cert.SubjectAltNames)
+	assert.Equal(t, []string{"Digital Signature"}, cert.KeyUsage)
+	assert.True(t, cert.KeyUsageCritical)
+	assert.Equal(t, []string{"TLS Web Server Authentication", "TLS Web Client Authentication"}, cert.ExtKeyUsage)
+	assert.False(t, cert.BasicConstraints.IsCA)
+	assert.True(t, cert.BasicConstraints.Critical)
+	assert.Equal(t, -1, cert.BasicConstraints.MaxPathLen)
+	assert.Equal(t, "3B:68:3F:34:3A:F5:47:34:CA:EF:A6:4E:3D:9A:BD:5E:6E:7A:CC:9F", cert.SubjectKeyID)
+	assert.Equal(t, "F6:85:0A:3B:11:86:E1:04:7D:0E:AA:0B:2C:D2:EE:CC:64:7B:7B:AE", cert.AuthorityKeyID)
+	assert.Equal(t, []string{"1.3.6.1.4.1.6449.1.2.2.7", "2.23.140.1.2.1 (Domain Validated)"}, cert.Policies)
+	assert.Equal(t, []string{"http://ocsp.sectigo.com"}, cert.OCSPServers)
+	assert.Equal(t, []string{"http://crt.sectigo.com/SectigoECCDomainValidationSecureServerCA.crt"}, cert.IssuingCertificateURLs)
+	assert.Nil(t, cert.NameConstraints)
+	assert.Equal(t, "ECDSA P-256", cert.PublicKeyAlgorithm)
+	assert.Equal(t, 256, cert.PublicKeySize)
+	assert.Equal(t, "ECDSA-SHA256", cert.SignatureAlgorithm)
+	assert.Len(t, cert.FingerprintSHA1, 59)
+	assert.Len(t, cert.FingerprintSHA256, 95)
+}
+
+func TestFromX509CertificateCA(t *testing.T) {
+	certificates := loadCertificates(t, "github.com-chain.pem")
+
+	cert := FromX509Certificate(certificates[1])
+
+	assert.True(t, cert.BasicConstraints.IsCA)
+	assert.Equal(t, 0, cert.BasicConstraints.MaxPathLen)
+	assert.Contains(t, cert.KeyUsage, "Certificate Sign")
+	assert.Contains(t, cert.KeyUsage, "CRL Sign")
+	assert.NotEmpty(t, cert.CRLDistributionPoints)
+}
+
+func TestFormatHex(t *testing.T) {
+	assert.Equal(t, "", FormatHex(nil))
+	assert.Equal(t, "0A:FF:10", FormatHex([]byte{0x0a, 0xff, 0x10}))
+}
@@ -0,0 +1,154 @@
+package certificate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// Chain is a certificate chain ordered from the leaf up to the (self-signed) root.
+type Chain struct {
+	Certificates []*x509.Certificate
+	Warnings     []string
+}
+
+// Leaf returns the end-entity certificate of the chain
+func (c *Chain) Leaf() *x509.Certificate {
+	if len(c.Certificates) == 0 {
+		return nil
+	}
+	return c.Certificates[0]
+}
+
+// OrderChain orders a bundle of certificates into a leaf first chain based on subject/issuer and key identifier matching.
+// Duplicate certificates and certificates that are not part of the chain of the leaf are dropped, a warning is added for each dropped certificate.
+func OrderChain(certificates []*x509.Certificate) (*Chain, error) {
+	if len(certificates) == 0 {
+		return nil, errors.New("no certificates to order")
+	}
+
+	chain := &Chain{}
+
+	unique := make([]*x509.Certificate, 0, len(certificates))
+	seen := make(map[[sha256.Size]byte]bool, len(certificates))
+	for _, cert := range certificates {
+		fingerprint := sha256.Sum256(cert.Raw)
+		if seen[fingerprint] {
+			chain.Warnings = append(chain.Warnings, fmt.Sprintf("duplicate certificate dropped: %s", displayName(cert)))
+			continue
+		}
+		seen[fingerprint] = true
+		unique = append(unique, cert)
+	}
+
+	for _, candidate := range leafCandidates(unique) {
+		path := buildPath(candidate, unique)
+		if len(path) > len(chain.Certificates) {
+			chain.Certificates = path
+		}
+	}
+
+	for _, cert := range unique {
+		if !contains(chain.Certificates, cert) {
+			chain.Warnings = append(chain.Warnings, fmt.Sprintf("unrelated certificate dropped: %s", displayName(cert)))
+		}
+	}
+
+	return chain, nil
+}
+
+// IssuedBy reports whether child has been issued by parent, based on the issuer and subject names and, when present, the key identifiers
+func IssuedBy(child, parent *x509.Certificate) bool {
+	if !bytes.Equal(child.RawIssuer, parent.RawSubject) {
+		return false
+	}
+
+	if len(child.AuthorityKeyId) > 0 && len(parent.SubjectKeyId) > 0 {
+		return bytes.Equal(child.AuthorityKeyId, parent.SubjectKeyId)
+	}
+
+	return true
+}
+
+// FindIssuer returns the certificate from the chain that issued cert, or nil when the issuer is not part of the chain
+func FindIssuer(cert *x509.Certificate, chain []*x509.Certificate) *x509.Certificate {
+	if isSelfSigned(cert) {
+		return nil
+	}
+
+	for _, candidate := range chain {
+		if candidate != cert && IssuedBy(cert, candidate) {
+			return candidate
+		}
+	}
+
+	return nil
+}
+
+// leafCandidates returns all certificates that did not issue any other certificate in the bundle, end-entity certificates are returned first.
+// When every certificate issued another one (e.g. a cross-signed loop) all certificates are returned.
+func leafCandidates(certificates []*x509.Certificate) []*x509.Certificate {
+	endEntities := make([]*x509.Certificate, 0)
+	authorities := make([]*x509.Certificate, 0)
+	for _, cert := range certificates {
+		if issuesAny(cert, certificates) {
+			continue
+		}
+
+		if cert.IsCA {
+			authorities = append(authorities, cert)
+		} else {
+			endEntities = append(endEntities, cert)
+		}
+	}
+
+	candidates := append(endEntities, authorities...)
+	if len(candidates) == 0 {
+		return certificates
+	}
+
+	return candidates
+}
+
+func issuesAny(parent *x509.Certificate, certificates []*x509.Certificate) bool {
+	for _, child := range certificates {
+		if child != parent && !isSelfSigned(child) && IssuedBy(child, parent) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildPath(leaf *x509.Certificate, certificates []*x509.Certificate) []*x509.Certificate {
+	path := []*x509.Certificate{leaf}
+	for current := leaf; ; {
+		issuer := FindIssuer(current, certificates)
+		if issuer == nil || contains(path, issuer) {
+			return path
+		}
+		path = append(path, issuer)
+		current = issuer
+	}
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return IssuedBy(cert, cert)
+}
+
+func contains(certificates []*x509.Certificate, cert *x509.Certificate) bool {
+	for _, c := range certificates {
+		if c == cert {
+			return true
+		}
+	}
+	return false
+}
+
+func displayName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	return cert.Subject.String()
+}
@@ -0,0 +1,83 @@
+package certificate
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadCertificates(t *testing.T, name string) []*x509.Certificate {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("..", "..", "..", "testing", "pki", name))
+	assert.NoError(t, err)
+
+	certificates, err := ParsePEMCertificate(raw)
+	assert.NoError(t, err)
+
+	return certificates
+}
+
+func TestOrderChainLeafFirst(t *testing.T) {
+	certificates := loadCertificates(t, "github.com-chain.pem")
+
+	chain, err := OrderChain(certificates)
+	assert.NoError(t, err)
+
+	assert.Equal(t, certificates, chain.Certificates)
+	assert.Equal(t, "github.com", chain.Leaf().Subject.CommonName)
+	assert.Empty(t, chain.Warnings)
+}
+
+func TestOrderChainRootFirst(t *testing.T) {
+	certificates := loadCertificates(t, "github.com-chain.pem")
+	rootFirst := slices.Clone(certificates)
+	slices.Reverse(rootFirst)
+
+	chain, err := OrderChain(rootFirst)
+	assert.NoError(t, err)
+
+	assert.Equal(t, certificates, chain.Certificates)
+	assert.Equal(t, "github.com", chain.Leaf().Subject.CommonName)
+}
+
+func TestOrderChainUnordered(t *testing.T) {
+	certificates := loadCertificates(t, "github.com-chain.pem")
+	unordered := []*x509.Certificate{certificates[1], certificates[2], certificates[0]}
+
+	chain, err := OrderChain(unordered)
+	assert.NoError(t, err)
+
+	assert.Equal(t, certificates, chain.Certificates)
+}
+
+func TestOrderChainDropsDuplicatesAndUnrelated(t *testing.T) {
+	certificates := loadCertificates(t, "github.com-chain.pem")
+	unrelated := loadCertificates(t, "org-on-crl.pem")
+
+	bundle := []*x509.Certificate{certificates[2], unrelated[0], certificates[0], certificates[1], certificates[0]}
+
+	chain, err := OrderChain(bundle)
+	assert.NoError(t, err)
+
+	assert.Equal(t, certificates, chain.Certificates)
+	assert.Len(t, chain.Warnings, 2)
+	assert.Contains(t, chain.Warnings, "duplicate certificate dropped: github.com")
+	assert.Contains(t, chain.Warnings, "unrelated certificate dropped: inway.test-crl")
+}
+
+func TestOrderChainEmpty(t *testing.T) {
+	_, err := OrderChain(nil)
+	assert.Error(t, err)
+}
+
+func TestFindIssuer(t *testing.T) {
+	certificates := loadCertificates(t, "github.com-chain.pem")
+
+	assert.Equal(t, certificates[1], FindIssuer(certificates[0], certificates))
+	assert.Equal(t, certificates[2], FindIssuer(certificates[1], certificates))
+	assert.Nil(t, FindIssuer(certificates[2], certificates))
+}
@@ -0,0 +1,141 @@
+package certificate
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// FieldComparison contains the values of a single field of two certificates
+type FieldComparison struct {
+	Field string
+	Old   []string
+	New   []string
+	Equal bool
+	// ExpectChange is set for fields that should differ after a renewal, like the serial number and the public key
+	ExpectChange bool
+}
+
+// Expected returns true when the field matches the expectation for a renewal
+func (f FieldComparison) Expected() bool {
+	return f.Equal != f.ExpectChange
+}
+
+// Removed returns the values that are only present in the old certificate
+func (f FieldComparison) Removed() []string {
+	return difference(f.Old, f.New)
+}
+
+// Added returns the values that are only present in the new certificate
+func (f FieldComparison) Added() []string {
+	return difference(f.New, f.Old)
+}
+
+// Comparison is the field by field comparison of a certificate and its renewal
+type Comparison struct {
+	Old    *Certificate
+	New    *Certificate
+	Fields []FieldComparison
+	// Warnings contains renewal problems that are not visible from a single field
+	Warnings []string
+}
+
+// Compare compares a certificate with its renewal. Lists like SANs are compared regardless of order.
+func Compare(old, new *Certificate) *Comparison {
+	comparison := &Comparison{Old: old, New: new}
+
+	add := func(field string, oldValues, newValues []string, expectChange bool) {
+		comparison.Fields = append(comparison.Fields, FieldComparison{
+			Field:        field,
+			Old:          oldValues,
+			New:          newValues,
+			Equal:        sameValues(oldValues, newValues),
+			ExpectChange: expectChange,
+		})
+	}
+
+	add("Subject", []string{old.Subject}, []string{new.Subject}, false)
+	add("Issuer", []string{old.Issuer}, []string{new.Issuer}, false)
+	add("Serialnumber", []string{old.SerialNumber}, []string{new.SerialNumber}, true)
+	add("NotBefore", []string{old.NotBefore.Format(time.RFC3339)}, []string{new.NotBefore.Format(time.RFC3339)}, true)
+	add("NotAfter", []string{old.NotAfter.Format(time.RFC3339)}, []string{new.NotAfter.Format(time.RFC3339)}, true)
+	add("Validity", []string{validityDays(old)}, []string{validityDays(new)}, false)
+	add("SANs", old.SubjectAltNames, new.SubjectAltNames, false)
+	add("Key Usage", withCriticalFlag(old.KeyUsage, old.KeyUsageCritical), withCriticalFlag(new.KeyUsage, new.KeyUsageCritical), false)
+	add("Ext Key Usage", old.ExtKeyUsage, new.ExtKeyUsage, false)
+	add("Basic Constraints", basicConstraintsValue(old.BasicConstraints), basicConstraintsValue(new.BasicConstraints), false)
+	add("Policies", old.Policies, new.Policies, false)
+	add("Public Key", []string{publicKeyValue(old)}, []string{publicKeyValue(new)}, false)
+	add("SPKI SHA-256", []string{publicKeyFingerprint(old)}, []string{publicKeyFingerprint(new)}, true)
+	add("Signature", []string{old.SignatureAlgorithm}, []string{new.SignatureAlgorithm}, false)
+	add("OCSP", old.OCSPServers, new.OCSPServers, false)
+	add("CA Issuers", old.IssuingCertificateURLs, new.IssuingCertificateURLs, false)
+	add("CRL DPs", old.CRLDistributionPoints, new.CRLDistributionPoints, false)
+
+	if !new.NotAfter.After(old.NotAfter) {
+		comparison.Warnings = append(comparison.Warnings, "the new certificate does not expire after the old certificate")
+	}
+	if new.NotBefore.Before(old.NotBefore) {
+		comparison.Warnings = append(comparison.Warnings, "the new certificate is valid before the old certificate")
+	}
+	if publicKeyFingerprint(old) == publicKeyFingerprint(new) {
+		comparison.Warnings = append(comparison.Warnings, "the new certificate reuses the public key of the old certificate")
+	}
+
+	return comparison
+}
+
+// Differences returns the number of fields that do not match the expectation for a renewal
+func (c *Comparison) Differences() int {
+	count := 0
+	for _, field := range c.Fields {
+		if !field.Expected() {
+			count++
+		}
+	}
+	return count
+}
+
+func sameValues(a, b []string) bool {
+	return len(difference(a, b)) == 0 && len(difference(b, a)) == 0
+}
+
+// difference returns the values of a that are not in b
+func difference(a, b []string) []string {
+	values := make([]string, 0)
+	for _, value := range a {
+		if !slices.Contains(b, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func validityDays(cert *Certificate) string {
+	return strconv.Itoa(int(cert.NotAfter.Sub(cert.NotBefore).Round(time.Hour).Hours()/24)) + " days"
+}
+
+func withCriticalFlag(values []string, critical bool) []string {
+	if critical && len(values) > 0 {
+		return append([]string{"critical"}, values...)
+	}
+	return values
+}
+
+func basicConstraintsValue(constraints *BasicConstraints) []string {
+	if constraints == nil {
+		return nil
+	}
+	return withCriticalFlag([]string{constraints.String()}, constraints.Critical)
+}
+
+func publicKeyValue(cert *Certificate) string {
+	return fmt.Sprintf("%s (%d bit)", cert.PublicKeyAlgorithm, cert.PublicKeySize)
+}
+
+func publicKeyFingerprint(cert *Certificate) string {
+	fingerprint := sha256.Sum256(cert.X509.RawSubjectPublicKeyInfo)
+	return FormatHex(fingerprint[:])
+}
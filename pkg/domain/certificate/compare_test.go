@@ -0,0 +1,80 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRenewalCertificate(t *testing.T, serial int64, notBefore time.Time, dnsNames []string) *Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "renew.certguard.test"},
+		DNSNames:     dnsNames,
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return FromX509Certificate(cert)
+}
+
+func TestCompareRenewal(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	old := newRenewalCertificate(t, 1, now.Add(-60*24*time.Hour), []string{"renew.certguard.test", "www.renew.certguard.test"})
+	renewed := newRenewalCertificate(t, 2, now, []string{"www.renew.certguard.test", "renew.certguard.test"})
+
+	comparison := Compare(old, renewed)
+
+	assert.Equal(t, 0, comparison.Differences())
+	assert.Empty(t, comparison.Warnings)
+}
+
+func TestCompareChangedSANs(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	old := newRenewalCertificate(t, 1, now.Add(-60*24*time.Hour), []string{"renew.certguard.test", "old.renew.certguard.test"})
+	renewed := newRenewalCertificate(t, 2, now, []string{"renew.certguard.test", "new.renew.certguard.test"})
+
+	comparison := Compare(old, renewed)
+
+	// the subject is equal, the SANs differ
+	assert.Equal(t, 1, comparison.Differences())
+	for _, field := range comparison.Fields {
+		if field.Field == "SANs" {
+			assert.False(t, field.Expected())
+			assert.Equal(t, []string{"DNS:old.renew.certguard.test"}, field.Removed())
+			assert.Equal(t, []string{"DNS:new.renew.certguard.test"}, field.Added())
+		}
+	}
+}
+
+func TestCompareSameCertificate(t *testing.T) {
+	leaf := FromX509Certificate(loadCertificates(t, "github.com-chain.pem")[0])
+
+	comparison := Compare(leaf, leaf)
+
+	// serial, validity dates and key are expected to change
+	assert.Equal(t, 4, comparison.Differences())
+	assert.Equal(t, []string{
+		"the new certificate does not expire after the old certificate",
+		"the new certificate reuses the public key of the old certificate",
+	}, comparison.Warnings)
+}
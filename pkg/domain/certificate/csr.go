@@ -0,0 +1,146 @@
+package certificate
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+var (
+	oidExtensionSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+	oidExtensionExtKeyUsage    = asn1.ObjectIdentifier{2, 5, 29, 37}
+)
+
+var extKeyUsageOIDs = map[string]x509.ExtKeyUsage{
+	"2.5.29.37.0":       x509.ExtKeyUsageAny,
+	"1.3.6.1.5.5.7.3.1": x509.ExtKeyUsageServerAuth,
+	"1.3.6.1.5.5.7.3.2": x509.ExtKeyUsageClientAuth,
+	"1.3.6.1.5.5.7.3.3": x509.ExtKeyUsageCodeSigning,
+	"1.3.6.1.5.5.7.3.4": x509.ExtKeyUsageEmailProtection,
+	"1.3.6.1.5.5.7.3.8": x509.ExtKeyUsageTimeStamping,
+	"1.3.6.1.5.5.7.3.9": x509.ExtKeyUsageOCSPSigning,
+}
+
+// CertificateRequest is the domain representation of a PKCS#10 certificate signing request
+type CertificateRequest struct {
+	X509               *x509.CertificateRequest
+	CommonName         string
+	Subject            string
+	SubjectAltNames    []string
+	KeyUsage           []string
+	KeyUsageCritical   bool
+	ExtKeyUsage        []string
+	BasicConstraints   *BasicConstraints
+	OtherExtensions    []string
+	PublicKeyAlgorithm string
+	PublicKeySize      int
+	SignatureAlgorithm string
+	// SignatureError is nil when the CSR is signed by the private key of the requested public key
+	SignatureError error
+}
+
+// SignatureValid returns true when the self-signature of the CSR is valid, proving possession of the private key
+func (c *CertificateRequest) SignatureValid() bool {
+	return c.SignatureError == nil
+}
+
+// FromX509CertificateRequest parses the subject, public key and all requested extensions of a CSR
+func FromX509CertificateRequest(csr *x509.CertificateRequest) *CertificateRequest {
+	publicKeyAlgorithm, publicKeySize := publicKeyInfo(csr.PublicKey, csr.PublicKeyAlgorithm)
+
+	request := &CertificateRequest{
+		X509:               csr,
+		CommonName:         csr.Subject.CommonName,
+		Subject:            csr.Subject.String(),
+		SubjectAltNames:    subjectAltNames(csr.DNSNames, csr.EmailAddresses, csr.IPAddresses, csr.URIs),
+		PublicKeyAlgorithm: publicKeyAlgorithm,
+		PublicKeySize:      publicKeySize,
+		SignatureAlgorithm: csr.SignatureAlgorithm.String(),
+		SignatureError:     csr.CheckSignature(),
+	}
+
+	for _, extension := range csr.Extensions {
+		var err error
+		switch {
+		case extension.Id.Equal(oidExtensionSubjectAltName):
+			// already parsed by the x509 package
+		case extension.Id.Equal(oidExtensionKeyUsage):
+			request.KeyUsage, err = requestedKeyUsage(extension.Value)
+			request.KeyUsageCritical = extension.Critical
+		case extension.Id.Equal(oidExtensionExtKeyUsage):
+			request.ExtKeyUsage, err = requestedExtKeyUsage(extension.Value)
+		case extension.Id.Equal(oidExtensionBasicConstraints):
+			request.BasicConstraints, err = requestedBasicConstraints(extension.Value)
+			if request.BasicConstraints != nil {
+				request.BasicConstraints.Critical = extension.Critical
+			}
+		default:
+			name := extension.Id.String()
+			if extension.Critical {
+				name += " (critical)"
+			}
+			request.OtherExtensions = append(request.OtherExtensions, name)
+		}
+
+		if err != nil {
+			request.OtherExtensions = append(request.OtherExtensions, fmt.Sprintf("%s (malformed: %v)", extension.Id, err))
+		}
+	}
+
+	return request
+}
+
+func requestedKeyUsage(value []byte) ([]string, error) {
+	var bits asn1.BitString
+	if _, err := asn1.Unmarshal(value, &bits); err != nil {
+		return nil, err
+	}
+
+	var usage x509.KeyUsage
+	for i := range 9 {
+		if bits.At(i) != 0 {
+			usage |= 1 << uint(i)
+		}
+	}
+
+	return keyUsages(usage), nil
+}
+
+func requestedExtKeyUsage(value []byte) ([]string, error) {
+	var oids []asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(value, &oids); err != nil {
+		return nil, err
+	}
+
+	usages := make([]string, 0, len(oids))
+	for _, oid := range oids {
+		usage, ok := extKeyUsageOIDs[oid.String()]
+		if !ok {
+			usages = append(usages, oid.String())
+			continue
+		}
+		usages = append(usages, extKeyUsageNames[usage])
+	}
+
+	return usages, nil
+}
+
+func requestedBasicConstraints(value []byte) (*BasicConstraints, error) {
+	var constraints struct {
+		IsCA       bool `asn1:"optional"`
+		MaxPathLen int  `asn1:"optional,default:-1"`
+	}
+	rest, err := asn1.Unmarshal(value, &constraints)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after basic constraints")
+	}
+
+	return &BasicConstraints{
+		IsCA:       constraints.IsCA,
+		MaxPathLen: constraints.MaxPathLen,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package certificate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// insecureSignatureAlgorithms are never accepted, regardless of the configured policy
+var insecureSignatureAlgorithms = []string{"MD2-RSA", "MD5-RSA", "SHA1-RSA", "DSA-SHA1", "ECDSA-SHA1"}
+
+// CSRPolicy contains the rules a certificate signing request is linted against
+type CSRPolicy struct {
+	MinRSAKeySize int
+	MinECKeySize  int
+	// AllowedKeyAlgorithms contains public key algorithms (RSA, ECDSA, Ed25519), all algorithms are allowed when empty
+	AllowedKeyAlgorithms []string
+	// AllowedSignatureAlgorithms contains signature algorithms as named by the x509 package (e.g. SHA256-RSA), all secure algorithms are allowed when empty
+	AllowedSignatureAlgorithms []string
+	RequireSANs                bool
+}
+
+func DefaultCSRPolicy() CSRPolicy {
+	return CSRPolicy{
+		MinRSAKeySize:        2048,
+		MinECKeySize:         256,
+		AllowedKeyAlgorithms: []string{"RSA", "ECDSA", "Ed25519"},
+		RequireSANs:          true,
+	}
+}
+
+// LintResult is the outcome of a single policy rule
+type LintResult struct {
+	Rule    string
+	Passed  bool
+	Message string
+}
+
+// Lint checks the CSR against every rule of the policy
+func (p CSRPolicy) Lint(csr *CertificateRequest) []LintResult {
+	results := make([]LintResult, 0, 5)
+
+	if csr.SignatureValid() {
+		results = append(results, LintResult{Rule: "signature", Passed: true, Message: "CSR signature is valid"})
+	} else {
+		results = append(results, LintResult{Rule: "signature", Message: fmt.Sprintf("CSR signature is invalid: %v", csr.SignatureError)})
+	}
+
+	keyAlgorithm, _, _ := strings.Cut(csr.PublicKeyAlgorithm, " ")
+	if len(p.AllowedKeyAlgorithms) == 0 || slices.Contains(p.AllowedKeyAlgorithms, keyAlgorithm) {
+		results = append(results, LintResult{Rule: "key-algorithm", Passed: true, Message: fmt.Sprintf("key algorithm %s is allowed", keyAlgorithm)})
+	} else {
+		results = append(results, LintResult{Rule: "key-algorithm", Message: fmt.Sprintf("key algorithm %s is not allowed, allowed: %s", keyAlgorithm, strings.Join(p.AllowedKeyAlgorithms, ", "))})
+	}
+
+	minKeySize := 0
+	switch keyAlgorithm {
+	case "RSA":
+		minKeySize = p.MinRSAKeySize
+	case "ECDSA":
+		minKeySize = p.MinECKeySize
+	}
+	if csr.PublicKeySize >= minKeySize {
+		results = append(results, LintResult{Rule: "key-size", Passed: true, Message: fmt.Sprintf("key size of %d bit is sufficient", csr.PublicKeySize)})
+	} else {
+		results = append(results, LintResult{Rule: "key-size", Message: fmt.Sprintf("key size of %d bit is below the minimum of %d bit", csr.PublicKeySize, minKeySize)})
+	}
+
+	switch {
+	case slices.Contains(insecureSignatureAlgorithms, csr.SignatureAlgorithm):
+		results = append(results, LintResult{Rule: "signature-algorithm", Message: fmt.Sprintf("signature algorithm %s is insecure", csr.SignatureAlgorithm)})
+	case len(p.AllowedSignatureAlgorithms) == 0 || slices.Contains(p.AllowedSignatureAlgorithms, csr.SignatureAlgorithm):
+		results = append(results, LintResult{Rule: "signature-algorithm", Passed: true, Message: fmt.Sprintf("signature algorithm %s is allowed", csr.SignatureAlgorithm)})
+	default:
+		results = append(results, LintResult{Rule: "signature-algorithm", Message: fmt.Sprintf("signature algorithm %s is not allowed, allowed: %s", csr.SignatureAlgorithm, strings.Join(p.AllowedSignatureAlgorithms, ", "))})
+	}
+
+	if p.RequireSANs {
+		results = append(results, lintSANs(csr))
+	}
+
+	return results
+}
+
+// lintSANs requires at least one SAN and, since clients ignore the common name, requires the common name to be one of the SANs
+func lintSANs(csr *CertificateRequest) LintResult {
+	if len(csr.SubjectAltNames) == 0 {
+		return LintResult{Rule: "subject-alt-names", Message: "CSR does not request any Subject Alternative Names"}
+	}
+
+	if csr.CommonName != "" && !slices.ContainsFunc(csr.SubjectAltNames, func(san string) bool {
+		_, name, _ := strings.Cut(san, ":")
+		return strings.EqualFold(name, csr.CommonName)
+	}) {
+		return LintResult{Rule: "subject-alt-names", Message: fmt.Sprintf("common name %s is not included in the Subject Alternative Names", csr.CommonName)}
+	}
+
+	return LintResult{Rule: "subject-alt-names", Passed: true, Message: fmt.Sprintf("%d Subject Alternative Names requested", len(csr.SubjectAltNames))}
+}
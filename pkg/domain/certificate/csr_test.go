@@ -0,0 +1,113 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCertificateRequest(t *testing.T, template *x509.CertificateRequest, key any) *x509.CertificateRequest {
+	t.Helper()
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	assert.NoError(t, err)
+	csr, err := x509.ParseCertificateRequest(der)
+	assert.NoError(t, err)
+
+	return csr
+}
+
+func TestFromX509CertificateRequest(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	keyUsage, err := asn1.Marshal(asn1.BitString{Bytes: []byte{0x80}, BitLength: 1})
+	assert.NoError(t, err)
+	extKeyUsage, err := asn1.Marshal([]asn1.ObjectIdentifier{{1, 3, 6, 1, 5, 5, 7, 3, 1}})
+	assert.NoError(t, err)
+
+	csr := newCertificateRequest(t, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "csr.certguard.test", Organization: []string{"CertGuard"}},
+		DNSNames: []string{"csr.certguard.test", "www.certguard.test"},
+		ExtraExtensions: []pkix.Extension{
+			{Id: oidExtensionKeyUsage, Critical: true, Value: keyUsage},
+			{Id: oidExtensionExtKeyUsage, Value: extKeyUsage},
+			{Id: asn1.ObjectIdentifier{1, 2, 3, 4}, Value: []byte{0x05, 0x00}},
+		},
+	}, key)
+
+	request := FromX509CertificateRequest(csr)
+
+	assert.Equal(t, "csr.certguard.test", request.CommonName)
+	assert.Equal(t, "CN=csr.certguard.test,O=CertGuard", request.Subject)
+	assert.Equal(t, []string{"DNS:csr.certguard.test", "DNS:www.certguard.test"}, request.SubjectAltNames)
+	assert.Equal(t, []string{"Digital Signature"}, request.KeyUsage)
+	assert.True(t, request.KeyUsageCritical)
+	assert.Equal(t, []string{"TLS Web Server Authentication"}, request.ExtKeyUsage)
+	assert.Equal(t, []string{"1.2.3.4"}, request.OtherExtensions)
+	assert.Equal(t, "ECDSA P-256", request.PublicKeyAlgorithm)
+	assert.Equal(t, 256, request.PublicKeySize)
+	assert.Equal(t, "ECDSA-SHA256", request.SignatureAlgorithm)
+	assert.True(t, request.SignatureValid())
+}
+
+func TestFromX509CertificateRequestInvalidSignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	csr := newCertificateRequest(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "csr.certguard.test"}}, key)
+
+	csr.Signature[len(csr.Signature)-1] ^= 0xff
+
+	request := FromX509CertificateRequest(csr)
+	assert.False(t, request.SignatureValid())
+}
+
+func TestCSRPolicyLint(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	csr := newCertificateRequest(t, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "csr.certguard.test"},
+		DNSNames: []string{"csr.certguard.test"},
+	}, key)
+
+	results := DefaultCSRPolicy().Lint(FromX509CertificateRequest(csr))
+
+	assert.Len(t, results, 5)
+	for _, result := range results {
+		assert.True(t, result.Passed, result.Message)
+	}
+}
+
+func TestCSRPolicyLintViolations(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	csr := newCertificateRequest(t, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "csr.certguard.test"},
+		DNSNames: []string{"other.certguard.test"},
+	}, key)
+
+	policy := DefaultCSRPolicy()
+	policy.AllowedKeyAlgorithms = []string{"ECDSA"}
+	policy.AllowedSignatureAlgorithms = []string{"ECDSA-SHA256"}
+
+	failed := make(map[string]string)
+	for _, result := range policy.Lint(FromX509CertificateRequest(csr)) {
+		if !result.Passed {
+			failed[result.Rule] = result.Message
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"key-algorithm":       "key algorithm RSA is not allowed, allowed: ECDSA",
+		"key-size":            "key size of 1024 bit is below the minimum of 2048 bit",
+		"signature-algorithm": "signature algorithm SHA256-RSA is not allowed, allowed: ECDSA-SHA256",
+		"subject-alt-names":   "common name csr.certguard.test is not included in the Subject Alternative Names",
+	}, failed)
+}
@@ -0,0 +1,200 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+var (
+	oidExtensionKeyUsage         = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidExtensionBasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+	oidExtensionNameConstraints  = asn1.ObjectIdentifier{2, 5, 29, 30}
+)
+
+var keyUsageNames = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "Digital Signature"},
+	{x509.KeyUsageContentCommitment, "Content Commitment"},
+	{x509.KeyUsageKeyEncipherment, "Key Encipherment"},
+	{x509.KeyUsageDataEncipherment, "Data Encipherment"},
+	{x509.KeyUsageKeyAgreement, "Key Agreement"},
+	{x509.KeyUsageCertSign, "Certificate Sign"},
+	{x509.KeyUsageCRLSign, "CRL Sign"},
+	{x509.KeyUsageEncipherOnly, "Encipher Only"},
+	{x509.KeyUsageDecipherOnly, "Decipher Only"},
+}
+
+var extKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                            "Any",
+	x509.ExtKeyUsageServerAuth:                     "TLS Web Server Authentication",
+	x509.ExtKeyUsageClientAuth:                     "TLS Web Client Authentication",
+	x509.ExtKeyUsageCodeSigning:                    "Code Signing",
+	x509.ExtKeyUsageEmailProtection:                "E-mail Protection",
+	x509.ExtKeyUsageIPSECEndSystem:                 "IPSec End System",
+	x509.ExtKeyUsageIPSECTunnel:                    "IPSec Tunnel",
+	x509.ExtKeyUsageIPSECUser:                      "IPSec User",
+	x509.ExtKeyUsageTimeStamping:                   "Time Stamping",
+	x509.ExtKeyUsageOCSPSigning:                    "OCSP Signing",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto:     "Microsoft Server Gated Crypto",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:      "Netscape Server Gated Crypto",
+	x509.ExtKeyUsageMicrosoftCommercialCodeSigning: "Microsoft Commercial Code Signing",
+	x509.ExtKeyUsageMicrosoftKernelCodeSigning:     "Microsoft Kernel Code Signing",
+}
+
+var policyNames = map[string]string{
+	"2.5.29.32.0":    "Any Policy",
+	"2.23.140.1.1":   "Extended Validation",
+	"2.23.140.1.2.1": "Domain Validated",
+	"2.23.140.1.2.2": "Organization Validated",
+	"2.23.140.1.2.3": "Individual Validated",
+	"2.23.140.1.3":   "Extended Validation Code Signing",
+	"2.23.140.1.4.1": "Code Signing",
+}
+
+func subjectAltNames(dnsNames, emailAddresses []string, ipAddresses []net.IP, uris []*url.URL) []string {
+	names := make([]string, 0, len(dnsNames)+len(emailAddresses)+len(ipAddresses)+len(uris))
+	for _, name := range dnsNames {
+		names = append(names, "DNS:"+name)
+	}
+	for _, email := range emailAddresses {
+		names = append(names, "email:"+email)
+	}
+	for _, ip := range ipAddresses {
+		names = append(names, "IP:"+ip.String())
+	}
+	for _, uri := range uris {
+		names = append(names, "URI:"+uri.String())
+	}
+	return names
+}
+
+func keyUsages(keyUsage x509.KeyUsage) []string {
+	usages := make([]string, 0)
+	for _, k := range keyUsageNames {
+		if keyUsage&k.usage != 0 {
+			usages = append(usages, k.name)
+		}
+	}
+	return usages
+}
+
+func extKeyUsages(cert *x509.Certificate) []string {
+	usages := make([]string, 0, len(cert.ExtKeyUsage)+len(cert.UnknownExtKeyUsage))
+	for _, usage := range cert.ExtKeyUsage {
+		name, ok := extKeyUsageNames[usage]
+		if !ok {
+			name = fmt.Sprintf("unknown (%d)", usage)
+		}
+		usages = append(usages, name)
+	}
+	for _, oid := range cert.UnknownExtKeyUsage {
+		usages = append(usages, oid.String())
+	}
+	return usages
+}
+
+func basicConstraints(cert *x509.Certificate) *BasicConstraints {
+	if !cert.BasicConstraintsValid {
+		return nil
+	}
+
+	maxPathLen := -1
+	if cert.MaxPathLen > 0 || cert.MaxPathLenZero {
+		maxPathLen = cert.MaxPathLen
+	}
+
+	return &BasicConstraints{
+		Critical:   isCritical(cert, oidExtensionBasicConstraints),
+		IsCA:       cert.IsCA,
+		MaxPathLen: maxPathLen,
+	}
+}
+
+func policies(cert *x509.Certificate) []string {
+	policies := make([]string, 0, len(cert.PolicyIdentifiers))
+	for _, oid := range cert.PolicyIdentifiers {
+		policy := oid.String()
+		if name, ok := policyNames[policy]; ok {
+			policy = fmt.Sprintf("%s (%s)", policy, name)
+		}
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+func nameConstraints(cert *x509.Certificate) *NameConstraints {
+	if !hasExtension(cert, oidExtensionNameConstraints) {
+		return nil
+	}
+
+	constraints := &NameConstraints{
+		Critical: cert.PermittedDNSDomainsCritical,
+	}
+
+	for _, domain := range cert.PermittedDNSDomains {
+		constraints.Permitted = append(constraints.Permitted, "DNS:"+domain)
+	}
+	for _, ipRange := range cert.PermittedIPRanges {
+		constraints.Permitted = append(constraints.Permitted, "IP:"+ipRange.String())
+	}
+	for _, email := range cert.PermittedEmailAddresses {
+		constraints.Permitted = append(constraints.Permitted, "email:"+email)
+	}
+	for _, uri := range cert.PermittedURIDomains {
+		constraints.Permitted = append(constraints.Permitted, "URI:"+uri)
+	}
+
+	for _, domain := range cert.ExcludedDNSDomains {
+		constraints.Excluded = append(constraints.Excluded, "DNS:"+domain)
+	}
+	for _, ipRange := range cert.ExcludedIPRanges {
+		constraints.Excluded = append(constraints.Excluded, "IP:"+ipRange.String())
+	}
+	for _, email := range cert.ExcludedEmailAddresses {
+		constraints.Excluded = append(constraints.Excluded, "email:"+email)
+	}
+	for _, uri := range cert.ExcludedURIDomains {
+		constraints.Excluded = append(constraints.Excluded, "URI:"+uri)
+	}
+
+	return constraints
+}
+
+func publicKeyInfo(key any, algorithm x509.PublicKeyAlgorithm) (string, int) {
+	switch publicKey := key.(type) {
+	case *rsa.PublicKey:
+		return "RSA", publicKey.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA " + publicKey.Curve.Params().Name, publicKey.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	default:
+		return algorithm.String(), 0
+	}
+}
+
+func hasExtension(cert *x509.Certificate, oid asn1.ObjectIdentifier) bool {
+	for _, extension := range cert.Extensions {
+		if extension.Id.Equal(oid) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCritical(cert *x509.Certificate, oid asn1.ObjectIdentifier) bool {
+	for _, extension := range cert.Extensions {
+		if extension.Id.Equal(oid) {
+			return extension.Critical
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package certificate
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+type Format string
+
+const (
+	FormatPEM     Format = "PEM"
+	FormatDER     Format = "DER"
+	FormatPKCS7   Format = "PKCS#7"
+	FormatPKCS12  Format = "PKCS#12"
+	FormatPKCS10  Format = "PKCS#10"
+	FormatJKS     Format = "JKS"
+	FormatJCEKS   Format = "JCEKS"
+	FormatUnknown Format = "unknown"
+)
+
+// ErrPasswordRequired is returned when a PKCS#12 bundle cannot be opened without a password
+var ErrPasswordRequired = errors.New("PKCS#12 bundle is password protected")
+
+var oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      asn1.RawValue
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      asn1.RawValue
+}
+
+type pfx struct {
+	Version  int
+	AuthSafe asn1.RawValue
+	MacData  asn1.RawValue `asn1:"optional"`
+}
+
+// DetectFormat determines the encoding of certificate data based on its content
+func DetectFormat(data []byte) Format {
+	if bytes.Contains(data, []byte("-----BEGIN ")) {
+		return FormatPEM
+	}
+
+	if format := javaKeystoreFormat(data); format != FormatUnknown {
+		return format
+	}
+
+	if _, err := x509.ParseCertificates(data); err == nil {
+		return FormatDER
+	}
+
+	if _, err := x509.ParseCertificateRequest(data); err == nil {
+		return FormatPKCS10
+	}
+
+	var ci contentInfo
+	if rest, err := asn1.Unmarshal(data, &ci); err == nil && len(rest) == 0 && ci.ContentType.Equal(oidSignedData) {
+		return FormatPKCS7
+	}
+
+	var p pfx
+	if rest, err := asn1.Unmarshal(data, &p); err == nil && len(rest) == 0 && p.Version == 3 {
+		return FormatPKCS12
+	}
+
+	return FormatUnknown
+}
+
+// ParseCertificates extracts all certificates from PEM, DER, PKCS#7, PKCS#12, JKS or JCEKS encoded data, base64 encoded binary formats are accepted as well.
+// The password is only used for PKCS#12 bundles and Java keystores, private keys contained in a bundle are discarded.
+func ParseCertificates(data []byte, password string) ([]*x509.Certificate, error) {
+	bundle, err := ParseBundle(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bundle.Certificates) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+
+	return bundle.Certificates, nil
+}
+
+// ParseBundle detects the format of the data and extracts all supported objects, only PEM data can contain objects other than certificates
+func ParseBundle(data []byte, password string) (*Bundle, error) {
+	format := DetectFormat(data)
+	if format == FormatUnknown {
+		if decoded, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(data), nil))); err == nil {
+			data = decoded
+			format = DetectFormat(data)
+		}
+	}
+
+	var certificates []*x509.Certificate
+	var err error
+	switch format {
+	case FormatPEM:
+		return ParsePEMBundle(data)
+	case FormatDER:
+		certificates, err = x509.ParseCertificates(data)
+	case FormatPKCS7:
+		certificates, err = ParsePKCS7(data)
+	case FormatPKCS12:
+		certificates, err = ParsePKCS12(data, password)
+	case FormatJKS, FormatJCEKS:
+		keystore, err := parseJavaKeystore(data, password, format)
+		if err != nil {
+			return nil, err
+		}
+		certificates = keystore.Certificates()
+	case FormatPKCS10:
+		csr, err := x509.ParseCertificateRequest(data)
+		if err != nil {
+			return nil, err
+		}
+		return &Bundle{CertificateRequests: []*x509.CertificateRequest{csr}}, nil
+	default:
+		return nil, errors.New("unsupported certificate format, supported formats are: PEM, DER, PKCS#7, PKCS#10, PKCS#12, JKS and JCEKS")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bundle{Certificates: certificates}, nil
+}
+
+// ParsePKCS7 extracts the certificates from a (degenerate) PKCS#7 SignedData structure, as used in .p7b and .p7c files
+func ParsePKCS7(data []byte) ([]*x509.Certificate, error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(data, &ci); err != nil {
+		return nil, errors.Join(errors.New("failed to parse PKCS#7 content info"), err)
+	}
+
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf("unsupported PKCS#7 content type: %s", ci.ContentType)
+	}
+
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, errors.Join(errors.New("failed to parse PKCS#7 signed data"), err)
+	}
+
+	if len(sd.Certificates.Bytes) == 0 {
+		return nil, errors.New("PKCS#7 structure does not contain certificates")
+	}
+
+	certificates, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to parse PKCS#7 certificates"), err)
+	}
+
+	return certificates, nil
+}
+
+// ParsePKCS12 extracts the certificates from a PKCS#12 bundle or Java trust store, the private key is decrypted by the PKCS#12 decoder but immediately discarded
+func ParsePKCS12(data []byte, password string) ([]*x509.Certificate, error) {
+	_, leaf, caCertificates, err := pkcs12.DecodeChain(data, password)
+	if err == nil {
+		return append([]*x509.Certificate{leaf}, caCertificates...), nil
+	}
+
+	if errors.Is(err, pkcs12.ErrIncorrectPassword) || errors.Is(err, pkcs12.ErrDecryption) {
+		if password == "" {
+			return nil, ErrPasswordRequired
+		}
+		return nil, errors.New("incorrect PKCS#12 password")
+	}
+
+	certificates, trustStoreErr := pkcs12.DecodeTrustStore(data, password)
+	if trustStoreErr != nil {
+		return nil, errors.Join(errors.New("failed to parse PKCS#12 bundle"), err, trustStoreErr)
+	}
+
+	return certificates, nil
+}
@@ -0,0 +1,82 @@
+package certificate
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readTestFile(t *testing.T, name string) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("..", "..", "..", "testing", "pki", name))
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestDetectFormat(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]Format{
+		"github.com-chain.pem":      FormatPEM,
+		"github.com.der":            FormatDER,
+		"github.com-chain.p7b":      FormatPKCS7,
+		"leaf.certguard.test.p12":   FormatPKCS12,
+		"malformed-certificate.pem": FormatPEM,
+		"truststore.jks":            FormatJKS,
+		"keystore.jceks":            FormatJCEKS,
+	}
+
+	for name, format := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, format, DetectFormat(readTestFile(t, name)))
+		})
+	}
+}
+
+func TestParseCertificatesDER(t *testing.T) {
+	certificates, err := ParseCertificates(readTestFile(t, "github.com.der"), "")
+	assert.NoError(t, err)
+
+	assert.Len(t, certificates, 1)
+	assert.Equal(t, "github.com", certificates[0].Subject.CommonName)
+}
+
+func TestParseCertificatesBase64DER(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(readTestFile(t, "github.com.der"))
+
+	certificates, err := ParseCertificates([]byte(encoded), "")
+	assert.NoError(t, err)
+
+	assert.Len(t, certificates, 1)
+}
+
+func TestParseCertificatesPKCS7(t *testing.T) {
+	certificates, err := ParseCertificates(readTestFile(t, "github.com-chain.p7b"), "")
+	assert.NoError(t, err)
+
+	assert.Len(t, certificates, 3)
+	assert.Equal(t, "github.com", certificates[0].Subject.CommonName)
+}
+
+func TestParseCertificatesPKCS12(t *testing.T) {
+	_, err := ParseCertificates(readTestFile(t, "leaf.certguard.test.p12"), "")
+	assert.ErrorIs(t, err, ErrPasswordRequired)
+
+	_, err = ParseCertificates(readTestFile(t, "leaf.certguard.test.p12"), "wrong")
+	assert.ErrorContains(t, err, "incorrect PKCS#12 password")
+
+	certificates, err := ParseCertificates(readTestFile(t, "leaf.certguard.test.p12"), "certguard")
+	assert.NoError(t, err)
+
+	assert.Len(t, certificates, 2)
+	assert.Equal(t, "leaf.certguard.test", certificates[0].Subject.CommonName)
+	assert.Equal(t, "CertGuard Test CA", certificates[1].Subject.CommonName)
+}
+
+func TestParseCertificatesUnknownFormat(t *testing.T) {
+	_, err := ParseCertificates([]byte("this is not a certificate"), "")
+	assert.ErrorContains(t, err, "unsupported certificate format")
+}
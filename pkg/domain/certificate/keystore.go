@@ -0,0 +1,306 @@
+package certificate
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+	"unicode/utf16"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const (
+	jksMagic   uint32 = 0xFEEDFEED
+	jceksMagic uint32 = 0xCECECECE
+)
+
+// jksWhitener is mixed into the integrity digest of JKS and JCEKS keystores
+const jksWhitener = "Mighty Aphrodite"
+
+// entry tags of JKS and JCEKS keystores
+const (
+	jksPrivateKeyTag  = 1
+	jksTrustedCertTag = 2
+	jceksSecretKeyTag = 3
+)
+
+type KeystoreEntryType string
+
+// the entry types use the names that keytool prints
+const (
+	PrivateKeyEntry         KeystoreEntryType = "PrivateKeyEntry"
+	TrustedCertificateEntry KeystoreEntryType = "trustedCertEntry"
+	SecretKeyEntry          KeystoreEntryType = "SecretKeyEntry"
+)
+
+// ErrKeystoreIntegrity is returned when the integrity digest of a JKS or JCEKS keystore does not match the password
+var ErrKeystoreIntegrity = errors.New("keystore password is incorrect or the keystore has been tampered with")
+
+// passwordRequiredError is returned for JKS and JCEKS keystores opened without a password, it matches ErrPasswordRequired
+type passwordRequiredError struct {
+	format Format
+}
+
+func (e passwordRequiredError) Error() string {
+	return fmt.Sprintf("%s keystore is password protected", e.format)
+}
+
+func (e passwordRequiredError) Is(target error) bool {
+	return target == ErrPasswordRequired
+}
+
+// KeystoreEntry is an alias in a keystore. Private key entries contain the certificate chain of the key, leaf first,
+// the key itself is never decrypted. Secret key entries do not contain certificates.
+type KeystoreEntry struct {
+	Alias        string
+	Type         KeystoreEntryType
+	Created      time.Time
+	Certificates []*x509.Certificate
+}
+
+// Certificate returns the leaf certificate of the entry, nil for secret key entries
+func (e KeystoreEntry) Certificate() *x509.Certificate {
+	if len(e.Certificates) == 0 {
+		return nil
+	}
+	return e.Certificates[0]
+}
+
+// Keystore contains the entries of a JKS, JCEKS or PKCS#12 keystore
+type Keystore struct {
+	Format  Format
+	Entries []KeystoreEntry
+	Notices []string
+}
+
+// Certificates returns the certificates of all entries, certificates that are part of multiple entries are returned once
+func (k *Keystore) Certificates() []*x509.Certificate {
+	certificates := make([]*x509.Certificate, 0, len(k.Entries))
+	for _, entry := range k.Entries {
+		for _, cert := range entry.Certificates {
+			if !slices.ContainsFunc(certificates, cert.Equal) {
+				certificates = append(certificates, cert)
+			}
+		}
+	}
+	return certificates
+}
+
+// ParseKeystore lists the entries of a JKS, JCEKS or PKCS#12 keystore. The password verifies the integrity of JKS and JCEKS keystores
+// and decrypts PKCS#12 files, private and secret keys are never decrypted or retained.
+// PKCS#12 friendly names are not read, the entries of PKCS#12 keystores are named after the common name of their certificate.
+func ParseKeystore(data []byte, password string) (*Keystore, error) {
+	switch format := DetectFormat(data); format {
+	case FormatJKS, FormatJCEKS:
+		return parseJavaKeystore(data, password, format)
+	case FormatPKCS12:
+		return parsePKCS12Keystore(data, password)
+	default:
+		return nil, fmt.Errorf("unsupported keystore format: %s, supported formats are: JKS, JCEKS and PKCS#12", format)
+	}
+}
+
+// javaKeystoreFormat detects the magic number of JKS and JCEKS keystores
+func javaKeystoreFormat(data []byte) Format {
+	if len(data) < 4 {
+		return FormatUnknown
+	}
+
+	switch binary.BigEndian.Uint32(data) {
+	case jksMagic:
+		return FormatJKS
+	case jceksMagic:
+		return FormatJCEKS
+	default:
+		return FormatUnknown
+	}
+}
+
+func parseJavaKeystore(data []byte, password string, format Format) (*Keystore, error) {
+	if password == "" {
+		return nil, passwordRequiredError{format: format}
+	}
+
+	r := &keystoreReader{r: bytes.NewReader(data)}
+	_ = r.uint32() // magic
+	version := r.uint32()
+	count := r.uint32()
+	if r.err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to read %s header", format), r.err)
+	}
+	if version != 1 && version != 2 {
+		return nil, fmt.Errorf("unsupported %s version: %d", format, version)
+	}
+
+	keystore := &Keystore{Format: format, Entries: make([]KeystoreEntry, 0, min(count, 1024))}
+	for i := range count {
+		tag := r.uint32()
+		entry := KeystoreEntry{Alias: r.utf(), Created: time.UnixMilli(int64(r.uint64()))}
+
+		switch tag {
+		case jksPrivateKeyTag:
+			entry.Type = PrivateKeyEntry
+			r.skip(r.uint32()) // encrypted private key
+			chainLength := r.uint32()
+			for range chainLength {
+				entry.Certificates = append(entry.Certificates, r.certificate(version))
+			}
+		case jksTrustedCertTag:
+			entry.Type = TrustedCertificateEntry
+			entry.Certificates = []*x509.Certificate{r.certificate(version)}
+		case jceksSecretKeyTag:
+			if format != FormatJCEKS {
+				return nil, fmt.Errorf("unsupported %s entry type: %d", format, tag)
+			}
+			// secret keys are stored as serialized Java objects, the length of the object is unknown without deserializing it
+			entry.Type = SecretKeyEntry
+			keystore.Entries = append(keystore.Entries, entry)
+			keystore.Notices = append(keystore.Notices,
+				fmt.Sprintf("secret key entry %s cannot be read, %d remaining entries are skipped and the keystore integrity is not verified", entry.Alias, count-i-1))
+			return keystore, nil
+		default:
+			return nil, fmt.Errorf("unsupported %s entry type: %d", format, tag)
+		}
+
+		if r.err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to read %s entry %d", format, i+1), r.err)
+		}
+		keystore.Entries = append(keystore.Entries, entry)
+	}
+
+	offset := len(data) - r.r.Len()
+	digest := make([]byte, sha1.Size)
+	if _, err := io.ReadFull(r.r, digest); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to read %s integrity digest", format), err)
+	}
+	if subtle.ConstantTimeCompare(digest, keystoreDigest(data[:offset], password)) != 1 {
+		return nil, ErrKeystoreIntegrity
+	}
+
+	return keystore, nil
+}
+
+// keystoreDigest computes the integrity digest of JKS and JCEKS keystores: SHA-1 over the UTF-16 encoded password, a whitener and the keystore contents
+func keystoreDigest(data []byte, password string) []byte {
+	h := sha1.New()
+	for _, c := range utf16.Encode([]rune(password)) {
+		h.Write([]byte{byte(c >> 8), byte(c)})
+	}
+	h.Write([]byte(jksWhitener))
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// parsePKCS12Keystore returns a private key entry for PKCS#12 files that contain a key and a trusted certificate entry for every certificate of a trust store
+func parsePKCS12Keystore(data []byte, password string) (*Keystore, error) {
+	keystore := &Keystore{Format: FormatPKCS12}
+
+	_, leaf, caCertificates, err := pkcs12.DecodeChain(data, password)
+	if err == nil {
+		keystore.Entries = append(keystore.Entries, KeystoreEntry{
+			Alias:        keystoreAlias(leaf),
+			Type:         PrivateKeyEntry,
+			Certificates: append([]*x509.Certificate{leaf}, caCertificates...),
+		})
+		return keystore, nil
+	}
+
+	certificates, err := ParsePKCS12(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cert := range certificates {
+		keystore.Entries = append(keystore.Entries, KeystoreEntry{
+			Alias:        keystoreAlias(cert),
+			Type:         TrustedCertificateEntry,
+			Certificates: []*x509.Certificate{cert},
+		})
+	}
+	return keystore, nil
+}
+
+// keystoreAlias names PKCS#12 entries after the common name of their certificate
+func keystoreAlias(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	return cert.Subject.String()
+}
+
+// keystoreReader reads the big endian encoded fields of a Java keystore, the first error is kept and stops further reads
+type keystoreReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (k *keystoreReader) read(n uint32) []byte {
+	if k.err != nil {
+		return nil
+	}
+	if int64(n) > int64(k.r.Len()) {
+		k.err = io.ErrUnexpectedEOF
+		return nil
+	}
+
+	b := make([]byte, n)
+	_, k.err = io.ReadFull(k.r, b)
+	return b
+}
+
+func (k *keystoreReader) skip(n uint32) {
+	k.read(n)
+}
+
+func (k *keystoreReader) uint32() uint32 {
+	b := k.read(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (k *keystoreReader) uint64() uint64 {
+	b := k.read(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// utf reads a string written with Java's DataOutput.writeUTF, aliases are ASCII in practice so modified UTF-8 is read as UTF-8
+func (k *keystoreReader) utf() string {
+	b := k.read(2)
+	if b == nil {
+		return ""
+	}
+	return string(k.read(uint32(binary.BigEndian.Uint16(b))))
+}
+
+// certificate reads a certificate, version 2 keystores prefix every certificate with its type
+func (k *keystoreReader) certificate(version uint32) *x509.Certificate {
+	if version == 2 {
+		if certificateType := k.utf(); k.err == nil && certificateType != "X.509" {
+			k.err = fmt.Errorf("unsupported certificate type: %s", certificateType)
+		}
+	}
+
+	der := k.read(k.uint32())
+	if k.err != nil {
+		return nil
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		k.err = errors.Join(errors.New("failed to parse certificate"), err)
+		return nil
+	}
+	return cert
+}
@@ -0,0 +1,86 @@
+package certificate
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeystoreJKS(t *testing.T) {
+	_, err := ParseKeystore(readTestFile(t, "truststore.jks"), "")
+	assert.ErrorIs(t, err, ErrPasswordRequired)
+	assert.EqualError(t, err, "JKS keystore is password protected")
+
+	_, err = ParseKeystore(readTestFile(t, "truststore.jks"), "wrong")
+	assert.ErrorIs(t, err, ErrKeystoreIntegrity)
+
+	keystore, err := ParseKeystore(readTestFile(t, "truststore.jks"), "certguard")
+	assert.NoError(t, err)
+
+	assert.Equal(t, FormatJKS, keystore.Format)
+	assert.Len(t, keystore.Entries, 4)
+	assert.Equal(t, "sectigo", keystore.Entries[0].Alias)
+	assert.Equal(t, TrustedCertificateEntry, keystore.Entries[0].Type)
+	assert.Equal(t, "Sectigo ECC Domain Validation Secure Server CA", keystore.Entries[0].Certificate().Subject.CommonName)
+	assert.Equal(t, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), keystore.Entries[0].Created.UTC())
+
+	privateKey := keystore.Entries[3]
+	assert.Equal(t, "certguard", privateKey.Alias)
+	assert.Equal(t, PrivateKeyEntry, privateKey.Type)
+	assert.Len(t, privateKey.Certificates, 2)
+	assert.Equal(t, "leaf.certguard.test", privateKey.Certificate().Subject.CommonName)
+
+	assert.Len(t, keystore.Certificates(), 5)
+}
+
+func TestParseKeystoreJCEKS(t *testing.T) {
+	keystore, err := ParseKeystore(readTestFile(t, "keystore.jceks"), "certguard")
+	assert.NoError(t, err)
+
+	assert.Equal(t, FormatJCEKS, keystore.Format)
+	assert.Len(t, keystore.Entries, 2)
+	assert.Equal(t, PrivateKeyEntry, keystore.Entries[0].Type)
+	assert.Equal(t, "github.com", keystore.Entries[1].Alias)
+	assert.Empty(t, keystore.Notices)
+
+	certificates, err := ParseCertificates(readTestFile(t, "keystore.jceks"), "certguard")
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 3)
+}
+
+func TestParseKeystoreSecretKey(t *testing.T) {
+	data := readTestFile(t, "keystore.jceks")
+	// replace the tag of the first entry with a secret key tag
+	secret := append([]byte{}, data...)
+	binary.BigEndian.PutUint32(secret[12:], jceksSecretKeyTag)
+
+	keystore, err := ParseKeystore(secret, "certguard")
+	assert.NoError(t, err)
+	assert.Len(t, keystore.Entries, 1)
+	assert.Equal(t, SecretKeyEntry, keystore.Entries[0].Type)
+	assert.Nil(t, keystore.Entries[0].Certificate())
+	assert.Equal(t, []string{"secret key entry certguard cannot be read, 1 remaining entries are skipped and the keystore integrity is not verified"}, keystore.Notices)
+}
+
+func TestParseKeystoreTruncated(t *testing.T) {
+	data := readTestFile(t, "truststore.jks")
+
+	_, err := ParseKeystore(data[:200], "certguard")
+	assert.ErrorContains(t, err, "failed to read JKS entry")
+}
+
+func TestParseKeystorePKCS12(t *testing.T) {
+	keystore, err := ParseKeystore(readTestFile(t, "leaf.certguard.test.p12"), "certguard")
+	assert.NoError(t, err)
+
+	assert.Equal(t, FormatPKCS12, keystore.Format)
+	assert.Len(t, keystore.Entries, 1)
+	assert.Equal(t, "leaf.certguard.test", keystore.Entries[0].Alias)
+	assert.Equal(t, PrivateKeyEntry, keystore.Entries[0].Type)
+	assert.Len(t, keystore.Entries[0].Certificates, 2)
+
+	_, err = ParseKeystore(readTestFile(t, "github.com.der"), "")
+	assert.ErrorContains(t, err, "unsupported keystore format: DER")
+}
@@ -0,0 +1,144 @@
+package certificate
+
+import (
+	"crypto/x509"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProfile follows the CA/Browser Forum Baseline Requirements
+//
+//go:embed profiles/cabf.yaml
+var defaultProfile []byte
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNotice  Severity = "notice"
+)
+
+// Finding is a problem found by a lint rule
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Profile is a named set of lint rules with their severity and parameters
+type Profile struct {
+	Name        string                `yaml:"name"`
+	Description string                `yaml:"description"`
+	Rules       map[string]RuleConfig `yaml:"rules"`
+}
+
+// RuleConfig configures a rule in a profile, all keys besides severity and disabled are passed to the rule as parameters
+type RuleConfig struct {
+	Severity Severity       `yaml:"severity"`
+	Disabled bool           `yaml:"disabled"`
+	Params   map[string]any `yaml:",inline"`
+}
+
+// DefaultProfile returns the bundled CA/Browser Forum profile
+func DefaultProfile() *Profile {
+	profile, err := ParseProfile(defaultProfile)
+	if err != nil {
+		panic(fmt.Sprintf("bundled lint profile is invalid: %v", err))
+	}
+	return profile
+}
+
+// LoadProfile reads a YAML lint profile from disk
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("could not read lint profile: %s", path), err)
+	}
+
+	return ParseProfile(data)
+}
+
+// ParseProfile parses a YAML lint profile, unknown rules and severities are rejected
+func ParseProfile(data []byte) (*Profile, error) {
+	profile := &Profile{}
+	if err := yaml.Unmarshal(data, profile); err != nil {
+		return nil, errors.Join(errors.New("could not parse lint profile"), err)
+	}
+
+	for name, config := range profile.Rules {
+		rule, ok := lintRules[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown lint rule %q, known rules: %s", name, strings.Join(LintRuleNames(), ", "))
+		}
+
+		switch config.Severity {
+		case "":
+			config.Severity = rule.severity
+			profile.Rules[name] = config
+		case SeverityError, SeverityWarning, SeverityNotice:
+		default:
+			return nil, fmt.Errorf("invalid severity %q for lint rule %q", config.Severity, name)
+		}
+	}
+
+	return profile, nil
+}
+
+// Lint runs all enabled rules of the profile against a certificate, leaf indicates whether the certificate is the leaf of its chain
+func (p *Profile) Lint(cert *x509.Certificate, leaf bool) []Finding {
+	findings := make([]Finding, 0)
+	for _, name := range LintRuleNames() {
+		config, ok := p.Rules[name]
+		if !ok || config.Disabled {
+			continue
+		}
+
+		rule := lintRules[name]
+		if rule.leafOnly && !leaf {
+			continue
+		}
+
+		if message := rule.check(cert, params(config.Params)); message != "" {
+			findings = append(findings, Finding{Rule: name, Severity: config.Severity, Message: message})
+		}
+	}
+
+	return findings
+}
+
+// LintRuleNames returns the names of all built-in lint rules in a stable order
+func LintRuleNames() []string {
+	names := make([]string, 0, len(lintRules))
+	for name := range lintRules {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// CountSeverity returns the number of findings with the given severity
+func CountSeverity(findings []Finding, severity Severity) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+type params map[string]any
+
+func (p params) int(name string, fallback int) int {
+	if value, ok := p[name].(int); ok {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,96 @@
+package certificate
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type lintRule struct {
+	severity Severity
+	// leafOnly rules are skipped for intermediate and root certificates
+	leafOnly bool
+	// check returns a message describing the problem, or an empty string when the certificate passes
+	check func(cert *x509.Certificate, p params) string
+}
+
+var sha1SignatureAlgorithms = []x509.SignatureAlgorithm{x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1}
+
+var lintRules = map[string]lintRule{
+	"validity-period": {
+		severity: SeverityError,
+		leafOnly: true,
+		check: func(cert *x509.Certificate, p params) string {
+			maxDays := p.int("max_days", 398)
+			// the validity period includes both NotBefore and NotAfter, hence the additional second
+			validity := cert.NotAfter.Sub(cert.NotBefore) + time.Second
+			if validity > time.Duration(maxDays)*24*time.Hour {
+				return fmt.Sprintf("validity period of %d days exceeds the maximum of %d days", int(validity.Hours()/24), maxDays)
+			}
+			return ""
+		},
+	},
+	"missing-san": {
+		severity: SeverityError,
+		leafOnly: true,
+		check: func(cert *x509.Certificate, _ params) string {
+			if len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses)+len(cert.URIs) == 0 {
+				return "certificate does not contain Subject Alternative Names"
+			}
+			return ""
+		},
+	},
+	"sha1-signature": {
+		severity: SeverityError,
+		check: func(cert *x509.Certificate, _ params) string {
+			// the signature on a self-signed root is not used for path validation
+			if isSelfSigned(cert) {
+				return ""
+			}
+			if slices.Contains(sha1SignatureAlgorithms, cert.SignatureAlgorithm) {
+				return fmt.Sprintf("certificate is signed with %s", cert.SignatureAlgorithm)
+			}
+			return ""
+		},
+	},
+	"rsa-key-size": {
+		severity: SeverityError,
+		check: func(cert *x509.Certificate, p params) string {
+			minBits := p.int("min_bits", 2048)
+			if key, ok := cert.PublicKey.(*rsa.PublicKey); ok && key.N.BitLen() < minBits {
+				return fmt.Sprintf("RSA key of %d bit is below the minimum of %d bit", key.N.BitLen(), minBits)
+			}
+			return ""
+		},
+	},
+	"ca-flag-on-leaf": {
+		severity: SeverityError,
+		leafOnly: true,
+		check: func(cert *x509.Certificate, _ params) string {
+			if cert.BasicConstraintsValid && cert.IsCA {
+				return "leaf certificate has the CA flag set"
+			}
+			return ""
+		},
+	},
+	"missing-aki": {
+		severity: SeverityWarning,
+		check: func(cert *x509.Certificate, _ params) string {
+			if len(cert.AuthorityKeyId) == 0 && !isSelfSigned(cert) {
+				return "certificate does not contain an Authority Key Identifier"
+			}
+			return ""
+		},
+	},
+	"expired": {
+		severity: SeverityWarning,
+		check: func(cert *x509.Certificate, _ params) string {
+			if time.Now().After(cert.NotAfter) {
+				return fmt.Sprintf("certificate expired on %s", cert.NotAfter.Format(time.DateOnly))
+			}
+			return ""
+		},
+	},
+}
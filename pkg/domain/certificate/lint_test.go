@@ -0,0 +1,116 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultProfile(t *testing.T) {
+	profile := DefaultProfile()
+
+	assert.Equal(t, "cabf", profile.Name)
+	assert.Len(t, profile.Rules, len(LintRuleNames()))
+}
+
+func TestLintChain(t *testing.T) {
+	certificates := loadCertificates(t, "github.com-chain.pem")
+	profile := DefaultProfile()
+
+	// the leaf expired in 2025, which is the only finding
+	assert.Equal(t, []Finding{{Rule: "expired", Severity: SeverityWarning, Message: "certificate expired on 2025-03-07"}}, profile.Lint(certificates[0], true))
+	assert.Empty(t, profile.Lint(certificates[1], false))
+	assert.Empty(t, profile.Lint(certificates[2], false))
+}
+
+func TestLintViolations(t *testing.T) {
+	cert := newLintCertificate(t)
+
+	findings := DefaultProfile().Lint(cert, true)
+
+	rules := make(map[string]Severity)
+	for _, finding := range findings {
+		rules[finding.Rule] = finding.Severity
+	}
+	assert.Equal(t, map[string]Severity{
+		"validity-period": SeverityError,
+		"missing-san":     SeverityError,
+		"rsa-key-size":    SeverityError,
+		"ca-flag-on-leaf": SeverityError,
+		"missing-aki":     SeverityWarning,
+	}, rules)
+
+	// leaf only rules are skipped for CA certificates
+	findings = DefaultProfile().Lint(cert, false)
+	assert.Len(t, findings, 2)
+}
+
+func TestParseProfile(t *testing.T) {
+	profile, err := ParseProfile([]byte(`
+name: internal
+description: internal PKI
+rules:
+  validity-period:
+    severity: notice
+    max_days: 3650
+  rsa-key-size:
+    min_bits: 1024
+  missing-aki:
+    disabled: true
+`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "internal", profile.Name)
+	assert.Equal(t, SeverityError, profile.Rules["rsa-key-size"].Severity)
+
+	cert := newLintCertificate(t)
+	assert.Empty(t, profile.Lint(cert, true))
+
+	profile.Rules["validity-period"] = RuleConfig{Severity: SeverityNotice, Params: map[string]any{"max_days": 30}}
+	assert.Equal(t, []Finding{{Rule: "validity-period", Severity: SeverityNotice, Message: "validity period of 1000 days exceeds the maximum of 30 days"}}, profile.Lint(cert, true))
+}
+
+func TestParseProfileInvalid(t *testing.T) {
+	_, err := ParseProfile([]byte("rules:\n  unknown-rule:\n    severity: error\n"))
+	assert.ErrorContains(t, err, `unknown lint rule "unknown-rule"`)
+
+	_, err = ParseProfile([]byte("rules:\n  missing-san:\n    severity: fatal\n"))
+	assert.ErrorContains(t, err, `invalid severity "fatal" for lint rule "missing-san"`)
+
+	_, err = ParseProfile([]byte("rules: ["))
+	assert.ErrorContains(t, err, "could not parse lint profile")
+}
+
+// newLintCertificate creates a leaf certificate issued by another key that violates most of the default rules
+func newLintCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	issuerKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	notBefore := time.Now().Add(-time.Hour)
+	issuer := &x509.Certificate{Subject: pkix.Name{CommonName: "Lint CA"}}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "lint.certguard.test"},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(1000*24*time.Hour - time.Second),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return cert
+}
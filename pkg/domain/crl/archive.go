@@ -0,0 +1,239 @@
+package crl
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// ArchiveVersion is the version of the export format, archives of a newer version are rejected
+const ArchiveVersion = 1
+
+const (
+	archiveManifest     = "manifest.json"
+	archiveCertificates = "certificates.jsonl"
+)
+
+// Archive is a portable export of the stored CRLs and the certificate inventory, it can be imported in every storage backend.
+// CRLs are archived in their original DER encoding, their revoked certificates are parsed again when the archive is imported.
+type Archive struct {
+	Created         time.Time
+	RevocationLists []*CertificateRevocationList
+	Certificates    []*StoredCertificate
+}
+
+type archiveManifestFile struct {
+	Version         int                      `json:"version"`
+	Created         time.Time                `json:"created"`
+	RevocationLists []archivedRevocationList `json:"revocation_lists"`
+	Certificates    int                      `json:"certificates"`
+}
+
+type archivedRevocationList struct {
+	File       string    `json:"file"`
+	Name       string    `json:"name"`
+	URL        string    `json:"url,omitempty"`
+	ThisUpdate time.Time `json:"this_update"`
+	NextUpdate time.Time `json:"next_update"`
+}
+
+type archivedCertificate struct {
+	Fingerprint  string    `json:"fingerprint"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	Raw          []byte    `json:"raw"`
+	Source       string    `json:"source"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+}
+
+// Write writes the archive as a gzip compressed tarball with a manifest.json, the DER encoded CRLs in crls/ and the inventory in certificates.jsonl
+func (a *Archive) Write(w io.Writer) error {
+	manifest := archiveManifestFile{
+		Version:         ArchiveVersion,
+		Created:         a.Created.UTC(),
+		RevocationLists: make([]archivedRevocationList, len(a.RevocationLists)),
+		Certificates:    len(a.Certificates),
+	}
+	for i, revocationList := range a.RevocationLists {
+		manifest.RevocationLists[i] = archivedRevocationList{
+			File:       fmt.Sprintf("crls/%04d.crl", i+1),
+			Name:       revocationList.Name,
+			ThisUpdate: revocationList.ThisUpdate.UTC(),
+			NextUpdate: revocationList.NextUpdate.UTC(),
+		}
+		if revocationList.URL != nil {
+			manifest.RevocationLists[i].URL = revocationList.URL.String()
+		}
+	}
+
+	manifestFile, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var certificates bytes.Buffer
+	encoder := json.NewEncoder(&certificates)
+	for _, certificate := range a.Certificates {
+		err := encoder.Encode(archivedCertificate{
+			Fingerprint:  certificate.Fingerprint,
+			Subject:      certificate.Subject,
+			Issuer:       certificate.Issuer,
+			SerialNumber: certificate.SerialNumber,
+			NotBefore:    certificate.NotBefore.UTC(),
+			NotAfter:     certificate.NotAfter.UTC(),
+			Raw:          certificate.Raw,
+			Source:       certificate.Source,
+			FirstSeen:    certificate.FirstSeen.UTC(),
+			LastSeen:     certificate.LastSeen.UTC(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err = writeArchiveFile(tw, archiveManifest, manifestFile, a.Created)
+	if err != nil {
+		return err
+	}
+
+	for i, revocationList := range a.RevocationLists {
+		err = writeArchiveFile(tw, manifest.RevocationLists[i].File, revocationList.Raw, a.Created)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = writeArchiveFile(tw, archiveCertificates, certificates.Bytes(), a.Created)
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeArchiveFile(tw *tar.Writer, name string, content []byte, modified time.Time) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: modified,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(content)
+	return err
+}
+
+// ReadArchive reads an archive written by Archive.Write, the CRLs only contain the metadata of the manifest and their DER encoding
+func ReadArchive(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Join(errors.New("archive is not gzip compressed"), err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(errors.New("could not read archive"), err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("could not read %s from archive", header.Name), err)
+		}
+		files[header.Name] = content
+	}
+
+	manifestFile, ok := files[archiveManifest]
+	if !ok {
+		return nil, fmt.Errorf("archive does not contain a %s", archiveManifest)
+	}
+
+	var manifest archiveManifestFile
+	if err := json.Unmarshal(manifestFile, &manifest); err != nil {
+		return nil, errors.Join(fmt.Errorf("invalid %s", archiveManifest), err)
+	}
+	if manifest.Version < 1 || manifest.Version > ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d, this version of certguard reads archives up to version %d", manifest.Version, ArchiveVersion)
+	}
+
+	archive := &Archive{
+		Created:         manifest.Created,
+		RevocationLists: make([]*CertificateRevocationList, len(manifest.RevocationLists)),
+	}
+	for i, archived := range manifest.RevocationLists {
+		raw, ok := files[archived.File]
+		if !ok {
+			return nil, fmt.Errorf("archive does not contain %s of CRL %s", archived.File, archived.Name)
+		}
+
+		revocationList := &CertificateRevocationList{
+			Name:       archived.Name,
+			ThisUpdate: archived.ThisUpdate,
+			NextUpdate: archived.NextUpdate,
+			Raw:        raw,
+		}
+		if archived.URL != "" {
+			revocationList.URL, err = url.Parse(archived.URL)
+			if err != nil {
+				return nil, errors.Join(fmt.Errorf("invalid URL of CRL %s", archived.Name), err)
+			}
+		}
+		archive.RevocationLists[i] = revocationList
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(files[archiveCertificates]))
+	// a line holds a base64 encoded certificate, which can be larger than the default buffer
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var archived archivedCertificate
+		if err := json.Unmarshal(scanner.Bytes(), &archived); err != nil {
+			return nil, errors.Join(fmt.Errorf("invalid certificate in %s", archiveCertificates), err)
+		}
+
+		archive.Certificates = append(archive.Certificates, &StoredCertificate{
+			Fingerprint:  archived.Fingerprint,
+			Subject:      archived.Subject,
+			Issuer:       archived.Issuer,
+			SerialNumber: archived.SerialNumber,
+			NotBefore:    archived.NotBefore,
+			NotAfter:     archived.NotAfter,
+			Raw:          archived.Raw,
+			Source:       archived.Source,
+			FirstSeen:    archived.FirstSeen,
+			LastSeen:     archived.LastSeen,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Join(fmt.Errorf("could not read %s", archiveCertificates), err)
+	}
+
+	return archive, nil
+}
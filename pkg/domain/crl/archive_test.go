@@ -0,0 +1,78 @@
+package crl
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	URL, err := url.Parse("http://crl.example.com/ca.crl")
+	assert.NoError(t, err)
+
+	archive := &Archive{
+		Created: now,
+		RevocationLists: []*CertificateRevocationList{
+			{Name: "Issuing CA", ThisUpdate: now.Add(-time.Hour), NextUpdate: now.Add(time.Hour), Raw: []byte("der of issuing ca"), URL: URL},
+			{Name: "Imported CA", ThisUpdate: now.Add(-time.Hour), NextUpdate: now.Add(time.Hour), Raw: []byte("der of imported ca")},
+		},
+		Certificates: []*StoredCertificate{
+			{ID: 7, Fingerprint: "aa", Subject: "CN=leaf", Issuer: "CN=Issuing CA", SerialNumber: "10", NotBefore: now, NotAfter: now.Add(time.Hour), Raw: []byte{1, 2, 3}, Source: "input", FirstSeen: now, LastSeen: now, RevokedBy: "Issuing CA"},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, archive.Write(&buf))
+
+	read, err := ReadArchive(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, now, read.Created)
+	assert.Len(t, read.RevocationLists, 2)
+	assert.Equal(t, "Issuing CA", read.RevocationLists[0].Name)
+	assert.Equal(t, URL.String(), read.RevocationLists[0].URL.String())
+	assert.Equal(t, []byte("der of issuing ca"), read.RevocationLists[0].Raw)
+	assert.Equal(t, now.Add(time.Hour), read.RevocationLists[0].NextUpdate)
+	assert.Nil(t, read.RevocationLists[1].URL)
+
+	// the ID and revocation status are derived by the storage the archive is imported in
+	certificate := *archive.Certificates[0]
+	certificate.ID = 0
+	certificate.RevokedBy = ""
+	assert.Equal(t, []*StoredCertificate{&certificate}, read.Certificates)
+}
+
+func TestReadArchiveInvalid(t *testing.T) {
+	_, err := ReadArchive(bytes.NewReader([]byte("not an archive")))
+	assert.ErrorContains(t, err, "not gzip compressed")
+
+	tests := map[string]struct {
+		files map[string]string
+		err   string
+	}{
+		"no manifest":   {files: map[string]string{"certificates.jsonl": ""}, err: "does not contain a manifest.json"},
+		"newer version": {files: map[string]string{"manifest.json": `{"version": 2}`}, err: "unsupported archive version 2"},
+		"missing CRL":   {files: map[string]string{"manifest.json": `{"version": 1, "revocation_lists": [{"file": "crls/0001.crl", "name": "CA"}]}`}, err: "does not contain crls/0001.crl"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gz)
+			for name, content := range tt.files {
+				assert.NoError(t, writeArchiveFile(tw, name, []byte(content), time.Now()))
+			}
+			assert.NoError(t, tw.Close())
+			assert.NoError(t, gz.Close())
+
+			_, err := ReadArchive(&buf)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
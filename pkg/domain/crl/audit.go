@@ -0,0 +1,204 @@
+package crl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditMethod is the method a certificate is checked for revocation with
+type AuditMethod string
+
+const (
+	AuditMethodCRL  AuditMethod = "CRL"
+	AuditMethodOCSP AuditMethod = "OCSP"
+)
+
+// AuditResult is the outcome of a revocation check
+type AuditResult string
+
+const (
+	AuditResultRevoked    AuditResult = "revoked"
+	AuditResultNotRevoked AuditResult = "not revoked"
+	AuditResultGood       AuditResult = "good"
+	AuditResultUnknown    AuditResult = "unknown"
+	AuditResultError      AuditResult = "error"
+)
+
+// AuditEntry records a revocation check of a certificate, entries are never updated or deleted
+type AuditEntry struct {
+	ID           int64
+	CheckedAt    time.Time
+	Method       AuditMethod
+	SerialNumber string
+	Subject      string
+	Issuer       string
+	// Source is the name of the CRL or the URL of the OCSP responder the certificate is checked against, empty when no CRL of the issuer is stored
+	Source string
+	// SourceThisUpdate is the this update of the CRL or of the OCSP response, it identifies the version of the source
+	SourceThisUpdate time.Time
+	Result           AuditResult
+	// Detail is the revocation reason of a revoked certificate or the error of a failed check
+	Detail string
+	// Origin is the part of certguard that performed the check, e.g. certificate view or scan-dir
+	Origin string
+}
+
+// AuditFilter selects audit entries, zero fields are not filtered on
+type AuditFilter struct {
+	// From selects entries checked at or after this time
+	From time.Time
+	// To selects entries checked before this time
+	To           time.Time
+	Method       AuditMethod
+	SerialNumber string
+	// Limit is the maximum number of entries, the most recent entries are returned first, 0 returns all entries
+	Limit int
+}
+
+// Matches applies the filter, except the limit, to an audit entry, for repositories that cannot filter in a query
+func (f AuditFilter) Matches(e *AuditEntry) bool {
+	if !f.From.IsZero() && e.CheckedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !e.CheckedAt.Before(f.To) {
+		return false
+	}
+	if f.Method != "" && e.Method != f.Method {
+		return false
+	}
+	return f.SerialNumber == "" || e.SerialNumber == f.SerialNumber
+}
+
+// ParseAuditFilter parses a filter query of space separated terms: "method:<crl|ocsp>", "after:<YYYY-MM-DD>", "before:<YYYY-MM-DD>"
+// and a serial number. Serial numbers of only digits are decimal, the audit log stores decimal serial numbers.
+func ParseAuditFilter(query string) (AuditFilter, error) {
+	var filter AuditFilter
+	for _, term := range strings.Fields(query) {
+		name, value, found := strings.Cut(term, ":")
+		switch {
+		case found && name == "method":
+			method, err := ParseAuditMethod(value)
+			if err != nil {
+				return filter, err
+			}
+			filter.Method = method
+		case found && name == "after":
+			after, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid date %s, dates are formatted as YYYY-MM-DD", value)
+			}
+			filter.From = after
+		case found && name == "before":
+			before, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid date %s, dates are formatted as YYYY-MM-DD", value)
+			}
+			filter.To = before
+		default:
+			serialNumbers, err := ParseSerialNumber(term)
+			if err != nil {
+				return filter, err
+			}
+			filter.SerialNumber = serialNumbers[0]
+		}
+	}
+
+	return filter, nil
+}
+
+// ParseAuditMethod parses crl or ocsp, case insensitive
+func ParseAuditMethod(method string) (AuditMethod, error) {
+	switch AuditMethod(strings.ToUpper(method)) {
+	case AuditMethodCRL:
+		return AuditMethodCRL, nil
+	case AuditMethodOCSP:
+		return AuditMethodOCSP, nil
+	default:
+		return "", fmt.Errorf("unknown method: %s, use crl or ocsp", method)
+	}
+}
+
+// auditRecord is the exported form of an audit entry
+type auditRecord struct {
+	ID               int64       `json:"id"`
+	CheckedAt        time.Time   `json:"checked_at"`
+	Method           AuditMethod `json:"method"`
+	SerialNumber     string      `json:"serial_number"`
+	Subject          string      `json:"subject"`
+	Issuer           string      `json:"issuer"`
+	Source           string      `json:"source"`
+	SourceThisUpdate *time.Time  `json:"source_this_update,omitempty"`
+	Result           AuditResult `json:"result"`
+	Detail           string      `json:"detail,omitempty"`
+	Origin           string      `json:"origin"`
+}
+
+func newAuditRecord(e *AuditEntry) auditRecord {
+	record := auditRecord{
+		ID:           e.ID,
+		CheckedAt:    e.CheckedAt,
+		Method:       e.Method,
+		SerialNumber: e.SerialNumber,
+		Subject:      e.Subject,
+		Issuer:       e.Issuer,
+		Source:       e.Source,
+		Result:       e.Result,
+		Detail:       e.Detail,
+		Origin:       e.Origin,
+	}
+	if !e.SourceThisUpdate.IsZero() {
+		record.SourceThisUpdate = &e.SourceThisUpdate
+	}
+	return record
+}
+
+// WriteAuditCSV writes the audit entries as CSV including a header row
+func WriteAuditCSV(w io.Writer, entries []*AuditEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "checked_at", "method", "serial_number", "subject", "issuer", "source", "source_this_update", "result", "detail", "origin"}); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		sourceThisUpdate := ""
+		if !e.SourceThisUpdate.IsZero() {
+			sourceThisUpdate = e.SourceThisUpdate.Format(time.RFC3339)
+		}
+
+		err := writer.Write([]string{strconv.FormatInt(e.ID, 10), e.CheckedAt.Format(time.RFC3339), string(e.Method), e.SerialNumber, e.Subject, e.Issuer, e.Source, sourceThisUpdate, string(e.Result), e.Detail, e.Origin})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteAuditJSON writes the audit entries as an indented JSON array
+func WriteAuditJSON(w io.Writer, entries []*AuditEntry) error {
+	records := make([]auditRecord, len(entries))
+	for i, e := range entries {
+		records[i] = newAuditRecord(e)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// WriteAuditJSONL writes the audit entries as JSON lines, one entry per line
+func WriteAuditJSONL(w io.Writer, entries []*AuditEntry) error {
+	encoder := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := encoder.Encode(newAuditRecord(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package crl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAuditFilter(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  AuditFilter
+	}{
+		"empty":         {query: "", want: AuditFilter{}},
+		"decimal":       {query: "4096", want: AuditFilter{SerialNumber: "4096"}},
+		"hex":           {query: "10:00", want: AuditFilter{SerialNumber: "4096"}},
+		"method":        {query: "method:ocsp", want: AuditFilter{Method: AuditMethodOCSP}},
+		"time range":    {query: "after:2026-01-01 before:2026-02-01", want: AuditFilter{From: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}},
+		"combined term": {query: "0xff method:CRL", want: AuditFilter{SerialNumber: "255", Method: AuditMethodCRL}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter, err := ParseAuditFilter(tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, filter)
+		})
+	}
+
+	for _, query := range []string{"method:ldap", "after:yesterday", "before:2026-13-01", "12g4"} {
+		_, err := ParseAuditFilter(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestAuditFilterMatches(t *testing.T) {
+	entry := &AuditEntry{
+		CheckedAt:    time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC),
+		Method:       AuditMethodCRL,
+		SerialNumber: "4096",
+	}
+
+	assert.True(t, AuditFilter{}.Matches(entry))
+	assert.True(t, AuditFilter{From: entry.CheckedAt}.Matches(entry))
+	assert.False(t, AuditFilter{To: entry.CheckedAt}.Matches(entry))
+	assert.True(t, AuditFilter{Method: AuditMethodCRL, SerialNumber: "4096"}.Matches(entry))
+	assert.False(t, AuditFilter{Method: AuditMethodOCSP}.Matches(entry))
+	assert.False(t, AuditFilter{SerialNumber: "255"}.Matches(entry))
+}
+
+func TestWriteAuditLog(t *testing.T) {
+	entries := []*AuditEntry{
+		{ID: 2, CheckedAt: time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC), Method: AuditMethodOCSP, SerialNumber: "4096", Source: "http://ocsp.example.com", Result: AuditResultGood, Origin: "scan-dir"},
+		{ID: 1, CheckedAt: time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC), Method: AuditMethodCRL, SerialNumber: "255", Source: "Example CA", SourceThisUpdate: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), Result: AuditResultRevoked, Detail: "KeyCompromise", Origin: "certificate view"},
+	}
+
+	var csvOut strings.Builder
+	assert.NoError(t, WriteAuditCSV(&csvOut, entries))
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "1,2026-01-14T12:00:00Z,CRL,255,,,Example CA,2026-01-10T00:00:00Z,revoked,KeyCompromise,certificate view", lines[2])
+
+	var jsonOut strings.Builder
+	assert.NoError(t, WriteAuditJSON(&jsonOut, entries))
+	var records []map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(jsonOut.String()), &records))
+	assert.Len(t, records, 2)
+	assert.NotContains(t, records[0], "source_this_update")
+	assert.Equal(t, "2026-01-10T00:00:00Z", records[1]["source_this_update"])
+
+	var jsonlOut strings.Builder
+	assert.NoError(t, WriteAuditJSONL(&jsonlOut, entries))
+	lines = strings.Split(strings.TrimSpace(jsonlOut.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"method":"OCSP"`)
+}
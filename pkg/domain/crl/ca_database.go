@@ -0,0 +1,275 @@
+package crl
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// CADatabaseFormat is the format of a CA database that revocations are imported from
+type CADatabaseFormat string
+
+const (
+	// CADatabaseOpenSSL is the index.txt database of the OpenSSL ca command
+	CADatabaseOpenSSL CADatabaseFormat = "openssl"
+	// CADatabaseStepCA are the revoked certificate records of step-ca, as JSON array or JSON lines
+	CADatabaseStepCA CADatabaseFormat = "step-ca"
+	// CADatabaseEJBCA are the revocation status responses of the EJBCA REST API, as JSON array or JSON lines
+	CADatabaseEJBCA CADatabaseFormat = "ejbca"
+)
+
+// DefaultCADatabaseValidity is the time an imported CA database is treated as current, a CA database has no next update
+const DefaultCADatabaseValidity = 7 * 24 * time.Hour
+
+// CADatabase holds the revoked certificates of a CA database, it is stored as an unsigned pseudo-CRL
+type CADatabase struct {
+	Format              CADatabaseFormat
+	RevokedCertificates []x509.RevocationListEntry
+	// Skipped is the number of certificates in the CA database that are not revoked
+	Skipped int
+	digest  [sha256.Size]byte
+}
+
+// ReadCADatabase reads an OpenSSL index.txt, or a JSON export of step-ca or EJBCA, the format is detected from the content
+func ReadCADatabase(r io.Reader) (*CADatabase, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	database := &CADatabase{digest: sha256.Sum256(content)}
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		err = database.readJSON(trimmed)
+	} else {
+		database.Format = CADatabaseOpenSSL
+		err = database.readIndex(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return database, nil
+}
+
+// RevocationList is the unsigned pseudo-CRL of the CA database, it is valid from imported until imported plus validity.
+// A pseudo-CRL has no signature, its Signature is a digest of the name, the import time and the CA database so every imported version is unique like the signature of a CRL.
+func (d *CADatabase) RevocationList(name string, imported time.Time, validity time.Duration) *CertificateRevocationList {
+	signature := sha256.New()
+	signature.Write([]byte(name))
+	signature.Write([]byte(imported.UTC().Format(time.RFC3339Nano)))
+	signature.Write(d.digest[:])
+
+	return &CertificateRevocationList{
+		Name:          name,
+		Signature:     signature.Sum(nil),
+		ThisUpdate:    imported,
+		NextUpdate:    imported.Add(validity),
+		LastRefreshed: imported,
+		Unsigned:      true,
+	}
+}
+
+// Entries yields the revoked certificates, to store them with Ingest
+func (d *CADatabase) Entries() iter.Seq2[x509.RevocationListEntry, error] {
+	return func(yield func(x509.RevocationListEntry, error) bool) {
+		for _, entry := range d.RevokedCertificates {
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
+
+// readIndex reads the tab separated lines of an index.txt: status, expiry date, revocation date with an optional reason, hex serial number, file name and subject.
+// Only the revoked certificates, with status R, are read.
+func (d *CADatabase) readIndex(content []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 4 {
+			return fmt.Errorf("line %d of index.txt: expected 6 tab separated fields, got %d", line, len(fields))
+		}
+		if fields[0] != "R" {
+			d.Skipped++
+			continue
+		}
+
+		entry, err := parseIndexEntry(fields[2], fields[3])
+		if err != nil {
+			return fmt.Errorf("line %d of index.txt: %w", line, err)
+		}
+		d.RevokedCertificates = append(d.RevokedCertificates, entry)
+	}
+	return scanner.Err()
+}
+
+// parseIndexEntry parses the revocation field, date[,reason[,hold instruction or compromise time]], and the hex serial number of an index.txt line
+func parseIndexEntry(revocation, serialNumber string) (x509.RevocationListEntry, error) {
+	revocationDate, reason, _ := strings.Cut(revocation, ",")
+	reason, _, _ = strings.Cut(reason, ",")
+
+	revocationTime, err := parseIndexTime(revocationDate)
+	if err != nil {
+		return x509.RevocationListEntry{}, err
+	}
+
+	entry := x509.RevocationListEntry{RevocationTime: revocationTime}
+	entry.SerialNumber, err = parseHexSerialNumber(serialNumber)
+	if err != nil {
+		return x509.RevocationListEntry{}, err
+	}
+	if reason != "" {
+		entry.ReasonCode, err = ParseReasonCode(reason)
+		if err != nil {
+			return x509.RevocationListEntry{}, err
+		}
+	}
+	return entry, nil
+}
+
+// parseIndexTime parses a time of the index.txt, written as UTCTime or as GeneralizedTime
+func parseIndexTime(value string) (time.Time, error) {
+	layout := "060102150405Z"
+	if len(value) == len("20060102150405Z") {
+		layout = "20060102150405Z"
+	}
+	parsed, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid revocation date: %q", value)
+	}
+	return parsed, nil
+}
+
+func parseHexSerialNumber(value string) (*big.Int, error) {
+	serialNumber, ok := new(big.Int).SetString(strings.ReplaceAll(value, ":", ""), 16)
+	if !ok || serialNumber.Sign() < 0 {
+		return nil, fmt.Errorf("invalid serial number: %q", value)
+	}
+	return serialNumber, nil
+}
+
+// reasonNameAliases are the revocation reasons that OpenSSL and EJBCA name differently, keyTime and CAkeyTime are OpenSSL reasons with a compromise time
+var reasonNameAliases = map[string]int{
+	"cakeytime":           2,
+	"keytime":             1,
+	"holdinstruction":     6,
+	"privilegeswithdrawn": 9,
+}
+
+// ParseReasonCode maps a revocation reason of OpenSSL (keyCompromise), EJBCA (KEY_COMPROMISE) or RevocationReasons to its reason code, ignoring case and underscores
+func ParseReasonCode(name string) (int, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	if code, ok := reasonNameAliases[normalized]; ok {
+		return code, nil
+	}
+	for code, reason := range RevocationReasons {
+		if strings.ToLower(reason.String()) == normalized {
+			return code, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown revocation reason: %q", name)
+}
+
+// caDatabaseRecord is a revoked certificate record of step-ca or a revocation status response of EJBCA
+type caDatabaseRecord struct {
+	// step-ca stores the decimal serial number, the reason code and the revocation time
+	Serial     string    `json:"Serial"`
+	ReasonCode int       `json:"ReasonCode"`
+	RevokedAt  time.Time `json:"RevokedAt"`
+
+	// EJBCA responds with the hex serial number, the reason name and the revocation date
+	SerialNumber     string    `json:"serial_number"`
+	RevocationReason string    `json:"revocation_reason"`
+	RevocationDate   time.Time `json:"revocation_date"`
+	Revoked          *bool     `json:"revoked"`
+}
+
+// readJSON reads the records of a JSON array or of JSON lines, all records are of step-ca or all records are of EJBCA
+func (d *CADatabase) readJSON(content []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	array := content[0] == '['
+	if array {
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	}
+
+	for record := 1; decoder.More(); record++ {
+		var decoded caDatabaseRecord
+		if err := decoder.Decode(&decoded); err != nil {
+			return errors.Join(fmt.Errorf("invalid record %d", record), err)
+		}
+
+		format, entry, revoked, err := decoded.entry()
+		if err != nil {
+			return fmt.Errorf("record %d: %w", record, err)
+		}
+		if d.Format != "" && d.Format != format {
+			return fmt.Errorf("record %d: %s record in a %s export", record, format, d.Format)
+		}
+		d.Format = format
+
+		if !revoked {
+			d.Skipped++
+			continue
+		}
+		d.RevokedCertificates = append(d.RevokedCertificates, entry)
+	}
+
+	if array {
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	}
+	if d.Format == "" {
+		return errors.New("the JSON export does not contain any records")
+	}
+	return nil
+}
+
+func (r *caDatabaseRecord) entry() (CADatabaseFormat, x509.RevocationListEntry, bool, error) {
+	switch {
+	case r.Serial != "":
+		serialNumber, ok := new(big.Int).SetString(r.Serial, 10)
+		if !ok {
+			return CADatabaseStepCA, x509.RevocationListEntry{}, false, fmt.Errorf("invalid serial number: %q", r.Serial)
+		}
+		if _, ok := RevocationReasons[r.ReasonCode]; !ok {
+			return CADatabaseStepCA, x509.RevocationListEntry{}, false, fmt.Errorf("invalid reason code: %d", r.ReasonCode)
+		}
+		return CADatabaseStepCA, x509.RevocationListEntry{SerialNumber: serialNumber, RevocationTime: r.RevokedAt, ReasonCode: r.ReasonCode}, true, nil
+	case r.SerialNumber != "":
+		serialNumber, err := parseHexSerialNumber(r.SerialNumber)
+		if err != nil {
+			return CADatabaseEJBCA, x509.RevocationListEntry{}, false, err
+		}
+		if (r.Revoked != nil && !*r.Revoked) || strings.EqualFold(r.RevocationReason, "NOT_REVOKED") {
+			return CADatabaseEJBCA, x509.RevocationListEntry{}, false, nil
+		}
+		entry := x509.RevocationListEntry{SerialNumber: serialNumber, RevocationTime: r.RevocationDate}
+		if r.RevocationReason != "" {
+			entry.ReasonCode, err = ParseReasonCode(r.RevocationReason)
+			if err != nil {
+				return CADatabaseEJBCA, x509.RevocationListEntry{}, false, err
+			}
+		}
+		return CADatabaseEJBCA, entry, true, nil
+	default:
+		return "", x509.RevocationListEntry{}, false, errors.New("the record has no Serial of step-ca or serial_number of EJBCA")
+	}
+}
@@ -0,0 +1,107 @@
+package crl
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const opensslIndex = "V\t270101000000Z\t\t1000\tunknown\t/CN=valid.example.com\n" +
+	"R\t270101000000Z\t260110120000Z,keyCompromise\t1001\tunknown\t/CN=compromised.example.com\n" +
+	"R\t270101000000Z\t260111120000Z\t0A\tunknown\t/CN=unspecified.example.com\n" +
+	"R\t20510101000000Z\t20500101000000Z,CAkeyTime,20491231000000Z\tFF\tunknown\t/CN=ca-compromised.example.com\n" +
+	"E\t250101000000Z\t\t1002\tunknown\t/CN=expired.example.com\n"
+
+func TestReadCADatabaseOpenSSL(t *testing.T) {
+	database, err := ReadCADatabase(strings.NewReader(opensslIndex))
+	assert.NoError(t, err)
+	assert.Equal(t, CADatabaseOpenSSL, database.Format)
+	assert.Equal(t, 2, database.Skipped)
+	assert.Len(t, database.RevokedCertificates, 3)
+
+	assert.Equal(t, big.NewInt(0x1001), database.RevokedCertificates[0].SerialNumber)
+	assert.Equal(t, time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC), database.RevokedCertificates[0].RevocationTime)
+	assert.Equal(t, 1, database.RevokedCertificates[0].ReasonCode)
+	assert.Equal(t, 0, database.RevokedCertificates[1].ReasonCode)
+	assert.Equal(t, time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC), database.RevokedCertificates[2].RevocationTime)
+	assert.Equal(t, 2, database.RevokedCertificates[2].ReasonCode)
+
+	_, err = ReadCADatabase(strings.NewReader("R\t270101000000Z\t260110120000Z,bogus\t1001\tunknown\t/CN=x\n"))
+	assert.ErrorContains(t, err, "line 1 of index.txt: unknown revocation reason")
+
+	_, err = ReadCADatabase(strings.NewReader("V\t270101000000Z\n"))
+	assert.ErrorContains(t, err, "expected 6 tab separated fields")
+}
+
+func TestReadCADatabaseIndexRoundTrip(t *testing.T) {
+	var exported strings.Builder
+	_, err := ExportRevocationList(&exported, exportRevocationList(), entrySeq(exportEntries()), ExportIndex)
+	assert.NoError(t, err)
+
+	database, err := ReadCADatabase(strings.NewReader(exported.String()))
+	assert.NoError(t, err)
+	for i, entry := range exportEntries() {
+		assert.Equal(t, entry.SerialNumber, database.RevokedCertificates[i].SerialNumber)
+		assert.Equal(t, entry.RevocationTime, database.RevokedCertificates[i].RevocationTime)
+		assert.Equal(t, entry.ReasonCode, database.RevokedCertificates[i].ReasonCode)
+	}
+}
+
+func TestReadCADatabaseStepCA(t *testing.T) {
+	records := `{"Serial":"4096","ProvisionerID":"admin","ReasonCode":4,"Reason":"renewed","RevokedAt":"2026-01-10T12:00:00Z","MTLS":false}
+{"Serial":"4097","ProvisionerID":"admin","ReasonCode":0,"RevokedAt":"2026-01-11T12:00:00Z","MTLS":false}
+`
+	database, err := ReadCADatabase(strings.NewReader(records))
+	assert.NoError(t, err)
+	assert.Equal(t, CADatabaseStepCA, database.Format)
+	assert.Len(t, database.RevokedCertificates, 2)
+	assert.Equal(t, big.NewInt(4096), database.RevokedCertificates[0].SerialNumber)
+	assert.Equal(t, 4, database.RevokedCertificates[0].ReasonCode)
+	assert.Equal(t, time.Date(2026, 1, 11, 12, 0, 0, 0, time.UTC), database.RevokedCertificates[1].RevocationTime)
+}
+
+func TestReadCADatabaseEJBCA(t *testing.T) {
+	records := `[
+  {"issuer_dn":"CN=Internal CA","serial_number":"1A2B","revocation_reason":"KEY_COMPROMISE","revocation_date":"2026-01-10T12:00:00Z","revoked":true},
+  {"issuer_dn":"CN=Internal CA","serial_number":"1A2C","revocation_reason":"PRIVILEGES_WITHDRAWN","revocation_date":"2026-01-11T12:00:00Z","revoked":true},
+  {"issuer_dn":"CN=Internal CA","serial_number":"1A2D","revocation_reason":"NOT_REVOKED","revoked":false}
+]`
+	database, err := ReadCADatabase(strings.NewReader(records))
+	assert.NoError(t, err)
+	assert.Equal(t, CADatabaseEJBCA, database.Format)
+	assert.Equal(t, 1, database.Skipped)
+	assert.Len(t, database.RevokedCertificates, 2)
+	assert.Equal(t, big.NewInt(0x1a2b), database.RevokedCertificates[0].SerialNumber)
+	assert.Equal(t, 1, database.RevokedCertificates[0].ReasonCode)
+	assert.Equal(t, 9, database.RevokedCertificates[1].ReasonCode)
+
+	_, err = ReadCADatabase(strings.NewReader(`[{"Serial":"1","RevokedAt":"2026-01-10T12:00:00Z"},{"serial_number":"02","revoked":true}]`))
+	assert.ErrorContains(t, err, "record 2: ejbca record in a step-ca export")
+
+	_, err = ReadCADatabase(strings.NewReader(`[{"subject":"CN=x"}]`))
+	assert.ErrorContains(t, err, "record 1")
+}
+
+func TestCADatabaseRevocationList(t *testing.T) {
+	database, err := ReadCADatabase(strings.NewReader(opensslIndex))
+	assert.NoError(t, err)
+
+	imported := time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)
+	revocationList := database.RevocationList("Internal CA", imported, DefaultCADatabaseValidity)
+	assert.True(t, revocationList.Unsigned)
+	assert.Empty(t, revocationList.Raw)
+	assert.Equal(t, imported, revocationList.ThisUpdate)
+	assert.Equal(t, imported.Add(7*24*time.Hour), revocationList.NextUpdate)
+	assert.NotEqual(t, revocationList.Signature, database.RevocationList("Other CA", imported, DefaultCADatabaseValidity).Signature)
+	assert.NotEqual(t, revocationList.Signature, database.RevocationList("Internal CA", imported.Add(time.Hour), DefaultCADatabaseValidity).Signature, "every import is a version of the pseudo-CRL")
+
+	count := 0
+	for _, err := range database.Entries() {
+		assert.NoError(t, err)
+		count++
+	}
+	assert.Equal(t, 3, count)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/x509"
 	"errors"
+	"iter"
 	"net/url"
 	"time"
 )
@@ -16,6 +17,10 @@ type CertificateRevocationList struct {
 	NextUpdate time.Time
 	Raw        []byte
 	URL        *url.URL
+	// LastRefreshed is the last time the CRL was downloaded or imported
+	LastRefreshed time.Time
+	// Unsigned marks a pseudo-CRL imported from a CA database, its revoked certificates are not signed by the CA
+	Unsigned bool
 }
 
 var RevocationReasons = map[int]RevocationReason{
@@ -42,25 +47,104 @@ func FromCRL(crl *x509.RevocationList, URL *url.URL) (*CertificateRevocationList
 	}, nil
 }
 
+// IngestBatchSize is the number of revoked certificates that is passed to the repository at once when a CRL is ingested
+const IngestBatchSize = 10_000
+
 func Process(ctx context.Context, URL *url.URL, crl *x509.RevocationList, store *Storage) error {
 	parsed, err := FromCRL(crl, URL)
 	if err != nil {
 		return err
 	}
 
-	id, err := store.Repository.Save(ctx, parsed)
-	if err != nil {
-		return err
+	_, err = Ingest(ctx, parsed, func(yield func(x509.RevocationListEntry, error) bool) {
+		for _, entry := range crl.RevokedCertificateEntries {
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}, store, nil)
+	return err
+}
+
+// Ingest stores a CRL and reads its revoked certificates in batches of IngestBatchSize, so only one batch is held in memory.
+// Progress is called after every stored batch with the total number of stored revoked certificates, it may be nil.
+// A failed batch does not roll back the batches stored before it, ingesting the CRL again replaces the revoked certificates that are stored.
+func Ingest(ctx context.Context, revocationList *CertificateRevocationList, entries iter.Seq2[x509.RevocationListEntry, error], store *Storage, progress func(stored int)) (int64, error) {
+	if revocationList.LastRefreshed.IsZero() {
+		revocationList.LastRefreshed = time.Now()
 	}
 
-	storedRevokedCertificates, err := store.Repository.SaveRevokedCertificates(ctx, id, crl.RevokedCertificateEntries)
+	id, err := store.Repository.Save(ctx, revocationList)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	stored := 0
+	batch := make([]x509.RevocationListEntry, 0, IngestBatchSize)
+	saveBatch := func() error {
+		saved, err := store.Repository.SaveRevokedCertificates(ctx, id, batch)
+		if err != nil {
+			return err
+		}
+		if saved < len(batch) {
+			return errors.New("not all revoked certificates saved")
+		}
+
+		stored += saved
+		batch = batch[:0]
+		if progress != nil {
+			progress(stored)
+		}
+		return nil
+	}
+
+	for entry, err := range entries {
+		if err != nil {
+			return 0, err
+		}
+
+		batch = append(batch, entry)
+		if len(batch) == IngestBatchSize {
+			if err := saveBatch(); err != nil {
+				return 0, err
+			}
+		}
 	}
 
-	if storedRevokedCertificates < len(crl.RevokedCertificateEntries) {
-		return errors.New("no revoked certificates saved")
+	if len(batch) > 0 {
+		if err := saveBatch(); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, nil
+}
+
+// LatestVersions returns the latest version of every stored CRL by name, the version Find returns.
+// A CRL is stored once per this update, the older versions are kept until the retention policy prunes them.
+func LatestVersions(revocationLists []*CertificateRevocationList) map[string]*CertificateRevocationList {
+	latest := make(map[string]*CertificateRevocationList, len(revocationLists))
+	for _, revocationList := range revocationLists {
+		current, ok := latest[revocationList.Name]
+		if !ok || revocationList.ThisUpdate.After(current.ThisUpdate) || (revocationList.ThisUpdate.Equal(current.ThisUpdate) && revocationList.ID > current.ID) {
+			latest[revocationList.Name] = revocationList
+		}
 	}
+	return latest
+}
 
-	return nil
+// FindRevocation returns the revocation of the serial number on the CRL with the ID, nil when the CRL does not revoke it.
+// A serial number is only unique per CA, revocations of the serial number on the CRLs of other CAs are ignored.
+// An entry with reason removeFromCRL releases a certificate on hold, it is not a revocation.
+func FindRevocation(ctx context.Context, repository Repository, revocationListID int64, serialNumber string) (*RevokedCertificate, error) {
+	revokedCertificates, err := repository.FindRevokedCertificatesBySerialNumber(ctx, serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, revoked := range revokedCertificates {
+		if revoked.RevocationListID == revocationListID && revoked.RevocationReason != RevocationReasonRemoveFromCRL {
+			return revoked, nil
+		}
+	}
+	return nil, nil
 }
@@ -0,0 +1,140 @@
+package crl
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ExpiryBucket int
+
+const (
+	Expired ExpiryBucket = iota
+	ExpiresWithin7Days
+	ExpiresWithin30Days
+	ExpiresWithin90Days
+	// ExpiresLater is not shown on the dashboard
+	ExpiresLater
+)
+
+// ExpiryBuckets are the buckets shown on the expiry dashboard
+var ExpiryBuckets = []ExpiryBucket{Expired, ExpiresWithin7Days, ExpiresWithin30Days, ExpiresWithin90Days}
+
+func (b ExpiryBucket) String() string {
+	switch b {
+	case Expired:
+		return "expired"
+	case ExpiresWithin7Days:
+		return "< 7 days"
+	case ExpiresWithin30Days:
+		return "< 30 days"
+	case ExpiresWithin90Days:
+		return "< 90 days"
+	default:
+		return "later"
+	}
+}
+
+// BucketFor returns the bucket of a certificate that expires at notAfter
+func BucketFor(notAfter, now time.Time) ExpiryBucket {
+	left := notAfter.Sub(now)
+	switch {
+	case left <= 0:
+		return Expired
+	case left < 7*24*time.Hour:
+		return ExpiresWithin7Days
+	case left < 30*24*time.Hour:
+		return ExpiresWithin30Days
+	case left < 90*24*time.Hour:
+		return ExpiresWithin90Days
+	default:
+		return ExpiresLater
+	}
+}
+
+// IssuerExpiry counts the certificates of an issuer per bucket
+type IssuerExpiry struct {
+	Issuer string
+	Counts [ExpiresLater]int
+}
+
+func (i IssuerExpiry) Total() int {
+	total := 0
+	for _, count := range i.Counts {
+		total += count
+	}
+	return total
+}
+
+// ExpiryDashboard groups certificates on the time left until they expire, certificates that expire after 90 days are left out
+type ExpiryDashboard struct {
+	Created      time.Time
+	Certificates [ExpiresLater][]*StoredCertificate
+	Issuers      []IssuerExpiry
+}
+
+func NewExpiryDashboard(certificates []*StoredCertificate, now time.Time) *ExpiryDashboard {
+	dashboard := &ExpiryDashboard{Created: now}
+	issuers := make(map[string]*IssuerExpiry)
+
+	for _, certificate := range certificates {
+		bucket := BucketFor(certificate.NotAfter, now)
+		if bucket == ExpiresLater {
+			continue
+		}
+
+		dashboard.Certificates[bucket] = append(dashboard.Certificates[bucket], certificate)
+		issuer, ok := issuers[certificate.Issuer]
+		if !ok {
+			issuer = &IssuerExpiry{Issuer: certificate.Issuer}
+			issuers[certificate.Issuer] = issuer
+		}
+		issuer.Counts[bucket]++
+	}
+
+	for _, bucket := range dashboard.Certificates {
+		slices.SortStableFunc(bucket, func(a, b *StoredCertificate) int {
+			return a.NotAfter.Compare(b.NotAfter)
+		})
+	}
+
+	for _, issuer := range issuers {
+		dashboard.Issuers = append(dashboard.Issuers, *issuer)
+	}
+	slices.SortFunc(dashboard.Issuers, func(a, b IssuerExpiry) int {
+		return cmp.Or(cmp.Compare(b.Total(), a.Total()), cmp.Compare(a.Issuer, b.Issuer))
+	})
+
+	return dashboard
+}
+
+// Count returns the number of certificates in a bucket
+func (d *ExpiryDashboard) Count(bucket ExpiryBucket) int {
+	return len(d.Certificates[bucket])
+}
+
+// ParseDuration parses a duration in days (30d) or weeks (2w), all Go durations like 12h are accepted as well
+func ParseDuration(duration string) (time.Duration, error) {
+	duration = strings.TrimSpace(duration)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		value, found := strings.CutSuffix(duration, suffix)
+		if !found {
+			continue
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", duration)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	parsed, err := time.ParseDuration(duration)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid duration: %s, use e.g. 30d, 2w or 12h", duration)
+	}
+	return parsed, nil
+}
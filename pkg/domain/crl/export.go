@@ -0,0 +1,320 @@
+package crl
+
+import (
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ExportFormat is a file format a stored CRL is exported to
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportJSON  ExportFormat = "json"
+	ExportJSONL ExportFormat = "jsonl"
+	// ExportIndex is the index.txt database of the OpenSSL ca command
+	ExportIndex ExportFormat = "index"
+	// ExportDER and ExportPEM re-export the original CRL as it was downloaded or imported
+	ExportDER ExportFormat = "der"
+	ExportPEM ExportFormat = "pem"
+)
+
+// ExportFormats lists the export formats, in the order they are cycled through in the TUI
+var ExportFormats = []ExportFormat{ExportCSV, ExportJSON, ExportJSONL, ExportIndex, ExportDER, ExportPEM}
+
+// ParseExportFormat validates the name of an export format
+func ParseExportFormat(format string) (ExportFormat, error) {
+	for _, exportFormat := range ExportFormats {
+		if strings.EqualFold(format, string(exportFormat)) {
+			return exportFormat, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported format: %s, allowed values: csv, json, jsonl, index, der, pem", format)
+}
+
+// FileExtension is the extension of an exported file, without the dot
+func (f ExportFormat) FileExtension() string {
+	switch f {
+	case ExportIndex:
+		return "txt"
+	case ExportDER:
+		return "crl"
+	default:
+		return string(f)
+	}
+}
+
+// Raw is true for the formats that re-export the original CRL instead of its revoked certificates
+func (f ExportFormat) Raw() bool {
+	return f == ExportDER || f == ExportPEM
+}
+
+type exportedRevocationList struct {
+	Name       string    `json:"name"`
+	URL        string    `json:"url,omitempty"`
+	ThisUpdate time.Time `json:"this_update"`
+	NextUpdate time.Time `json:"next_update"`
+	Signature  string    `json:"signature"`
+	// Unsigned marks a pseudo-CRL imported from a CA database, its signature is a digest and not a signature of the CA
+	Unsigned bool `json:"unsigned,omitempty"`
+}
+
+type exportedRevokedCertificate struct {
+	CRL             string              `json:"crl,omitempty"`
+	SerialNumber    string              `json:"serial_number"`
+	SerialNumberHex string              `json:"serial_number_hex"`
+	RevocationDate  time.Time           `json:"revocation_date"`
+	Reason          RevocationReason    `json:"reason"`
+	Extensions      []exportedExtension `json:"extensions,omitempty"`
+}
+
+type exportedExtension struct {
+	OID      string `json:"oid"`
+	Critical bool   `json:"critical"`
+	// Value is the hex encoded DER value of the extension
+	Value string `json:"value"`
+}
+
+func newExportedRevokedCertificate(entry x509.RevocationListEntry) exportedRevokedCertificate {
+	serialNumber := entry.SerialNumber.String()
+	exported := exportedRevokedCertificate{
+		SerialNumber:    serialNumber,
+		SerialNumberHex: FormatSerialNumberHex(serialNumber),
+		RevocationDate:  entry.RevocationTime,
+		Reason:          RevocationReasons[entry.ReasonCode],
+	}
+	for _, extension := range entry.Extensions {
+		exported.Extensions = append(exported.Extensions, exportedExtension{
+			OID:      extension.Id.String(),
+			Critical: extension.Critical,
+			Value:    hex.EncodeToString(extension.Value),
+		})
+	}
+	return exported
+}
+
+// ExportRevocationList writes a CRL in the format, the revoked certificates are written while they are read from entries.
+// The DER and PEM formats write the stored raw CRL and do not read entries.
+func ExportRevocationList(w io.Writer, revocationList *CertificateRevocationList, entries iter.Seq2[x509.RevocationListEntry, error], format ExportFormat) (int, error) {
+	switch format {
+	case ExportDER, ExportPEM:
+		return 0, writeRawRevocationList(w, revocationList, format)
+	case ExportCSV:
+		return writeRevokedCertificatesCSV(w, revocationList, entries)
+	case ExportJSON:
+		return writeRevokedCertificatesJSON(w, revocationList, entries)
+	case ExportJSONL:
+		return writeRevokedCertificatesJSONL(w, revocationList, entries)
+	case ExportIndex:
+		return writeRevokedCertificatesIndex(w, revocationList, entries)
+	default:
+		return 0, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func writeRawRevocationList(w io.Writer, revocationList *CertificateRevocationList, format ExportFormat) error {
+	if revocationList.Unsigned {
+		return fmt.Errorf("%s is an unsigned CRL imported from a CA database, there is no original CRL to export", revocationList.Name)
+	}
+	if len(revocationList.Raw) == 0 {
+		return fmt.Errorf("the original CRL of %s is not stored, download or import it again", revocationList.Name)
+	}
+
+	if format == ExportPEM {
+		return pem.Encode(w, &pem.Block{Type: "X509 CRL", Bytes: revocationList.Raw})
+	}
+	_, err := w.Write(revocationList.Raw)
+	return err
+}
+
+func writeRevokedCertificatesCSV(w io.Writer, revocationList *CertificateRevocationList, entries iter.Seq2[x509.RevocationListEntry, error]) (int, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"crl", "serial_number", "serial_number_hex", "revocation_date", "reason", "extensions"}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for entry, err := range entries {
+		if err != nil {
+			return count, err
+		}
+
+		exported := newExportedRevokedCertificate(entry)
+		extensions := make([]string, len(exported.Extensions))
+		for i, extension := range exported.Extensions {
+			extensions[i] = extension.OID + "=" + extension.Value
+			if extension.Critical {
+				extensions[i] = extension.OID + "(critical)=" + extension.Value
+			}
+		}
+
+		err := writer.Write([]string{revocationList.Name, exported.SerialNumber, exported.SerialNumberHex, exported.RevocationDate.Format(time.RFC3339), exported.Reason.String(), strings.Join(extensions, ";")})
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	writer.Flush()
+	return count, writer.Error()
+}
+
+// writeRevokedCertificatesJSON writes an indented JSON object with the metadata of the CRL and its revoked certificates,
+// the revoked certificates are encoded one at a time so the CRL is never held in memory
+func writeRevokedCertificatesJSON(w io.Writer, revocationList *CertificateRevocationList, entries iter.Seq2[x509.RevocationListEntry, error]) (int, error) {
+	exported := exportedRevocationList{
+		Name:       revocationList.Name,
+		ThisUpdate: revocationList.ThisUpdate,
+		NextUpdate: revocationList.NextUpdate,
+		Signature:  hex.EncodeToString(revocationList.Signature),
+		Unsigned:   revocationList.Unsigned,
+	}
+	if revocationList.URL != nil {
+		exported.URL = revocationList.URL.String()
+	}
+
+	metadata, err := json.MarshalIndent(exported, "  ", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if _, err := fmt.Fprintf(w, "{\n  \"crl\": %s,\n  \"revoked_certificates\": [", metadata); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for entry, err := range entries {
+		if err != nil {
+			return count, err
+		}
+
+		revokedCertificate, err := json.MarshalIndent(newExportedRevokedCertificate(entry), "    ", "  ")
+		if err != nil {
+			return count, err
+		}
+
+		separator := ","
+		if count == 0 {
+			separator = ""
+		}
+		if _, err := fmt.Fprintf(w, "%s\n    %s", separator, revokedCertificate); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	closing := "\n  ]\n}\n"
+	if count == 0 {
+		closing = "]\n}\n"
+	}
+	_, err = io.WriteString(w, closing)
+	return count, err
+}
+
+// writeRevokedCertificatesJSONL writes a JSON line per revoked certificate, every line contains the name of the CRL
+func writeRevokedCertificatesJSONL(w io.Writer, revocationList *CertificateRevocationList, entries iter.Seq2[x509.RevocationListEntry, error]) (int, error) {
+	encoder := json.NewEncoder(w)
+	count := 0
+	for entry, err := range entries {
+		if err != nil {
+			return count, err
+		}
+
+		exported := newExportedRevokedCertificate(entry)
+		exported.CRL = revocationList.Name
+		if err := encoder.Encode(exported); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// indexReasons are the revocation reasons of the OpenSSL index.txt, reasons that OpenSSL does not know are written without reason
+var indexReasons = map[RevocationReason]string{
+	RevocationReasonKeyCompromise:        "keyCompromise",
+	RevocationReasonCACompromise:         "CACompromise",
+	RevocationReasonAffiliationChanged:   "affiliationChanged",
+	RevocationReasonSuperseded:           "superseded",
+	RevocationReasonCessationOfOperation: "cessationOfOperation",
+	RevocationReasonCertificateHold:      "certificateHold",
+	RevocationReasonRemoveFromCRL:        "removeFromCRL",
+}
+
+// writeRevokedCertificatesIndex writes the revoked certificates as the index.txt database of the OpenSSL ca command.
+// A CRL does not contain the expiry date and subject of the revoked certificates, the next update of the CRL is written as expiry date and the subject is unknown.
+func writeRevokedCertificatesIndex(w io.Writer, revocationList *CertificateRevocationList, entries iter.Seq2[x509.RevocationListEntry, error]) (int, error) {
+	expiry := revocationList.NextUpdate
+	if expiry.IsZero() {
+		expiry = revocationList.ThisUpdate
+	}
+
+	count := 0
+	for entry, err := range entries {
+		if err != nil {
+			return count, err
+		}
+
+		revocation := formatIndexTime(entry.RevocationTime)
+		if reason, ok := indexReasons[RevocationReasons[entry.ReasonCode]]; ok {
+			revocation += "," + reason
+		}
+
+		serialNumber := strings.ToUpper(entry.SerialNumber.Text(16))
+		if len(serialNumber)%2 == 1 {
+			serialNumber = "0" + serialNumber
+		}
+
+		if _, err := fmt.Fprintf(w, "R\t%s\t%s\t%s\tunknown\tunknown\n", formatIndexTime(expiry), revocation, serialNumber); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// formatIndexTime formats a time like OpenSSL does in the index.txt, as UTCTime until 2050 and as GeneralizedTime after
+func formatIndexTime(t time.Time) string {
+	t = t.UTC()
+	if t.Year() >= 2050 {
+		return t.Format("20060102150405Z")
+	}
+	return t.Format("060102150405Z")
+}
+
+// RevokedCertificateEntries converts stored revoked certificates to CRL entries, for CRLs of which the original CRL is not stored.
+// The entry extensions other than the reason code are only available in the original CRL.
+func RevokedCertificateEntries(revokedCertificates []*RevokedCertificate) iter.Seq2[x509.RevocationListEntry, error] {
+	reasonCodes := make(map[RevocationReason]int, len(RevocationReasons))
+	for code, reason := range RevocationReasons {
+		reasonCodes[reason] = code
+	}
+
+	return func(yield func(x509.RevocationListEntry, error) bool) {
+		for _, revokedCertificate := range revokedCertificates {
+			serialNumber, ok := new(big.Int).SetString(revokedCertificate.SerialNumber, 10)
+			if !ok {
+				yield(x509.RevocationListEntry{}, errors.New("invalid serial number: "+revokedCertificate.SerialNumber))
+				return
+			}
+
+			entry := x509.RevocationListEntry{
+				SerialNumber:   serialNumber,
+				RevocationTime: revokedCertificate.RevocationDate,
+				ReasonCode:     reasonCodes[revokedCertificate.RevocationReason],
+			}
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package crl
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"iter"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func entrySeq(entries []x509.RevocationListEntry) iter.Seq2[x509.RevocationListEntry, error] {
+	return func(yield func(x509.RevocationListEntry, error) bool) {
+		for _, entry := range entries {
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
+
+func exportEntries() []x509.RevocationListEntry {
+	revocationTime := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	return []x509.RevocationListEntry{
+		{SerialNumber: big.NewInt(4096), RevocationTime: revocationTime},
+		{
+			SerialNumber:   big.NewInt(255),
+			RevocationTime: revocationTime,
+			ReasonCode:     1,
+			Extensions: []pkix.Extension{
+				{Id: asn1.ObjectIdentifier{2, 5, 29, 21}, Value: []byte{0x0a, 0x01, 0x01}},
+				{Id: asn1.ObjectIdentifier{2, 5, 29, 24}, Critical: true, Value: []byte{0x18, 0x00}},
+			},
+		},
+	}
+}
+
+func exportRevocationList() *CertificateRevocationList {
+	return &CertificateRevocationList{
+		Name:       "Example CA",
+		ThisUpdate: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC),
+		NextUpdate: time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC),
+		Signature:  []byte{0xca, 0xfe},
+		Raw:        []byte{0x30, 0x00},
+	}
+}
+
+func TestExportRevocationListCSV(t *testing.T) {
+	var out bytes.Buffer
+	count, err := ExportRevocationList(&out, exportRevocationList(), entrySeq(exportEntries()), ExportCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, []string{
+		"crl,serial_number,serial_number_hex,revocation_date,reason,extensions",
+		"Example CA,4096,10:00,2026-01-10T12:00:00Z,unspecified,",
+		"Example CA,255,ff,2026-01-10T12:00:00Z,keyCompromise,2.5.29.21=0a0101;2.5.29.24(critical)=1800",
+	}, lines)
+}
+
+func TestExportRevocationListJSON(t *testing.T) {
+	var out bytes.Buffer
+	count, err := ExportRevocationList(&out, exportRevocationList(), entrySeq(exportEntries()), ExportJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	var exported struct {
+		CRL                 exportedRevocationList       `json:"crl"`
+		RevokedCertificates []exportedRevokedCertificate `json:"revoked_certificates"`
+	}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &exported))
+	assert.Equal(t, "Example CA", exported.CRL.Name)
+	assert.Equal(t, "cafe", exported.CRL.Signature)
+	assert.Len(t, exported.RevokedCertificates, 2)
+	assert.Equal(t, exportedExtension{OID: "2.5.29.24", Critical: true, Value: "1800"}, exported.RevokedCertificates[1].Extensions[1])
+
+	out.Reset()
+	_, err = ExportRevocationList(&out, exportRevocationList(), entrySeq(nil), ExportJSON)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &exported))
+	assert.Empty(t, exported.RevokedCertificates)
+}
+
+func TestExportRevocationListJSONL(t *testing.T) {
+	var out bytes.Buffer
+	_, err := ExportRevocationList(&out, exportRevocationList(), entrySeq(exportEntries()), ExportJSONL)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	var exported exportedRevokedCertificate
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &exported))
+	assert.Equal(t, "Example CA", exported.CRL)
+	assert.Equal(t, RevocationReasonKeyCompromise, exported.Reason)
+}
+
+func TestExportRevocationListIndex(t *testing.T) {
+	var out bytes.Buffer
+	_, err := ExportRevocationList(&out, exportRevocationList(), entrySeq(exportEntries()), ExportIndex)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "R\t260117000000Z\t260110120000Z\t1000\tunknown\tunknown\n"+
+		"R\t260117000000Z\t260110120000Z,keyCompromise\tFF\tunknown\tunknown\n", out.String())
+	assert.Equal(t, "20500101000000Z", formatIndexTime(time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestExportRevocationListRaw(t *testing.T) {
+	revocationList := exportRevocationList()
+
+	var out bytes.Buffer
+	_, err := ExportRevocationList(&out, revocationList, nil, ExportDER)
+	assert.NoError(t, err)
+	assert.Equal(t, revocationList.Raw, out.Bytes())
+
+	out.Reset()
+	_, err = ExportRevocationList(&out, revocationList, nil, ExportPEM)
+	assert.NoError(t, err)
+	block, _ := pem.Decode(out.Bytes())
+	assert.Equal(t, "X509 CRL", block.Type)
+	assert.Equal(t, revocationList.Raw, block.Bytes)
+
+	revocationList.Raw = nil
+	_, err = ExportRevocationList(&out, revocationList, nil, ExportDER)
+	assert.ErrorContains(t, err, "the original CRL of Example CA is not stored")
+
+	revocationList.Unsigned = true
+	_, err = ExportRevocationList(&out, revocationList, nil, ExportPEM)
+	assert.ErrorContains(t, err, "Example CA is an unsigned CRL imported from a CA database")
+}
+
+func TestParseExportFormat(t *testing.T) {
+	format, err := ParseExportFormat("JSONL")
+	assert.NoError(t, err)
+	assert.Equal(t, ExportJSONL, format)
+	assert.Equal(t, "txt", ExportIndex.FileExtension())
+
+	_, err = ParseExportFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestRevokedCertificateEntries(t *testing.T) {
+	revocationDate := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	entries := RevokedCertificateEntries([]*RevokedCertificate{{SerialNumber: "4096", RevocationDate: revocationDate, RevocationReason: RevocationReasonSuperseded}})
+
+	for entry, err := range entries {
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(4096), entry.SerialNumber)
+		assert.Equal(t, revocationDate, entry.RevocationTime)
+		assert.Equal(t, 4, entry.ReasonCode)
+	}
+
+	for _, err := range RevokedCertificateEntries([]*RevokedCertificate{{SerialNumber: "10:00"}}) {
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,101 @@
+package crl
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// RetentionPolicy selects the stored CRLs that are pruned, the zero value keeps every CRL
+type RetentionPolicy struct {
+	// KeepVersions is the number of CRLs that is kept per issuer, the most recent by this update, 0 keeps all versions
+	KeepVersions int
+	// ExpiredFor prunes CRLs that are past their next update for longer than this duration, 0 disables the rule
+	ExpiredFor time.Duration
+	// NotRefreshedFor prunes CRLs that have not been downloaded or imported for longer than this duration, 0 disables the rule
+	NotRefreshedFor time.Duration
+}
+
+// Enabled is true when at least one rule of the policy is set
+func (p RetentionPolicy) Enabled() bool {
+	return p.KeepVersions > 0 || p.ExpiredFor > 0 || p.NotRefreshedFor > 0
+}
+
+// PrunedRevocationList is a CRL that is removed by the retention policy, with the rule that removed it
+type PrunedRevocationList struct {
+	RevocationList *CertificateRevocationList
+	Reason         string
+}
+
+// Select returns the CRLs the policy removes, a CRL that violates several rules is reported for the first rule only
+func (p RetentionPolicy) Select(revocationLists []*CertificateRevocationList, now time.Time) []*PrunedRevocationList {
+	pruned := make([]*PrunedRevocationList, 0)
+	superseded := p.supersededVersions(revocationLists)
+
+	for _, revocationList := range revocationLists {
+		var reason string
+		switch {
+		case superseded[revocationList.ID]:
+			reason = fmt.Sprintf("more than %d versions of the issuer are stored", p.KeepVersions)
+		case p.ExpiredFor > 0 && !revocationList.NextUpdate.IsZero() && now.Sub(revocationList.NextUpdate) > p.ExpiredFor:
+			reason = fmt.Sprintf("past its next update since %s", revocationList.NextUpdate.Format(time.DateOnly))
+		case p.NotRefreshedFor > 0 && !revocationList.LastRefreshed.IsZero() && now.Sub(revocationList.LastRefreshed) > p.NotRefreshedFor:
+			reason = fmt.Sprintf("not refreshed since %s", revocationList.LastRefreshed.Format(time.DateOnly))
+		default:
+			continue
+		}
+
+		pruned = append(pruned, &PrunedRevocationList{RevocationList: revocationList, Reason: reason})
+	}
+
+	return pruned
+}
+
+// supersededVersions returns the IDs of the CRLs beyond the most recent KeepVersions of their issuer
+func (p RetentionPolicy) supersededVersions(revocationLists []*CertificateRevocationList) map[int64]bool {
+	superseded := make(map[int64]bool)
+	if p.KeepVersions <= 0 {
+		return superseded
+	}
+
+	versions := make(map[string][]*CertificateRevocationList)
+	for _, revocationList := range revocationLists {
+		versions[revocationList.Name] = append(versions[revocationList.Name], revocationList)
+	}
+
+	for _, issuerVersions := range versions {
+		slices.SortFunc(issuerVersions, func(a, b *CertificateRevocationList) int {
+			return cmp.Or(b.ThisUpdate.Compare(a.ThisUpdate), cmp.Compare(b.ID, a.ID))
+		})
+		for _, revocationList := range issuerVersions[min(p.KeepVersions, len(issuerVersions)):] {
+			superseded[revocationList.ID] = true
+		}
+	}
+
+	return superseded
+}
+
+// Prune removes the stored CRLs selected by the policy together with their revoked certificates, nothing is removed on a dry run
+func Prune(ctx context.Context, store *Storage, policy RetentionPolicy, now time.Time, dryRun bool) ([]*PrunedRevocationList, error) {
+	revocationLists, err := store.Repository.List(ctx)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not list the stored CRLs"), err)
+	}
+
+	pruned := policy.Select(revocationLists, now)
+	if dryRun {
+		return pruned, nil
+	}
+
+	for i, revocationList := range pruned {
+		err := store.Repository.Delete(ctx, revocationList.RevocationList.ID)
+		if err != nil {
+			return pruned[:i], errors.Join(fmt.Errorf("could not remove CRL %s", revocationList.RevocationList.Name), err)
+		}
+	}
+
+	return pruned, nil
+}
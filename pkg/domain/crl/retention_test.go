@@ -0,0 +1,44 @@
+package crl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionPolicySelect(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	revocationLists := []*CertificateRevocationList{
+		{ID: 1, Name: "Issuing CA", ThisUpdate: now.AddDate(0, 0, -2), NextUpdate: now.AddDate(0, 0, 5), LastRefreshed: now},
+		{ID: 2, Name: "Issuing CA", ThisUpdate: now.AddDate(0, 0, -1), NextUpdate: now.AddDate(0, 0, 6), LastRefreshed: now},
+		{ID: 3, Name: "Expired CA", ThisUpdate: now.AddDate(0, 0, -60), NextUpdate: now.AddDate(0, 0, -40), LastRefreshed: now},
+		{ID: 4, Name: "Stale CA", ThisUpdate: now.AddDate(0, 0, -1), NextUpdate: now.AddDate(1, 0, 0), LastRefreshed: now.AddDate(0, 0, -100)},
+		{ID: 5, Name: "Unknown refresh", ThisUpdate: now.AddDate(0, 0, -1), NextUpdate: now.AddDate(1, 0, 0)},
+	}
+
+	tests := map[string]struct {
+		policy RetentionPolicy
+		want   []int64
+	}{
+		"zero value":     {policy: RetentionPolicy{}, want: []int64{}},
+		"keep versions":  {policy: RetentionPolicy{KeepVersions: 1}, want: []int64{1}},
+		"expired for":    {policy: RetentionPolicy{ExpiredFor: 30 * 24 * time.Hour}, want: []int64{3}},
+		"expired longer": {policy: RetentionPolicy{ExpiredFor: 60 * 24 * time.Hour}, want: []int64{}},
+		"not refreshed":  {policy: RetentionPolicy{NotRefreshedFor: 90 * 24 * time.Hour}, want: []int64{4}},
+		"all rules":      {policy: RetentionPolicy{KeepVersions: 1, ExpiredFor: 24 * time.Hour, NotRefreshedFor: 24 * time.Hour}, want: []int64{1, 3, 4}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.policy.KeepVersions > 0 || tt.policy.ExpiredFor > 0 || tt.policy.NotRefreshedFor > 0, tt.policy.Enabled())
+
+			ids := make([]int64, 0)
+			for _, pruned := range tt.policy.Select(revocationLists, now) {
+				assert.NotEmpty(t, pruned.Reason)
+				ids = append(ids, pruned.RevocationList.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
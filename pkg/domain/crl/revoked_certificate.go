@@ -1,6 +1,12 @@
 package crl
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
 
 type RevokedCertificate struct {
 	SerialNumber     string
@@ -28,3 +34,159 @@ const (
 	RevocationReasonPriviledgeWithdrawn  RevocationReason = "priviledgeWithdrawn"
 	RevocationReasonAACompromise         RevocationReason = "aACompromise"
 )
+
+// RevokedCertificateFilter selects revoked certificates of a CRL, the zero value matches all revoked certificates
+type RevokedCertificateFilter struct {
+	// SerialNumberPrefix is a prefix of the decimal serial number, or of the hex serial number when SerialNumberHex is set
+	SerialNumberPrefix string
+	SerialNumberHex    bool
+	Reason             RevocationReason
+	// RevokedAfter matches revocations on or after the time, RevokedBefore matches revocations before the time
+	RevokedAfter  time.Time
+	RevokedBefore time.Time
+}
+
+// Matches applies the filter to a revoked certificate, for repositories that cannot filter in a query
+func (f RevokedCertificateFilter) Matches(c *RevokedCertificate) bool {
+	serialNumber := c.SerialNumber
+	if f.SerialNumberHex {
+		serialNumber = SerialNumberHex(c.SerialNumber)
+	}
+	if !strings.HasPrefix(serialNumber, f.SerialNumberPrefix) {
+		return false
+	}
+
+	if f.Reason != "" && c.RevocationReason != f.Reason {
+		return false
+	}
+
+	if !f.RevokedAfter.IsZero() && c.RevocationDate.Before(f.RevokedAfter) {
+		return false
+	}
+
+	return f.RevokedBefore.IsZero() || c.RevocationDate.Before(f.RevokedBefore)
+}
+
+// SerialNumberHex converts a decimal serial number to lower case hex without leading zeros, repositories store it next to the decimal serial number
+func SerialNumberHex(serialNumber string) string {
+	n, ok := new(big.Int).SetString(serialNumber, 10)
+	if !ok {
+		return ""
+	}
+	return n.Text(16)
+}
+
+// FormatSerialNumberHex formats a decimal serial number as colon separated hex bytes, the notation of openssl and browsers
+func FormatSerialNumberHex(serialNumber string) string {
+	hex := SerialNumberHex(serialNumber)
+	if hex == "" {
+		return serialNumber
+	}
+	if len(hex)%2 == 1 {
+		hex = "0" + hex
+	}
+
+	pairs := make([]string, 0, len(hex)/2)
+	for i := 0; i < len(hex); i += 2 {
+		pairs = append(pairs, hex[i:i+2])
+	}
+	return strings.Join(pairs, ":")
+}
+
+// ParseSerialNumber normalizes a serial number in decimal, hex or colon separated hex notation to the decimal serial numbers it can stand for.
+// Serial numbers of only digits are ambiguous, they result in the decimal and the hex interpretation.
+func ParseSerialNumber(serialNumber string) ([]string, error) {
+	serialNumber = strings.Join(strings.Fields(serialNumber), "")
+	if serialNumber == "" {
+		return nil, errors.New("serial number is empty")
+	}
+
+	normalized, hex, err := parseSerialNumberPrefix(serialNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	n, _ := new(big.Int).SetString(normalized, 16)
+	if hex {
+		return []string{n.String()}, nil
+	}
+
+	decimal, _ := new(big.Int).SetString(normalized, 10)
+	if decimal.Cmp(n) == 0 {
+		return []string{decimal.String()}, nil
+	}
+	return []string{decimal.String(), n.String()}, nil
+}
+
+// ParseRevokedCertificateFilter parses a filter query of space separated terms: "reason:<reason>", "after:<YYYY-MM-DD>", "before:<YYYY-MM-DD>"
+// and a serial number prefix. The prefix is hex when it starts with 0x, contains colons or contains the letters a-f, otherwise it is decimal.
+func ParseRevokedCertificateFilter(query string) (RevokedCertificateFilter, error) {
+	var filter RevokedCertificateFilter
+	for _, term := range strings.Fields(query) {
+		name, value, found := strings.Cut(term, ":")
+		switch {
+		case found && name == "reason":
+			reason, err := parseRevocationReason(value)
+			if err != nil {
+				return filter, err
+			}
+			filter.Reason = reason
+		case found && name == "after":
+			after, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid date %s, dates are formatted as YYYY-MM-DD", value)
+			}
+			filter.RevokedAfter = after
+		case found && name == "before":
+			before, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid date %s, dates are formatted as YYYY-MM-DD", value)
+			}
+			filter.RevokedBefore = before
+		default:
+			prefix, hex, err := parseSerialNumberPrefix(term)
+			if err != nil {
+				return filter, err
+			}
+			filter.SerialNumberPrefix = prefix
+			filter.SerialNumberHex = hex
+		}
+	}
+
+	return filter, nil
+}
+
+func parseRevocationReason(value string) (RevocationReason, error) {
+	for _, reason := range RevocationReasons {
+		if strings.EqualFold(reason.String(), value) {
+			return reason, nil
+		}
+	}
+	return "", fmt.Errorf("unknown revocation reason: %s", value)
+}
+
+// parseSerialNumberPrefix normalizes a decimal or hex serial number prefix, prefixes are lower case without leading zeros like the stored serial numbers
+func parseSerialNumberPrefix(value string) (string, bool, error) {
+	value = strings.ToLower(value)
+	hex := strings.HasPrefix(value, "0x") || strings.Contains(value, ":") || strings.ContainsAny(value, "abcdef")
+	if !hex {
+		if strings.Trim(value, "0123456789") != "" {
+			return "", false, fmt.Errorf("invalid serial number: %s", value)
+		}
+		return trimLeadingZeros(value), false, nil
+	}
+
+	value = strings.ReplaceAll(strings.TrimPrefix(value, "0x"), ":", "")
+	if value == "" || strings.Trim(value, "0123456789abcdef") != "" {
+		return "", false, fmt.Errorf("invalid hex serial number: %s", value)
+	}
+
+	return trimLeadingZeros(value), true, nil
+}
+
+func trimLeadingZeros(value string) string {
+	if value = strings.TrimLeft(value, "0"); value == "" {
+		return "0"
+	}
+	return value
+}
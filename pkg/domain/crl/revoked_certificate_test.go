@@ -0,0 +1,97 @@
+package crl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRevokedCertificateFilter(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  RevokedCertificateFilter
+	}{
+		"empty":          {query: "", want: RevokedCertificateFilter{}},
+		"decimal":        {query: "1234", want: RevokedCertificateFilter{SerialNumberPrefix: "1234"}},
+		"hex letters":    {query: "0A1B", want: RevokedCertificateFilter{SerialNumberPrefix: "a1b", SerialNumberHex: true}},
+		"hex prefix":     {query: "0x0100", want: RevokedCertificateFilter{SerialNumberPrefix: "100", SerialNumberHex: true}},
+		"colons":         {query: "00:ff:01", want: RevokedCertificateFilter{SerialNumberPrefix: "ff01", SerialNumberHex: true}},
+		"decimal zeros":  {query: "0012", want: RevokedCertificateFilter{SerialNumberPrefix: "12"}},
+		"decimal zero":   {query: "000", want: RevokedCertificateFilter{SerialNumberPrefix: "0"}},
+		"hex zeros":      {query: "000a1b", want: RevokedCertificateFilter{SerialNumberPrefix: "a1b", SerialNumberHex: true}},
+		"hex zero":       {query: "0x00", want: RevokedCertificateFilter{SerialNumberPrefix: "0", SerialNumberHex: true}},
+		"reason":         {query: "reason:KeyCompromise", want: RevokedCertificateFilter{Reason: RevocationReasonKeyCompromise}},
+		"date range":     {query: "after:2024-01-01 before:2024-02-01", want: RevokedCertificateFilter{RevokedAfter: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), RevokedBefore: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}},
+		"combined terms": {query: "ff reason:superseded", want: RevokedCertificateFilter{SerialNumberPrefix: "ff", SerialNumberHex: true, Reason: RevocationReasonSuperseded}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			filter, err := ParseRevokedCertificateFilter(tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, filter)
+		})
+	}
+}
+
+func TestParseRevokedCertificateFilterInvalid(t *testing.T) {
+	for _, query := range []string{"reason:unknown", "after:01-01-2024", "before:tomorrow", "12x4", "0x", "ff:gg"} {
+		_, err := ParseRevokedCertificateFilter(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestRevokedCertificateFilterMatches(t *testing.T) {
+	revokedCertificate := &RevokedCertificate{
+		SerialNumber:     "255",
+		RevocationReason: RevocationReasonKeyCompromise,
+		RevocationDate:   time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+	}
+
+	assert.True(t, RevokedCertificateFilter{}.Matches(revokedCertificate))
+	assert.True(t, RevokedCertificateFilter{SerialNumberPrefix: "25"}.Matches(revokedCertificate))
+	assert.True(t, RevokedCertificateFilter{SerialNumberPrefix: "ff", SerialNumberHex: true}.Matches(revokedCertificate))
+	assert.False(t, RevokedCertificateFilter{SerialNumberPrefix: "ff"}.Matches(revokedCertificate))
+	assert.False(t, RevokedCertificateFilter{Reason: RevocationReasonSuperseded}.Matches(revokedCertificate))
+	assert.True(t, RevokedCertificateFilter{RevokedAfter: revokedCertificate.RevocationDate}.Matches(revokedCertificate))
+	assert.False(t, RevokedCertificateFilter{RevokedBefore: revokedCertificate.RevocationDate}.Matches(revokedCertificate))
+}
+
+func TestParseSerialNumber(t *testing.T) {
+	tests := map[string]struct {
+		serialNumber string
+		want         []string
+	}{
+		"decimal and hex":  {serialNumber: "4096", want: []string{"4096", "16534"}},
+		"leading zeros":    {serialNumber: "0010", want: []string{"10", "16"}},
+		"equal notations":  {serialNumber: "7", want: []string{"7"}},
+		"hex":              {serialNumber: "0x1000", want: []string{"4096"}},
+		"hex letters":      {serialNumber: "FF", want: []string{"255"}},
+		"colon separated":  {serialNumber: "00:10:00", want: []string{"4096"}},
+		"space separated":  {serialNumber: " 0a 1b ", want: []string{"2587"}},
+		"large serial":     {serialNumber: "7d:6b:65:3f:71:52:b6:4a:1c:5d:38:a8:ac:2e:fd:8e", want: []string{"166711128786793716751417920899070754190"}},
+		"zero":             {serialNumber: "00", want: []string{"0"}},
+		"hex prefix zeros": {serialNumber: "0x00ff", want: []string{"255"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			serialNumbers, err := ParseSerialNumber(tt.serialNumber)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, serialNumbers)
+		})
+	}
+
+	for _, serialNumber := range []string{"", "0x", "12g4", "reason:superseded"} {
+		_, err := ParseSerialNumber(serialNumber)
+		assert.Error(t, err, serialNumber)
+	}
+}
+
+func TestFormatSerialNumberHex(t *testing.T) {
+	assert.Equal(t, "ff", FormatSerialNumberHex("255"))
+	assert.Equal(t, "10:00", FormatSerialNumberHex("4096"))
+	assert.Equal(t, "01:00:00", FormatSerialNumberHex("65536"))
+	assert.Equal(t, "not a serial", FormatSerialNumberHex("not a serial"))
+}
@@ -3,8 +3,12 @@ package crl
 import (
 	"context"
 	"crypto/x509"
+	"errors"
 )
 
+// ErrNotFound is returned by Repository.Find when no CRL with the name is stored
+var ErrNotFound = errors.New("certificate revocation list not found")
+
 type Repository interface {
 	Save(ctx context.Context, crl *CertificateRevocationList) (int64, error)
 	Find(ctx context.Context, name string) (*CertificateRevocationList, error)
@@ -12,7 +16,16 @@ type Repository interface {
 	Delete(ctx context.Context, id int64) error
 	SaveRevokedCertificates(ctx context.Context, revocationListId int64, revokedCertificates []x509.RevocationListEntry) (int, error)
 	FindRevokedCertificates(ctx context.Context, revocationListId int64) ([]*RevokedCertificate, error)
+	ListRevokedCertificates(ctx context.Context, revocationListId int64, filter RevokedCertificateFilter, offset, limit int) ([]*RevokedCertificate, error)
+	CountRevokedCertificates(ctx context.Context, revocationListId int64, filter RevokedCertificateFilter) (int, error)
 	FindRevokedCertificate(ctx context.Context, serialnumber string) (*RevokedCertificate, error)
+	FindRevokedCertificatesBySerialNumber(ctx context.Context, serialnumber string) ([]*RevokedCertificate, error)
+	SaveCertificate(ctx context.Context, certificate *StoredCertificate) (int64, error)
+	ListCertificates(ctx context.Context, filter CertificateFilter) ([]*StoredCertificate, error)
+	FindCertificate(ctx context.Context, fingerprint string) (*StoredCertificate, error)
+	DeleteCertificate(ctx context.Context, id int64) error
+	SaveAuditEntry(ctx context.Context, entry *AuditEntry) (int64, error)
+	ListAuditEntries(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error)
 }
 
 type Storage struct {
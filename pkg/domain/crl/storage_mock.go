@@ -3,18 +3,48 @@ package crl
 import (
 	"context"
 	"crypto/x509"
+	"maps"
+	"slices"
+	"sync"
 )
 
 // TODO create better mock repository that can be used for testing
 type MockRepository struct {
 	CRLs                      map[int64]*CertificateRevocationList
 	RevokedCertificateEntries map[int64][]x509.RevocationListEntry
+	Certificates              map[string]*StoredCertificate
+	// AuditEntries are saved concurrently by the OCSP checks of a directory scan
+	AuditEntries []*AuditEntry
+	auditMu      sync.Mutex
 }
 
 func (r *MockRepository) FindRevokedCertificate(_ context.Context, _ string) (*RevokedCertificate, error) {
 	return nil, nil
 }
 
+func (r *MockRepository) FindRevokedCertificatesBySerialNumber(_ context.Context, serialnumber string) ([]*RevokedCertificate, error) {
+	revokedCertificates := make([]*RevokedCertificate, 0)
+	for _, CRLID := range slices.Sorted(maps.Keys(r.RevokedCertificateEntries)) {
+		for _, entry := range r.RevokedCertificateEntries[CRLID] {
+			if entry.SerialNumber.String() != serialnumber {
+				continue
+			}
+
+			revokedCertificate := &RevokedCertificate{
+				SerialNumber:     serialnumber,
+				RevocationDate:   entry.RevocationTime,
+				RevocationReason: RevocationReasons[entry.ReasonCode],
+				RevocationListID: CRLID,
+			}
+			if CRL, ok := r.CRLs[CRLID]; ok {
+				revokedCertificate.RevokedBy = CRL.Name
+			}
+			revokedCertificates = append(revokedCertificates, revokedCertificate)
+		}
+	}
+	return revokedCertificates, nil
+}
+
 func (r *MockRepository) List(_ context.Context) ([]*CertificateRevocationList, error) {
 	return []*CertificateRevocationList{}, nil
 }
@@ -29,13 +59,8 @@ func (r *MockRepository) Find(_ context.Context, _ string) (*CertificateRevocati
 }
 
 func (r *MockRepository) SaveRevokedCertificates(_ context.Context, crlID int64, entries []x509.RevocationListEntry) (int, error) {
-	CRLentries, ok := r.RevokedCertificateEntries[crlID]
-	if !ok {
-		CRLentries = entries
-	} else {
-		CRLentries = append(CRLentries, entries...)
-	}
-	return len(CRLentries), nil
+	r.RevokedCertificateEntries[crlID] = append(r.RevokedCertificateEntries[crlID], entries...)
+	return len(entries), nil
 }
 
 func (r *MockRepository) FindRevokedCertificates(_ context.Context, CRLID int64) ([]*RevokedCertificate, error) {
@@ -47,22 +72,115 @@ func (r *MockRepository) FindRevokedCertificates(_ context.Context, CRLID int64)
 
 	for _, entry := range CRL {
 		revokedCertifcates = append(revokedCertifcates, &RevokedCertificate{
-			SerialNumber:   entry.SerialNumber.String(),
-			RevocationDate: entry.RevocationTime,
+			SerialNumber:     entry.SerialNumber.String(),
+			RevocationDate:   entry.RevocationTime,
+			RevocationReason: RevocationReasons[entry.ReasonCode],
 		})
 	}
 	return revokedCertifcates, nil
 }
 
+func (r *MockRepository) ListRevokedCertificates(ctx context.Context, CRLID int64, filter RevokedCertificateFilter, offset, limit int) ([]*RevokedCertificate, error) {
+	revokedCertificates, err := r.filterRevokedCertificates(ctx, CRLID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return revokedCertificates[min(offset, len(revokedCertificates)):min(offset+limit, len(revokedCertificates))], nil
+}
+
+func (r *MockRepository) CountRevokedCertificates(ctx context.Context, CRLID int64, filter RevokedCertificateFilter) (int, error) {
+	revokedCertificates, err := r.filterRevokedCertificates(ctx, CRLID, filter)
+	return len(revokedCertificates), err
+}
+
+func (r *MockRepository) filterRevokedCertificates(ctx context.Context, CRLID int64, filter RevokedCertificateFilter) ([]*RevokedCertificate, error) {
+	revokedCertificates, err := r.FindRevokedCertificates(ctx, CRLID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(revokedCertificates, func(c *RevokedCertificate) bool { return !filter.Matches(c) }), nil
+}
+
 func (r *MockRepository) Delete(_ context.Context, _ int64) error {
 	return nil
 }
 
+func (r *MockRepository) SaveCertificate(_ context.Context, certificate *StoredCertificate) (int64, error) {
+	if r.Certificates == nil {
+		r.Certificates = make(map[string]*StoredCertificate)
+	}
+
+	stored, ok := r.Certificates[certificate.Fingerprint]
+	if ok {
+		stored.Source = certificate.Source
+		stored.LastSeen = certificate.LastSeen
+		return stored.ID, nil
+	}
+
+	saved := *certificate
+	saved.ID = int64(len(r.Certificates) + 1)
+	r.Certificates[certificate.Fingerprint] = &saved
+	return saved.ID, nil
+}
+
+func (r *MockRepository) ListCertificates(_ context.Context, filter CertificateFilter) ([]*StoredCertificate, error) {
+	certificates := make([]*StoredCertificate, 0)
+	for _, certificate := range r.Certificates {
+		if filter.Matches(certificate) {
+			certificates = append(certificates, certificate)
+		}
+	}
+	slices.SortFunc(certificates, func(a, b *StoredCertificate) int {
+		return a.NotAfter.Compare(b.NotAfter)
+	})
+	return certificates, nil
+}
+
+func (r *MockRepository) FindCertificate(_ context.Context, fingerprint string) (*StoredCertificate, error) {
+	return r.Certificates[fingerprint], nil
+}
+
+func (r *MockRepository) DeleteCertificate(_ context.Context, id int64) error {
+	for fingerprint, certificate := range r.Certificates {
+		if certificate.ID == id {
+			delete(r.Certificates, fingerprint)
+		}
+	}
+	return nil
+}
+
 func NewMockStorage() (*Storage, error) {
 	CRLs := make(map[int64]*CertificateRevocationList)
 	RevokedCertificateEntries := make(map[int64][]x509.RevocationListEntry)
 	return NewStorage(&MockRepository{
 		CRLs:                      CRLs,
 		RevokedCertificateEntries: RevokedCertificateEntries,
+		Certificates:              make(map[string]*StoredCertificate),
 	}, "test", "test/import")
 }
+
+func (r *MockRepository) SaveAuditEntry(_ context.Context, entry *AuditEntry) (int64, error) {
+	r.auditMu.Lock()
+	defer r.auditMu.Unlock()
+
+	saved := *entry
+	saved.ID = int64(len(r.AuditEntries) + 1)
+	r.AuditEntries = append(r.AuditEntries, &saved)
+	return saved.ID, nil
+}
+
+func (r *MockRepository) ListAuditEntries(_ context.Context, filter AuditFilter) ([]*AuditEntry, error) {
+	r.auditMu.Lock()
+	defer r.auditMu.Unlock()
+
+	entries := make([]*AuditEntry, 0)
+	for _, entry := range slices.Backward(r.AuditEntries) {
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
@@ -0,0 +1,95 @@
+package crl
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// StoredCertificate is a certificate in the inventory of inspected certificates
+type StoredCertificate struct {
+	ID           int64
+	Fingerprint  string
+	Subject      string
+	Issuer       string
+	SerialNumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	Raw          []byte
+	// Source describes where the certificate was last seen, e.g. the path of an imported file or a TLS endpoint
+	Source    string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// RevokedBy is the name of the stored CRL the certificate is listed on, empty when it is not found on any stored CRL
+	RevokedBy string
+}
+
+// NewStoredCertificate creates an inventory entry for a certificate seen at the given time
+func NewStoredCertificate(cert *x509.Certificate, source string, seen time.Time) *StoredCertificate {
+	return &StoredCertificate{
+		Fingerprint:  Fingerprint(cert),
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		NotBefore:    cert.NotBefore.UTC(),
+		NotAfter:     cert.NotAfter.UTC(),
+		Raw:          cert.Raw,
+		Source:       source,
+		FirstSeen:    seen.UTC(),
+		LastSeen:     seen.UTC(),
+	}
+}
+
+// Fingerprint returns the hex encoded SHA-256 fingerprint of the DER encoded certificate
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *StoredCertificate) Revoked() bool {
+	return c.RevokedBy != ""
+}
+
+// Certificate parses the raw DER encoded certificate
+func (c *StoredCertificate) Certificate() (*x509.Certificate, error) {
+	return x509.ParseCertificate(c.Raw)
+}
+
+type RevocationFilter string
+
+const (
+	RevocationFilterAll        RevocationFilter = ""
+	RevocationFilterRevoked    RevocationFilter = "revoked"
+	RevocationFilterNotRevoked RevocationFilter = "not revoked"
+)
+
+// CertificateFilter selects certificates from the inventory, zero values do not filter
+type CertificateFilter struct {
+	// Issuer matches part of the issuer distinguished name, case insensitive
+	Issuer string
+	// ExpiresBefore selects certificates that expire before this time, including already expired certificates
+	ExpiresBefore time.Time
+	Revocation    RevocationFilter
+}
+
+// Matches applies the filter to a certificate, for repositories that cannot filter in a query
+func (f CertificateFilter) Matches(c *StoredCertificate) bool {
+	if f.Issuer != "" && !strings.Contains(strings.ToLower(c.Issuer), strings.ToLower(f.Issuer)) {
+		return false
+	}
+
+	if !f.ExpiresBefore.IsZero() && c.NotAfter.After(f.ExpiresBefore) {
+		return false
+	}
+
+	switch f.Revocation {
+	case RevocationFilterRevoked:
+		return c.Revoked()
+	case RevocationFilterNotRevoked:
+		return !c.Revoked()
+	}
+
+	return true
+}
@@ -0,0 +1,146 @@
+package ct
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// bundledLogList is a snapshot of the Google v3 log list, it can be refreshed with: make ctlogs
+//
+//go:embed log_list.json
+var bundledLogList []byte
+
+// Log is a Certificate Transparency log as published in a log list
+type Log struct {
+	Description string
+	Operator    string
+	URL         string
+	LogID       []byte
+	PublicKey   crypto.PublicKey
+	MMD         time.Duration
+	State       string
+}
+
+// LogList contains all known Certificate Transparency logs
+type LogList struct {
+	Version   string
+	Timestamp time.Time
+	Logs      []*Log
+}
+
+type logListJSON struct {
+	Version          string         `json:"version"`
+	LogListTimestamp time.Time      `json:"log_list_timestamp"`
+	Operators        []operatorJSON `json:"operators"`
+}
+
+type operatorJSON struct {
+	Name      string    `json:"name"`
+	Logs      []logJSON `json:"logs"`
+	TiledLogs []logJSON `json:"tiled_logs"`
+}
+
+type logJSON struct {
+	Description   string                     `json:"description"`
+	LogID         string                     `json:"log_id"`
+	Key           string                     `json:"key"`
+	URL           string                     `json:"url"`
+	SubmissionURL string                     `json:"submission_url"`
+	MMD           int                        `json:"mmd"`
+	State         map[string]json.RawMessage `json:"state"`
+}
+
+// ParseLogList parses a log list in the Google v3 JSON format (https://www.gstatic.com/ct/log_list/v3/log_list_schema.json)
+func ParseLogList(data []byte) (*LogList, error) {
+	var raw logListJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Join(errors.New("could not parse CT log list"), err)
+	}
+
+	logList := &LogList{
+		Version:   raw.Version,
+		Timestamp: raw.LogListTimestamp,
+		Logs:      make([]*Log, 0),
+	}
+
+	for _, operator := range raw.Operators {
+		for _, l := range append(operator.Logs, operator.TiledLogs...) {
+			ctLog, err := parseLog(operator.Name, l)
+			if err != nil {
+				return nil, err
+			}
+			logList.Logs = append(logList.Logs, ctLog)
+		}
+	}
+
+	return logList, nil
+}
+
+func parseLog(operator string, l logJSON) (*Log, error) {
+	key, err := base64.StdEncoding.DecodeString(l.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key for CT log %q: %v", l.Description, err)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key for CT log %q: %v", l.Description, err)
+	}
+
+	logID := sha256.Sum256(key)
+	if l.LogID != "" {
+		expected, err := base64.StdEncoding.DecodeString(l.LogID)
+		if err != nil || !bytes.Equal(expected, logID[:]) {
+			return nil, fmt.Errorf("log_id of CT log %q does not match its key", l.Description)
+		}
+	}
+
+	url := l.URL
+	if url == "" {
+		url = l.SubmissionURL
+	}
+
+	state := ""
+	for s := range l.State {
+		state = s
+	}
+
+	return &Log{
+		Description: l.Description,
+		Operator:    operator,
+		URL:         url,
+		LogID:       logID[:],
+		PublicKey:   publicKey,
+		MMD:         time.Duration(l.MMD) * time.Second,
+		State:       state,
+	}, nil
+}
+
+// BundledLogList returns the log list that is embedded in the binary
+func BundledLogList() *LogList {
+	logList, err := ParseLogList(bundledLogList)
+	if err != nil {
+		log.Printf("could not parse bundled CT log list: %v", err)
+		return &LogList{}
+	}
+	return logList
+}
+
+// Find returns the log with the given log ID or nil if the log is not known
+func (l *LogList) Find(logID []byte) *Log {
+	for _, ctLog := range l.Logs {
+		if bytes.Equal(ctLog.LogID, logID) {
+			return ctLog
+		}
+	}
+	return nil
+}
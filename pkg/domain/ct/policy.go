@@ -0,0 +1,95 @@
+package ct
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+// Policy describes the requirements a certificate must meet to be considered Certificate Transparency compliant.
+// When MinSCTs is 0 the number of required SCTs depends on the certificate lifetime, following the Chrome CT policy.
+type Policy struct {
+	MinSCTs      int
+	MinOperators int
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MinSCTs:      0,
+		MinOperators: 2,
+	}
+}
+
+// VerifiedSCT is a SCT together with the log that issued it and the outcome of the signature verification
+type VerifiedSCT struct {
+	SCT *SignedCertificateTimestamp
+	Log *Log
+	Err error
+}
+
+func (v *VerifiedSCT) Valid() bool {
+	return v.Log != nil && v.Err == nil
+}
+
+type PolicyResult struct {
+	Compliant    bool
+	ValidSCTs    int
+	RequiredSCTs int
+	Operators    int
+	MinOperators int
+}
+
+// Report is the Certificate Transparency summary of a certificate
+type Report struct {
+	SCTs   []*VerifiedSCT
+	Policy PolicyResult
+}
+
+// Check parses and verifies all embedded SCTs of cert and evaluates them against the policy
+func Check(cert, issuer *x509.Certificate, logs *LogList, policy Policy) (*Report, error) {
+	scts, err := ParseSCTs(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		SCTs: make([]*VerifiedSCT, 0, len(scts)),
+	}
+
+	operators := make(map[string]bool)
+	for _, sct := range scts {
+		verified := &VerifiedSCT{
+			SCT: sct,
+			Log: logs.Find(sct.LogID),
+		}
+
+		if verified.Log == nil {
+			verified.Err = ErrUnknownLog
+		} else {
+			verified.Err = VerifySCT(sct, cert, issuer, verified.Log)
+		}
+
+		if verified.Valid() {
+			report.Policy.ValidSCTs++
+			operators[verified.Log.Operator] = true
+		}
+		report.SCTs = append(report.SCTs, verified)
+	}
+
+	report.Policy.Operators = len(operators)
+	report.Policy.MinOperators = policy.MinOperators
+	report.Policy.RequiredSCTs = policy.requiredSCTs(cert)
+	report.Policy.Compliant = report.Policy.ValidSCTs >= report.Policy.RequiredSCTs && report.Policy.Operators >= policy.MinOperators
+
+	return report, nil
+}
+
+func (p Policy) requiredSCTs(cert *x509.Certificate) int {
+	if p.MinSCTs > 0 {
+		return p.MinSCTs
+	}
+
+	if cert.NotAfter.Sub(cert.NotBefore) <= 180*24*time.Hour {
+		return 2
+	}
+	return 3
+}
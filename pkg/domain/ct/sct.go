@@ -0,0 +1,247 @@
+package ct
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var oidExtensionSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+const (
+	hashAlgorithmSHA256      uint8 = 4
+	signatureAlgorithmRSA    uint8 = 1
+	signatureAlgorithmECDSA  uint8 = 3
+	signatureTypeTimestamp   uint8 = 0
+	logEntryTypePrecertEntry       = 1
+)
+
+var (
+	ErrUnknownLog    = errors.New("SCT is issued by an unknown log")
+	ErrMissingIssuer = errors.New("issuer certificate is required to verify SCT")
+)
+
+// SignedCertificateTimestamp is a SCT embedded in a certificate as defined in RFC 6962 section 3.2
+type SignedCertificateTimestamp struct {
+	Version            uint8
+	LogID              []byte
+	Timestamp          time.Time
+	Extensions         []byte
+	HashAlgorithm      uint8
+	SignatureAlgorithm uint8
+	Signature          []byte
+}
+
+// SignatureAlgorithmName returns the name of the signature algorithm in the same notation as the x509 package
+func (s *SignedCertificateTimestamp) SignatureAlgorithmName() string {
+	hash := fmt.Sprintf("hash(%d)", s.HashAlgorithm)
+	if s.HashAlgorithm == hashAlgorithmSHA256 {
+		hash = "SHA256"
+	}
+
+	switch s.SignatureAlgorithm {
+	case signatureAlgorithmRSA:
+		return "RSA-" + hash
+	case signatureAlgorithmECDSA:
+		return "ECDSA-" + hash
+	default:
+		return fmt.Sprintf("signature(%d)-%s", s.SignatureAlgorithm, hash)
+	}
+}
+
+// ParseSCTs decodes the SCT list extension of a certificate, a certificate without SCTs results in an empty list
+func ParseSCTs(cert *x509.Certificate) ([]*SignedCertificateTimestamp, error) {
+	scts := make([]*SignedCertificateTimestamp, 0)
+
+	var extensionValue []byte
+	for _, extension := range cert.Extensions {
+		if extension.Id.Equal(oidExtensionSCTList) {
+			extensionValue = extension.Value
+		}
+	}
+
+	if extensionValue == nil {
+		return scts, nil
+	}
+
+	var sctList []byte
+	if _, err := asn1.Unmarshal(extensionValue, &sctList); err != nil {
+		return nil, errors.Join(errors.New("could not decode SCT list extension"), err)
+	}
+
+	list := cryptobyte.String(sctList)
+	var serializedSCTs cryptobyte.String
+	if !list.ReadUint16LengthPrefixed(&serializedSCTs) || !list.Empty() {
+		return nil, errors.New("malformed SCT list")
+	}
+
+	for !serializedSCTs.Empty() {
+		var serializedSCT cryptobyte.String
+		if !serializedSCTs.ReadUint16LengthPrefixed(&serializedSCT) {
+			return nil, errors.New("malformed SCT list entry")
+		}
+
+		sct, err := parseSCT(serializedSCT)
+		if err != nil {
+			return nil, err
+		}
+		scts = append(scts, sct)
+	}
+
+	return scts, nil
+}
+
+func parseSCT(s cryptobyte.String) (*SignedCertificateTimestamp, error) {
+	sct := &SignedCertificateTimestamp{}
+	var logID, extensions, signature []byte
+	var timestamp uint64
+
+	if !s.ReadUint8(&sct.Version) ||
+		!s.ReadBytes(&logID, sha256.Size) ||
+		!s.ReadUint64(&timestamp) ||
+		!s.ReadUint16LengthPrefixed((*cryptobyte.String)(&extensions)) ||
+		!s.ReadUint8(&sct.HashAlgorithm) ||
+		!s.ReadUint8(&sct.SignatureAlgorithm) ||
+		!s.ReadUint16LengthPrefixed((*cryptobyte.String)(&signature)) ||
+		!s.Empty() {
+		return nil, errors.New("malformed SCT")
+	}
+
+	if sct.Version != 0 {
+		return nil, fmt.Errorf("unsupported SCT version: %d", sct.Version)
+	}
+
+	sct.LogID = logID
+	sct.Timestamp = time.UnixMilli(int64(timestamp)).UTC()
+	sct.Extensions = extensions
+	sct.Signature = signature
+
+	return sct, nil
+}
+
+// VerifySCT verifies the signature of a SCT embedded in cert against the public key of the log
+func VerifySCT(sct *SignedCertificateTimestamp, cert, issuer *x509.Certificate, log *Log) error {
+	if issuer == nil {
+		return ErrMissingIssuer
+	}
+
+	if sct.HashAlgorithm != hashAlgorithmSHA256 {
+		return fmt.Errorf("unsupported SCT hash algorithm: %d", sct.HashAlgorithm)
+	}
+
+	tbs, err := removeSCTExtension(cert.RawTBSCertificate)
+	if err != nil {
+		return err
+	}
+
+	issuerKeyHash := sha256.Sum256(issuer.RawSubjectPublicKeyInfo)
+
+	var b cryptobyte.Builder
+	b.AddUint8(sct.Version)
+	b.AddUint8(signatureTypeTimestamp)
+	b.AddUint64(uint64(sct.Timestamp.UnixMilli()))
+	b.AddUint16(logEntryTypePrecertEntry)
+	b.AddBytes(issuerKeyHash[:])
+	b.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(tbs)
+	})
+	b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(sct.Extensions)
+	})
+
+	signedData, err := b.Bytes()
+	if err != nil {
+		return errors.Join(errors.New("could not construct SCT signed data"), err)
+	}
+	digest := sha256.Sum256(signedData)
+
+	switch publicKey := log.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		if sct.SignatureAlgorithm != signatureAlgorithmECDSA {
+			return errors.New("SCT signature algorithm does not match the log key")
+		}
+		if !ecdsa.VerifyASN1(publicKey, digest[:], sct.Signature) {
+			return errors.New("invalid SCT signature")
+		}
+	case *rsa.PublicKey:
+		if sct.SignatureAlgorithm != signatureAlgorithmRSA {
+			return errors.New("SCT signature algorithm does not match the log key")
+		}
+		if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], sct.Signature); err != nil {
+			return errors.Join(errors.New("invalid SCT signature"), err)
+		}
+	default:
+		return fmt.Errorf("unsupported log public key type: %T", publicKey)
+	}
+
+	return nil
+}
+
+// removeSCTExtension reconstructs the precertificate TBSCertificate by removing the SCT list extension
+func removeSCTExtension(rawTBS []byte) ([]byte, error) {
+	input := cryptobyte.String(rawTBS)
+	var tbs cryptobyte.String
+	if !input.ReadASN1(&tbs, cryptobyte_asn1.SEQUENCE) {
+		return nil, errors.New("malformed TBSCertificate")
+	}
+
+	var b cryptobyte.Builder
+	var failure error
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		for !tbs.Empty() {
+			var element cryptobyte.String
+			var tag cryptobyte_asn1.Tag
+			if !tbs.ReadAnyASN1Element(&element, &tag) {
+				failure = errors.New("malformed TBSCertificate element")
+				return
+			}
+
+			if tag != cryptobyte_asn1.Tag(3).Constructed().ContextSpecific() {
+				b.AddBytes(element)
+				continue
+			}
+
+			var extensions cryptobyte.String
+			if !element.ReadASN1(&extensions, tag) || !extensions.ReadASN1(&extensions, cryptobyte_asn1.SEQUENCE) {
+				failure = errors.New("malformed TBSCertificate extensions")
+				return
+			}
+
+			b.AddASN1(tag, func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					for !extensions.Empty() {
+						var extension, extensionContent cryptobyte.String
+						var oid asn1.ObjectIdentifier
+						if !extensions.ReadASN1Element(&extension, cryptobyte_asn1.SEQUENCE) {
+							failure = errors.New("malformed extension")
+							return
+						}
+						extensionContent = extension
+						if !extensionContent.ReadASN1(&extensionContent, cryptobyte_asn1.SEQUENCE) || !extensionContent.ReadASN1ObjectIdentifier(&oid) {
+							failure = errors.New("malformed extension")
+							return
+						}
+						if !oid.Equal(oidExtensionSCTList) {
+							b.AddBytes(extension)
+						}
+					}
+				})
+			})
+		}
+	})
+
+	if failure != nil {
+		return nil, failure
+	}
+
+	return b.Bytes()
+}
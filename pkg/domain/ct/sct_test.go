@@ -0,0 +1,286 @@
+package ct
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/pkg/domain/certificate"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+func TestParseSCTs(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("..", "..", "..", "testing", "pki", "github.com-chain.pem"))
+	assert.NoError(t, err)
+	certificates, err := certificate.ParsePEMCertificate(raw)
+	assert.NoError(t, err)
+
+	scts, err := ParseSCTs(certificates[0])
+	assert.NoError(t, err)
+
+	assert.Len(t, scts, 3)
+	assert.Equal(t, "CF:11:56:EE:D5:2E:7C:AF:F3:87:5B:D9:69:2E:9B:E9:1A:71:67:4A:B0:17:EC:AC:01:D2:5B:77:CE:CC:3B:08", certificate.FormatHex(scts[0].LogID))
+	assert.Equal(t, time.Date(2024, time.March, 7, 0, 5, 45, 113000000, time.UTC), scts[0].Timestamp)
+	assert.Equal(t, "ECDSA-SHA256", scts[0].SignatureAlgorithmName())
+
+	noSCTs, err := ParseSCTs(certificates[1])
+	assert.NoError(t, err)
+	assert.Empty(t, noSCTs)
+}
+
+func TestCheckUnknownLogs(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("..", "..", "..", "testing", "pki", "github.com-chain.pem"))
+	assert.NoError(t, err)
+	certificates, err := certificate.ParsePEMCertificate(raw)
+	assert.NoError(t, err)
+
+	report, err := Check(certificates[0], certificates[1], &LogList{}, DefaultPolicy())
+	assert.NoError(t, err)
+
+	assert.Len(t, report.SCTs, 3)
+	for _, sct := range report.SCTs {
+		assert.ErrorIs(t, sct.Err, ErrUnknownLog)
+	}
+	assert.False(t, report.Policy.Compliant)
+	assert.Equal(t, 3, report.Policy.RequiredSCTs)
+}
+
+func TestCheckVerifiesSCTs(t *testing.T) {
+	testPKI := newTestPKI(t)
+
+	report, err := Check(testPKI.leaf, testPKI.issuer, testPKI.logs, Policy{MinSCTs: 2, MinOperators: 2})
+	assert.NoError(t, err)
+
+	assert.Len(t, report.SCTs, 2)
+	for _, sct := range report.SCTs {
+		assert.NoError(t, sct.Err)
+		assert.True(t, sct.Valid())
+	}
+	assert.Equal(t, "Test Log 0", report.SCTs[0].Log.Description)
+	assert.True(t, report.Policy.Compliant)
+	assert.Equal(t, 2, report.Policy.Operators)
+}
+
+// githubLogKeys are the public keys of the logs that issued the SCTs of the github.com certificate, the log ID of a log is the SHA-256 hash of its key
+var githubLogKeys = map[string]string{
+	"zxFW7tUufK/zh1vZaS6b6RpxZ0qwF+ysAdJbd87MOwg=": "MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEguLOkEA/gQ7f6uEgK14uMFRGgblY7a+9/zanngtfamuRpcGY4fLN6xcgcMoqEuZUeFDc/239HKe2Oh/5JqkbvQ==",
+	"ouMK5EXvva2bfjjtR2d3U9eCW4SU1yteGyzEuVCkR+c=": "MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEKeBpU9ejnCaIZeX39EsdF5vDvf8ELTHdLPxikl4y4EiROIQfS4ercpnMHfh8+TxYVFs3ELGr2IP7hPGVPy4vHA==",
+	"TnWjJ1yaEMM4W2zU3z9S6x3w4I4bjWnAsfpksWKaOd8=": "MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEIIKh+WdoqOTblJji4WiH5AltIDUzODyvFKrXCBjw/Rab0/98J4LUh7dOJEY7+66+yCNSICuqRAX+VPnV8R1Fmg==",
+}
+
+func TestCheckVerifiesGithubSCTs(t *testing.T) {
+	raw, err := os.ReadFile(filepath.Join("..", "..", "..", "testing", "pki", "github.com-chain.pem"))
+	assert.NoError(t, err)
+	certificates, err := certificate.ParsePEMCertificate(raw)
+	assert.NoError(t, err)
+
+	logs := &LogList{}
+	for logID, key := range githubLogKeys {
+		ctLog, err := parseLog("Operator "+logID, logJSON{Description: logID, LogID: logID, Key: key})
+		assert.NoError(t, err)
+		logs.Logs = append(logs.Logs, ctLog)
+	}
+
+	// the SCTs are signed by the logs over the precertificate, the github.com certificate without its SCT list extension
+	report, err := Check(certificates[0], certificates[1], logs, DefaultPolicy())
+	assert.NoError(t, err)
+
+	assert.Len(t, report.SCTs, 3)
+	for _, sct := range report.SCTs {
+		assert.NoError(t, sct.Err)
+		assert.True(t, sct.Valid())
+		assert.Equal(t, base64.StdEncoding.EncodeToString(sct.SCT.LogID), sct.Log.Description)
+	}
+	assert.Equal(t, 3, report.Policy.ValidSCTs)
+
+	// the SCTs are bound to the key of the issuer of the precertificate
+	report, err = Check(certificates[0], certificates[2], logs, DefaultPolicy())
+	assert.NoError(t, err)
+	for _, sct := range report.SCTs {
+		assert.ErrorContains(t, sct.Err, "invalid SCT signature")
+	}
+}
+
+func TestCheckVerifiesGithubSCTsWithBundledLogList(t *testing.T) {
+	logs := BundledLogList()
+	if len(logs.Logs) == 0 {
+		t.Skip("the bundled CT log list is empty, run make ctlogs to bundle the Google v3 log list")
+	}
+
+	raw, err := os.ReadFile(filepath.Join("..", "..", "..", "testing", "pki", "github.com-chain.pem"))
+	assert.NoError(t, err)
+	certificates, err := certificate.ParsePEMCertificate(raw)
+	assert.NoError(t, err)
+
+	report, err := Check(certificates[0], certificates[1], logs, DefaultPolicy())
+	assert.NoError(t, err)
+
+	assert.Len(t, report.SCTs, 3)
+	for _, sct := range report.SCTs {
+		assert.NoError(t, sct.Err)
+		assert.True(t, sct.Valid())
+	}
+	assert.Equal(t, 3, report.Policy.ValidSCTs)
+}
+
+func TestCheckRequiresDistinctOperators(t *testing.T) {
+	testPKI := newTestPKI(t)
+
+	report, err := Check(testPKI.leaf, testPKI.issuer, testPKI.logs, Policy{MinSCTs: 2, MinOperators: 3})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, report.Policy.ValidSCTs)
+	assert.False(t, report.Policy.Compliant)
+}
+
+func TestCheckInvalidSignature(t *testing.T) {
+	testPKI := newTestPKI(t)
+
+	// an SCT signed for another issuer key must not verify
+	report, err := Check(testPKI.leaf, testPKI.leaf, testPKI.logs, DefaultPolicy())
+	assert.NoError(t, err)
+
+	for _, sct := range report.SCTs {
+		assert.ErrorContains(t, sct.Err, "invalid SCT signature")
+	}
+	assert.Equal(t, 0, report.Policy.ValidSCTs)
+}
+
+func TestCheckMissingIssuer(t *testing.T) {
+	testPKI := newTestPKI(t)
+
+	report, err := Check(testPKI.leaf, nil, testPKI.logs, DefaultPolicy())
+	assert.NoError(t, err)
+
+	for _, sct := range report.SCTs {
+		assert.ErrorIs(t, sct.Err, ErrMissingIssuer)
+	}
+}
+
+type testPKI struct {
+	issuer *x509.Certificate
+	leaf   *x509.Certificate
+	logs   *LogList
+}
+
+// newTestPKI creates an issuer and a leaf certificate with SCTs from two generated logs with distinct operators
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	issuerTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	issuerDER, err := x509.CreateCertificate(rand.Reader, issuerTemplate, issuerTemplate, &issuerKey.PublicKey, issuerKey)
+	assert.NoError(t, err)
+	issuer, err := x509.ParseCertificate(issuerDER)
+	assert.NoError(t, err)
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test.example.com"},
+		DNSNames:     []string{"test.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	precertDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, issuer, &leafKey.PublicKey, issuerKey)
+	assert.NoError(t, err)
+	precert, err := x509.ParseCertificate(precertDER)
+	assert.NoError(t, err)
+
+	logs := &LogList{}
+	var sctList cryptobyte.Builder
+	sctList.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		for i := range 2 {
+			logKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			assert.NoError(t, err)
+			logKeyDER, err := x509.MarshalPKIXPublicKey(&logKey.PublicKey)
+			assert.NoError(t, err)
+
+			parsed, err := ParseLogList(fmt.Appendf(nil, `{"operators":[{"name":"Operator %d","logs":[{"description":"Test Log %d","key":"%s"}]}]}`, i, i, base64.StdEncoding.EncodeToString(logKeyDER)))
+			assert.NoError(t, err)
+			logs.Logs = append(logs.Logs, parsed.Logs...)
+
+			b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+				b.AddBytes(signSCT(t, logKey, parsed.Logs[0].LogID, precert, issuer))
+			})
+		}
+	})
+	sctListBytes, err := sctList.Bytes()
+	assert.NoError(t, err)
+	extensionValue, err := asn1.Marshal(sctListBytes)
+	assert.NoError(t, err)
+
+	leafTemplate.ExtraExtensions = []pkix.Extension{{Id: oidExtensionSCTList, Value: extensionValue}}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, issuer, &leafKey.PublicKey, issuerKey)
+	assert.NoError(t, err)
+	leaf, err := x509.ParseCertificate(leafDER)
+	assert.NoError(t, err)
+
+	return &testPKI{
+		issuer: issuer,
+		leaf:   leaf,
+		logs:   logs,
+	}
+}
+
+func signSCT(t *testing.T, logKey *ecdsa.PrivateKey, logID []byte, precert, issuer *x509.Certificate) []byte {
+	t.Helper()
+
+	timestamp := uint64(time.Now().UnixMilli())
+	issuerKeyHash := sha256.Sum256(issuer.RawSubjectPublicKeyInfo)
+
+	var signed cryptobyte.Builder
+	signed.AddUint8(0)
+	signed.AddUint8(signatureTypeTimestamp)
+	signed.AddUint64(timestamp)
+	signed.AddUint16(logEntryTypePrecertEntry)
+	signed.AddBytes(issuerKeyHash[:])
+	signed.AddUint24LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(precert.RawTBSCertificate)
+	})
+	signed.AddUint16(0)
+	signedData, err := signed.Bytes()
+	assert.NoError(t, err)
+
+	digest := sha256.Sum256(signedData)
+	signature, err := ecdsa.SignASN1(rand.Reader, logKey, digest[:])
+	assert.NoError(t, err)
+
+	var sct cryptobyte.Builder
+	sct.AddUint8(0)
+	sct.AddBytes(logID)
+	sct.AddUint64(timestamp)
+	sct.AddUint16(0)
+	sct.AddUint8(hashAlgorithmSHA256)
+	sct.AddUint8(signatureAlgorithmECDSA)
+	sct.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(signature)
+	})
+	sctBytes, err := sct.Bytes()
+	assert.NoError(t, err)
+
+	return sctBytes
+}
@@ -0,0 +1,212 @@
+package ocsp
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"golang.org/x/crypto/ocsp"
+)
+
+// maxRequestSize limits the body of an OCSP request, a request for one certificate with a nonce is around 150 bytes
+const maxRequestSize = 10 * 1024
+
+// ResponderIssuer is a CA the responder answers for, the responses are signed by a delegated responder certificate issued by the CA
+type ResponderIssuer struct {
+	Certificate          *x509.Certificate
+	ResponderCertificate *x509.Certificate
+	ResponderKey         crypto.Signer
+	// CRL is the name of the stored CRL of the CA, the common name of the CA when it is empty
+	CRL string
+}
+
+// Responder is an RFC 6960 OCSP responder that answers from the revoked certificates of the stored CRLs of its issuers.
+// A serial number that is not on the CRL is good, the CRL cannot tell certificates that were never issued apart.
+type Responder struct {
+	repository crl.Repository
+	issuers    []ResponderIssuer
+	now        func() time.Time
+}
+
+// NewResponder verifies that each responder certificate is issued by its CA, may sign OCSP responses and matches its key
+func NewResponder(repository crl.Repository, issuers []ResponderIssuer) (*Responder, error) {
+	if len(issuers) == 0 {
+		return nil, errors.New("the OCSP responder needs at least one issuer")
+	}
+
+	configured := make([]ResponderIssuer, 0, len(issuers))
+	for _, issuer := range issuers {
+		if issuer.Certificate == nil || issuer.ResponderCertificate == nil || issuer.ResponderKey == nil {
+			return nil, errors.New("an issuer of the OCSP responder needs a CA certificate, a responder certificate and a responder key")
+		}
+		name := issuer.Certificate.Subject.CommonName
+		if err := issuer.ResponderCertificate.CheckSignatureFrom(issuer.Certificate); err != nil {
+			return nil, errors.Join(fmt.Errorf("the responder certificate %s is not issued by %s", issuer.ResponderCertificate.Subject.CommonName, name), err)
+		}
+		if !hasOCSPSigning(issuer.ResponderCertificate) {
+			return nil, fmt.Errorf("the responder certificate %s does not have the OCSP signing extended key usage", issuer.ResponderCertificate.Subject.CommonName)
+		}
+		publicKey, ok := issuer.ResponderKey.Public().(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !publicKey.Equal(issuer.ResponderCertificate.PublicKey) {
+			return nil, fmt.Errorf("the responder key does not belong to the responder certificate %s", issuer.ResponderCertificate.Subject.CommonName)
+		}
+		if _, _, err := signatureAlgorithm(issuer.ResponderKey.Public()); err != nil {
+			return nil, err
+		}
+
+		if issuer.CRL == "" {
+			issuer.CRL = name
+		}
+		configured = append(configured, issuer)
+	}
+
+	return &Responder{repository: repository, issuers: configured, now: time.Now}, nil
+}
+
+func hasOCSPSigning(certificate *x509.Certificate) bool {
+	for _, usage := range certificate.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageOCSPSigning {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeHTTP answers OCSP requests sent with POST and with GET, a GET request is the base64 and URL encoded request appended to the URL of the responder, RFC 6960 appendix A.1
+func (r *Responder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var request []byte
+	switch req.Method {
+	case http.MethodPost:
+		var err error
+		request, err = io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+		if err != nil {
+			http.Error(w, "could not read the OCSP request", http.StatusBadRequest)
+			return
+		}
+	case http.MethodGet:
+		// the request is the last path segment, the escaped path keeps an encoded / of the base64 request in that segment
+		path := req.URL.EscapedPath()
+		encoded, err := url.PathUnescape(path[strings.LastIndex(path, "/")+1:])
+		if err != nil {
+			writeResponse(w, ocsp.MalformedRequestErrorResponse)
+			return
+		}
+		request, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			writeResponse(w, ocsp.MalformedRequestErrorResponse)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "OCSP requests are sent with GET or POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response, nextUpdate := r.respond(req.Context(), request)
+	// a response without a nonce is the same for every client until the next update of the CRL, RFC 5019 section 6.2
+	if !nextUpdate.IsZero() {
+		if maxAge := int(nextUpdate.Sub(r.now()).Seconds()); maxAge > 0 {
+			w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(maxAge)+", public, no-transform, must-revalidate")
+		}
+	}
+	writeResponse(w, response)
+}
+
+func writeResponse(w http.ResponseWriter, response []byte) {
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	_, _ = w.Write(response)
+}
+
+// Respond answers a DER encoded OCSP request with a DER encoded OCSP response, errors are answered with an OCSP error response
+func (r *Responder) Respond(ctx context.Context, rawRequest []byte) []byte {
+	response, _ := r.respond(ctx, rawRequest)
+	return response
+}
+
+// respond returns the response and, for a response that may be cached, the next update of the CRL it is based on
+func (r *Responder) respond(ctx context.Context, rawRequest []byte) ([]byte, time.Time) {
+	request, err := ocsp.ParseRequest(rawRequest)
+	if err != nil {
+		log.Printf("could not parse OCSP request, err: %v", err)
+		return ocsp.MalformedRequestErrorResponse, time.Time{}
+	}
+	nonce, err := parseNonce(rawRequest)
+	if err != nil {
+		log.Printf("could not parse the nonce of the OCSP request for serialnumber: %s, err: %v", request.SerialNumber, err)
+		return ocsp.MalformedRequestErrorResponse, time.Time{}
+	}
+
+	issuer, ok := r.issuer(request)
+	if !ok {
+		log.Printf("OCSP request for serialnumber: %s of an issuer that is not configured", request.SerialNumber)
+		return ocsp.UnauthorizedErrorResponse, time.Time{}
+	}
+
+	now := r.now()
+	revocationList, err := r.repository.Find(ctx, issuer.CRL)
+	if err != nil || revocationList == nil || revocationList.ID == 0 {
+		log.Printf("the CRL %s is not stored, err: %v", issuer.CRL, err)
+		return ocsp.TryLaterErrorResponse, time.Time{}
+	}
+	if now.After(revocationList.NextUpdate) {
+		log.Printf("the CRL %s expired at %s, OCSP requests are answered after it is refreshed", issuer.CRL, revocationList.NextUpdate.Format(time.RFC3339))
+		return ocsp.TryLaterErrorResponse, time.Time{}
+	}
+
+	template := ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: request.SerialNumber,
+		ThisUpdate:   revocationList.ThisUpdate,
+		NextUpdate:   revocationList.NextUpdate,
+		IssuerHash:   request.HashAlgorithm,
+	}
+	revoked, err := crl.FindRevocation(ctx, r.repository, revocationList.ID, request.SerialNumber.String())
+	if err != nil {
+		log.Printf("could not find the revoked certificate with serialnumber: %s, err: %v", request.SerialNumber, err)
+		return ocsp.InternalErrorErrorResponse, time.Time{}
+	}
+	if revoked != nil {
+		template.Status = ocsp.Revoked
+		template.RevokedAt = revoked.RevocationDate
+		template.RevocationReason, err = crl.ParseReasonCode(revoked.RevocationReason.String())
+		if err != nil {
+			template.RevocationReason = ocsp.Unspecified
+		}
+	}
+
+	response, err := createResponse(issuer, template, nonce, now)
+	if err != nil {
+		log.Printf("could not create the OCSP response for serialnumber: %s, err: %v", request.SerialNumber, err)
+		return ocsp.InternalErrorErrorResponse, time.Time{}
+	}
+	if nonce != nil {
+		return response, time.Time{}
+	}
+	return response, revocationList.NextUpdate
+}
+
+// issuer returns the configured issuer with the name and key hashes of the request
+func (r *Responder) issuer(request *ocsp.Request) (ResponderIssuer, bool) {
+	for _, issuer := range r.issuers {
+		nameHash, keyHash, err := issuerHashes(issuer.Certificate, request.HashAlgorithm)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(nameHash, request.IssuerNameHash) && bytes.Equal(keyHash, request.IssuerKeyHash) {
+			return issuer, true
+		}
+	}
+	return ResponderIssuer{}, false
+}
@@ -0,0 +1,334 @@
+package ocsp
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pimg/certguard/internal/adapter/memory"
+	"github.com/pimg/certguard/pkg/domain/crl"
+	"github.com/pimg/certguard/pkg/testpki"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+)
+
+// newTestResponder generates a PKI with a good and two revoked leaf certificates and stores the base CRL of the intermediate CA
+func newTestResponder(t *testing.T) (*testpki.PKI, *Responder, *httptest.Server) {
+	t.Helper()
+	pki, err := testpki.Generate(testpki.Options{
+		Leaves: []string{"good.certguard.test", "revoked.certguard.test", "superseded.certguard.test"},
+		Revocations: []testpki.Revocation{
+			{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber + 1), ReasonCode: ocsp.KeyCompromise},
+			{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber + 2), ReasonCode: ocsp.Superseded},
+		},
+	})
+	assert.NoError(t, err)
+
+	storage, err := crl.NewStorage(memory.NewMemoryStorage(), t.TempDir(), t.TempDir())
+	assert.NoError(t, err)
+	revocationList, err := x509.ParseRevocationList(pki.BaseCRL)
+	assert.NoError(t, err)
+	assert.NoError(t, crl.Process(context.Background(), nil, revocationList, storage))
+
+	responder, err := NewResponder(storage.Repository, []ResponderIssuer{{
+		Certificate:          pki.Intermediate.Certificate,
+		ResponderCertificate: pki.Responder.Certificate,
+		ResponderKey:         pki.Responder.Key,
+	}})
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(responder)
+	t.Cleanup(server.Close)
+	return pki, responder, server
+}
+
+func post(t *testing.T, server *httptest.Server, request []byte) []byte {
+	t.Helper()
+	response, err := http.Post(server.URL, "application/ocsp-request", bytes.NewReader(request))
+	assert.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, "application/ocsp-response", response.Header.Get("Content-Type"))
+	raw, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	return raw
+}
+
+func createRequest(t *testing.T, cert, issuer *x509.Certificate) []byte {
+	t.Helper()
+	request, err := ocsp.CreateRequest(cert, issuer, &ocsp.RequestOptions{Hash: crypto.SHA256})
+	assert.NoError(t, err)
+	return request
+}
+
+// withNonce adds a nonce extension to a request of golang.org/x/crypto/ocsp, which cannot create requests with extensions
+func withNonce(t *testing.T, rawRequest, nonce []byte) []byte {
+	t.Helper()
+	var request ocspRequest
+	_, err := asn1.Unmarshal(rawRequest, &request)
+	assert.NoError(t, err)
+
+	value, err := asn1.Marshal(nonce)
+	assert.NoError(t, err)
+	request.TBSRequest.RequestExtensions = []pkix.Extension{{Id: oidNonce, Value: value}}
+	encoded, err := asn1.Marshal(request)
+	assert.NoError(t, err)
+	return encoded
+}
+
+// responseNonce returns the nonce of the response extensions, golang.org/x/crypto/ocsp does not parse them
+func responseNonce(t *testing.T, rawResponse []byte) []byte {
+	t.Helper()
+	var response responseASN1
+	_, err := asn1.Unmarshal(rawResponse, &response)
+	assert.NoError(t, err)
+	var basic basicResponse
+	_, err = asn1.Unmarshal(response.Response.Response, &basic)
+	assert.NoError(t, err)
+	var data responseData
+	_, err = asn1.Unmarshal(basic.TBSResponseData.FullBytes, &data)
+	assert.NoError(t, err)
+
+	for _, extension := range data.ResponseExtensions {
+		if extension.Id.Equal(oidNonce) {
+			var nonce []byte
+			_, err := asn1.Unmarshal(extension.Value, &nonce)
+			assert.NoError(t, err)
+			return nonce
+		}
+	}
+	return nil
+}
+
+func TestResponder(t *testing.T) {
+	pki, responder, server := newTestResponder(t)
+	intermediate := pki.Intermediate.Certificate
+	stored, err := responder.repository.Find(context.Background(), intermediate.Subject.CommonName)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		leaf   *x509.Certificate
+		status int
+		reason int
+	}{
+		{"good", pki.Leaves[0].Certificate, ocsp.Good, 0},
+		{"revoked", pki.Leaves[1].Certificate, ocsp.Revoked, ocsp.KeyCompromise},
+		{"superseded", pki.Leaves[2].Certificate, ocsp.Revoked, ocsp.Superseded},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, err := ocsp.ParseResponseForCert(post(t, server, createRequest(t, test.leaf, intermediate)), test.leaf, intermediate)
+			assert.NoError(t, err)
+			assert.Equal(t, test.status, response.Status)
+			assert.Equal(t, test.reason, response.RevocationReason)
+			assert.Equal(t, pki.Responder.Certificate.Raw, response.Certificate.Raw)
+			// the update times are those of the stored CRL
+			assert.True(t, stored.ThisUpdate.Equal(response.ThisUpdate))
+			assert.True(t, stored.NextUpdate.Equal(response.NextUpdate))
+		})
+	}
+}
+
+func TestResponderSerialNumberOnTwoCRLs(t *testing.T) {
+	ctx := context.Background()
+	pki, err := testpki.Generate(testpki.Options{
+		Leaves:      []string{"good.certguard.test", "revoked.certguard.test"},
+		Revocations: []testpki.Revocation{{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber + 1), ReasonCode: ocsp.CessationOfOperation}},
+	})
+	assert.NoError(t, err)
+	// another CA issues the same serial numbers and revokes both, its CRL is stored first
+	other, err := testpki.Generate(testpki.Options{
+		Name: "Other",
+		Revocations: []testpki.Revocation{
+			{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber), ReasonCode: ocsp.KeyCompromise},
+			{SerialNumber: big.NewInt(testpki.FirstLeafSerialNumber + 1), ReasonCode: ocsp.KeyCompromise},
+		},
+	})
+	assert.NoError(t, err)
+
+	storage, err := crl.NewStorage(memory.NewMemoryStorage(), t.TempDir(), t.TempDir())
+	assert.NoError(t, err)
+	for _, der := range [][]byte{other.BaseCRL, pki.BaseCRL} {
+		revocationList, err := x509.ParseRevocationList(der)
+		assert.NoError(t, err)
+		assert.NoError(t, crl.Process(ctx, nil, revocationList, storage))
+	}
+
+	responder, err := NewResponder(storage.Repository, []ResponderIssuer{{
+		Certificate:          pki.Intermediate.Certificate,
+		ResponderCertificate: pki.Responder.Certificate,
+		ResponderKey:         pki.Responder.Key,
+	}})
+	assert.NoError(t, err)
+
+	intermediate := pki.Intermediate.Certificate
+	good := pki.Leaves[0].Certificate
+	response, err := ocsp.ParseResponseForCert(responder.Respond(ctx, createRequest(t, good, intermediate)), good, intermediate)
+	assert.NoError(t, err)
+	assert.Equal(t, ocsp.Good, response.Status, "a revocation by another CA does not revoke the certificate")
+
+	revoked := pki.Leaves[1].Certificate
+	response, err = ocsp.ParseResponseForCert(responder.Respond(ctx, createRequest(t, revoked, intermediate)), revoked, intermediate)
+	assert.NoError(t, err)
+	assert.Equal(t, ocsp.Revoked, response.Status)
+	assert.Equal(t, ocsp.CessationOfOperation, response.RevocationReason)
+}
+
+func TestResponderRefreshedCRL(t *testing.T) {
+	ctx := context.Background()
+	pki, responder, _ := newTestResponder(t)
+	intermediate := pki.Intermediate.Certificate
+	storage, err := crl.NewStorage(responder.repository, t.TempDir(), t.TempDir())
+	assert.NoError(t, err)
+	stored, err := responder.repository.Find(ctx, intermediate.Subject.CommonName)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		thisUpdate time.Time
+	}{
+		// the same version is stored again, it replaces the revoked certificates of the stored version
+		{"same version", stored.ThisUpdate},
+		{"next version", stored.ThisUpdate.Add(time.Hour)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the refreshed CRL no longer revokes the first revoked leaf and changes the reason of the second
+			der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+				Number:     big.NewInt(2),
+				ThisUpdate: test.thisUpdate,
+				NextUpdate: test.thisUpdate.AddDate(0, 0, 7),
+				RevokedCertificateEntries: []x509.RevocationListEntry{
+					{SerialNumber: pki.Leaves[2].Certificate.SerialNumber, RevocationTime: test.thisUpdate, ReasonCode: ocsp.CessationOfOperation},
+				},
+			}, intermediate, pki.Intermediate.Key)
+			assert.NoError(t, err)
+			revocationList, err := x509.ParseRevocationList(der)
+			assert.NoError(t, err)
+			assert.NoError(t, crl.Process(ctx, nil, revocationList, storage))
+
+			released := pki.Leaves[1].Certificate
+			response, err := ocsp.ParseResponseForCert(responder.Respond(ctx, createRequest(t, released, intermediate)), released, intermediate)
+			assert.NoError(t, err)
+			assert.Equal(t, ocsp.Good, response.Status)
+
+			superseded := pki.Leaves[2].Certificate
+			response, err = ocsp.ParseResponseForCert(responder.Respond(ctx, createRequest(t, superseded, intermediate)), superseded, intermediate)
+			assert.NoError(t, err)
+			assert.Equal(t, ocsp.Revoked, response.Status)
+			assert.Equal(t, ocsp.CessationOfOperation, response.RevocationReason)
+		})
+	}
+}
+
+func TestResponderGet(t *testing.T) {
+	pki, _, server := newTestResponder(t)
+	leaf, intermediate := pki.Leaves[1].Certificate, pki.Intermediate.Certificate
+
+	encoded := url.PathEscape(base64.StdEncoding.EncodeToString(createRequest(t, leaf, intermediate)))
+	httpResponse, err := http.Get(server.URL + "/ocsp/" + encoded)
+	assert.NoError(t, err)
+	defer httpResponse.Body.Close()
+	assert.Contains(t, httpResponse.Header.Get("Cache-Control"), "max-age=")
+	raw, err := io.ReadAll(httpResponse.Body)
+	assert.NoError(t, err)
+
+	response, err := ocsp.ParseResponseForCert(raw, leaf, intermediate)
+	assert.NoError(t, err)
+	assert.Equal(t, ocsp.Revoked, response.Status)
+
+	httpResponse, err = http.Head(server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, httpResponse.Body.Close())
+	assert.Equal(t, http.StatusMethodNotAllowed, httpResponse.StatusCode)
+}
+
+func TestResponderNonce(t *testing.T) {
+	pki, _, server := newTestResponder(t)
+	leaf, intermediate := pki.Leaves[0].Certificate, pki.Intermediate.Certificate
+	nonce := bytes.Repeat([]byte{0x5a}, 32)
+
+	raw := post(t, server, withNonce(t, createRequest(t, leaf, intermediate), nonce))
+	response, err := ocsp.ParseResponseForCert(raw, leaf, intermediate)
+	assert.NoError(t, err)
+	assert.Equal(t, ocsp.Good, response.Status)
+	assert.Equal(t, nonce, responseNonce(t, raw))
+
+	assert.Nil(t, responseNonce(t, post(t, server, createRequest(t, leaf, intermediate))), "a request without a nonce gets a response without a nonce")
+	assert.Equal(t, ocsp.MalformedRequestErrorResponse, post(t, server, withNonce(t, createRequest(t, leaf, intermediate), make([]byte, 33))))
+}
+
+func TestResponderErrors(t *testing.T) {
+	pki, responder, server := newTestResponder(t)
+	leaf, intermediate := pki.Leaves[0].Certificate, pki.Intermediate.Certificate
+
+	assert.Equal(t, ocsp.MalformedRequestErrorResponse, post(t, server, []byte("malformed")))
+	// the intermediate CA is issued by the root CA, which the responder does not answer for
+	assert.Equal(t, ocsp.UnauthorizedErrorResponse, post(t, server, createRequest(t, intermediate, pki.Root.Certificate)))
+
+	responder.now = func() time.Time { return time.Now().AddDate(0, 0, 8) }
+	assert.Equal(t, ocsp.TryLaterErrorResponse, responder.Respond(context.Background(), createRequest(t, leaf, intermediate)), "the CRL expired")
+
+	empty, err := NewResponder(memory.NewMemoryStorage(), []ResponderIssuer{{
+		Certificate:          intermediate,
+		ResponderCertificate: pki.Responder.Certificate,
+		ResponderKey:         pki.Responder.Key,
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, ocsp.TryLaterErrorResponse, empty.Respond(context.Background(), createRequest(t, leaf, intermediate)), "the CRL is not stored")
+}
+
+func TestNewResponder(t *testing.T) {
+	pki, err := testpki.Generate(testpki.Options{})
+	assert.NoError(t, err)
+	repository := memory.NewMemoryStorage()
+
+	_, err = NewResponder(repository, nil)
+	assert.Error(t, err)
+
+	_, err = NewResponder(repository, []ResponderIssuer{{Certificate: pki.Intermediate.Certificate, ResponderCertificate: pki.Leaves[0].Certificate, ResponderKey: pki.Leaves[0].Key}})
+	assert.ErrorContains(t, err, "OCSP signing")
+
+	_, err = NewResponder(repository, []ResponderIssuer{{Certificate: pki.Root.Certificate, ResponderCertificate: pki.Responder.Certificate, ResponderKey: pki.Responder.Key}})
+	assert.ErrorContains(t, err, "is not issued by")
+
+	_, err = NewResponder(repository, []ResponderIssuer{{Certificate: pki.Intermediate.Certificate, ResponderCertificate: pki.Responder.Certificate, ResponderKey: pki.Intermediate.Key}})
+	assert.ErrorContains(t, err, "does not belong to")
+
+	responder, err := NewResponder(repository, []ResponderIssuer{{Certificate: pki.Intermediate.Certificate, ResponderCertificate: pki.Responder.Certificate, ResponderKey: pki.Responder.Key}})
+	assert.NoError(t, err)
+	assert.Equal(t, pki.Intermediate.Certificate.Subject.CommonName, responder.issuers[0].CRL)
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	pki, err := testpki.Generate(testpki.Options{})
+	assert.NoError(t, err)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(pki.Responder.Key)
+	assert.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(pki.Responder.Key)
+	assert.NoError(t, err)
+
+	for _, der := range [][]byte{pkcs8, sec1} {
+		key, err := ParsePrivateKey(der)
+		assert.NoError(t, err)
+		assert.True(t, pki.Responder.Key.Equal(key))
+	}
+
+	_, err = ParsePrivateKey([]byte("not a key"))
+	assert.Error(t, err)
+}
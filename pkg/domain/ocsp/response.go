@@ -0,0 +1,254 @@
+package ocsp
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// golang.org/x/crypto/ocsp does not parse request extensions or marshal response extensions, the responder encodes
+// the requests and responses it needs for nonces itself, RFC 6960 section 4
+
+var (
+	// oidNonce is the nonce extension of a request that the response echoes, RFC 8954
+	oidNonce     = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2}
+	oidOCSPBasic = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+
+	hashOIDs = map[crypto.Hash]asn1.ObjectIdentifier{
+		crypto.SHA1:   {1, 3, 14, 3, 2, 26},
+		crypto.SHA256: {2, 16, 840, 1, 101, 3, 4, 2, 1},
+		crypto.SHA384: {2, 16, 840, 1, 101, 3, 4, 2, 2},
+		crypto.SHA512: {2, 16, 840, 1, 101, 3, 4, 2, 3},
+	}
+)
+
+// maxNonceSize is the longest nonce a responder has to accept, RFC 8954 section 2.1
+const maxNonceSize = 32
+
+type ocspRequest struct {
+	TBSRequest        tbsRequest
+	OptionalSignature asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type tbsRequest struct {
+	Version           int           `asn1:"explicit,tag:0,default:0,optional"`
+	RequestorName     asn1.RawValue `asn1:"explicit,tag:1,optional"`
+	RequestList       []asn1.RawValue
+	RequestExtensions []pkix.Extension `asn1:"explicit,tag:2,optional"`
+}
+
+type responseASN1 struct {
+	Status   asn1.Enumerated
+	Response responseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type responseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+type basicResponse struct {
+	TBSResponseData    asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type responseData struct {
+	Version            int `asn1:"optional,default:0,explicit,tag:0"`
+	RawResponderID     asn1.RawValue
+	ProducedAt         time.Time `asn1:"generalized"`
+	Responses          []singleResponse
+	ResponseExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type certID struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+type singleResponse struct {
+	CertID     certID
+	Good       asn1.Flag   `asn1:"tag:0,optional"`
+	Revoked    revokedInfo `asn1:"tag:1,optional"`
+	Unknown    asn1.Flag   `asn1:"tag:2,optional"`
+	ThisUpdate time.Time   `asn1:"generalized"`
+	NextUpdate time.Time   `asn1:"generalized,explicit,tag:0,optional"`
+}
+
+type revokedInfo struct {
+	RevocationTime time.Time       `asn1:"generalized"`
+	Reason         asn1.Enumerated `asn1:"explicit,tag:0,optional"`
+}
+
+// parseNonce returns the nonce extension of a DER encoded request, nil when the request has no nonce
+func parseNonce(rawRequest []byte) (*pkix.Extension, error) {
+	var request ocspRequest
+	if _, err := asn1.Unmarshal(rawRequest, &request); err != nil {
+		return nil, err
+	}
+
+	for _, extension := range request.TBSRequest.RequestExtensions {
+		if !extension.Id.Equal(oidNonce) {
+			continue
+		}
+		var nonce []byte
+		if rest, err := asn1.Unmarshal(extension.Value, &nonce); err != nil || len(rest) > 0 {
+			return nil, errors.New("the nonce is not an octet string")
+		}
+		if len(nonce) == 0 || len(nonce) > maxNonceSize {
+			return nil, fmt.Errorf("the nonce is %d bytes, a nonce is 1 to %d bytes", len(nonce), maxNonceSize)
+		}
+		return &pkix.Extension{Id: oidNonce, Value: extension.Value}, nil
+	}
+	return nil, nil
+}
+
+// issuerHashes returns the hash of the subject and of the public key of the issuer that identify it in a request
+func issuerHashes(issuer *x509.Certificate, hash crypto.Hash) ([]byte, []byte, error) {
+	if _, ok := hashOIDs[hash]; !ok || !hash.Available() {
+		return nil, nil, fmt.Errorf("unsupported issuer hash algorithm %v", hash)
+	}
+
+	var publicKeyInfo struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &publicKeyInfo); err != nil {
+		return nil, nil, err
+	}
+
+	h := hash.New()
+	h.Write(issuer.RawSubject)
+	nameHash := h.Sum(nil)
+	h.Reset()
+	h.Write(publicKeyInfo.PublicKey.RightAlign())
+	return nameHash, h.Sum(nil), nil
+}
+
+// signatureAlgorithm returns the hash and signature algorithm of a responder key, the algorithms golang.org/x/crypto/ocsp verifies
+func signatureAlgorithm(publicKey crypto.PublicKey) (crypto.Hash, pkix.AlgorithmIdentifier, error) {
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		return crypto.SHA256, pkix.AlgorithmIdentifier{Algorithm: oidSHA256WithRSA, Parameters: asn1.NullRawValue}, nil
+	case *ecdsa.PublicKey:
+		switch publicKey.Curve {
+		case elliptic.P384():
+			return crypto.SHA384, pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA384}, nil
+		case elliptic.P521():
+			return crypto.SHA512, pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA512}, nil
+		default:
+			return crypto.SHA256, pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}, nil
+		}
+	default:
+		return 0, pkix.AlgorithmIdentifier{}, fmt.Errorf("unsupported responder key %T, use an RSA or ECDSA key", publicKey)
+	}
+}
+
+// createResponse signs a basic OCSP response for the template with the responder key of the issuer, a nonce is echoed in the response extensions
+func createResponse(issuer ResponderIssuer, template ocsp.Response, nonce *pkix.Extension, now time.Time) ([]byte, error) {
+	nameHash, keyHash, err := issuerHashes(issuer.Certificate, template.IssuerHash)
+	if err != nil {
+		return nil, err
+	}
+
+	single := singleResponse{
+		CertID: certID{
+			HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: hashOIDs[template.IssuerHash], Parameters: asn1.NullRawValue},
+			NameHash:      nameHash,
+			IssuerKeyHash: keyHash,
+			SerialNumber:  template.SerialNumber,
+		},
+		ThisUpdate: template.ThisUpdate.UTC(),
+		NextUpdate: template.NextUpdate.UTC(),
+	}
+	switch template.Status {
+	case ocsp.Good:
+		single.Good = true
+	case ocsp.Revoked:
+		single.Revoked = revokedInfo{RevocationTime: template.RevokedAt.UTC(), Reason: asn1.Enumerated(template.RevocationReason)}
+	default:
+		single.Unknown = true
+	}
+
+	data := responseData{
+		// the responder is identified by the subject of its certificate, the certificate is included in the response
+		RawResponderID: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 1, IsCompound: true, Bytes: issuer.ResponderCertificate.RawSubject},
+		ProducedAt:     now.UTC().Truncate(time.Second),
+		Responses:      []singleResponse{single},
+	}
+	if nonce != nil {
+		data.ResponseExtensions = []pkix.Extension{*nonce}
+	}
+	tbs, err := asn1.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, algorithm, err := signatureAlgorithm(issuer.ResponderKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write(tbs)
+	signature, err := issuer.ResponderKey.Sign(rand.Reader, h.Sum(nil), hash)
+	if err != nil {
+		return nil, err
+	}
+
+	basic, err := asn1.Marshal(basicResponse{
+		TBSResponseData:    asn1.RawValue{FullBytes: tbs},
+		SignatureAlgorithm: algorithm,
+		Signature:          asn1.BitString{Bytes: signature, BitLength: 8 * len(signature)},
+		Certificates:       []asn1.RawValue{{FullBytes: issuer.ResponderCertificate.Raw}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(responseASN1{
+		Status:   asn1.Enumerated(ocsp.Success),
+		Response: responseBytes{ResponseType: oidOCSPBasic, Response: basic},
+	})
+}
+
+// ParsePrivateKey parses a PEM or DER encoded PKCS #8, SEC 1 EC or PKCS #1 RSA private key of a responder
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(data); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key %T", key)
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParseECPrivateKey(data); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(data); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("could not parse the private key, use a PKCS #8, EC or RSA private key")
+}
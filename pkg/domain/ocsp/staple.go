@@ -0,0 +1,92 @@
+package ocsp
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// clockSkew is tolerated between the clock of the OCSP responder and the local clock
+const clockSkew = 5 * time.Minute
+
+type StapleStatus string
+
+const (
+	// StapleMissing is used when the server did not staple an OCSP response
+	StapleMissing StapleStatus = "missing"
+	StapleValid   StapleStatus = "valid"
+	// StapleStale is used for responses outside of their thisUpdate and nextUpdate window
+	StapleStale StapleStatus = "stale"
+	// StapleInvalid is used for responses that cannot be parsed or have an invalid signature
+	StapleInvalid StapleStatus = "invalid"
+	// StapleMismatch is used when the stapled status differs from the status returned by the responder
+	StapleMismatch StapleStatus = "mismatch"
+)
+
+// Staple is the result of validating a stapled OCSP response
+type Staple struct {
+	Status   StapleStatus
+	Response *ocsp.Response
+	// Fresh is the response of the OCSP responder the staple is compared with, nil when the responder was not queried
+	Fresh    *ocsp.Response
+	Problems []string
+}
+
+// CheckStaple validates the freshness window of a stapled response, parseErr is the error returned when parsing and verifying the signature of the response
+func CheckStaple(raw []byte, response *ocsp.Response, parseErr error, now time.Time) *Staple {
+	if len(raw) == 0 {
+		return &Staple{Status: StapleMissing, Problems: []string{"server did not staple an OCSP response"}}
+	}
+
+	if parseErr != nil {
+		return &Staple{Status: StapleInvalid, Problems: []string{fmt.Sprintf("stapled OCSP response is invalid: %v", parseErr)}}
+	}
+
+	staple := &Staple{Status: StapleValid, Response: response}
+
+	if response.ThisUpdate.After(now.Add(clockSkew)) {
+		staple.Status = StapleInvalid
+		staple.Problems = append(staple.Problems, fmt.Sprintf("stapled OCSP response is not valid before %s", response.ThisUpdate.Format(time.RFC3339)))
+	}
+
+	switch {
+	case response.NextUpdate.IsZero():
+		staple.Problems = append(staple.Problems, "stapled OCSP response does not contain a nextUpdate, its freshness cannot be determined")
+	case now.After(response.NextUpdate.Add(clockSkew)):
+		staple.Status = StapleStale
+		staple.Problems = append(staple.Problems, fmt.Sprintf("stapled OCSP response expired at %s", response.NextUpdate.Format(time.RFC3339)))
+	}
+
+	return staple
+}
+
+// Compare compares the staple with a fresh response from the OCSP responder
+func (s *Staple) Compare(fresh *ocsp.Response) {
+	s.Fresh = fresh
+	if s.Response == nil || fresh == nil {
+		return
+	}
+
+	if s.Response.Status != fresh.Status {
+		s.Status = StapleMismatch
+		s.Problems = append(s.Problems, fmt.Sprintf("stapled status %s differs from the responder status %s", StatusName(s.Response.Status), StatusName(fresh.Status)))
+		return
+	}
+
+	if fresh.ThisUpdate.After(s.Response.ThisUpdate) {
+		s.Problems = append(s.Problems, fmt.Sprintf("the responder has a response that is %s newer than the staple", fresh.ThisUpdate.Sub(s.Response.ThisUpdate).Round(time.Minute)))
+	}
+}
+
+// StatusName returns the name of an OCSP certificate status
+func StatusName(status int) string {
+	switch status {
+	case ocsp.Good:
+		return "Good"
+	case ocsp.Revoked:
+		return "Revoked"
+	default:
+		return "Unknown"
+	}
+}
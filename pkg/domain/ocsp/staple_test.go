@@ -0,0 +1,61 @@
+package ocsp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestCheckStaple(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	raw := []byte{0x30}
+
+	tests := []struct {
+		name     string
+		raw      []byte
+		response *ocsp.Response
+		parseErr error
+		status   StapleStatus
+		problems int
+	}{
+		{"missing", nil, nil, nil, StapleMissing, 1},
+		{"invalid signature", raw, nil, errors.New("bad signature on basic response"), StapleInvalid, 1},
+		{"valid", raw, &ocsp.Response{ThisUpdate: now.Add(-time.Hour), NextUpdate: now.Add(time.Hour)}, nil, StapleValid, 0},
+		{"no next update", raw, &ocsp.Response{ThisUpdate: now.Add(-time.Hour)}, nil, StapleValid, 1},
+		{"expired", raw, &ocsp.Response{ThisUpdate: now.Add(-48 * time.Hour), NextUpdate: now.Add(-24 * time.Hour)}, nil, StapleStale, 1},
+		{"not yet valid", raw, &ocsp.Response{ThisUpdate: now.Add(time.Hour), NextUpdate: now.Add(48 * time.Hour)}, nil, StapleInvalid, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			staple := CheckStaple(test.raw, test.response, test.parseErr, now)
+			assert.Equal(t, test.status, staple.Status)
+			assert.Len(t, staple.Problems, test.problems)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	now := time.Now()
+	response := &ocsp.Response{Status: ocsp.Good, ThisUpdate: now.Add(-time.Hour), NextUpdate: now.Add(time.Hour)}
+
+	staple := CheckStaple([]byte{0x30}, response, nil, now)
+	staple.Compare(&ocsp.Response{Status: ocsp.Good, ThisUpdate: now.Add(-time.Hour)})
+	assert.Equal(t, StapleValid, staple.Status)
+	assert.Empty(t, staple.Problems)
+
+	staple = CheckStaple([]byte{0x30}, response, nil, now)
+	staple.Compare(&ocsp.Response{Status: ocsp.Good, ThisUpdate: now})
+	assert.Equal(t, StapleValid, staple.Status)
+	assert.Equal(t, []string{"the responder has a response that is 1h0m0s newer than the staple"}, staple.Problems)
+
+	staple = CheckStaple([]byte{0x30}, response, nil, now)
+	staple.Compare(&ocsp.Response{Status: ocsp.Revoked, ThisUpdate: now})
+	assert.Equal(t, StapleMismatch, staple.Status)
+	assert.Equal(t, []string{"stapled status Good differs from the responder status Revoked"}, staple.Problems)
+	assert.Equal(t, ocsp.Revoked, staple.Fresh.Status)
+}
@@ -0,0 +1,115 @@
+package scan
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pimg/certguard/pkg/domain/certificate"
+)
+
+// maxFileSize limits the size of files that are inspected, certificate files are small and larger files are skipped without reading them
+const maxFileSize = 1 << 20
+
+// CertificateExtensions are the file extensions of supported certificate formats, files with other extensions are only included when their content is recognized
+var CertificateExtensions = append([]string{".pem", ".crt", ".cer", ".der", ".p7b", ".p7c", ".p12", ".pfx"}, KeystoreExtensions...)
+
+// CertificateFile is a certificate found in a file, a file can contain multiple certificates
+type CertificateFile struct {
+	Path        string
+	Certificate *x509.Certificate
+}
+
+// FileError is a file that looks like a certificate file but could not be parsed
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (f FileError) Error() string {
+	return fmt.Sprintf("%s: %v", f.Path, f.Err)
+}
+
+// Directory recursively discovers certificates in PEM, DER, PKCS#7, PKCS#12 and Java keystore files below root. Hidden directories are skipped,
+// symbolic links to files are followed so certificates mounted from Kubernetes secrets are found once.
+// The password is used for PKCS#12 bundles and Java keystores.
+func Directory(root, password string) ([]CertificateFile, []FileError, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	certificates := make([]CertificateFile, 0)
+	fileErrors := make([]FileError, 0)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			fileErrors = append(fileErrors, FileError{Path: path, Err: err})
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxFileSize {
+			return nil
+		}
+
+		found, err := certificateFile(path, password)
+		if err != nil {
+			fileErrors = append(fileErrors, FileError{Path: path, Err: err})
+			return nil
+		}
+
+		certificates = append(certificates, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return certificates, fileErrors, nil
+}
+
+// certificateFile parses a single file, files with an unknown extension are skipped when their content is not a supported format
+func certificateFile(path, password string) ([]CertificateFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	knownExtension := slices.Contains(CertificateExtensions, strings.ToLower(filepath.Ext(path)))
+	format := certificate.DetectFormat(data)
+	if !knownExtension && (format == certificate.FormatUnknown || format == certificate.FormatPKCS10) {
+		return nil, nil
+	}
+
+	bundle, err := certificate.ParseBundle(data, password)
+	if err != nil {
+		if !knownExtension {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// files without certificates, e.g. private keys stored as .pem, are not reported
+	found := make([]CertificateFile, len(bundle.Certificates))
+	for i, cert := range bundle.Certificates {
+		found[i] = CertificateFile{Path: path, Certificate: cert}
+	}
+	return found, nil
+}
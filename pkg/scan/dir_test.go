@@ -0,0 +1,66 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func copyTestFile(t *testing.T, name, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "testing", "pki", name))
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
+	assert.NoError(t, os.WriteFile(dst, data, 0o644))
+}
+
+func TestDirectory(t *testing.T) {
+	root := t.TempDir()
+	copyTestFile(t, "github.com-chain.pem", filepath.Join(root, "github.com-chain.pem"))
+	copyTestFile(t, "github.com.der", filepath.Join(root, "secrets", "tls"))
+	copyTestFile(t, "github.com-chain.p7b", filepath.Join(root, "secrets", "chain.p7b"))
+	copyTestFile(t, "leaf.certguard.test.p12", filepath.Join(root, "keystores", "leaf.p12"))
+	copyTestFile(t, "malformed-certificate.pem", filepath.Join(root, "malformed.pem"))
+	copyTestFile(t, "github.com-chain.pem", filepath.Join(root, ".git", "ignored.pem"))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("# certificates"), 0o644))
+	assert.NoError(t, os.Symlink(filepath.Join(root, "secrets", "tls"), filepath.Join(root, "tls.crt")))
+
+	certificates, fileErrors, err := Directory(root, "certguard")
+	assert.NoError(t, err)
+
+	counts := make(map[string]int)
+	for _, certificate := range certificates {
+		rel, err := filepath.Rel(root, certificate.Path)
+		assert.NoError(t, err)
+		counts[rel]++
+	}
+
+	assert.Equal(t, map[string]int{
+		"github.com-chain.pem": 3,
+		"secrets/tls":          1,
+		"secrets/chain.p7b":    3,
+		"keystores/leaf.p12":   2,
+		"tls.crt":              1,
+	}, counts)
+
+	assert.Len(t, fileErrors, 1)
+	assert.Equal(t, filepath.Join(root, "malformed.pem"), fileErrors[0].Path)
+}
+
+func TestDirectoryPasswordRequired(t *testing.T) {
+	root := t.TempDir()
+	copyTestFile(t, "leaf.certguard.test.p12", filepath.Join(root, "leaf.p12"))
+
+	certificates, fileErrors, err := Directory(root, "")
+	assert.NoError(t, err)
+	assert.Empty(t, certificates)
+	assert.Len(t, fileErrors, 1)
+	assert.EqualError(t, fileErrors[0], filepath.Join(root, "leaf.p12")+": PKCS#12 bundle is password protected")
+}
+
+func TestDirectoryNotADirectory(t *testing.T) {
+	_, _, err := Directory(filepath.Join("..", "..", "testing", "pki", "github.com.der"), "")
+	assert.ErrorContains(t, err, "is not a directory")
+}
@@ -0,0 +1,33 @@
+package scan
+
+import (
+	"github.com/pimg/certguard/pkg/domain/certificate"
+)
+
+// KeystoreExtensions are the file extensions of Java keystores, these files are opened in the keystore view
+var KeystoreExtensions = []string{".jks", ".jceks", ".keystore", ".truststore", ".ks"}
+
+// KeystoreEntry is an entry of a keystore with the revocation status of its certificate, the path of the report entry is the path of the keystore
+type KeystoreEntry struct {
+	certificate.KeystoreEntry
+	ReportEntry
+}
+
+// KeystoreReport contains the entries of a keystore file
+type KeystoreReport struct {
+	Path    string
+	Format  certificate.Format
+	Entries []KeystoreEntry
+	Notices []string
+}
+
+// Revoked returns the number of entries with a revoked certificate
+func (k *KeystoreReport) Revoked() int {
+	revoked := 0
+	for _, entry := range k.Entries {
+		if entry.Revocation == RevocationRevoked {
+			revoked++
+		}
+	}
+	return revoked
+}
@@ -0,0 +1,140 @@
+package scan
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RevocationStatus string
+
+const (
+	RevocationRevoked RevocationStatus = "revoked"
+	// RevocationGood is only used when an OCSP responder confirmed the certificate is not revoked
+	RevocationGood RevocationStatus = "good"
+	// RevocationNotListed is used when the certificate is not found on any of the stored CRLs
+	RevocationNotListed RevocationStatus = "not listed"
+	RevocationUnknown   RevocationStatus = "unknown"
+)
+
+type SortField string
+
+const (
+	SortByPath       SortField = "path"
+	SortByExpiry     SortField = "expiry"
+	SortByRevocation SortField = "revocation"
+	SortByIssuer     SortField = "issuer"
+	SortBySubject    SortField = "subject"
+)
+
+// SortFields lists the fields a report can be sorted on, in the order they are cycled through in the TUI
+var SortFields = []SortField{SortByPath, SortByExpiry, SortByRevocation, SortByIssuer, SortBySubject}
+
+// ParseSortField validates the name of a sort field
+func ParseSortField(field string) (SortField, error) {
+	for _, sortField := range SortFields {
+		if string(sortField) == strings.ToLower(field) {
+			return sortField, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported sort field: %s, allowed values: path, expiry, revocation, issuer, subject", field)
+}
+
+// ReportEntry is a certificate found during a directory scan, entries for files that could not be parsed only contain the path and error
+type ReportEntry struct {
+	Path             string           `json:"path"`
+	Subject          string           `json:"subject,omitempty"`
+	Issuer           string           `json:"issuer,omitempty"`
+	SerialNumber     string           `json:"serial_number,omitempty"`
+	NotAfter         time.Time        `json:"not_after,omitzero"`
+	Revocation       RevocationStatus `json:"revocation,omitempty"`
+	RevocationSource string           `json:"revocation_source,omitempty"`
+	Error            string           `json:"error,omitempty"`
+}
+
+// DaysLeft returns the number of days until the certificate expires, negative for expired certificates
+func (e ReportEntry) DaysLeft(now time.Time) int {
+	return int(e.NotAfter.Sub(now).Hours() / 24)
+}
+
+// Report is the result of a directory scan
+type Report struct {
+	Root    string        `json:"root"`
+	Created time.Time     `json:"created"`
+	Entries []ReportEntry `json:"entries"`
+}
+
+// Sort orders the entries on field, entries with an equal value are ordered on path
+func (r *Report) Sort(field SortField) {
+	slices.SortStableFunc(r.Entries, func(a, b ReportEntry) int {
+		var order int
+		switch field {
+		case SortByExpiry:
+			order = a.NotAfter.Compare(b.NotAfter)
+		case SortByRevocation:
+			order = cmp.Compare(revocationRank(a.Revocation), revocationRank(b.Revocation))
+		case SortByIssuer:
+			order = cmp.Compare(a.Issuer, b.Issuer)
+		case SortBySubject:
+			order = cmp.Compare(a.Subject, b.Subject)
+		}
+
+		if order != 0 {
+			return order
+		}
+		return cmp.Compare(a.Path, b.Path)
+	})
+}
+
+// revocationRank orders revoked certificates first
+func revocationRank(status RevocationStatus) int {
+	switch status {
+	case RevocationRevoked:
+		return 0
+	case RevocationUnknown:
+		return 1
+	case RevocationNotListed:
+		return 2
+	case RevocationGood:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// WriteCSV writes the entries of the report as CSV including a header row
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"path", "subject", "issuer", "serial_number", "not_after", "days_left", "revocation", "revocation_source", "error"}); err != nil {
+		return err
+	}
+
+	for _, entry := range r.Entries {
+		notAfter, daysLeft := "", ""
+		if !entry.NotAfter.IsZero() {
+			notAfter = entry.NotAfter.Format(time.RFC3339)
+			daysLeft = strconv.Itoa(entry.DaysLeft(r.Created))
+		}
+
+		err := writer.Write([]string{entry.Path, entry.Subject, entry.Issuer, entry.SerialNumber, notAfter, daysLeft, string(entry.Revocation), entry.RevocationSource, entry.Error})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,86 @@
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testReport() *Report {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &Report{
+		Root:    "certs",
+		Created: created,
+		Entries: []ReportEntry{
+			{Path: "certs/b.pem", Subject: "b.example.com", Issuer: "CA 2", SerialNumber: "2", NotAfter: created.AddDate(0, 0, 90), Revocation: RevocationNotListed, RevocationSource: "CRL"},
+			{Path: "certs/a.pem", Subject: "a.example.com", Issuer: "CA 1", SerialNumber: "1", NotAfter: created.AddDate(0, 0, 10), Revocation: RevocationRevoked, RevocationSource: "CRL"},
+			{Path: "certs/c.p12", Error: "PKCS#12 bundle is password protected"},
+			{Path: "certs/d.pem", Subject: "d.example.com", Issuer: "CA 1", SerialNumber: "3", NotAfter: created.AddDate(0, 0, 30), Revocation: RevocationGood, RevocationSource: "OCSP"},
+		},
+	}
+}
+
+func paths(report *Report) []string {
+	paths := make([]string, len(report.Entries))
+	for i, entry := range report.Entries {
+		paths[i] = entry.Path
+	}
+	return paths
+}
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		field    SortField
+		expected []string
+	}{
+		{SortByPath, []string{"certs/a.pem", "certs/b.pem", "certs/c.p12", "certs/d.pem"}},
+		{SortByExpiry, []string{"certs/c.p12", "certs/a.pem", "certs/d.pem", "certs/b.pem"}},
+		{SortByRevocation, []string{"certs/a.pem", "certs/b.pem", "certs/d.pem", "certs/c.p12"}},
+		{SortByIssuer, []string{"certs/c.p12", "certs/a.pem", "certs/d.pem", "certs/b.pem"}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.field), func(t *testing.T) {
+			t.Parallel()
+			report := testReport()
+			report.Sort(test.field)
+			assert.Equal(t, test.expected, paths(report))
+		})
+	}
+}
+
+func TestParseSortField(t *testing.T) {
+	field, err := ParseSortField("Expiry")
+	assert.NoError(t, err)
+	assert.Equal(t, SortByExpiry, field)
+
+	_, err = ParseSortField("size")
+	assert.ErrorContains(t, err, "unsupported sort field: size")
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, testReport().WriteCSV(&buffer))
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 5)
+	assert.Equal(t, "path,subject,issuer,serial_number,not_after,days_left,revocation,revocation_source,error", lines[0])
+	assert.Equal(t, "certs/b.pem,b.example.com,CA 2,2,2026-04-01T00:00:00Z,90,not listed,CRL,", lines[1])
+	assert.Equal(t, "certs/c.p12,,,,,,,,PKCS#12 bundle is password protected", lines[3])
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.NoError(t, testReport().WriteJSON(&buffer))
+
+	var report map[string]any
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &report))
+	entries := report["entries"].([]any)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, "revoked", entries[1].(map[string]any)["revocation"])
+	assert.NotContains(t, entries[2].(map[string]any), "not_after")
+}
@@ -0,0 +1,189 @@
+package scan
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+type StartTLS string
+
+const (
+	StartTLSNone StartTLS = ""
+	StartTLSSMTP StartTLS = "smtp"
+	StartTLSIMAP StartTLS = "imap"
+	StartTLSLDAP StartTLS = "ldap"
+)
+
+var defaultPorts = map[StartTLS]string{
+	StartTLSNone: "443",
+	StartTLSSMTP: "25",
+	StartTLSIMAP: "143",
+	StartTLSLDAP: "389",
+}
+
+const defaultTimeout = 10 * time.Second
+
+// Options configures the connection to a TLS endpoint
+type Options struct {
+	// Address is host:port, the default port of the protocol is used when the port is omitted
+	Address string
+	// ServerName is sent as SNI and used for hostname verification, it defaults to the host of Address
+	ServerName string
+	StartTLS   StartTLS
+	// RootCAs is used to verify the presented chain, the system roots are used when nil
+	RootCAs *x509.CertPool
+	Timeout time.Duration
+}
+
+// Result contains the chain and the stapled OCSP response presented by a TLS endpoint
+type Result struct {
+	Address    string
+	ServerName string
+	// Certificates in the order presented by the server
+	Certificates []*x509.Certificate
+	// StapledOCSP is the raw OCSP response stapled to the handshake, nil when the server did not staple a response
+	StapledOCSP []byte
+	TLSVersion  string
+	CipherSuite string
+	// VerifyError is nil when the presented chain is trusted for ServerName
+	VerifyError error
+}
+
+// ParseStartTLS validates a STARTTLS protocol name
+func ParseStartTLS(protocol string) (StartTLS, error) {
+	switch StartTLS(strings.ToLower(protocol)) {
+	case StartTLSNone:
+		return StartTLSNone, nil
+	case StartTLSSMTP:
+		return StartTLSSMTP, nil
+	case StartTLSIMAP:
+		return StartTLSIMAP, nil
+	case StartTLSLDAP:
+		return StartTLSLDAP, nil
+	default:
+		return StartTLSNone, fmt.Errorf("unsupported STARTTLS protocol: %s, supported protocols are: smtp, imap and ldap", protocol)
+	}
+}
+
+// LoadCertPool reads PEM encoded CA certificates from a file
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("could not read CA file: %s", path), err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("CA file does not contain PEM certificates: %s", path)
+	}
+
+	return pool, nil
+}
+
+// Scan completes a TLS handshake with the endpoint and captures the presented chain. The chain is captured even when it is not trusted,
+// the outcome of the verification is stored in the result.
+func Scan(ctx context.Context, opts Options) (*Result, error) {
+	address, host, err := normalizeAddress(opts.Address, opts.StartTLS)
+	if err != nil {
+		return nil, err
+	}
+
+	serverName := opts.ServerName
+	if serverName == "" && net.ParseIP(host) == nil {
+		serverName = host
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("could not connect to %s", address), err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := startTLS(conn, opts.StartTLS); err != nil {
+		return nil, errors.Join(fmt.Errorf("STARTTLS negotiation with %s failed", address), err)
+	}
+
+	// verification is done after the handshake, so untrusted chains can be inspected as well
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, errors.Join(fmt.Errorf("TLS handshake with %s failed", address), err)
+	}
+
+	// IP addresses are not sent as SNI, but are verified against the IP SANs
+	verifyName := serverName
+	if verifyName == "" {
+		verifyName = host
+	}
+
+	state := tlsConn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("%s did not present a certificate", address)
+	}
+
+	return &Result{
+		Address:      address,
+		ServerName:   serverName,
+		Certificates: state.PeerCertificates,
+		StapledOCSP:  state.OCSPResponse,
+		TLSVersion:   tls.VersionName(state.Version),
+		CipherSuite:  tls.CipherSuiteName(state.CipherSuite),
+		VerifyError:  verify(state.PeerCertificates, verifyName, opts.RootCAs),
+	}, nil
+}
+
+func normalizeAddress(address string, protocol StartTLS) (string, string, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return "", "", errors.New("address is empty, expected host:port")
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		// no port in the address, use the default port of the protocol
+		host = strings.Trim(address, "[]")
+		port = defaultPorts[protocol]
+	}
+
+	if host == "" {
+		return "", "", fmt.Errorf("invalid address: %s, expected host:port", address)
+	}
+
+	return net.JoinHostPort(host, port), host, nil
+}
+
+func verify(certificates []*x509.Certificate, serverName string, roots *x509.CertPool) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
@@ -0,0 +1,278 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testServer struct {
+	address string
+	roots   *x509.CertPool
+	staple  []byte
+}
+
+// newTestServer starts a local TLS listener for localhost, the handshake is preceded by the STARTTLS negotiation of the protocol
+func newTestServer(t *testing.T, protocol StartTLS) *testServer {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "CertGuard Scan CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	assert.NoError(t, err)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, &leafKey.PublicKey, caKey)
+	assert.NoError(t, err)
+
+	staple := []byte{0x30, 0x03, 0x0a, 0x01, 0x00}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{leafDER, caDER},
+			PrivateKey:  leafKey,
+			OCSPStaple:  staple,
+		}},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if serveStartTLS(conn, protocol) != nil {
+					return
+				}
+				_ = tls.Server(conn, config).Handshake()
+			}()
+		}
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	return &testServer{
+		address: listener.Addr().String(),
+		roots:   roots,
+		staple:  staple,
+	}
+}
+
+func serveStartTLS(conn net.Conn, protocol StartTLS) error {
+	reader := bufio.NewReader(conn)
+	switch protocol {
+	case StartTLSSMTP:
+		_, _ = io.WriteString(conn, "220 localhost ESMTP\r\n")
+		_, _ = reader.ReadString('\n')
+		_, _ = io.WriteString(conn, "250-localhost\r\n250 STARTTLS\r\n")
+		_, _ = reader.ReadString('\n')
+		_, err := io.WriteString(conn, "220 ready to start TLS\r\n")
+		return err
+	case StartTLSIMAP:
+		_, _ = io.WriteString(conn, "* OK IMAP4rev1 ready\r\n")
+		_, _ = reader.ReadString('\n')
+		_, err := io.WriteString(conn, "a001 OK begin TLS negotiation now\r\n")
+		return err
+	case StartTLSLDAP:
+		if _, err := readBERElement(conn); err != nil {
+			return err
+		}
+		_, err := conn.Write([]byte{0x30, 0x0c, 0x02, 0x01, 0x01, 0x78, 0x07, 0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00})
+		return err
+	}
+	return nil
+}
+
+func TestScan(t *testing.T) {
+	server := newTestServer(t, StartTLSNone)
+
+	result, err := Scan(context.Background(), Options{Address: server.address, ServerName: "localhost", RootCAs: server.roots})
+	assert.NoError(t, err)
+
+	assert.Len(t, result.Certificates, 2)
+	assert.Equal(t, "localhost", result.Certificates[0].Subject.CommonName)
+	assert.Equal(t, server.staple, result.StapledOCSP)
+	assert.Equal(t, "TLS 1.3", result.TLSVersion)
+	assert.NoError(t, result.VerifyError)
+}
+
+func TestScanUntrusted(t *testing.T) {
+	server := newTestServer(t, StartTLSNone)
+
+	result, err := Scan(context.Background(), Options{Address: server.address, ServerName: "localhost"})
+	assert.NoError(t, err)
+
+	// the chain is captured, even though it is not trusted by the system roots
+	assert.Len(t, result.Certificates, 2)
+	assert.Error(t, result.VerifyError)
+}
+
+func TestScanServerNameMismatch(t *testing.T) {
+	server := newTestServer(t, StartTLSNone)
+
+	result, err := Scan(context.Background(), Options{Address: server.address, ServerName: "other.certguard.test", RootCAs: server.roots})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "other.certguard.test", result.ServerName)
+	assert.ErrorContains(t, result.VerifyError, "other.certguard.test")
+}
+
+func TestScanStartTLS(t *testing.T) {
+	t.Parallel()
+	for _, protocol := range []StartTLS{StartTLSSMTP, StartTLSIMAP, StartTLSLDAP} {
+		t.Run(string(protocol), func(t *testing.T) {
+			t.Parallel()
+			server := newTestServer(t, protocol)
+
+			result, err := Scan(context.Background(), Options{Address: server.address, StartTLS: protocol, RootCAs: server.roots})
+			assert.NoError(t, err)
+
+			assert.Equal(t, "", result.ServerName)
+			assert.Len(t, result.Certificates, 2)
+			assert.NoError(t, result.VerifyError)
+		})
+	}
+}
+
+func TestStartTLSLDAP(t *testing.T) {
+	tests := map[string]struct {
+		response []byte
+		err      string
+	}{
+		"short lengths": {response: []byte{0x30, 0x0c, 0x02, 0x01, 0x01, 0x78, 0x07, 0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00}},
+		// Active Directory encodes every length in the long form with 4 bytes, DER requires the short form
+		"long lengths": {response: []byte{
+			0x30, 0x84, 0x00, 0x00, 0x00, 0x10, 0x02, 0x01, 0x01,
+			0x78, 0x84, 0x00, 0x00, 0x00, 0x07, 0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00,
+		}},
+		"refused": {
+			response: []byte{0x30, 0x84, 0x00, 0x00, 0x00, 0x10, 0x02, 0x01, 0x01, 0x78, 0x84, 0x00, 0x00, 0x00, 0x07, 0x0a, 0x01, 0x34, 0x04, 0x00, 0x04, 0x00},
+			err:      "LDAP server refused StartTLS with result code 52",
+		},
+		"unexpected response": {
+			response: []byte{0x30, 0x0c, 0x02, 0x01, 0x01, 0x65, 0x07, 0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00},
+			err:      "unexpected LDAP response with tag 5",
+		},
+		"oversized response": {
+			response: []byte{0x30, 0x84, 0xff, 0xff, 0xff, 0xff},
+			err:      "LDAP response of 4294967295 bytes exceeds the maximum of 4096 bytes",
+		},
+		"truncated response": {
+			response: []byte{0x30, 0x08, 0x02, 0x01, 0x01, 0x78, 0x07, 0x0a, 0x01, 0x00},
+			err:      "could not parse LDAP response",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer client.Close()
+			go func() {
+				defer server.Close()
+				if _, err := readBERElement(server); err == nil {
+					_, _ = server.Write(tt.response)
+				}
+			}()
+
+			err := startTLSLDAP(client)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestScanConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	assert.NoError(t, listener.Close())
+
+	_, err = Scan(context.Background(), Options{Address: address, Timeout: time.Second})
+	assert.ErrorContains(t, err, "could not connect to "+address)
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	t.Parallel()
+	type testCase struct {
+		input    string
+		protocol StartTLS
+		address  string
+		host     string
+		wantErr  bool
+	}
+
+	testCases := []testCase{
+		{input: "github.com:443", address: "github.com:443", host: "github.com"},
+		{input: "github.com", address: "github.com:443", host: "github.com"},
+		{input: "mail.example.com", protocol: StartTLSSMTP, address: "mail.example.com:25", host: "mail.example.com"},
+		{input: "[::1]:636", address: "[::1]:636", host: "::1"},
+		{input: " ", wantErr: true},
+		{input: ":443", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+			address, host, err := normalizeAddress(tc.input, tc.protocol)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.address, address)
+			assert.Equal(t, tc.host, host)
+		})
+	}
+}
+
+func TestParseStartTLS(t *testing.T) {
+	protocol, err := ParseStartTLS("SMTP")
+	assert.NoError(t, err)
+	assert.Equal(t, StartTLSSMTP, protocol)
+
+	_, err = ParseStartTLS("pop3")
+	assert.ErrorContains(t, err, "unsupported STARTTLS protocol: pop3")
+}
@@ -0,0 +1,245 @@
+package scan
+
+import (
+	"bufio"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// ldapStartTLSRequest is an LDAP ExtendedRequest (message ID 1) for the StartTLS OID 1.3.6.1.4.1.1466.20037
+var ldapStartTLSRequest = []byte{
+	0x30, 0x1d, 0x02, 0x01, 0x01, 0x77, 0x18, 0x80, 0x16,
+	'1', '.', '3', '.', '6', '.', '1', '.', '4', '.', '1', '.', '1', '4', '6', '6', '.', '2', '0', '0', '3', '7',
+}
+
+const ldapExtendedResponseTag = 24
+
+// maxLDAPResponseSize limits the StartTLS response that is read, the response has a result code and at most a short diagnostic message
+const maxLDAPResponseSize = 4096
+
+// startTLS upgrades a plain text connection to the point where the TLS handshake can start
+func startTLS(conn net.Conn, protocol StartTLS) error {
+	switch protocol {
+	case StartTLSNone:
+		return nil
+	case StartTLSSMTP:
+		return startTLSSMTP(conn)
+	case StartTLSIMAP:
+		return startTLSIMAP(conn)
+	case StartTLSLDAP:
+		return startTLSLDAP(conn)
+	default:
+		return fmt.Errorf("unsupported STARTTLS protocol: %s", protocol)
+	}
+}
+
+func startTLSSMTP(conn net.Conn) error {
+	// the server sends nothing after its final reply until the handshake starts, so the buffered reader cannot consume handshake bytes
+	reader := bufio.NewReader(conn)
+	if err := readSMTPReply(reader, "220"); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(conn, "EHLO certguard\r\n"); err != nil {
+		return err
+	}
+	if err := readSMTPReply(reader, "250"); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(conn, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	return readSMTPReply(reader, "220")
+}
+
+// readSMTPReply reads a, possibly multiline, SMTP reply and checks its status code
+func readSMTPReply(reader *bufio.Reader, code string) error {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		if len(line) < 4 || !strings.HasPrefix(line, code) {
+			return fmt.Errorf("unexpected SMTP reply: %s", strings.TrimSpace(line))
+		}
+
+		// a dash after the code indicates that more lines follow
+		if line[3] != '-' {
+			return nil
+		}
+	}
+}
+
+func startTLSIMAP(conn net.Conn) error {
+	reader := bufio.NewReader(conn)
+	greeting, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("unexpected IMAP greeting: %s", strings.TrimSpace(greeting))
+	}
+
+	if _, err := io.WriteString(conn, "a001 STARTTLS\r\n"); err != nil {
+		return err
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		// untagged responses may precede the tagged response
+		if strings.HasPrefix(line, "*") {
+			continue
+		}
+		if strings.HasPrefix(line, "a001 OK") {
+			return nil
+		}
+		return fmt.Errorf("IMAP server refused STARTTLS: %s", strings.TrimSpace(line))
+	}
+}
+
+func startTLSLDAP(conn net.Conn) error {
+	if _, err := conn.Write(ldapStartTLSRequest); err != nil {
+		return err
+	}
+
+	raw, err := readBERElement(conn)
+	if err != nil {
+		return err
+	}
+
+	// LDAPMessage ::= SEQUENCE { messageID INTEGER, protocolOp ExtendedResponse, ... }, RFC 4511 section 4.2
+	message, _, err := parseBERElement(raw)
+	if err != nil || message.class != asn1.ClassUniversal || message.tag != asn1.TagSequence {
+		return errors.Join(errors.New("could not parse LDAP response"), err)
+	}
+	messageID, rest, err := parseBERElement(message.content)
+	if err != nil || messageID.class != asn1.ClassUniversal || messageID.tag != asn1.TagInteger {
+		return errors.Join(errors.New("could not parse LDAP message ID"), err)
+	}
+	response, _, err := parseBERElement(rest)
+	if err != nil {
+		return errors.Join(errors.New("could not parse LDAP response"), err)
+	}
+	if response.class != asn1.ClassApplication || response.tag != ldapExtendedResponseTag {
+		return fmt.Errorf("unexpected LDAP response with tag %d", response.tag)
+	}
+
+	result, _, err := parseBERElement(response.content)
+	if err != nil || result.class != asn1.ClassUniversal || result.tag != asn1.TagEnum {
+		return errors.Join(errors.New("could not parse LDAP result code"), err)
+	}
+	resultCode, err := parseBERInteger(result.content)
+	if err != nil {
+		return errors.Join(errors.New("could not parse LDAP result code"), err)
+	}
+	if resultCode != 0 {
+		return fmt.Errorf("LDAP server refused StartTLS with result code %d", resultCode)
+	}
+
+	return nil
+}
+
+// berElement is a BER encoded element with a tag of at most 30, the tags LDAP uses
+type berElement struct {
+	class   int
+	tag     int
+	content []byte
+}
+
+// parseBERElement parses the first element of data and returns the bytes that follow it. Unlike encoding/asn1, which only accepts DER,
+// lengths in the long form that fit the short form are accepted, Active Directory encodes every length in 4 bytes.
+// LDAP only uses definite lengths, RFC 4511 section 5.1.
+func parseBERElement(data []byte) (berElement, []byte, error) {
+	if len(data) < 2 {
+		return berElement{}, nil, errors.New("truncated BER element")
+	}
+
+	element := berElement{class: int(data[0] >> 6), tag: int(data[0] & 0x1f)}
+	if element.tag == 0x1f {
+		return berElement{}, nil, errors.New("unsupported BER high tag number")
+	}
+
+	length, offset := int(data[1]), 2
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 || size > 4 {
+			return berElement{}, nil, errors.New("unsupported BER length encoding")
+		}
+		if len(data) < offset+size {
+			return berElement{}, nil, errors.New("truncated BER length")
+		}
+
+		length = 0
+		for _, b := range data[offset : offset+size] {
+			length = length<<8 | int(b)
+		}
+		offset += size
+	}
+
+	if length > len(data)-offset {
+		return berElement{}, nil, errors.New("truncated BER element")
+	}
+	element.content = data[offset : offset+length]
+	return element, data[offset+length:], nil
+}
+
+// parseBERInteger parses the two's complement content of an INTEGER or ENUMERATED element
+func parseBERInteger(content []byte) (int64, error) {
+	if len(content) == 0 || len(content) > 8 {
+		return 0, fmt.Errorf("unsupported BER integer of %d bytes", len(content))
+	}
+
+	value := int64(int8(content[0]))
+	for _, b := range content[1:] {
+		value = value<<8 | int64(b)
+	}
+	return value, nil
+}
+
+// readBERElement reads exactly one BER element from the connection, so no bytes of the TLS handshake are consumed
+func readBERElement(reader io.Reader) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+
+	length := int(header[1])
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 || size > 4 {
+			return nil, errors.New("unsupported BER length encoding")
+		}
+
+		lengthBytes := make([]byte, size)
+		if _, err := io.ReadFull(reader, lengthBytes); err != nil {
+			return nil, err
+		}
+		header = append(header, lengthBytes...)
+
+		var longLength uint64
+		for _, b := range lengthBytes {
+			longLength = longLength<<8 | uint64(b)
+		}
+		if longLength > maxLDAPResponseSize {
+			return nil, fmt.Errorf("LDAP response of %d bytes exceeds the maximum of %d bytes", longLength, maxLDAPResponseSize)
+		}
+		length = int(longLength)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, err
+	}
+
+	return append(header, body...), nil
+}
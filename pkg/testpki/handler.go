@@ -0,0 +1,86 @@
+package testpki
+
+import (
+	"encoding/base64"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// maxOCSPRequestSize limits the body of an OCSP request, a request for one certificate is around 100 bytes
+const maxOCSPRequestSize = 10 * 1024
+
+// Handler serves the CRLs at /root.crl, /intermediate.crl and /intermediate-delta.crl and answers OCSP requests at /ocsp.
+// OCSP requests are answered by the intermediate CA: a revoked serial number is revoked, the other leaf certificates are good and other serial numbers are unknown.
+func (p *PKI) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /root.crl", serveCRL(p.RootCRL))
+	mux.HandleFunc("GET /intermediate.crl", serveCRL(p.BaseCRL))
+	mux.HandleFunc("GET /intermediate-delta.crl", serveCRL(p.DeltaCRL))
+	mux.HandleFunc("POST /ocsp", func(w http.ResponseWriter, r *http.Request) {
+		request, err := io.ReadAll(io.LimitReader(r.Body, maxOCSPRequestSize))
+		if err != nil {
+			http.Error(w, "could not read the OCSP request", http.StatusBadRequest)
+			return
+		}
+		p.respondOCSP(w, request)
+	})
+	// a GET request is the base64 encoded, and URL encoded, request appended to the URL of the responder, RFC 6960 appendix A.1
+	mux.HandleFunc("GET /ocsp/{request...}", func(w http.ResponseWriter, r *http.Request) {
+		encoded, err := url.PathUnescape(r.PathValue("request"))
+		if err != nil {
+			_, _ = w.Write(ocsp.MalformedRequestErrorResponse)
+			return
+		}
+		request, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			_, _ = w.Write(ocsp.MalformedRequestErrorResponse)
+			return
+		}
+		p.respondOCSP(w, request)
+	})
+	return mux
+}
+
+func serveCRL(der []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/pkix-crl")
+		_, _ = w.Write(der)
+	}
+}
+
+func (p *PKI) respondOCSP(w http.ResponseWriter, rawRequest []byte) {
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	request, err := ocsp.ParseRequest(rawRequest)
+	if err != nil {
+		_, _ = w.Write(ocsp.MalformedRequestErrorResponse)
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	template := ocsp.Response{
+		Status:       ocsp.Unknown,
+		SerialNumber: request.SerialNumber,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(time.Hour),
+	}
+	if revocation, ok := p.Revocation(request.SerialNumber); ok {
+		template.Status = ocsp.Revoked
+		template.RevokedAt = revocation.RevokedAt
+		template.RevocationReason = revocation.ReasonCode
+	} else if _, ok := p.Leaf(request.SerialNumber); ok {
+		template.Status = ocsp.Good
+	}
+
+	response, err := ocsp.CreateResponse(p.Intermediate.Certificate, p.Intermediate.Certificate, template, p.Intermediate.Key)
+	if err != nil {
+		log.Printf("could not create the OCSP response for serialnumber: %s, err: %v", request.SerialNumber, err)
+		_, _ = w.Write(ocsp.InternalErrorErrorResponse)
+		return
+	}
+	_, _ = w.Write(response)
+}
@@ -0,0 +1,273 @@
+// Package testpki generates a PKI for local testing: a root and intermediate CA, a delegated OCSP responder certificate, leaf certificates, a base and a delta CRL
+// of the intermediate CA with chosen revoked serial numbers, and an HTTP handler that serves the CRLs and answers OCSP requests.
+package testpki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pimg/certguard/pkg/domain/crl"
+)
+
+// FirstLeafSerialNumber is the serial number of the first leaf certificate, the next leaf certificates count up from it
+const FirstLeafSerialNumber = 4096
+
+// DefaultName is the prefix of the common names of the generated CAs
+const DefaultName = "CertGuard Test"
+
+// oidDeltaCRLIndicator marks a delta CRL and holds the CRL number of its base CRL, RFC 5280 section 5.2.4
+var oidDeltaCRLIndicator = asn1.ObjectIdentifier{2, 5, 29, 27}
+
+// Revocation revokes a serial number on the base CRL, or on the delta CRL, of the intermediate CA
+type Revocation struct {
+	SerialNumber *big.Int
+	ReasonCode   int
+	// RevokedAt is the revocation time, the time the PKI is generated when it is zero
+	RevokedAt time.Time
+	Delta     bool
+}
+
+// ParseRevocation parses a revocation of the form serial[:reason], the serial number is decimal or hex with a 0x prefix
+// and the reason is a name like keyCompromise, the reason is unspecified when it is left out
+func ParseRevocation(value string, delta bool) (Revocation, error) {
+	serialNumber, reason, _ := strings.Cut(value, ":")
+	revocation := Revocation{Delta: delta}
+
+	var ok bool
+	revocation.SerialNumber, ok = new(big.Int).SetString(serialNumber, 0)
+	if !ok || revocation.SerialNumber.Sign() <= 0 {
+		return Revocation{}, fmt.Errorf("invalid serial number: %q, use a decimal or 0x prefixed hex serial number", serialNumber)
+	}
+
+	if reason != "" {
+		code, err := crl.ParseReasonCode(reason)
+		if err != nil {
+			return Revocation{}, err
+		}
+		revocation.ReasonCode = code
+	}
+	return revocation, nil
+}
+
+// Options of a generated PKI, the zero value generates a PKI with one leaf certificate and no revocations
+type Options struct {
+	// Name is the prefix of the common names of the CAs, DefaultName when it is empty
+	Name string
+	// Leaves are the common names of the leaf certificates, a leaf.certguard.test certificate is generated when it is empty
+	Leaves      []string
+	Revocations []Revocation
+	// URL is the base URL of the CRLs and the OCSP responder in the certificates, the certificates contain no URLs when it is empty
+	URL string
+	// Now is the time the PKI is generated, the current time when it is zero
+	Now time.Time
+}
+
+// Issuer is a generated certificate with its private key
+type Issuer struct {
+	Certificate *x509.Certificate
+	Key         *ecdsa.PrivateKey
+}
+
+// PKI is a generated root and intermediate CA with leaf certificates and the DER encoded CRLs
+type PKI struct {
+	Root         *Issuer
+	Intermediate *Issuer
+	// Responder is a delegated OCSP responder certificate issued by the intermediate CA
+	Responder   *Issuer
+	Leaves      []*Issuer
+	Revocations []Revocation
+	// RootCRL is the CRL of the root CA, it does not revoke any certificate
+	RootCRL []byte
+	// BaseCRL and DeltaCRL are the CRLs of the intermediate CA, the delta CRL lists the revocations since the base CRL
+	BaseCRL  []byte
+	DeltaCRL []byte
+	url      string
+}
+
+// Generate creates a PKI with ECDSA P-256 keys
+func Generate(opts Options) (*PKI, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.UTC().Truncate(time.Second)
+
+	name := opts.Name
+	if name == "" {
+		name = DefaultName
+	}
+	leaves := opts.Leaves
+	if len(leaves) == 0 {
+		leaves = []string{"leaf.certguard.test"}
+	}
+	baseURL := strings.TrimSuffix(opts.URL, "/")
+
+	pki := &PKI{url: baseURL}
+	var err error
+	pki.Root, err = issue(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name + " Root CA", Organization: []string{"certguard"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            1,
+	}, nil)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not generate the root CA"), err)
+	}
+
+	intermediate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: name + " Intermediate CA", Organization: []string{"certguard"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(5, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	if baseURL != "" {
+		intermediate.CRLDistributionPoints = []string{baseURL + "/root.crl"}
+	}
+	pki.Intermediate, err = issue(intermediate, pki.Root)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not generate the intermediate CA"), err)
+	}
+
+	pki.Responder, err = issue(&x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: name + " OCSP Responder", Organization: []string{"certguard"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+	}, pki.Intermediate)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not generate the OCSP responder certificate"), err)
+	}
+
+	for i, commonName := range leaves {
+		leaf := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(FirstLeafSerialNumber + i)),
+			Subject:      pkix.Name{CommonName: commonName},
+			DNSNames:     []string{commonName},
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     now.AddDate(0, 0, 90),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		if baseURL != "" {
+			leaf.CRLDistributionPoints = []string{baseURL + "/intermediate.crl"}
+			leaf.OCSPServer = []string{baseURL + "/ocsp"}
+		}
+
+		issued, err := issue(leaf, pki.Intermediate)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("could not generate leaf certificate %s", commonName), err)
+		}
+		pki.Leaves = append(pki.Leaves, issued)
+	}
+
+	var base, delta []x509.RevocationListEntry
+	for _, revocation := range opts.Revocations {
+		if revocation.RevokedAt.IsZero() {
+			revocation.RevokedAt = now
+		}
+		pki.Revocations = append(pki.Revocations, revocation)
+
+		entry := x509.RevocationListEntry{SerialNumber: revocation.SerialNumber, RevocationTime: revocation.RevokedAt, ReasonCode: revocation.ReasonCode}
+		if revocation.Delta {
+			delta = append(delta, entry)
+		} else {
+			base = append(base, entry)
+		}
+	}
+
+	pki.RootCRL, err = revocationList(pki.Root, 1, now, now.AddDate(0, 0, 30), nil, nil)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not generate the CRL of the root CA"), err)
+	}
+	pki.BaseCRL, err = revocationList(pki.Intermediate, 1, now, now.AddDate(0, 0, 7), base, nil)
+	if err != nil {
+		return nil, errors.Join(errors.New("could not generate the base CRL"), err)
+	}
+
+	baseNumber, err := asn1.Marshal(big.NewInt(1))
+	if err != nil {
+		return nil, err
+	}
+	pki.DeltaCRL, err = revocationList(pki.Intermediate, 2, now, now.AddDate(0, 0, 1), delta, []pkix.Extension{{Id: oidDeltaCRLIndicator, Critical: true, Value: baseNumber}})
+	if err != nil {
+		return nil, errors.Join(errors.New("could not generate the delta CRL"), err)
+	}
+
+	return pki, nil
+}
+
+// issue generates a key and signs the certificate with parent, a nil parent self-signs the certificate
+func issue(template *x509.Certificate, parent *Issuer) (*Issuer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.Certificate, parent.Key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Issuer{Certificate: certificate, Key: key}, nil
+}
+
+func revocationList(issuer *Issuer, number int64, thisUpdate, nextUpdate time.Time, entries []x509.RevocationListEntry, extensions []pkix.Extension) ([]byte, error) {
+	return x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(number),
+		ThisUpdate:                thisUpdate,
+		NextUpdate:                nextUpdate,
+		RevokedCertificateEntries: entries,
+		ExtraExtensions:           extensions,
+	}, issuer.Certificate, issuer.Key)
+}
+
+// Revocation returns the revocation of a serial number, a serial number revoked on the base and the delta CRL returns the delta revocation
+func (p *PKI) Revocation(serialNumber *big.Int) (Revocation, bool) {
+	var found *Revocation
+	for i, revocation := range p.Revocations {
+		if revocation.SerialNumber.Cmp(serialNumber) == 0 && (found == nil || revocation.Delta) {
+			found = &p.Revocations[i]
+		}
+	}
+	if found == nil {
+		return Revocation{}, false
+	}
+	return *found, true
+}
+
+// Leaf returns the leaf certificate with the serial number
+func (p *PKI) Leaf(serialNumber *big.Int) (*Issuer, bool) {
+	for _, leaf := range p.Leaves {
+		if leaf.Certificate.SerialNumber.Cmp(serialNumber) == 0 {
+			return leaf, true
+		}
+	}
+	return nil, false
+}
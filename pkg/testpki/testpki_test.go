@@ -0,0 +1,177 @@
+package testpki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+)
+
+func generate(t *testing.T, opts Options) *PKI {
+	t.Helper()
+	pki, err := Generate(opts)
+	assert.NoError(t, err)
+	return pki
+}
+
+func TestGenerate(t *testing.T) {
+	pki := generate(t, Options{
+		Leaves: []string{"www.certguard.test", "mail.certguard.test"},
+		Revocations: []Revocation{
+			{SerialNumber: big.NewInt(FirstLeafSerialNumber), ReasonCode: 1},
+			{SerialNumber: big.NewInt(FirstLeafSerialNumber + 1), ReasonCode: 4, Delta: true},
+		},
+		URL: "http://127.0.0.1:8889/",
+	})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(pki.Root.Certificate)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(pki.Intermediate.Certificate)
+	assert.Len(t, pki.Leaves, 2)
+	for i, leaf := range pki.Leaves {
+		assert.Equal(t, big.NewInt(int64(FirstLeafSerialNumber+i)), leaf.Certificate.SerialNumber)
+		_, err := leaf.Certificate.Verify(x509.VerifyOptions{DNSName: leaf.Certificate.Subject.CommonName, Roots: roots, Intermediates: intermediates})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{"http://127.0.0.1:8889/intermediate.crl"}, pki.Leaves[0].Certificate.CRLDistributionPoints)
+	assert.Equal(t, []string{"http://127.0.0.1:8889/ocsp"}, pki.Leaves[0].Certificate.OCSPServer)
+	assert.Equal(t, DefaultName+" Intermediate CA", pki.Intermediate.Certificate.Subject.CommonName)
+	assert.NoError(t, pki.Responder.Certificate.CheckSignatureFrom(pki.Intermediate.Certificate))
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}, pki.Responder.Certificate.ExtKeyUsage)
+
+	base, err := x509.ParseRevocationList(pki.BaseCRL)
+	assert.NoError(t, err)
+	assert.NoError(t, base.CheckSignatureFrom(pki.Intermediate.Certificate))
+	assert.Len(t, base.RevokedCertificateEntries, 1)
+	assert.Equal(t, 1, base.RevokedCertificateEntries[0].ReasonCode)
+
+	delta, err := x509.ParseRevocationList(pki.DeltaCRL)
+	assert.NoError(t, err)
+	assert.NoError(t, delta.CheckSignatureFrom(pki.Intermediate.Certificate))
+	assert.Len(t, delta.RevokedCertificateEntries, 1)
+	assert.Equal(t, big.NewInt(2), delta.Number)
+	assert.True(t, delta.Extensions[len(delta.Extensions)-1].Id.Equal(oidDeltaCRLIndicator))
+
+	root, err := x509.ParseRevocationList(pki.RootCRL)
+	assert.NoError(t, err)
+	assert.NoError(t, root.CheckSignatureFrom(pki.Root.Certificate))
+	assert.Empty(t, root.RevokedCertificateEntries)
+
+	revocation, ok := pki.Revocation(big.NewInt(FirstLeafSerialNumber + 1))
+	assert.True(t, ok)
+	assert.True(t, revocation.Delta)
+	assert.False(t, revocation.RevokedAt.IsZero())
+}
+
+func TestGenerateDefaults(t *testing.T) {
+	pki := generate(t, Options{})
+	assert.Len(t, pki.Leaves, 1)
+	assert.Equal(t, "leaf.certguard.test", pki.Leaves[0].Certificate.Subject.CommonName)
+	assert.Empty(t, pki.Leaves[0].Certificate.CRLDistributionPoints)
+	assert.Empty(t, pki.Leaves[0].Certificate.OCSPServer)
+}
+
+func TestParseRevocation(t *testing.T) {
+	revocation, err := ParseRevocation("0x1000:keyCompromise", false)
+	assert.NoError(t, err)
+	assert.Equal(t, Revocation{SerialNumber: big.NewInt(4096), ReasonCode: 1}, revocation)
+
+	revocation, err = ParseRevocation("4097", true)
+	assert.NoError(t, err)
+	assert.Equal(t, Revocation{SerialNumber: big.NewInt(4097), Delta: true}, revocation)
+
+	_, err = ParseRevocation("serial:keyCompromise", false)
+	assert.Error(t, err)
+	_, err = ParseRevocation("4096:bogus", false)
+	assert.Error(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	pki := generate(t, Options{Leaves: []string{"*.certguard.test"}})
+	dir := filepath.Join(t.TempDir(), "pki")
+
+	written, err := pki.Write(dir)
+	assert.NoError(t, err)
+	assert.Contains(t, written, filepath.Join(dir, "_.certguard.test.pem"))
+	assert.Contains(t, written, filepath.Join(dir, "intermediate-delta.crl"))
+	assert.Contains(t, written, filepath.Join(dir, "ocsp-responder-key.pem"))
+
+	crl, err := os.ReadFile(filepath.Join(dir, "intermediate.crl"))
+	assert.NoError(t, err)
+	assert.Equal(t, pki.BaseCRL, crl)
+
+	info, err := os.Stat(filepath.Join(dir, "intermediate-key.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	_, err = pki.Write(dir)
+	assert.Error(t, err, "existing files are not overwritten")
+}
+
+func TestHandler(t *testing.T) {
+	pki := generate(t, Options{
+		Leaves:      []string{"good.certguard.test", "revoked.certguard.test"},
+		Revocations: []Revocation{{SerialNumber: big.NewInt(FirstLeafSerialNumber + 1), ReasonCode: ocsp.Superseded}},
+	})
+	server := httptest.NewServer(pki.Handler())
+	t.Cleanup(server.Close)
+
+	response, err := http.Get(server.URL + "/intermediate.crl")
+	assert.NoError(t, err)
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, response.Body.Close())
+	assert.Equal(t, pki.BaseCRL, body)
+
+	query := func(t *testing.T, cert *x509.Certificate, get bool) *ocsp.Response {
+		t.Helper()
+		request, err := ocsp.CreateRequest(cert, pki.Intermediate.Certificate, &ocsp.RequestOptions{Hash: crypto.SHA1})
+		assert.NoError(t, err)
+
+		var httpResponse *http.Response
+		if get {
+			httpResponse, err = http.Get(server.URL + "/ocsp/" + url.PathEscape(base64.StdEncoding.EncodeToString(request)))
+		} else {
+			httpResponse, err = http.Post(server.URL+"/ocsp", "application/ocsp-request", bytes.NewReader(request))
+		}
+		assert.NoError(t, err)
+		defer httpResponse.Body.Close()
+		raw, err := io.ReadAll(httpResponse.Body)
+		assert.NoError(t, err)
+
+		parsed, err := ocsp.ParseResponseForCert(raw, cert, pki.Intermediate.Certificate)
+		assert.NoError(t, err)
+		return parsed
+	}
+
+	assert.Equal(t, ocsp.Good, query(t, pki.Leaves[0].Certificate, false).Status)
+	revoked := query(t, pki.Leaves[1].Certificate, true)
+	assert.Equal(t, ocsp.Revoked, revoked.Status)
+	assert.Equal(t, ocsp.Superseded, revoked.RevocationReason)
+	assert.WithinDuration(t, time.Now(), revoked.RevokedAt, time.Minute)
+
+	// a certificate of the intermediate CA that is not a generated leaf certificate
+	unknown := *pki.Leaves[0].Certificate
+	unknown.SerialNumber = big.NewInt(1)
+	assert.Equal(t, ocsp.Unknown, query(t, &unknown, false).Status)
+
+	response, err = http.Post(server.URL+"/ocsp", "application/ocsp-request", bytes.NewReader([]byte("malformed")))
+	assert.NoError(t, err)
+	body, err = io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, response.Body.Close())
+	assert.Equal(t, ocsp.MalformedRequestErrorResponse, body)
+}
@@ -0,0 +1,107 @@
+package testpki
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type pkiFile struct {
+	name    string
+	content []byte
+	mode    os.FileMode
+}
+
+// Write writes the certificates in PEM, the private keys in PKCS #8 PEM and the DER encoded CRLs to dir, existing files are never overwritten.
+// A leaf certificate is written with the intermediate CA, so the files of a leaf are a certificate chain that CertGuard imports.
+func (p *PKI) Write(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Join(errors.New("could not create the output directory"), err)
+	}
+
+	files := []pkiFile{
+		{name: "root.pem", content: certificatesPEM(p.Root.Certificate), mode: 0o644},
+		{name: "intermediate.pem", content: certificatesPEM(p.Intermediate.Certificate), mode: 0o644},
+		{name: "ca-chain.pem", content: certificatesPEM(p.Intermediate.Certificate, p.Root.Certificate), mode: 0o644},
+		{name: "ocsp-responder.pem", content: certificatesPEM(p.Responder.Certificate), mode: 0o644},
+		{name: "root.crl", content: p.RootCRL, mode: 0o644},
+		{name: "intermediate.crl", content: p.BaseCRL, mode: 0o644},
+		{name: "intermediate-delta.crl", content: p.DeltaCRL, mode: 0o644},
+	}
+
+	rootKey, err := keyFile("root", p.Root)
+	if err != nil {
+		return nil, err
+	}
+	intermediateKey, err := keyFile("intermediate", p.Intermediate)
+	if err != nil {
+		return nil, err
+	}
+	responderKey, err := keyFile("ocsp-responder", p.Responder)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, rootKey, intermediateKey, responderKey)
+
+	for _, leaf := range p.Leaves {
+		name := fileName(leaf.Certificate.Subject.CommonName)
+		leafKey, err := keyFile(name, leaf)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, pkiFile{name: name + ".pem", content: certificatesPEM(leaf.Certificate, p.Intermediate.Certificate), mode: 0o644}, leafKey)
+	}
+
+	written := make([]string, 0, len(files))
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		if err := writeFile(path, file.content, file.mode); err != nil {
+			return written, errors.Join(fmt.Errorf("could not write %s", path), err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// keyFile encodes the private key of an issuer, only the owner can read it
+func keyFile(name string, issuer *Issuer) (pkiFile, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(issuer.Key)
+	if err != nil {
+		return pkiFile{}, errors.Join(fmt.Errorf("could not encode the private key of %s", name), err)
+	}
+	return pkiFile{name: name + "-key.pem", content: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), mode: 0o600}, nil
+}
+
+func writeFile(path string, content []byte, mode os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func certificatesPEM(certificates ...*x509.Certificate) []byte {
+	var encoded []byte
+	for _, certificate := range certificates {
+		encoded = append(encoded, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})...)
+	}
+	return encoded
+}
+
+// fileName is the file name of a leaf certificate, named after its common name
+func fileName(commonName string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '/' || r == '\\' || r == '*' {
+			return '_'
+		}
+		return r
+	}, commonName)
+}